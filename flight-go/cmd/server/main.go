@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/mirahekatiket/flight-go/internal/cache"
 	"github.com/mirahekatiket/flight-go/internal/config"
+	"github.com/mirahekatiket/flight-go/internal/crypto"
 	"github.com/mirahekatiket/flight-go/internal/database"
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/handlers"
 	"github.com/mirahekatiket/flight-go/internal/middleware"
 	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/notifications"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 	"github.com/mirahekatiket/flight-go/internal/router"
 	"github.com/mirahekatiket/flight-go/internal/services"
+	"github.com/mirahekatiket/flight-go/internal/webhooks"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -38,6 +46,13 @@ func main() {
 	// Load configuration
 	cfg := config.Load()
 
+	// Passenger document numbers are encrypted at rest, the serializer must exist before migrating
+	documentCipher, err := crypto.NewCipher(cfg.DocumentEncryptionKey)
+	if err != nil {
+		log.Fatalf("Failed to set up document encryption: %v", err)
+	}
+	crypto.RegisterSerializer(documentCipher)
+
 	// Connect to dual databases (staging and production)
 	dualDB, err := database.ConnectDual(cfg)
 	if err != nil {
@@ -59,8 +74,20 @@ func main() {
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(mainDB)
+	sessionRepo := repository.NewSessionRepository(mainDB)
 	orderRepo := repository.NewOrderRepository(mainDB)
 	whitelistRepo := repository.NewWhitelistRepository(mainDB)
+	exchangeRateRepo := repository.NewExchangeRateRepository(mainDB)
+	promotionRepo := repository.NewPromotionRepository(mainDB)
+	travelerRepo := repository.NewTravelerRepository(mainDB)
+	seatMapRepo := repository.NewSeatMapRepository(mainDB)
+	seatRepo := repository.NewSeatAssignmentRepository(mainDB)
+	disruptionRepo := repository.NewScheduleDisruptionRepository(mainDB)
+	notificationRepo := repository.NewNotificationRepository(mainDB)
+	deliveryRepo := repository.NewNotificationDeliveryRepository(mainDB)
+	webhookSubscriptionRepo := repository.NewWebhookSubscriptionRepository(mainDB)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(mainDB)
+	unitOfWork := repository.NewUnitOfWork(mainDB)
 
 	// Initialize dual repositories for airlines, airports, schedules
 	stagingAirlineRepo := repository.NewAirlineRepository(dualDB.Staging)
@@ -68,10 +95,52 @@ func main() {
 	stagingAirportRepo := repository.NewAirportRepository(dualDB.Staging)
 	stagingScheduleRepo := repository.NewScheduleRepository(dualDB.Staging)
 	productionScheduleRepo := repository.NewScheduleRepository(dualDB.Production)
+	stagingFareRuleRepo := repository.NewFareRuleRepository(dualDB.Staging)
+	productionFareRuleRepo := repository.NewFareRuleRepository(dualDB.Production)
+	stagingFareFamilyRepo := repository.NewFareFamilyRepository(dualDB.Staging)
+	productionFareFamilyRepo := repository.NewFareFamilyRepository(dualDB.Production)
+	stagingFeeRuleRepo := repository.NewFeeRuleRepository(dualDB.Staging)
+	productionFeeRuleRepo := repository.NewFeeRuleRepository(dualDB.Production)
+	stagingAncillaryRepo := repository.NewAncillaryRepository(dualDB.Staging)
+	productionAncillaryRepo := repository.NewAncillaryRepository(dualDB.Production)
+
+	// Search result cache shared by every service that writes schedules or airlines
+	var searchCache *services.SearchCache
+	if cfg.SearchCacheTTL > 0 {
+		searchCache = services.NewSearchCache(cache.NewMemory(cfg.SearchCacheMaxEntries), cfg.SearchCacheTTL)
+	}
+
+	// Notifications are queued in the main database and delivered in the background
+	templates, err := notifications.NewTemplates()
+	if err != nil {
+		log.Fatalf("Failed to load notification templates: %v", err)
+	}
+	notifier := notifications.NewNotifier(templates, deliveryRepo, notificationChannels(cfg), cfg.NotificationMaxAttempts, cfg.NotificationRetryDelay)
+	go notifier.Run(context.Background())
+
+	// Partner webhooks are queued the same way
+	webhookDispatcher := webhooks.NewDispatcher(webhookSubscriptionRepo, webhookDeliveryRepo, cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.WebhookRetryDelay)
+	go webhookDispatcher.Run(context.Background())
+
+	// Services publish what happened on the event bus, the search cache, disruption detection,
+	// notifications and partner webhooks react to it as subscribers
+	bus := events.NewBus(cfg.EventWorkers, cfg.EventQueueSize)
+	go bus.Run(context.Background())
 
 	// Initialize services
-	authService := services.NewAuthService(userRepo, cfg)
-	whitelistService := services.NewWhitelistService(whitelistRepo)
+	authService := services.NewAuthService(userRepo, orderRepo, sessionRepo, bus, cfg)
+	whitelistService := services.NewWhitelistService(whitelistRepo, bus)
+	currencyService := services.NewCurrencyService(exchangeRateRepo)
+	exchangeRateService := services.NewExchangeRateService(exchangeRateRepo)
+	pricingService := services.NewPricingService(
+		stagingFareRuleRepo,
+		productionFareRuleRepo,
+		stagingFareFamilyRepo,
+		productionFareFamilyRepo,
+		orderRepo,
+		currencyService,
+	)
+	feeService := services.NewFeeService(stagingFeeRuleRepo, productionFeeRuleRepo, currencyService)
 
 	// Create dual schedule service with both repositories, whitelist service, and airline repo
 	scheduleService := services.NewDualScheduleService(
@@ -79,15 +148,51 @@ func main() {
 		productionScheduleRepo,
 		whitelistService,
 		stagingAirlineRepo,
+		searchCache,
+		pricingService,
+		feeService,
+		bus,
+		cfg,
 	)
 
 	// Create services for both environments
-	stagingAirlineService := services.NewAirlineService(stagingAirlineRepo)
-	productionAirlineService := services.NewAirlineService(productionAirlineRepo)
-	stagingScheduleService := services.NewScheduleService(stagingScheduleRepo)
-	productionScheduleService := services.NewScheduleService(productionScheduleRepo)
+	stagingAirlineService := services.NewAirlineService(stagingAirlineRepo, bus, models.EnvStaging)
+	productionAirlineService := services.NewAirlineService(productionAirlineRepo, bus, models.EnvProduction)
+	disruptionService := services.NewDisruptionService(orderRepo, disruptionRepo, unitOfWork, cfg.ScheduleChangeThreshold, bus)
+	stagingScheduleService := services.NewScheduleService(stagingScheduleRepo, bus, models.EnvStaging)
+	productionScheduleService := services.NewScheduleService(productionScheduleRepo, bus, models.EnvProduction)
+	stagingFareRuleService := services.NewFareRuleService(stagingFareRuleRepo)
+	productionFareRuleService := services.NewFareRuleService(productionFareRuleRepo)
+	stagingFareFamilyService := services.NewFareFamilyService(stagingFareFamilyRepo)
+	productionFareFamilyService := services.NewFareFamilyService(productionFareFamilyRepo)
+	stagingFeeRuleService := services.NewFeeRuleService(stagingFeeRuleRepo)
+	productionFeeRuleService := services.NewFeeRuleService(productionFeeRuleRepo)
+	stagingAncillaryService := services.NewAncillaryService(stagingAncillaryRepo)
+	productionAncillaryService := services.NewAncillaryService(productionAncillaryRepo)
+
+	scheduleResolver := services.NewScheduleResolver(stagingScheduleRepo, productionScheduleRepo, whitelistService)
+	promotionService := services.NewPromotionService(promotionRepo, scheduleResolver, currencyService)
+	travelerService := services.NewTravelerService(travelerRepo)
+	seatMapService := services.NewSeatMapService(seatMapRepo)
+	seatService := services.NewSeatService(seatMapRepo, seatRepo, scheduleResolver, currencyService)
+	ancillaryPricingService := services.NewAncillaryPricingService(
+		stagingAncillaryRepo,
+		productionAncillaryRepo,
+		scheduleResolver,
+		currencyService,
+		cfg.AncillaryCutoff,
+	)
+	orderService := services.NewOrderService(orderRepo, scheduleResolver, pricingService, feeService, promotionService, travelerService, seatService, ancillaryPricingService, unitOfWork, bus)
+	notificationService := services.NewNotificationService(notificationRepo, deliveryRepo)
+	webhookService := services.NewWebhookService(webhookSubscriptionRepo, webhookDeliveryRepo, webhookDispatcher)
+	userService := services.NewUserService(userRepo, unitOfWork, authService)
 
-	orderService := services.NewOrderService(orderRepo, stagingScheduleRepo)
+	// Subscribe in the order sync subscribers should run: the cache is dropped before disruption
+	// detection reads the changed schedule
+	searchCache.Subscribe(bus)
+	disruptionService.Subscribe(bus)
+	services.NewNotificationSubscriber(notifier).Subscribe(bus)
+	services.NewWebhookSubscriber(webhookDispatcher).Subscribe(bus)
 
 	// Create default admin user in main database
 	createAdminUser(mainDB, cfg)
@@ -101,10 +206,24 @@ func main() {
 	productionAirlineHandler := handlers.NewAirlineHandler(productionAirlineService)
 	stagingScheduleHandler := handlers.NewScheduleHandler(stagingScheduleService)
 	productionScheduleHandler := handlers.NewScheduleHandler(productionScheduleService)
+	stagingFareRuleHandler := handlers.NewFareRuleHandler(stagingFareRuleService)
+	productionFareRuleHandler := handlers.NewFareRuleHandler(productionFareRuleService)
+	stagingFareFamilyHandler := handlers.NewFareFamilyHandler(stagingFareFamilyService)
+	productionFareFamilyHandler := handlers.NewFareFamilyHandler(productionFareFamilyService)
+	stagingFeeRuleHandler := handlers.NewFeeRuleHandler(stagingFeeRuleService)
+	productionFeeRuleHandler := handlers.NewFeeRuleHandler(productionFeeRuleService)
+	stagingAncillaryHandler := handlers.NewAncillaryHandler(stagingAncillaryService, ancillaryPricingService)
+	productionAncillaryHandler := handlers.NewAncillaryHandler(productionAncillaryService, ancillaryPricingService)
 	airportHandler := handlers.NewAirportHandler(stagingAirportRepo)
 	scheduleHandler := handlers.NewScheduleHandler(scheduleService) // For public search (dual)
 	orderHandler := handlers.NewOrderHandler(orderService)
 	whitelistHandler := handlers.NewWhitelistHandler(whitelistService)
+	cacheHandler := handlers.NewCacheHandler(searchCache)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService)
+	promotionHandler := handlers.NewPromotionHandler(promotionService)
+	travelerHandler := handlers.NewTravelerHandler(travelerService)
+	guestOrderHandler := handlers.NewGuestOrderHandler(orderService, services.NewBookingTokenService(cfg))
+	seatMapHandler := handlers.NewSeatMapHandler(seatMapService, seatService)
 
 	// Create environment-aware handler
 	envHandler := handlers.NewEnvAwareHandler(
@@ -112,6 +231,14 @@ func main() {
 		productionAirlineHandler,
 		stagingScheduleHandler,
 		productionScheduleHandler,
+		stagingFareRuleHandler,
+		productionFareRuleHandler,
+		stagingFareFamilyHandler,
+		productionFareFamilyHandler,
+		stagingFeeRuleHandler,
+		productionFeeRuleHandler,
+		stagingAncillaryHandler,
+		productionAncillaryHandler,
 	)
 
 	// Setup router
@@ -124,6 +251,19 @@ func main() {
 		orderHandler,
 		whitelistHandler,
 		envHandler,
+		cacheHandler,
+		exchangeRateHandler,
+		promotionHandler,
+		travelerHandler,
+		guestOrderHandler,
+		middleware.NewRateLimiter(cfg.GuestRateLimit, cfg.GuestRateWindow),
+		seatMapHandler,
+		stagingAncillaryHandler,
+		handlers.NewNotificationHandler(notificationService),
+		handlers.NewDisruptionHandler(disruptionService),
+		handlers.NewWebhookHandler(webhookService),
+		handlers.NewUserHandler(userService),
+		middleware.NewIdempotency(cache.NewMemory(cfg.IdempotencyMaxEntries), cfg.IdempotencyTTL),
 	)
 
 	engine := r.Setup()
@@ -137,6 +277,30 @@ func main() {
 	}
 }
 
+// notificationChannels builds the channels named in NOTIFICATION_CHANNELS
+func notificationChannels(cfg *config.Config) []notifications.Channel {
+	var channels []notifications.Channel
+	for _, name := range strings.Split(cfg.NotificationChannels, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "smtp":
+			channels = append(channels, notifications.NewSMTP(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom))
+		case "webhook":
+			if cfg.NotificationWebhookURL == "" {
+				log.Fatalf("NOTIFICATION_WEBHOOK_URL is required for the webhook notification channel")
+			}
+			channels = append(channels, notifications.NewWebhook(cfg.NotificationWebhookURL, 10*time.Second))
+		case "file":
+			channels = append(channels, notifications.NewFile(cfg.NotificationFilePath))
+		case "memory":
+			channels = append(channels, notifications.NewMemory())
+		default:
+			log.Fatalf("Unknown notification channel %q", name)
+		}
+	}
+	return channels
+}
+
 func createAdminUser(db *gorm.DB, cfg *config.Config) {
 	// Check if admin already exists
 	var existingAdmin models.User
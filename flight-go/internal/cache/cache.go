@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a byte-oriented key/value store with per-entry TTLs.
+// It mirrors the subset of Redis commands we rely on (GET, SET EX, DEL, SCAN+DEL)
+// so a Redis-compatible backend can be added next to the in-memory one.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	DeletePrefix(ctx context.Context, prefix string) error
+	Name() string
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"time"
 )
 
@@ -11,9 +12,57 @@ type Config struct {
 	StagingDatabasePath    string
 	ProductionDatabasePath string
 	JWTSecret              string
-	JWTExpiration          time.Duration
+	AccessTokenTTL         time.Duration
+	RefreshTokenTTL        time.Duration // Since the last refresh
 	AdminEmail             string
 	AdminPassword          string
+
+	// Flight search fan-out across staging and production
+	SearchWorkers           int
+	StagingSearchTimeout    time.Duration
+	ProductionSearchTimeout time.Duration
+
+	// Flight search result cache, a zero TTL disables it
+	SearchCacheTTL        time.Duration
+	SearchCacheMaxEntries int
+
+	// Secret the passenger document encryption key is derived from
+	DocumentEncryptionKey string
+
+	// Requests a client IP may make to the guest checkout and booking lookup routes per window
+	GuestRateLimit  int
+	GuestRateWindow time.Duration
+
+	// How long before departure ancillary sales close
+	AncillaryCutoff time.Duration
+
+	// How long responses to requests with an Idempotency-Key are kept for replay
+	IdempotencyTTL        time.Duration
+	IdempotencyMaxEntries int
+
+	// How far a schedule's departure or arrival may move before booked passengers must respond
+	ScheduleChangeThreshold time.Duration
+
+	// Notification channels, comma separated from smtp, webhook, file and memory
+	NotificationChannels    string
+	NotificationMaxAttempts int
+	NotificationRetryDelay  time.Duration // Before the first retry, doubling after each further failure
+	NotificationFilePath    string
+	NotificationWebhookURL  string
+	SMTPHost                string
+	SMTPPort                int
+	SMTPUsername            string
+	SMTPPassword            string
+	SMTPFrom                string
+
+	// Partner webhook deliveries
+	WebhookTimeout     time.Duration
+	WebhookMaxAttempts int
+	WebhookRetryDelay  time.Duration // Before the first retry, doubling after each further failure
+
+	// Domain event bus running asynchronous subscribers like notifications and webhooks
+	EventWorkers   int
+	EventQueueSize int
 }
 
 func Load() *Config {
@@ -23,9 +72,47 @@ func Load() *Config {
 		StagingDatabasePath:    getEnv("STAGING_DATABASE_PATH", "staging.db"),
 		ProductionDatabasePath: getEnv("PRODUCTION_DATABASE_PATH", "production.db"),
 		JWTSecret:              getEnv("JWT_SECRET", "your-super-secret-key-change-in-production"),
-		JWTExpiration:          24 * time.Hour,
+		AccessTokenTTL:         getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:        getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
 		AdminEmail:             getEnv("ADMIN_EMAIL", "admin@tiket.com"),
 		AdminPassword:          getEnv("ADMIN_PASSWORD", "admin123"),
+
+		SearchWorkers:           getEnvInt("SEARCH_WORKERS", 4),
+		StagingSearchTimeout:    getEnvDuration("STAGING_SEARCH_TIMEOUT", 3*time.Second),
+		ProductionSearchTimeout: getEnvDuration("PRODUCTION_SEARCH_TIMEOUT", 3*time.Second),
+
+		SearchCacheTTL:        getEnvDuration("SEARCH_CACHE_TTL", 5*time.Minute),
+		SearchCacheMaxEntries: getEnvInt("SEARCH_CACHE_MAX_ENTRIES", 1000),
+
+		DocumentEncryptionKey: getEnv("DOCUMENT_ENCRYPTION_KEY", "your-document-encryption-key-change-in-production"),
+
+		GuestRateLimit:  getEnvInt("GUEST_RATE_LIMIT", 10),
+		GuestRateWindow: getEnvDuration("GUEST_RATE_WINDOW", time.Minute),
+
+		AncillaryCutoff: getEnvDuration("ANCILLARY_CUTOFF", 4*time.Hour),
+
+		IdempotencyTTL:        getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyMaxEntries: getEnvInt("IDEMPOTENCY_MAX_ENTRIES", 10000),
+
+		ScheduleChangeThreshold: getEnvDuration("SCHEDULE_CHANGE_THRESHOLD", time.Hour),
+
+		NotificationChannels:    getEnv("NOTIFICATION_CHANNELS", "file"),
+		NotificationMaxAttempts: getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		NotificationRetryDelay:  getEnvDuration("NOTIFICATION_RETRY_DELAY", 30*time.Second),
+		NotificationFilePath:    getEnv("NOTIFICATION_FILE_PATH", "notifications.log"),
+		NotificationWebhookURL:  getEnv("NOTIFICATION_WEBHOOK_URL", ""),
+		SMTPHost:                getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:                getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:            getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:            getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:                getEnv("SMTP_FROM", "no-reply@flight.com"),
+
+		WebhookTimeout:     getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookRetryDelay:  getEnvDuration("WEBHOOK_RETRY_DELAY", 30*time.Second),
+
+		EventWorkers:   getEnvInt("EVENT_WORKERS", 4),
+		EventQueueSize: getEnvInt("EVENT_QUEUE_SIZE", 1000),
 	}
 }
 
@@ -35,3 +122,17 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCipherRoundTrip(t *testing.T) {
+	cipher, err := NewCipher("document-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		plaintext string
+	}{
+		{name: "passport number", plaintext: "A1234567"},
+		{name: "national id", plaintext: "3171000000000001"},
+		{name: "unicode", plaintext: "Ñandú-42"},
+		{name: "empty stays empty", plaintext: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sealed, err := cipher.Encrypt(tt.plaintext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.plaintext == "" {
+				if sealed != "" {
+					t.Errorf("Encrypt(\"\") = %q, want empty", sealed)
+				}
+			} else if !strings.HasPrefix(sealed, encryptedPrefix) || strings.Contains(sealed, tt.plaintext) {
+				t.Errorf("Encrypt(%q) = %q, want an opaque %s value", tt.plaintext, sealed, encryptedPrefix)
+			}
+
+			opened, err := cipher.Decrypt(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opened != tt.plaintext {
+				t.Errorf("Decrypt = %q, want %q", opened, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestCipherEncryptUsesFreshNonces(t *testing.T) {
+	cipher, _ := NewCipher("document-key")
+	first, _ := cipher.Encrypt("A1234567")
+	second, _ := cipher.Encrypt("A1234567")
+	if first == second {
+		t.Error("encrypting the same value twice gave the same ciphertext")
+	}
+}
+
+func TestCipherDecryptRejects(t *testing.T) {
+	cipher, _ := NewCipher("document-key")
+	other, _ := NewCipher("another-key")
+	sealed, _ := cipher.Encrypt("A1234567")
+	sealedByOther, _ := other.Encrypt("A1234567")
+	tampered := sealed[:len(sealed)-4] + "AAAA"
+
+	tests := []struct {
+		name       string
+		ciphertext string
+		malformed  bool
+	}{
+		{name: "plaintext", ciphertext: "A1234567", malformed: true},
+		{name: "not base64", ciphertext: encryptedPrefix + "%%%", malformed: true},
+		{name: "shorter than a nonce", ciphertext: encryptedPrefix + "AAAA", malformed: true},
+		{name: "sealed under another key", ciphertext: sealedByOther},
+		{name: "tampered", ciphertext: tampered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := cipher.Decrypt(tt.ciphertext)
+			if err == nil {
+				t.Fatal("Decrypt succeeded, want an error")
+			}
+			if errors.Is(err, ErrMalformedCiphertext) != tt.malformed {
+				t.Errorf("err = %v, malformed %v", err, tt.malformed)
+			}
+		})
+	}
+}
+
+func TestNewCipherRequiresSecret(t *testing.T) {
+	if _, err := NewCipher(""); err == nil {
+		t.Error("NewCipher(\"\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,52 @@
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName is the gorm serializer that encrypts string columns at rest,
+// used as `gorm:"serializer:encrypted"`
+const SerializerName = "encrypted"
+
+// Serializer encrypts a string field on write and decrypts it on read
+type Serializer struct {
+	cipher *Cipher
+}
+
+// RegisterSerializer makes the encrypted serializer available to gorm.
+// It must run before any model using it is migrated or queried.
+func RegisterSerializer(cipher *Cipher) {
+	schema.RegisterSerializer(SerializerName, Serializer{cipher: cipher})
+}
+
+func (s Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var ciphertext string
+	switch value := dbValue.(type) {
+	case nil:
+	case string:
+		ciphertext = value
+	case []byte:
+		ciphertext = string(value)
+	default:
+		return fmt.Errorf("cannot decrypt %T into %s", dbValue, field.Name)
+	}
+
+	plaintext, err := s.cipher.Decrypt(ciphertext)
+	if err != nil {
+		return fmt.Errorf("decrypt %s: %w", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+func (s Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("cannot encrypt %T in %s", fieldValue, field.Name)
+	}
+	return s.cipher.Encrypt(plaintext)
+}
@@ -0,0 +1,20 @@
+package database
+
+import "github.com/mirahekatiket/flight-go/internal/models"
+
+// defaultAncillaries is the add-on catalogue every seeded airline sells, prices in IDR
+func defaultAncillaries(airlineID string) []models.Ancillary {
+	ancillaries := []models.Ancillary{
+		{AirlineID: airlineID, Type: models.ChargeBaggage, Code: "BAG5", Name: "Extra baggage 5 kg", WeightKg: 5, Price: 135000, SortOrder: 1},
+		{AirlineID: airlineID, Type: models.ChargeBaggage, Code: "BAG10", Name: "Extra baggage 10 kg", WeightKg: 10, Price: 250000, SortOrder: 2},
+		{AirlineID: airlineID, Type: models.ChargeBaggage, Code: "BAG20", Name: "Extra baggage 20 kg", WeightKg: 20, Price: 450000, SortOrder: 3},
+		{AirlineID: airlineID, Type: models.ChargeMeal, Code: "MEAL-NASI", Name: "Nasi goreng ayam", Description: "Chicken fried rice with a drink", Price: 55000, SortOrder: 1},
+		{AirlineID: airlineID, Type: models.ChargeMeal, Code: "MEAL-VEG", Name: "Vegetarian meal", Description: "Vegetable rice bowl with a drink", Price: 55000, SortOrder: 2},
+		{AirlineID: airlineID, Type: models.ChargeInsurance, Code: "INS-TRAVEL", Name: "Travel insurance", Description: "Covers flight delays, lost baggage and medical costs during the trip", Price: 35000, SortOrder: 1},
+	}
+	for i := range ancillaries {
+		ancillaries[i].Currency = models.DefaultCurrency
+		ancillaries[i].IsActive = true
+	}
+	return ancillaries
+}
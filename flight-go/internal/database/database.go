@@ -15,7 +15,7 @@ const (
 )
 
 func Connect(cfg *config.Config) (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open(cfg.DatabasePath), &gorm.Config{
+	db, err := gorm.Open(sqlite.Open(SQLiteDSN(cfg.DatabasePath)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -23,6 +23,16 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	// Auto migrate models
+	if err := Migrate(db); err != nil {
+		return nil, err
+	}
+
+	log.Println("Database connected and migrated successfully")
+	return db, nil
+}
+
+// Migrate runs the auto migrations for every model on the given database
+func Migrate(db *gorm.DB) error {
 	if err := db.AutoMigrate(
 		&models.User{},
 		&models.Airline{},
@@ -31,12 +41,112 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		&models.Order{},
 		&models.Passenger{},
 		&models.WhitelistedUser{},
+		&models.FareRule{},
+		&models.FareFamily{},
+		&models.FeeRule{},
+		&models.ExchangeRate{},
+		&models.Promotion{},
+		&models.PromotionRedemption{},
+		&models.Traveler{},
+		&models.OrderChange{},
+		&models.SeatMap{},
+		&models.SeatAssignment{},
+		&models.Ancillary{},
+		&models.OrderAncillary{},
+		&models.ScheduleDisruption{},
+		&models.Notification{},
+		&models.NotificationDelivery{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.Session{},
+		&models.UserChange{},
 	); err != nil {
-		return nil, err
+		return err
 	}
 
-	log.Println("Database connected and migrated successfully")
-	return db, nil
+	if err := migrateOrderAmounts(db); err != nil {
+		return err
+	}
+
+	if err := backfillOrderPNRs(db); err != nil {
+		return err
+	}
+
+	return backfillFlightSnapshots(db)
+}
+
+// migrateOrderAmounts moves orders from the float64 total_amount column (implicitly IDR)
+// to integer minor units in total_amount_minor
+func migrateOrderAmounts(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Order{}, "total_amount") {
+		return nil
+	}
+
+	if err := db.Exec(
+		"UPDATE orders SET total_amount_minor = CAST(ROUND(total_amount * ?) AS INTEGER), currency = ?",
+		models.ToMinorUnits(1, models.DefaultCurrency), models.DefaultCurrency,
+	).Error; err != nil {
+		return err
+	}
+
+	return db.Migrator().DropColumn(&models.Order{}, "total_amount")
+}
+
+// backfillOrderPNRs gives orders created before booking references existed a PNR
+func backfillOrderPNRs(db *gorm.DB) error {
+	var orderIDs []string
+	if err := db.Model(&models.Order{}).Where("pnr IS NULL OR pnr = ''").Pluck("id", &orderIDs).Error; err != nil {
+		return err
+	}
+
+	for _, id := range orderIDs {
+		var err error
+		for attempt := 0; attempt < 5; attempt++ {
+			if err = db.Model(&models.Order{}).Where("id = ?", id).UpdateColumn("pnr", models.NewPNR()).Error; err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillFlightSnapshots gives orders booked before they kept a flight snapshot one, taken from
+// their schedule as it is now, and the airline ID to snapshots without it. Only staging orders can
+// be filled in, their schedules are in this database.
+func backfillFlightSnapshots(db *gorm.DB) error {
+	var orders []models.Order
+	if err := db.Where("flight IS NULL AND environment = ?", models.EnvStaging).Find(&orders).Error; err != nil {
+		return err
+	}
+
+	// Snapshots taken before they kept the airline ID get the one of their schedule, which never changes
+	if err := db.Exec(
+		`UPDATE orders SET flight = json_set(flight, '$.airline_id', (SELECT airline_id FROM schedules WHERE schedules.id = orders.schedule_id))
+		WHERE environment = ? AND flight IS NOT NULL AND json_extract(flight, '$.airline_id') IS NULL
+		AND EXISTS (SELECT 1 FROM schedules WHERE schedules.id = orders.schedule_id)`,
+		models.EnvStaging,
+	).Error; err != nil {
+		return err
+	}
+
+	for _, order := range orders {
+		var schedule models.Schedule
+		if err := db.Unscoped().
+			Preload("Airline").
+			Preload("DepartureAirport").
+			Preload("ArrivalAirport").
+			First(&schedule, "id = ?", order.ScheduleID).Error; err != nil {
+			continue
+		}
+		snapshot := &models.Order{Flight: models.NewFlightSnapshot(&schedule, order.CabinClass)}
+		if err := db.Model(&models.Order{}).Where("id = ?", order.ID).Select("Flight").Updates(snapshot).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SeedDefaultData seeds initial data
@@ -73,6 +183,8 @@ func SeedDefaultData(db *gorm.DB, cfg *config.Config, env string) error {
 		{BaseModel: models.BaseModel{ID: "srg"}, Code: "SRG", City: "Semarang", Name: getName("Ahmad Yani International Airport", env)},
 		{BaseModel: models.BaseModel{ID: "soc"}, Code: "SOC", City: "Solo", Name: getName("Adisumarmo International Airport", env)},
 		{BaseModel: models.BaseModel{ID: "lop"}, Code: "LOP", City: "Lombok", Name: getName("Lombok International Airport", env)},
+		{BaseModel: models.BaseModel{ID: "sin"}, Code: "SIN", City: "Singapore", Country: "SG", Name: getName("Changi Airport", env)},
+		{BaseModel: models.BaseModel{ID: "kul"}, Code: "KUL", City: "Kuala Lumpur", Country: "MY", Name: getName("Kuala Lumpur International Airport", env)},
 	}
 
 	for _, airport := range airports {
@@ -157,6 +269,18 @@ func SeedDefaultData(db *gorm.DB, cfg *config.Config, env string) error {
 		}
 	}
 
+	// Seed each airline's ancillary catalogue
+	for _, airline := range airlines {
+		for _, ancillary := range defaultAncillaries(airline.ID) {
+			db.FirstOrCreate(&ancillary, models.Ancillary{AirlineID: ancillary.AirlineID, Code: ancillary.Code})
+		}
+	}
+
+	// Seed seat maps for the aircraft flown above
+	for _, seatMap := range defaultSeatMaps() {
+		db.FirstOrCreate(&seatMap, models.SeatMap{Aircraft: seatMap.Aircraft})
+	}
+
 	log.Println("Default data seeded successfully")
 	return nil
 }
@@ -1,15 +1,29 @@
 package database
 
 import (
+	"fmt"
 	"log"
+	"strings"
 
 	"github.com/mirahekatiket/flight-go/internal/config"
-	"github.com/mirahekatiket/flight-go/internal/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// How long a connection waits for another one's write lock before failing with SQLITE_BUSY
+const sqliteBusyTimeoutMs = 5000
+
+// SQLiteDSN opens path in WAL mode, so readers don't block the writer, with a busy timeout
+// and write-locking transactions so concurrent writers queue up instead of failing
+func SQLiteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s_busy_timeout=%d&_journal_mode=WAL&_txlock=immediate", path, separator, sqliteBusyTimeoutMs)
+}
+
 // DualDB manages both staging and production databases
 type DualDB struct {
 	Staging    *gorm.DB
@@ -20,7 +34,7 @@ type DualDB struct {
 // ConnectDual connects to both staging and production databases
 func ConnectDual(cfg *config.Config) (*DualDB, error) {
 	// Connect to staging database
-	stagingDB, err := gorm.Open(sqlite.Open(cfg.StagingDatabasePath), &gorm.Config{
+	stagingDB, err := gorm.Open(sqlite.Open(SQLiteDSN(cfg.StagingDatabasePath)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -28,21 +42,13 @@ func ConnectDual(cfg *config.Config) (*DualDB, error) {
 	}
 
 	// Run migrations on staging database
-	if err := stagingDB.AutoMigrate(
-		&models.User{},
-		&models.Airline{},
-		&models.Airport{},
-		&models.Schedule{},
-		&models.Order{},
-		&models.Passenger{},
-		&models.WhitelistedUser{},
-	); err != nil {
+	if err := Migrate(stagingDB); err != nil {
 		return nil, err
 	}
 	log.Println("Staging database migrated successfully")
 
 	// Connect to production database
-	productionDB, err := gorm.Open(sqlite.Open(cfg.ProductionDatabasePath), &gorm.Config{
+	productionDB, err := gorm.Open(sqlite.Open(SQLiteDSN(cfg.ProductionDatabasePath)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -50,15 +56,7 @@ func ConnectDual(cfg *config.Config) (*DualDB, error) {
 	}
 
 	// Run migrations on production database
-	if err := productionDB.AutoMigrate(
-		&models.User{},
-		&models.Airline{},
-		&models.Airport{},
-		&models.Schedule{},
-		&models.Order{},
-		&models.Passenger{},
-		&models.WhitelistedUser{},
-	); err != nil {
+	if err := Migrate(productionDB); err != nil {
 		return nil, err
 	}
 	log.Println("Production database migrated successfully")
@@ -0,0 +1,60 @@
+package database
+
+import "github.com/mirahekatiket/flight-go/internal/models"
+
+// Seat prices in IDR, front and exit row seats cost extra while premium cabins include the seat
+const (
+	standardSeatPrice = 50000
+	frontSeatPrice    = 75000
+	exitRowSeatPrice  = 150000
+)
+
+// defaultSeatMaps lays out the aircraft flown by the seeded schedules
+func defaultSeatMaps() []models.SeatMap {
+	return []models.SeatMap{
+		{
+			Aircraft: "Boeing 737-800",
+			Currency: models.DefaultCurrency,
+			Zones: []models.SeatZone{
+				{Name: "First", CabinClass: models.CabinFirst, FromRow: 1, ToRow: 2, Layout: "AC DF"},
+				{Name: "Business", CabinClass: models.CabinBusiness, FromRow: 3, ToRow: 9, Layout: "AC DF"},
+				{Name: "Front", CabinClass: models.CabinEconomy, FromRow: 10, ToRow: 14, Layout: "ABC DEF", Price: frontSeatPrice},
+				{Name: "Extra legroom", CabinClass: models.CabinEconomy, FromRow: 15, ToRow: 16, Layout: "ABC DEF", Price: exitRowSeatPrice},
+				{Name: "Standard", CabinClass: models.CabinEconomy, FromRow: 17, ToRow: 39, Layout: "ABC DEF", Price: standardSeatPrice},
+			},
+			ExitRows: []int{15, 16},
+		},
+		{
+			Aircraft: "Boeing 737-900ER",
+			Currency: models.DefaultCurrency,
+			Zones: []models.SeatZone{
+				{Name: "Business", CabinClass: models.CabinBusiness, FromRow: 1, ToRow: 5, Layout: "AC DF"},
+				{Name: "Front", CabinClass: models.CabinEconomy, FromRow: 6, ToRow: 10, Layout: "ABC DEF", Price: frontSeatPrice},
+				{Name: "Extra legroom", CabinClass: models.CabinEconomy, FromRow: 11, ToRow: 12, Layout: "ABC DEF", Price: exitRowSeatPrice},
+				{Name: "Standard", CabinClass: models.CabinEconomy, FromRow: 13, ToRow: 35, Layout: "ABC DEF", Price: standardSeatPrice},
+			},
+			ExitRows: []int{11, 12},
+		},
+		{
+			Aircraft: "Airbus A320",
+			Currency: models.DefaultCurrency,
+			Zones: []models.SeatZone{
+				{Name: "Business", CabinClass: models.CabinBusiness, FromRow: 1, ToRow: 6, Layout: "AC DF"},
+				{Name: "Front", CabinClass: models.CabinEconomy, FromRow: 7, ToRow: 10, Layout: "ABC DEF", Price: frontSeatPrice},
+				{Name: "Extra legroom", CabinClass: models.CabinEconomy, FromRow: 11, ToRow: 12, Layout: "ABC DEF", Price: exitRowSeatPrice},
+				{Name: "Standard", CabinClass: models.CabinEconomy, FromRow: 13, ToRow: 40, Layout: "ABC DEF", Price: standardSeatPrice},
+			},
+			ExitRows: []int{11, 12},
+		},
+		{
+			Aircraft: "Boeing 737-500",
+			Currency: models.DefaultCurrency,
+			Zones: []models.SeatZone{
+				{Name: "Front", CabinClass: models.CabinEconomy, FromRow: 1, ToRow: 5, Layout: "ABC DEF", Price: frontSeatPrice},
+				{Name: "Extra legroom", CabinClass: models.CabinEconomy, FromRow: 6, ToRow: 6, Layout: "ABC DEF", Price: exitRowSeatPrice},
+				{Name: "Standard", CabinClass: models.CabinEconomy, FromRow: 7, ToRow: 22, Layout: "ABC DEF", Price: standardSeatPrice},
+			},
+			ExitRows: []int{6},
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"sync"
+)
+
+// Event is something that happened in the domain, published once it is committed
+type Event interface {
+	EventName() string
+}
+
+// Mode is how a subscriber is called
+type Mode int
+
+const (
+	// Sync subscribers run in the publishing goroutine, in the order they subscribed, before
+	// Publish returns. Use them for work the publisher's caller must see done, like cache invalidation.
+	Sync Mode = iota
+	// Async subscribers run on the bus's workers after Publish returns, for slow side effects
+	// like sending notifications
+	Async
+)
+
+type subscriber struct {
+	name   string
+	mode   Mode
+	handle func(Event) error
+}
+
+type job struct {
+	subscriber subscriber
+	event      Event
+}
+
+// Bus is an in-process publish/subscribe bus. Subscriber errors and panics are logged and never
+// reach the publisher, the event already happened. A nil Bus drops every event.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[reflect.Type][]subscriber
+	queue       chan job
+	workers     int
+}
+
+// NewBus runs async subscribers on workers goroutines once Run is called, queueing up to
+// queueSize events for them. Publish blocks while the queue is full.
+func NewBus(workers, queueSize int) *Bus {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Bus{
+		subscribers: make(map[reflect.Type][]subscriber),
+		queue:       make(chan job, queueSize),
+		workers:     workers,
+	}
+}
+
+// Subscribe calls handler with every event of type E published on bus. name identifies the
+// subscriber in logs.
+func Subscribe[E Event](bus *Bus, name string, mode Mode, handler func(E) error) {
+	eventType := reflect.TypeOf((*E)(nil)).Elem()
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.subscribers[eventType] = append(bus.subscribers[eventType], subscriber{
+		name: name,
+		mode: mode,
+		handle: func(event Event) error {
+			return handler(event.(E))
+		},
+	})
+}
+
+// Publish hands event to its subscribers, running the sync ones straight away and queueing the
+// async ones
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers[reflect.TypeOf(event)]
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		if s.mode == Async {
+			b.queue <- job{subscriber: s, event: event}
+			continue
+		}
+		call(s, event)
+	}
+}
+
+// Run works through the async subscribers' queue until ctx is done
+func (b *Bus) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < b.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case j := <-b.queue:
+					call(j.subscriber, j.event)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// call runs one subscriber, logging rather than passing on its failure
+func call(s subscriber, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event subscriber %s panicked on %s: %v", s.name, event.EventName(), r)
+		}
+	}()
+	if err := s.handle(event); err != nil {
+		log.Printf("Event subscriber %s failed on %s: %v", s.name, event.EventName(), err)
+	}
+}
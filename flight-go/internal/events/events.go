@@ -0,0 +1,71 @@
+package events
+
+import "github.com/mirahekatiket/flight-go/internal/models"
+
+// OrderCreated is published once an order and its passengers are saved. Order events carry a
+// clone of the order the publisher returns to its caller, async subscribers read it while the
+// caller goes on changing its own.
+type OrderCreated struct {
+	Order *models.Order
+}
+
+func (OrderCreated) EventName() string { return "order.created" }
+
+// OrderStatusChanged is published once an order moved from one status to another, by its owner,
+// an admin or a refund after a schedule change
+type OrderStatusChanged struct {
+	Order     *models.Order
+	From      models.OrderStatus
+	ChangedBy string
+	Refund    int64 // Owed back in minor units of the order currency, 0 when none
+}
+
+func (OrderStatusChanged) EventName() string { return "order.status_changed" }
+
+// ScheduleChanged is published once a schedule of Environment was created, updated or deleted.
+// Before is nil for a new schedule and After nil for a deleted one. Each event has its own copies,
+// shared with neither the caller nor the event of the other environment.
+type ScheduleChanged struct {
+	Environment models.Environment
+	Before      *models.Schedule
+	After       *models.Schedule
+}
+
+func (ScheduleChanged) EventName() string { return "schedule.changed" }
+
+// ScheduleDisrupted is published once a material schedule change has been recorded against
+// the orders booked on it
+type ScheduleDisrupted struct {
+	Disruption *models.ScheduleDisruption
+	Schedule   *models.Schedule // As changed
+	Orders     []models.Order
+}
+
+func (ScheduleDisrupted) EventName() string { return "schedule.disrupted" }
+
+// AirlineChanged is published once an airline of Environment was created, updated or deleted
+type AirlineChanged struct {
+	Environment models.Environment
+	AirlineID   string
+}
+
+func (AirlineChanged) EventName() string { return "airline.changed" }
+
+// EmailVerificationRequested is published once a user registered or asked for a new
+// verification email. Token proves the owner of User's email when they send it back.
+type EmailVerificationRequested struct {
+	User  *models.User
+	Token string
+}
+
+func (EmailVerificationRequested) EventName() string { return "user.email_verification_requested" }
+
+// WhitelistChanged is published once a whitelisted user was added, changed or removed. Granted and
+// Revoked are the airline IDs the user gained and lost production access to.
+type WhitelistChanged struct {
+	User    *models.WhitelistedUser
+	Granted []string
+	Revoked []string
+}
+
+func (WhitelistChanged) EventName() string { return "whitelist.changed" }
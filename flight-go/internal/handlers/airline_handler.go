@@ -42,6 +42,14 @@ func (h *AirlineHandler) Create(c *gin.Context) {
 			BadRequestResponse(c, "Airline code already exists")
 			return
 		}
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
+		if err == services.ErrInvalidPassengerRules {
+			BadRequestResponse(c, "Invalid passenger rules: percentages must be 0-100, max_passengers at least 1 and adult_min_age above child_min_age")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to create airline")
 		return
 	}
@@ -105,6 +113,14 @@ func (h *AirlineHandler) Update(c *gin.Context) {
 			BadRequestResponse(c, "Airline code already exists")
 			return
 		}
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
+		if err == services.ErrInvalidPassengerRules {
+			BadRequestResponse(c, "Invalid passenger rules: percentages must be 0-100, max_passengers at least 1 and adult_min_age above child_min_age")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to update airline")
 		return
 	}
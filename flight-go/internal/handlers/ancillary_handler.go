@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type AncillaryHandler struct {
+	ancillaryService services.AncillaryService
+	ancillaryPricing *services.AncillaryPricingService
+}
+
+func NewAncillaryHandler(ancillaryService services.AncillaryService, ancillaryPricing *services.AncillaryPricingService) *AncillaryHandler {
+	return &AncillaryHandler{ancillaryService: ancillaryService, ancillaryPricing: ancillaryPricing}
+}
+
+// FlightOffers godoc
+// @Summary List flight ancillaries
+// @Description List the extra baggage, meals and insurance the flight's airline sells, priced per passenger
+// @Tags Flights
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param currency query string false "Price in this currency, defaults to the schedule's"
+// @Success 200 {object} Response{data=[]models.AncillaryOffer}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /flights/{id}/ancillaries [get]
+func (h *AncillaryHandler) FlightOffers(c *gin.Context) {
+	offers, err := h.ancillaryPricing.FlightOffers(middleware.GetUserEmail(c), c.Param("id"), c.Query("currency"))
+	if err != nil {
+		switch err {
+		case services.ErrScheduleNotFound:
+			NotFoundResponse(c, "Flight not found")
+		case services.ErrUnsupportedCurrency:
+			BadRequestResponse(c, "Unsupported currency")
+		case services.ErrNoExchangeRate:
+			BadRequestResponse(c, "No exchange rate available for the requested currency")
+		default:
+			InternalServerErrorResponse(c, "Failed to list ancillaries")
+		}
+		return
+	}
+
+	SuccessResponse(c, offers)
+}
+
+// Create godoc
+// @Summary Create ancillary
+// @Description Add an extra baggage tier, meal or insurance to an airline's catalogue (admin only)
+// @Tags Admin - Ancillaries
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param request body services.CreateAncillaryRequest true "Ancillary data"
+// @Success 201 {object} Response{data=models.Ancillary}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/ancillaries [post]
+func (h *AncillaryHandler) Create(c *gin.Context) {
+	var req services.CreateAncillaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	ancillary, err := h.ancillaryService.Create(req)
+	if err != nil {
+		ancillaryErrorResponse(c, err, "Failed to create ancillary")
+		return
+	}
+
+	CreatedResponse(c, ancillary)
+}
+
+// GetByID godoc
+// @Summary Get ancillary
+// @Description Get a single catalogue ancillary (admin only)
+// @Tags Admin - Ancillaries
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Ancillary ID"
+// @Success 200 {object} Response{data=models.Ancillary}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/ancillaries/{id} [get]
+func (h *AncillaryHandler) GetByID(c *gin.Context) {
+	ancillary, err := h.ancillaryService.GetByID(c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "Ancillary not found")
+		return
+	}
+
+	SuccessResponse(c, ancillary)
+}
+
+// Update godoc
+// @Summary Update ancillary
+// @Description Update a catalogue ancillary, orders keep the price they were sold at (admin only)
+// @Tags Admin - Ancillaries
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Ancillary ID"
+// @Param request body services.UpdateAncillaryRequest true "Ancillary data"
+// @Success 200 {object} Response{data=models.Ancillary}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/ancillaries/{id} [put]
+func (h *AncillaryHandler) Update(c *gin.Context) {
+	var req services.UpdateAncillaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	ancillary, err := h.ancillaryService.Update(c.Param("id"), req)
+	if err != nil {
+		ancillaryErrorResponse(c, err, "Failed to update ancillary")
+		return
+	}
+
+	SuccessResponse(c, ancillary)
+}
+
+// Delete godoc
+// @Summary Delete ancillary
+// @Description Remove an ancillary from the catalogue, orders that bought it keep it (admin only)
+// @Tags Admin - Ancillaries
+// @Security BearerAuth
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Ancillary ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/ancillaries/{id} [delete]
+func (h *AncillaryHandler) Delete(c *gin.Context) {
+	if err := h.ancillaryService.Delete(c.Param("id")); err != nil {
+		if err == services.ErrAncillaryNotFound {
+			NotFoundResponse(c, "Ancillary not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete ancillary")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Ancillary deleted successfully"})
+}
+
+// List godoc
+// @Summary List ancillaries
+// @Description Get a paginated list of catalogue ancillaries (admin only)
+// @Tags Admin - Ancillaries
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param airline_id query string false "Filter by airline ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/ancillaries [get]
+func (h *AncillaryHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.ancillaryService.List(c.Query("airline_id"), page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list ancillaries")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// ancillaryErrorResponse responds to a failed ancillary create or update
+func ancillaryErrorResponse(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrAncillaryNotFound:
+		NotFoundResponse(c, "Ancillary not found")
+	case services.ErrInvalidAncillary:
+		BadRequestResponse(c, "Invalid type, price, currency or weight (baggage only)")
+	case services.ErrAncillaryCodeExists:
+		BadRequestResponse(c, "Ancillary code already exists for this airline")
+	default:
+		InternalServerErrorResponse(c, message)
+	}
+}
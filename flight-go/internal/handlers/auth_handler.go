@@ -55,6 +55,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Success 200 {object} Response{data=TokenResponse}
 // @Failure 400 {object} ErrorMessageResponse
 // @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req services.LoginRequest
@@ -69,6 +70,10 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			UnauthorizedResponse(c, "Invalid email or password")
 			return
 		}
+		if err == services.ErrUserDisabled {
+			ForbiddenResponse(c, "Account is disabled")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to login")
 		return
 	}
@@ -76,6 +81,168 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	SuccessResponse(c, response)
 }
 
+// Refresh godoc
+// @Summary Refresh tokens
+// @Description Trade a refresh token for a new access and refresh token pair. Each refresh token works once, reusing one revokes its session.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} Response{data=TokenResponse}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req services.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	response, err := h.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		if err == services.ErrInvalidRefreshToken {
+			UnauthorizedResponse(c, "Invalid or expired refresh token")
+			return
+		}
+		if err == services.ErrUserDisabled {
+			ForbiddenResponse(c, "Account is disabled")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to refresh token")
+		return
+	}
+
+	SuccessResponse(c, response)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email
+// @Description Confirm the user owns their email with the token from the verification email. Guest orders booked with the email move to the account.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} Response{data=VerifyEmailResponse}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req services.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	response, err := h.authService.VerifyEmail(req.Token)
+	if err != nil {
+		if err == services.ErrInvalidVerificationToken {
+			BadRequestResponse(c, "Invalid or expired verification token")
+			return
+		}
+		if err == services.ErrUserDisabled {
+			ForbiddenResponse(c, "Account is disabled")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to verify email")
+		return
+	}
+
+	SuccessResponse(c, response)
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send the current user a new email verification token
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 429 {object} ErrorMessageResponse
+// @Router /auth/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	if err := h.authService.RequestEmailVerification(middleware.GetUserID(c)); err != nil {
+		if err == services.ErrEmailAlreadyVerified {
+			BadRequestResponse(c, "Email is already verified")
+			return
+		}
+		if err == services.ErrUserNotFound {
+			NotFoundResponse(c, "User not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to send verification email")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Verification email sent"})
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revoke the current session, its refresh token and access tokens
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(middleware.GetSessionID(c)); err != nil {
+		InternalServerErrorResponse(c, "Failed to logout")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Logged out"})
+}
+
+// LogoutAll godoc
+// @Summary Logout everywhere
+// @Description Revoke every session of the current user
+// @Tags Auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	if err := h.authService.RevokeAllSessions(middleware.GetUserID(c)); err != nil {
+		InternalServerErrorResponse(c, "Failed to logout")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Logged out of all sessions"})
+}
+
+// RevokeUserSessions godoc
+// @Summary Revoke a user's sessions
+// @Description Log a user out everywhere, their access tokens stop working straight away
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/users/{id}/revoke-sessions [post]
+func (h *AuthHandler) RevokeUserSessions(c *gin.Context) {
+	if err := h.authService.RevokeAllSessions(c.Param("id")); err != nil {
+		if err == services.ErrUserNotFound {
+			NotFoundResponse(c, "User not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to revoke sessions")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Sessions revoked"})
+}
+
 // Me godoc
 // @Summary Get current user
 // @Description Get the currently authenticated user's information
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type CacheHandler struct {
+	searchCache *services.SearchCache
+}
+
+func NewCacheHandler(searchCache *services.SearchCache) *CacheHandler {
+	return &CacheHandler{searchCache: searchCache}
+}
+
+// SearchStats godoc
+// @Summary Get search cache stats
+// @Description Get hit/miss statistics of the flight search cache (admin only)
+// @Tags Admin - Cache
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} Response{data=services.SearchCacheStats}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Router /admin/cache/search [get]
+func (h *CacheHandler) SearchStats(c *gin.Context) {
+	SuccessResponse(c, h.searchCache.Stats())
+}
+
+// FlushSearch godoc
+// @Summary Flush search cache
+// @Description Drop every cached flight search result (admin only)
+// @Tags Admin - Cache
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Router /admin/cache/search [delete]
+func (h *CacheHandler) FlushSearch(c *gin.Context) {
+	h.searchCache.Flush()
+	SuccessResponse(c, gin.H{"message": "Search cache flushed successfully"})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type DisruptionHandler struct {
+	disruptions *services.DisruptionService
+}
+
+func NewDisruptionHandler(disruptions *services.DisruptionService) *DisruptionHandler {
+	return &DisruptionHandler{disruptions: disruptions}
+}
+
+// ListBySchedule godoc
+// @Summary List schedule disruptions
+// @Description List the material changes made to a schedule, latest first, with how many orders each affected
+// @Tags Schedules
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param env query string false "Environment (staging or production)" default(staging)
+// @Success 200 {object} Response{data=[]ScheduleDisruption}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/schedules/{id}/disruptions [get]
+func (h *DisruptionHandler) ListBySchedule(c *gin.Context) {
+	disruptions, err := h.disruptions.ListBySchedule(models.Environment(getEnv(c)), c.Param("id"))
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list schedule disruptions")
+		return
+	}
+
+	SuccessResponse(c, disruptions)
+}
@@ -10,6 +10,14 @@ type EnvAwareHandler struct {
 	productionAirlineHandler *AirlineHandler
 	stagingScheduleHandler *ScheduleHandler
 	productionScheduleHandler *ScheduleHandler
+	stagingFareRuleHandler *FareRuleHandler
+	productionFareRuleHandler *FareRuleHandler
+	stagingFareFamilyHandler *FareFamilyHandler
+	productionFareFamilyHandler *FareFamilyHandler
+	stagingFeeRuleHandler *FeeRuleHandler
+	productionFeeRuleHandler *FeeRuleHandler
+	stagingAncillaryHandler *AncillaryHandler
+	productionAncillaryHandler *AncillaryHandler
 }
 
 func NewEnvAwareHandler(
@@ -17,12 +25,28 @@ func NewEnvAwareHandler(
 	productionAirlineHandler *AirlineHandler,
 	stagingScheduleHandler *ScheduleHandler,
 	productionScheduleHandler *ScheduleHandler,
+	stagingFareRuleHandler *FareRuleHandler,
+	productionFareRuleHandler *FareRuleHandler,
+	stagingFareFamilyHandler *FareFamilyHandler,
+	productionFareFamilyHandler *FareFamilyHandler,
+	stagingFeeRuleHandler *FeeRuleHandler,
+	productionFeeRuleHandler *FeeRuleHandler,
+	stagingAncillaryHandler *AncillaryHandler,
+	productionAncillaryHandler *AncillaryHandler,
 ) *EnvAwareHandler {
 	return &EnvAwareHandler{
 		stagingAirlineHandler:     stagingAirlineHandler,
 		productionAirlineHandler:  productionAirlineHandler,
 		stagingScheduleHandler:    stagingScheduleHandler,
 		productionScheduleHandler: productionScheduleHandler,
+		stagingFareRuleHandler:    stagingFareRuleHandler,
+		productionFareRuleHandler: productionFareRuleHandler,
+		stagingFareFamilyHandler:    stagingFareFamilyHandler,
+		productionFareFamilyHandler: productionFareFamilyHandler,
+		stagingFeeRuleHandler:       stagingFeeRuleHandler,
+		productionFeeRuleHandler:    productionFeeRuleHandler,
+		stagingAncillaryHandler:     stagingAncillaryHandler,
+		productionAncillaryHandler:  productionAncillaryHandler,
 	}
 }
 
@@ -135,3 +159,203 @@ func (h *EnvAwareHandler) DeleteSchedule(c *gin.Context) {
 	}
 }
 
+
+// Fare rules - Environment-aware fare rule list
+func (h *EnvAwareHandler) ListFareRules(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareRuleHandler.List(c)
+	} else {
+		h.stagingFareRuleHandler.List(c)
+	}
+}
+
+// Fare rules - Environment-aware fare rule create
+func (h *EnvAwareHandler) CreateFareRule(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareRuleHandler.Create(c)
+	} else {
+		h.stagingFareRuleHandler.Create(c)
+	}
+}
+
+// Fare rules - Environment-aware fare rule get by ID
+func (h *EnvAwareHandler) GetFareRuleByID(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareRuleHandler.GetByID(c)
+	} else {
+		h.stagingFareRuleHandler.GetByID(c)
+	}
+}
+
+// Fare rules - Environment-aware fare rule update
+func (h *EnvAwareHandler) UpdateFareRule(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareRuleHandler.Update(c)
+	} else {
+		h.stagingFareRuleHandler.Update(c)
+	}
+}
+
+// Fare rules - Environment-aware fare rule delete
+func (h *EnvAwareHandler) DeleteFareRule(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareRuleHandler.Delete(c)
+	} else {
+		h.stagingFareRuleHandler.Delete(c)
+	}
+}
+
+// Fare families - Environment-aware fare family list
+func (h *EnvAwareHandler) ListFareFamilies(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareFamilyHandler.List(c)
+	} else {
+		h.stagingFareFamilyHandler.List(c)
+	}
+}
+
+// Fare families - Environment-aware fare family create
+func (h *EnvAwareHandler) CreateFareFamily(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareFamilyHandler.Create(c)
+	} else {
+		h.stagingFareFamilyHandler.Create(c)
+	}
+}
+
+// Fare families - Environment-aware fare family get by ID
+func (h *EnvAwareHandler) GetFareFamilyByID(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareFamilyHandler.GetByID(c)
+	} else {
+		h.stagingFareFamilyHandler.GetByID(c)
+	}
+}
+
+// Fare families - Environment-aware fare family update
+func (h *EnvAwareHandler) UpdateFareFamily(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareFamilyHandler.Update(c)
+	} else {
+		h.stagingFareFamilyHandler.Update(c)
+	}
+}
+
+// Fare families - Environment-aware fare family delete
+func (h *EnvAwareHandler) DeleteFareFamily(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFareFamilyHandler.Delete(c)
+	} else {
+		h.stagingFareFamilyHandler.Delete(c)
+	}
+}
+
+// Fee rules - Environment-aware fee rule list
+func (h *EnvAwareHandler) ListFeeRules(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFeeRuleHandler.List(c)
+	} else {
+		h.stagingFeeRuleHandler.List(c)
+	}
+}
+
+// Fee rules - Environment-aware fee rule create
+func (h *EnvAwareHandler) CreateFeeRule(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFeeRuleHandler.Create(c)
+	} else {
+		h.stagingFeeRuleHandler.Create(c)
+	}
+}
+
+// Fee rules - Environment-aware fee rule get by ID
+func (h *EnvAwareHandler) GetFeeRuleByID(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFeeRuleHandler.GetByID(c)
+	} else {
+		h.stagingFeeRuleHandler.GetByID(c)
+	}
+}
+
+// Fee rules - Environment-aware fee rule update
+func (h *EnvAwareHandler) UpdateFeeRule(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFeeRuleHandler.Update(c)
+	} else {
+		h.stagingFeeRuleHandler.Update(c)
+	}
+}
+
+// Fee rules - Environment-aware fee rule delete
+func (h *EnvAwareHandler) DeleteFeeRule(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionFeeRuleHandler.Delete(c)
+	} else {
+		h.stagingFeeRuleHandler.Delete(c)
+	}
+}
+
+// Ancillaries - Environment-aware ancillary list
+func (h *EnvAwareHandler) ListAncillaries(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionAncillaryHandler.List(c)
+	} else {
+		h.stagingAncillaryHandler.List(c)
+	}
+}
+
+// Ancillaries - Environment-aware ancillary create
+func (h *EnvAwareHandler) CreateAncillary(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionAncillaryHandler.Create(c)
+	} else {
+		h.stagingAncillaryHandler.Create(c)
+	}
+}
+
+// Ancillaries - Environment-aware ancillary get by ID
+func (h *EnvAwareHandler) GetAncillaryByID(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionAncillaryHandler.GetByID(c)
+	} else {
+		h.stagingAncillaryHandler.GetByID(c)
+	}
+}
+
+// Ancillaries - Environment-aware ancillary update
+func (h *EnvAwareHandler) UpdateAncillary(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionAncillaryHandler.Update(c)
+	} else {
+		h.stagingAncillaryHandler.Update(c)
+	}
+}
+
+// Ancillaries - Environment-aware ancillary delete
+func (h *EnvAwareHandler) DeleteAncillary(c *gin.Context) {
+	env := getEnv(c)
+	if env == "production" {
+		h.productionAncillaryHandler.Delete(c)
+	} else {
+		h.stagingAncillaryHandler.Delete(c)
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService services.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateService services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{exchangeRateService: exchangeRateService}
+}
+
+// Create godoc
+// @Summary Create exchange rate
+// @Description Add an exchange rate for a currency pair from its effective date (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateExchangeRateRequest true "Exchange rate data"
+// @Success 201 {object} Response{data=models.ExchangeRate}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/exchange-rates [post]
+func (h *ExchangeRateHandler) Create(c *gin.Context) {
+	var req services.CreateExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	rate, err := h.exchangeRateService.Create(req)
+	if err != nil {
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
+		if err == services.ErrInvalidExchangeRate {
+			BadRequestResponse(c, "Rate must be positive between two different currencies, effective_from must be RFC3339 or YYYY-MM-DD")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to create exchange rate")
+		return
+	}
+
+	CreatedResponse(c, rate)
+}
+
+// GetByID godoc
+// @Summary Get exchange rate
+// @Description Get a single exchange rate (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Exchange rate ID"
+// @Success 200 {object} Response{data=models.ExchangeRate}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/exchange-rates/{id} [get]
+func (h *ExchangeRateHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	rate, err := h.exchangeRateService.GetByID(id)
+	if err != nil {
+		NotFoundResponse(c, "Exchange rate not found")
+		return
+	}
+
+	SuccessResponse(c, rate)
+}
+
+// Delete godoc
+// @Summary Delete exchange rate
+// @Description Delete an exchange rate, the pair falls back to its previous rate (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Param id path string true "Exchange rate ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/exchange-rates/{id} [delete]
+func (h *ExchangeRateHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.exchangeRateService.Delete(id); err != nil {
+		if err == services.ErrExchangeRateNotFound {
+			NotFoundResponse(c, "Exchange rate not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete exchange rate")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Exchange rate deleted successfully"})
+}
+
+// List godoc
+// @Summary List exchange rates
+// @Description Get a paginated list of exchange rates, latest first per pair (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Filter by source currency"
+// @Param to query string false "Filter by target currency"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/exchange-rates [get]
+func (h *ExchangeRateHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.exchangeRateService.List(c.Query("from"), c.Query("to"), page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list exchange rates")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
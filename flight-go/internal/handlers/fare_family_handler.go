@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type FareFamilyHandler struct {
+	fareFamilyService services.FareFamilyService
+}
+
+func NewFareFamilyHandler(fareFamilyService services.FareFamilyService) *FareFamilyHandler {
+	return &FareFamilyHandler{fareFamilyService: fareFamilyService}
+}
+
+// Create godoc
+// @Summary Create fare family
+// @Description Create a fare family within an airline cabin (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param request body services.CreateFareFamilyRequest true "Fare family data"
+// @Success 201 {object} Response{data=models.FareFamily}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-families [post]
+func (h *FareFamilyHandler) Create(c *gin.Context) {
+	var req services.CreateFareFamilyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	family, err := h.fareFamilyService.Create(req)
+	if err != nil {
+		if err == services.ErrInvalidFareFamily {
+			BadRequestResponse(c, "Invalid cabin class, price multiplier, seat bucket or passenger percentages")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to create fare family")
+		return
+	}
+
+	CreatedResponse(c, family)
+}
+
+// GetByID godoc
+// @Summary Get fare family
+// @Description Get a single fare family (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fare family ID"
+// @Success 200 {object} Response{data=models.FareFamily}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/fare-families/{id} [get]
+func (h *FareFamilyHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	family, err := h.fareFamilyService.GetByID(id)
+	if err != nil {
+		NotFoundResponse(c, "Fare family not found")
+		return
+	}
+
+	SuccessResponse(c, family)
+}
+
+// Update godoc
+// @Summary Update fare family
+// @Description Update a fare family (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fare family ID"
+// @Param request body services.UpdateFareFamilyRequest true "Fare family data"
+// @Success 200 {object} Response{data=models.FareFamily}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-families/{id} [put]
+func (h *FareFamilyHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req services.UpdateFareFamilyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	family, err := h.fareFamilyService.Update(id, req)
+	if err != nil {
+		if err == services.ErrFareFamilyNotFound {
+			NotFoundResponse(c, "Fare family not found")
+			return
+		}
+		if err == services.ErrInvalidFareFamily {
+			BadRequestResponse(c, "Invalid cabin class, price multiplier, seat bucket or passenger percentages")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to update fare family")
+		return
+	}
+
+	SuccessResponse(c, family)
+}
+
+// Delete godoc
+// @Summary Delete fare family
+// @Description Delete a fare family (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fare family ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-families/{id} [delete]
+func (h *FareFamilyHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.fareFamilyService.Delete(id); err != nil {
+		if err == services.ErrFareFamilyNotFound {
+			NotFoundResponse(c, "Fare family not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete fare family")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Fare family deleted successfully"})
+}
+
+// List godoc
+// @Summary List fare families
+// @Description Get a paginated list of fare families (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param airline_id query string false "Filter by airline ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-families [get]
+func (h *FareFamilyHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	airlineID := c.Query("airline_id")
+
+	result, err := h.fareFamilyService.List(airlineID, page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list fare families")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
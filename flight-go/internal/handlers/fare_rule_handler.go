@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type FareRuleHandler struct {
+	fareRuleService services.FareRuleService
+}
+
+func NewFareRuleHandler(fareRuleService services.FareRuleService) *FareRuleHandler {
+	return &FareRuleHandler{fareRuleService: fareRuleService}
+}
+
+// Create godoc
+// @Summary Create fare rule
+// @Description Create a dynamic pricing rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param request body services.CreateFareRuleRequest true "Fare rule data"
+// @Success 201 {object} Response{data=models.FareRule}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-rules [post]
+func (h *FareRuleHandler) Create(c *gin.Context) {
+	var req services.CreateFareRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	rule, err := h.fareRuleService.Create(req)
+	if err != nil {
+		if err == services.ErrInvalidFareRule {
+			BadRequestResponse(c, "Invalid fare rule conditions for its type")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to create fare rule")
+		return
+	}
+
+	CreatedResponse(c, rule)
+}
+
+// GetByID godoc
+// @Summary Get fare rule
+// @Description Get a single dynamic pricing rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fare rule ID"
+// @Success 200 {object} Response{data=models.FareRule}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/fare-rules/{id} [get]
+func (h *FareRuleHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	rule, err := h.fareRuleService.GetByID(id)
+	if err != nil {
+		NotFoundResponse(c, "Fare rule not found")
+		return
+	}
+
+	SuccessResponse(c, rule)
+}
+
+// Update godoc
+// @Summary Update fare rule
+// @Description Update a dynamic pricing rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fare rule ID"
+// @Param request body services.UpdateFareRuleRequest true "Fare rule data"
+// @Success 200 {object} Response{data=models.FareRule}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-rules/{id} [put]
+func (h *FareRuleHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req services.UpdateFareRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	rule, err := h.fareRuleService.Update(id, req)
+	if err != nil {
+		if err == services.ErrFareRuleNotFound {
+			NotFoundResponse(c, "Fare rule not found")
+			return
+		}
+		if err == services.ErrInvalidFareRule {
+			BadRequestResponse(c, "Invalid fare rule conditions for its type")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to update fare rule")
+		return
+	}
+
+	SuccessResponse(c, rule)
+}
+
+// Delete godoc
+// @Summary Delete fare rule
+// @Description Delete a dynamic pricing rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fare rule ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-rules/{id} [delete]
+func (h *FareRuleHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.fareRuleService.Delete(id); err != nil {
+		if err == services.ErrFareRuleNotFound {
+			NotFoundResponse(c, "Fare rule not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete fare rule")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Fare rule deleted successfully"})
+}
+
+// List godoc
+// @Summary List fare rules
+// @Description Get a paginated list of dynamic pricing rules in priority order (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fare-rules [get]
+func (h *FareRuleHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.fareRuleService.List(page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list fare rules")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
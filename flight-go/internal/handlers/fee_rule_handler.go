@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type FeeRuleHandler struct {
+	feeRuleService services.FeeRuleService
+}
+
+func NewFeeRuleHandler(feeRuleService services.FeeRuleService) *FeeRuleHandler {
+	return &FeeRuleHandler{feeRuleService: feeRuleService}
+}
+
+// Create godoc
+// @Summary Create fee rule
+// @Description Create a tax, fee or surcharge rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param request body services.CreateFeeRuleRequest true "Fee rule data"
+// @Success 201 {object} Response{data=models.FeeRule}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fee-rules [post]
+func (h *FeeRuleHandler) Create(c *gin.Context) {
+	var req services.CreateFeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	rule, err := h.feeRuleService.Create(req)
+	if err != nil {
+		if err == services.ErrInvalidFeeRule {
+			BadRequestResponse(c, "Invalid fee rule amount, currency or cabin for its type")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to create fee rule")
+		return
+	}
+
+	CreatedResponse(c, rule)
+}
+
+// GetByID godoc
+// @Summary Get fee rule
+// @Description Get a single tax, fee or surcharge rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fee rule ID"
+// @Success 200 {object} Response{data=models.FeeRule}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/fee-rules/{id} [get]
+func (h *FeeRuleHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	rule, err := h.feeRuleService.GetByID(id)
+	if err != nil {
+		NotFoundResponse(c, "Fee rule not found")
+		return
+	}
+
+	SuccessResponse(c, rule)
+}
+
+// Update godoc
+// @Summary Update fee rule
+// @Description Update a tax, fee or surcharge rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fee rule ID"
+// @Param request body services.UpdateFeeRuleRequest true "Fee rule data"
+// @Success 200 {object} Response{data=models.FeeRule}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fee-rules/{id} [put]
+func (h *FeeRuleHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req services.UpdateFeeRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	rule, err := h.feeRuleService.Update(id, req)
+	if err != nil {
+		if err == services.ErrFeeRuleNotFound {
+			NotFoundResponse(c, "Fee rule not found")
+			return
+		}
+		if err == services.ErrInvalidFeeRule {
+			BadRequestResponse(c, "Invalid fee rule amount, currency or cabin for its type")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to update fee rule")
+		return
+	}
+
+	SuccessResponse(c, rule)
+}
+
+// Delete godoc
+// @Summary Delete fee rule
+// @Description Delete a tax, fee or surcharge rule (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param id path string true "Fee rule ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fee-rules/{id} [delete]
+func (h *FeeRuleHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.feeRuleService.Delete(id); err != nil {
+		if err == services.ErrFeeRuleNotFound {
+			NotFoundResponse(c, "Fee rule not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete fee rule")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Fee rule deleted successfully"})
+}
+
+// List godoc
+// @Summary List fee rules
+// @Description Get a paginated list of tax, fee and surcharge rules (admin only)
+// @Tags Admin - Pricing
+// @Security BearerAuth
+// @Produce json
+// @Param env query string false "Environment (staging, production)" default(staging)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/fee-rules [get]
+func (h *FeeRuleHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.feeRuleService.List(page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list fee rules")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+// GuestOrderHandler serves checkout for buyers without an account.
+// Guests manage their order with the booking token returned when it was created.
+type GuestOrderHandler struct {
+	orderService  services.OrderService
+	bookingTokens *services.BookingTokenService
+}
+
+func NewGuestOrderHandler(orderService services.OrderService, bookingTokens *services.BookingTokenService) *GuestOrderHandler {
+	return &GuestOrderHandler{orderService: orderService, bookingTokens: bookingTokens}
+}
+
+// Create godoc
+// @Summary Create guest order
+// @Description Book a flight without an account. The response carries a booking token for managing the order, which also moves the order to an account later.
+// @Tags Guest Orders
+// @Accept json
+// @Produce json
+// @Param request body CreateOrderRequest true "Order data"
+// @Param Idempotency-Key header string false "Retry safely, a repeated key replays the first response"
+// @Success 201 {object} Response{data=GuestOrderResponse}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 409 {object} ErrorMessageResponse
+// @Failure 429 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders [post]
+func (h *GuestOrderHandler) Create(c *gin.Context) {
+	var req services.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, err := h.orderService.Create("", "", req)
+	if err != nil {
+		createOrderErrorResponse(c, err)
+		return
+	}
+
+	token, err := h.bookingTokens.Issue(order)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to issue booking token")
+		return
+	}
+
+	order.MaskDocuments()
+	CreatedResponse(c, gin.H{"order": order, "booking_token": token})
+}
+
+// GetByID godoc
+// @Summary Get guest order
+// @Description Get a guest order with its booking token
+// @Tags Guest Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Success 200 {object} Response{data=Order}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /guest/orders/{id} [get]
+func (h *GuestOrderHandler) GetByID(c *gin.Context) {
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	order.MaskDocuments()
+	SuccessResponse(c, order)
+}
+
+// Itinerary godoc
+// @Summary Download guest order itinerary
+// @Description Render the itinerary and e-ticket receipt of a guest order as PDF or plain text
+// @Tags Guest Orders
+// @Produce application/pdf
+// @Produce plain
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Param format query string false "pdf or text" default(pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/itinerary [get]
+func (h *GuestOrderHandler) Itinerary(c *gin.Context) {
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	writeItinerary(c, order)
+}
+
+// Cancel godoc
+// @Summary Cancel guest order
+// @Description Cancel a guest order with its booking token
+// @Tags Guest Orders
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Param Idempotency-Key header string false "Retry safely, a repeated key replays the first response"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 409 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/cancel [post]
+func (h *GuestOrderHandler) Cancel(c *gin.Context) {
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	if err := h.orderService.Cancel(order.ID, ""); err != nil {
+		if err == services.ErrOrderNotCancellable {
+			BadRequestResponse(c, "Only pending or confirmed orders can be cancelled")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to cancel order")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Order cancelled successfully"})
+}
+
+// Claim godoc
+// @Summary Claim guest order
+// @Description Move a guest order to the signed-in user's account with its booking token
+// @Tags Guest Orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Success 200 {object} Response{data=Order}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/claim [post]
+func (h *GuestOrderHandler) Claim(c *gin.Context) {
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	claimed, err := h.orderService.Claim(order.ID, middleware.GetUserID(c))
+	if err != nil {
+		claimOrderErrorResponse(c, err)
+		return
+	}
+
+	claimed.MaskDocuments()
+	SuccessResponse(c, claimed)
+}
+
+// AddAncillaries godoc
+// @Summary Add ancillaries to guest order
+// @Description Buy extra baggage, meals or insurance for a guest order's passengers with its booking token, until the cut-off before departure
+// @Tags Guest Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Param request body services.AddAncillariesRequest true "Ancillaries per passenger"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/ancillaries [post]
+func (h *GuestOrderHandler) AddAncillaries(c *gin.Context) {
+	var req services.AddAncillariesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.AddAncillaries(order.ID, "", req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	order.MaskDocuments()
+	SuccessResponse(c, order)
+}
+
+// AcceptDisruption godoc
+// @Summary Accept schedule change on guest order
+// @Description Keep a guest order on its flight after the airline retimed it or changed the aircraft, with its booking token
+// @Tags Guest Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/disruption/accept [post]
+func (h *GuestOrderHandler) AcceptDisruption(c *gin.Context) {
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.AcceptDisruption(order.ID, "")
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	order.MaskDocuments()
+	SuccessResponse(c, order)
+}
+
+// RefundDisruption godoc
+// @Summary Refund disrupted guest order
+// @Description Cancel a guest order whose flight the airline changed or cancelled, with the full amount paid owed back
+// @Tags Guest Orders
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Param Idempotency-Key header string false "Retry safely, a repeated key replays the first response"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 409 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/disruption/refund [post]
+func (h *GuestOrderHandler) RefundDisruption(c *gin.Context) {
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.RefundDisruption(order.ID, "")
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	order.MaskDocuments()
+	SuccessResponse(c, order)
+}
+
+// Rebook godoc
+// @Summary Rebook disrupted guest order
+// @Description Move a guest order whose flight the airline changed or cancelled to another date or schedule of the same airline on the same route, free of charge
+// @Tags Guest Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param X-Booking-Token header string false "Booking token, or pass it as the token query parameter"
+// @Param token query string false "Booking token"
+// @Param request body services.ChangeFlightRequest true "New flight"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /guest/orders/{id}/disruption/rebook [post]
+func (h *GuestOrderHandler) Rebook(c *gin.Context) {
+	var req services.ChangeFlightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.authorizedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.Rebook(order.ID, "", req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	order.MaskDocuments()
+	SuccessResponse(c, order)
+}
+
+// authorizedOrder loads the order of the id path parameter if the request's booking token
+// was issued for it, writing the error response if not
+func (h *GuestOrderHandler) authorizedOrder(c *gin.Context) (*models.Order, bool) {
+	token := c.GetHeader("X-Booking-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token == "" {
+		UnauthorizedResponse(c, "Booking token required")
+		return nil, false
+	}
+
+	orderID, err := h.bookingTokens.Validate(token)
+	if err != nil || orderID != c.Param("id") {
+		UnauthorizedResponse(c, "Invalid booking token")
+		return nil, false
+	}
+
+	order, err := h.orderService.GetByID(orderID)
+	if err != nil {
+		NotFoundResponse(c, "Order not found")
+		return nil, false
+	}
+
+	return order, true
+}
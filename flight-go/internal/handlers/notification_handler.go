@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// List godoc
+// @Summary List notifications
+// @Description Get a paginated list of the current user's notifications, newest first
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Param unread query bool false "Only notifications not read yet"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /me/notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	unreadOnly, _ := strconv.ParseBool(c.Query("unread"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.notificationService.List(userID, unreadOnly, page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list notifications")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// MarkRead godoc
+// @Summary Mark notification read
+// @Description Mark one of the current user's notifications as read
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} Response{data=Notification}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	notification, err := h.notificationService.MarkRead(userID, c.Param("id"))
+	if err != nil {
+		if err == services.ErrNotificationNotFound {
+			NotFoundResponse(c, "Notification not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to mark notification read")
+		return
+	}
+
+	SuccessResponse(c, notification)
+}
+
+// ListDeliveries godoc
+// @Summary List notification deliveries
+// @Description Get a paginated log of notification deliveries across every channel, newest first (admin only)
+// @Tags Notifications
+// @Security BearerAuth
+// @Produce json
+// @Param recipient query string false "Recipient email"
+// @Param status query string false "Delivery status (pending, sent, failed)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/notifications/deliveries [get]
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.notificationService.ListDeliveries(c.Query("recipient"), models.DeliveryStatus(c.Query("status")), page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list notification deliveries")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
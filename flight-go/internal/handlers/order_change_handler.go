@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+// QuoteFlightChange godoc
+// @Summary Quote flight change
+// @Description Price moving an order to another date or another schedule of the same airline on the same route, without changing it
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.ChangeFlightRequest true "New flight"
+// @Success 200 {object} Response{data=ChangeQuote}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /orders/{id}/change-flight/quote [post]
+func (h *OrderHandler) QuoteFlightChange(c *gin.Context) {
+	var req services.ChangeFlightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.orderService.QuoteFlightChange(order.ID, req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	SuccessResponse(c, quote)
+}
+
+// ChangeFlight godoc
+// @Summary Change flight
+// @Description Move an order to another date or another schedule of the same airline on the same route. The order is repriced, charged the fare family's change fee and its cabin seats move in one step. Selected seats are given up.
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.ChangeFlightRequest true "New flight"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/change-flight [post]
+func (h *OrderHandler) ChangeFlight(c *gin.Context) {
+	var req services.ChangeFlightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.ChangeFlight(order.ID, middleware.GetUserID(c), req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// QuoteNameCorrection godoc
+// @Summary Quote name correction
+// @Description Check a passenger name correction against the character limit and price it, without changing the order
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.CorrectNameRequest true "Corrected name"
+// @Success 200 {object} Response{data=ChangeQuote}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /orders/{id}/name-correction/quote [post]
+func (h *OrderHandler) QuoteNameCorrection(c *gin.Context) {
+	var req services.CorrectNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	quote, err := h.orderService.QuoteNameCorrection(order.ID, req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	SuccessResponse(c, quote)
+}
+
+// CorrectName godoc
+// @Summary Correct passenger name
+// @Description Correct a misspelt passenger name, changing at most 3 characters
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.CorrectNameRequest true "Corrected name"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/name-correction [post]
+func (h *OrderHandler) CorrectName(c *gin.Context) {
+	var req services.CorrectNameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.CorrectName(order.ID, middleware.GetUserID(c), req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// SelectSeats godoc
+// @Summary Select seats
+// @Description Pick, change or give up (empty seat) the seats of an order's passengers. The order is repriced with each seat zone's price.
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.SelectSeatsRequest true "Seats per passenger"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/seats [post]
+func (h *OrderHandler) SelectSeats(c *gin.Context) {
+	var req services.SelectSeatsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.SelectSeats(order.ID, middleware.GetUserID(c), req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// AddAncillaries godoc
+// @Summary Add ancillaries
+// @Description Buy extra baggage, meals or insurance for an order's passengers, until the cut-off before departure. The order is repriced with each ancillary's price.
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.AddAncillariesRequest true "Ancillaries per passenger"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/ancillaries [post]
+func (h *OrderHandler) AddAncillaries(c *gin.Context) {
+	var req services.AddAncillariesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.AddAncillaries(order.ID, middleware.GetUserID(c), req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// History godoc
+// @Summary Get order history
+// @Description List every change made to an order, oldest first
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} Response{data=[]OrderChange}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/history [get]
+func (h *OrderHandler) History(c *gin.Context) {
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	changes, err := h.orderService.History(order.ID)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to load order history")
+		return
+	}
+
+	SuccessResponse(c, changes)
+}
+
+// ownedOrder loads the order of the id path parameter if the user owns it or is an admin,
+// writing the error response if not
+func (h *OrderHandler) ownedOrder(c *gin.Context) (*models.Order, bool) {
+	order, err := h.orderService.GetByID(c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "Order not found")
+		return nil, false
+	}
+
+	if order.UserID != middleware.GetUserID(c) && middleware.GetUserRole(c) != models.RoleAdmin {
+		ForbiddenResponse(c, "Access denied")
+		return nil, false
+	}
+
+	return order, true
+}
+
+// changeOrderErrorResponse responds to a failed order change or change quote
+func changeOrderErrorResponse(c *gin.Context, err error) {
+	var validationErr *services.PassengerValidationError
+	if errors.As(err, &validationErr) {
+		ValidationErrorResponse(c, "Seats or ancillaries are not available", validationErr.Errors)
+		return
+	}
+
+	switch err {
+	case services.ErrOrderNotFound:
+		NotFoundResponse(c, "Order not found")
+	case services.ErrPassengerNotFound:
+		NotFoundResponse(c, "Passenger not found")
+	case services.ErrOrderNotChangeable:
+		BadRequestResponse(c, "Only pending or confirmed orders whose flight has not left can be changed")
+	case services.ErrFareNotChangeable:
+		BadRequestResponse(c, "The fare family does not allow changes")
+	case services.ErrInvalidFlightChange:
+		BadRequestResponse(c, "The new flight must be another date or schedule of the same airline on the same route")
+	case services.ErrScheduleNotFound:
+		BadRequestResponse(c, "Flight schedule not found")
+	case services.ErrInvalidFlightDate:
+		BadRequestResponse(c, "Invalid flight date")
+	case services.ErrFareFamilyNotFound:
+		BadRequestResponse(c, "Fare family no longer available")
+	case services.ErrFareFamilySoldOut:
+		BadRequestResponse(c, "Fare family sold out")
+	case services.ErrInsufficientSeats:
+		BadRequestResponse(c, "Not enough seats left in cabin")
+	case services.ErrNoExchangeRate:
+		BadRequestResponse(c, "No exchange rate available for the order's currency")
+	case services.ErrInvalidNameCorrection:
+		BadRequestResponse(c, "The corrected name must differ from the current one")
+	case services.ErrSeatMapNotFound:
+		BadRequestResponse(c, "Seat selection is not available on this aircraft")
+	case services.ErrSeatTaken:
+		BadRequestResponse(c, "A selected seat was just taken, please pick another")
+	case services.ErrAncillarySalesClosed:
+		BadRequestResponse(c, "Ancillary sales for this flight have closed")
+	case services.ErrNoDisruption:
+		BadRequestResponse(c, "The order has no schedule change awaiting a response")
+	case services.ErrDisruptionNotAcceptable:
+		BadRequestResponse(c, "The flight was cancelled, request a refund or rebook instead")
+	case services.ErrNameCorrectionExceedsMax:
+		BadRequestResponse(c, "A name correction may change at most "+strconv.Itoa(services.NameCorrectionLimit)+" characters")
+	default:
+		InternalServerErrorResponse(c, "Failed to change order")
+	}
+}
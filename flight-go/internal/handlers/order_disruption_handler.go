@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+// AcceptDisruption godoc
+// @Summary Accept schedule change
+// @Description Keep an order on its flight after the airline retimed it or changed the aircraft. Cancelled flights can only be refunded or rebooked.
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/disruption/accept [post]
+func (h *OrderHandler) AcceptDisruption(c *gin.Context) {
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.AcceptDisruption(order.ID, middleware.GetUserID(c))
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// RefundDisruption godoc
+// @Summary Refund disrupted order
+// @Description Cancel an order whose flight the airline changed or cancelled, with the full amount paid owed back
+// @Tags Orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param Idempotency-Key header string false "Retry safely, a repeated key replays the first response"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 409 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/disruption/refund [post]
+func (h *OrderHandler) RefundDisruption(c *gin.Context) {
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.RefundDisruption(order.ID, middleware.GetUserID(c))
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// Rebook godoc
+// @Summary Rebook disrupted order
+// @Description Move an order whose flight the airline changed or cancelled to another date or schedule of the same airline on the same route, free of charge. Selected seats are given up.
+// @Tags Orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body services.ChangeFlightRequest true "New flight"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /orders/{id}/disruption/rebook [post]
+func (h *OrderHandler) Rebook(c *gin.Context) {
+	var req services.ChangeFlightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, ok := h.ownedOrder(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.orderService.Rebook(order.ID, middleware.GetUserID(c), req)
+	if err != nil {
+		changeOrderErrorResponse(c, err)
+		return
+	}
+
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
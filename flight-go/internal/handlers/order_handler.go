@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"errors"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -25,9 +27,11 @@ func NewOrderHandler(orderService services.OrderService) *OrderHandler {
 // @Accept json
 // @Produce json
 // @Param request body CreateOrderRequest true "Order data"
+// @Param Idempotency-Key header string false "Retry safely, a repeated key replays the first response"
 // @Success 201 {object} Response{data=Order}
 // @Failure 400 {object} ErrorMessageResponse
 // @Failure 401 {object} ErrorMessageResponse
+// @Failure 409 {object} ErrorMessageResponse
 // @Failure 500 {object} ErrorMessageResponse
 // @Router /orders [post]
 func (h *OrderHandler) Create(c *gin.Context) {
@@ -43,20 +47,13 @@ func (h *OrderHandler) Create(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.Create(userID, req)
+	order, err := h.orderService.Create(userID, middleware.GetUserEmail(c), req)
 	if err != nil {
-		if err == services.ErrScheduleNotFound {
-			BadRequestResponse(c, "Flight schedule not found")
-			return
-		}
-		if err == services.ErrInvalidFlightDate {
-			BadRequestResponse(c, "Invalid flight date")
-			return
-		}
-		InternalServerErrorResponse(c, "Failed to create order: "+err.Error())
+		createOrderErrorResponse(c, err)
 		return
 	}
 
+	maskDocuments(c, order)
 	CreatedResponse(c, order)
 }
 
@@ -89,9 +86,151 @@ func (h *OrderHandler) GetByID(c *gin.Context) {
 		return
 	}
 
+	maskDocuments(c, order)
+	SuccessResponse(c, order)
+}
+
+// Itinerary godoc
+// @Summary Download order itinerary
+// @Description Render the itinerary and e-ticket receipt of an order as PDF or plain text
+// @Tags Orders
+// @Security BearerAuth
+// @Produce application/pdf
+// @Produce plain
+// @Param id path string true "Order ID"
+// @Param format query string false "pdf or text" default(pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /orders/{id}/itinerary [get]
+func (h *OrderHandler) Itinerary(c *gin.Context) {
+	id := c.Param("id")
+	userID := middleware.GetUserID(c)
+	userRole := middleware.GetUserRole(c)
+
+	order, err := h.orderService.GetByID(id)
+	if err != nil {
+		NotFoundResponse(c, "Order not found")
+		return
+	}
+
+	// Check if user owns this order or is admin
+	if order.UserID != userID && userRole != models.RoleAdmin {
+		ForbiddenResponse(c, "Access denied")
+		return
+	}
+
+	writeItinerary(c, order)
+}
+
+// GetBooking godoc
+// @Summary Look up booking
+// @Description Look up a booking by its PNR and the contact email it was made with, no login required
+// @Tags Bookings
+// @Produce json
+// @Param pnr path string true "Booking reference (PNR)"
+// @Param email query string true "Contact email of the booking"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /bookings/{pnr} [get]
+func (h *OrderHandler) GetBooking(c *gin.Context) {
+	order, ok := h.findBooking(c)
+	if !ok {
+		return
+	}
+
+	order.MaskDocuments()
+	SuccessResponse(c, order)
+}
+
+// BookingItinerary godoc
+// @Summary Download booking itinerary
+// @Description Render the itinerary and e-ticket receipt of a booking looked up by PNR and contact email
+// @Tags Bookings
+// @Produce application/pdf
+// @Produce plain
+// @Param pnr path string true "Booking reference (PNR)"
+// @Param email query string true "Contact email of the booking"
+// @Param format query string false "pdf or text" default(pdf)
+// @Success 200 {file} file
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /bookings/{pnr}/itinerary [get]
+func (h *OrderHandler) BookingItinerary(c *gin.Context) {
+	order, ok := h.findBooking(c)
+	if !ok {
+		return
+	}
+
+	writeItinerary(c, order)
+}
+
+// ClaimBooking godoc
+// @Summary Claim booking
+// @Description Move a guest booking to the signed-in user's account, proving it is theirs with the surname of one of its passengers
+// @Tags Bookings
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param pnr path string true "Booking reference (PNR)"
+// @Param request body services.ClaimBookingRequest true "Passenger surname"
+// @Success 200 {object} Response{data=Order}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /bookings/{pnr}/claim [post]
+func (h *OrderHandler) ClaimBooking(c *gin.Context) {
+	var req services.ClaimBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	order, err := h.orderService.ClaimBooking(c.Param("pnr"), middleware.GetUserID(c), req)
+	if err != nil {
+		claimOrderErrorResponse(c, err)
+		return
+	}
+
+	order.MaskDocuments()
 	SuccessResponse(c, order)
 }
 
+// claimOrderErrorResponse writes the response for a failed claim. Orders of another account
+// read as not found, so claiming cannot probe for bookings.
+func claimOrderErrorResponse(c *gin.Context, err error) {
+	if err == services.ErrOrderNotFound {
+		NotFoundResponse(c, "Booking not found")
+		return
+	}
+	InternalServerErrorResponse(c, "Failed to claim order")
+}
+
+// findBooking loads the booking of the pnr path parameter, writing the error response if it can't
+func (h *OrderHandler) findBooking(c *gin.Context) (*models.Order, bool) {
+	email := c.Query("email")
+	if email == "" {
+		BadRequestResponse(c, "email is required")
+		return nil, false
+	}
+
+	order, err := h.orderService.GetByPNR(c.Param("pnr"), email)
+	if err != nil {
+		if err == services.ErrOrderNotFound {
+			NotFoundResponse(c, "Booking not found")
+			return nil, false
+		}
+		InternalServerErrorResponse(c, "Failed to look up booking")
+		return nil, false
+	}
+
+	return order, true
+}
+
 // Update godoc
 // @Summary Update order
 // @Description Update an existing order (admin only)
@@ -117,12 +256,36 @@ func (h *OrderHandler) Update(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.Update(id, req)
+	order, err := h.orderService.Update(id, middleware.GetUserID(c), req)
 	if err != nil {
 		if err == services.ErrOrderNotFound {
 			NotFoundResponse(c, "Order not found")
 			return
 		}
+		if err == services.ErrOrderNotCancellable {
+			BadRequestResponse(c, "Only pending or confirmed orders can be cancelled")
+			return
+		}
+		if err == services.ErrScheduleNotFound {
+			BadRequestResponse(c, "Flight schedule not found")
+			return
+		}
+		if err == services.ErrFareFamilyNotFound {
+			BadRequestResponse(c, "Fare family not available for this cabin")
+			return
+		}
+		if err == services.ErrFareFamilySoldOut {
+			BadRequestResponse(c, "Fare family sold out")
+			return
+		}
+		if err == services.ErrInsufficientSeats {
+			BadRequestResponse(c, "Not enough seats left in cabin")
+			return
+		}
+		if err == services.ErrPromoCodeUsedUp {
+			BadRequestResponse(c, "Promo code usage limit reached")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to update order")
 		return
 	}
@@ -136,10 +299,13 @@ func (h *OrderHandler) Update(c *gin.Context) {
 // @Tags Orders
 // @Security BearerAuth
 // @Param id path string true "Order ID"
+// @Param Idempotency-Key header string false "Retry safely, a repeated key replays the first response"
 // @Success 200 {object} SuccessMessageResponse
+// @Failure 400 {object} ErrorMessageResponse
 // @Failure 401 {object} ErrorMessageResponse
 // @Failure 403 {object} ErrorMessageResponse
 // @Failure 404 {object} ErrorMessageResponse
+// @Failure 409 {object} ErrorMessageResponse
 // @Failure 500 {object} ErrorMessageResponse
 // @Router /orders/{id}/cancel [post]
 func (h *OrderHandler) Cancel(c *gin.Context) {
@@ -160,7 +326,11 @@ func (h *OrderHandler) Cancel(c *gin.Context) {
 		return
 	}
 
-	if err := h.orderService.Cancel(id); err != nil {
+	if err := h.orderService.Cancel(id, userID); err != nil {
+		if err == services.ErrOrderNotCancellable {
+			BadRequestResponse(c, "Only pending or confirmed orders can be cancelled")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to cancel order")
 		return
 	}
@@ -222,5 +392,84 @@ func (h *OrderHandler) ListMyOrders(c *gin.Context) {
 		return
 	}
 
+	orders := result.Data.([]models.Order)
+	for i := range orders {
+		maskDocuments(c, &orders[i])
+	}
+
 	SuccessResponse(c, result)
 }
+
+// createOrderErrorResponse responds to a failed order creation
+func createOrderErrorResponse(c *gin.Context, err error) {
+	var validationErr *services.PassengerValidationError
+	if errors.As(err, &validationErr) {
+		ValidationErrorResponse(c, "Passengers do not meet the airline's booking rules", validationErr.Errors)
+		return
+	}
+	if err == services.ErrScheduleNotFound {
+		BadRequestResponse(c, "Flight schedule not found")
+		return
+	}
+	if err == services.ErrInvalidFlightDate {
+		BadRequestResponse(c, "Invalid flight date")
+		return
+	}
+	if err == services.ErrFareFamilyNotFound {
+		BadRequestResponse(c, "Fare family not available for this cabin")
+		return
+	}
+	if err == services.ErrFareFamilySoldOut {
+		BadRequestResponse(c, "Fare family sold out")
+		return
+	}
+	if err == services.ErrInsufficientSeats {
+		BadRequestResponse(c, "Not enough seats left in cabin")
+		return
+	}
+	if err == services.ErrUnsupportedCurrency {
+		BadRequestResponse(c, "Unsupported currency")
+		return
+	}
+	if err == services.ErrNoExchangeRate {
+		BadRequestResponse(c, "No exchange rate available for the requested currency")
+		return
+	}
+	if err == services.ErrSeatMapNotFound {
+		BadRequestResponse(c, "Seat selection is not available on this aircraft")
+		return
+	}
+	if err == services.ErrSeatTaken {
+		BadRequestResponse(c, "A selected seat was just taken, please pick another")
+		return
+	}
+	if err == services.ErrAncillarySalesClosed {
+		BadRequestResponse(c, "Ancillary sales for this flight have closed")
+		return
+	}
+	if message, ok := promoCodeErrorMessage(err); ok {
+		BadRequestResponse(c, message)
+		return
+	}
+	InternalServerErrorResponse(c, "Failed to create order: "+err.Error())
+}
+
+// writeItinerary responds with the order's itinerary in the requested format
+func writeItinerary(c *gin.Context, order *models.Order) {
+	switch c.DefaultQuery("format", "pdf") {
+	case "pdf":
+		c.Header("Content-Disposition", `attachment; filename="`+order.PNR+`.pdf"`)
+		c.Data(http.StatusOK, "application/pdf", services.RenderItineraryPDF(order))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(services.RenderItineraryText(order)))
+	default:
+		BadRequestResponse(c, "format must be pdf or text")
+	}
+}
+
+// maskDocuments hides passenger document numbers from everyone but admins
+func maskDocuments(c *gin.Context, order *models.Order) {
+	if middleware.GetUserRole(c) != models.RoleAdmin {
+		order.MaskDocuments()
+	}
+}
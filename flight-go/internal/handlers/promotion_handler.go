@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type PromotionHandler struct {
+	promotionService services.PromotionService
+}
+
+func NewPromotionHandler(promotionService services.PromotionService) *PromotionHandler {
+	return &PromotionHandler{promotionService: promotionService}
+}
+
+// Create godoc
+// @Summary Create promotion
+// @Description Create a promo code campaign (admin only)
+// @Tags Admin - Promotions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreatePromotionRequest true "Promotion data"
+// @Success 201 {object} Response{data=models.Promotion}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/promotions [post]
+func (h *PromotionHandler) Create(c *gin.Context) {
+	var req services.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	promotion, err := h.promotionService.Create(req)
+	if err != nil {
+		if err == services.ErrInvalidPromotion {
+			BadRequestResponse(c, "Invalid promotion discount, scope or validity window")
+			return
+		}
+		if err == services.ErrPromoCodeExists {
+			BadRequestResponse(c, "Promo code already exists")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to create promotion")
+		return
+	}
+
+	CreatedResponse(c, promotion)
+}
+
+// GetByID godoc
+// @Summary Get promotion
+// @Description Get a single promo code campaign (admin only)
+// @Tags Admin - Promotions
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} Response{data=models.Promotion}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/promotions/{id} [get]
+func (h *PromotionHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+
+	promotion, err := h.promotionService.GetByID(id)
+	if err != nil {
+		NotFoundResponse(c, "Promotion not found")
+		return
+	}
+
+	SuccessResponse(c, promotion)
+}
+
+// Update godoc
+// @Summary Update promotion
+// @Description Update a promo code campaign (admin only)
+// @Tags Admin - Promotions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Promotion ID"
+// @Param request body services.UpdatePromotionRequest true "Promotion data"
+// @Success 200 {object} Response{data=models.Promotion}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/promotions/{id} [put]
+func (h *PromotionHandler) Update(c *gin.Context) {
+	id := c.Param("id")
+
+	var req services.UpdatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	promotion, err := h.promotionService.Update(id, req)
+	if err != nil {
+		if err == services.ErrPromotionNotFound {
+			NotFoundResponse(c, "Promotion not found")
+			return
+		}
+		if err == services.ErrInvalidPromotion {
+			BadRequestResponse(c, "Invalid promotion discount, scope or validity window")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to update promotion")
+		return
+	}
+
+	SuccessResponse(c, promotion)
+}
+
+// Delete godoc
+// @Summary Delete promotion
+// @Description Delete a promo code campaign (admin only)
+// @Tags Admin - Promotions
+// @Security BearerAuth
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/promotions/{id} [delete]
+func (h *PromotionHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.promotionService.Delete(id); err != nil {
+		if err == services.ErrPromotionNotFound {
+			NotFoundResponse(c, "Promotion not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete promotion")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Promotion deleted successfully"})
+}
+
+// List godoc
+// @Summary List promotions
+// @Description Get a paginated list of promo code campaigns (admin only)
+// @Tags Admin - Promotions
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/promotions [get]
+func (h *PromotionHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.promotionService.List(page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list promotions")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// Validate godoc
+// @Summary Validate promo code
+// @Description Check a promo code against a flight and order total and return the discount it would give, without redeeming it
+// @Tags Promotions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.ValidatePromoCodeRequest true "Promo code and order"
+// @Success 200 {object} Response{data=services.PromoCodeValidation}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /promotions/validate [post]
+func (h *PromotionHandler) Validate(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	var req services.ValidatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	result, err := h.promotionService.Validate(userID, middleware.GetUserEmail(c), req)
+	if err != nil {
+		if message, ok := promoCodeErrorMessage(err); ok {
+			BadRequestResponse(c, message)
+			return
+		}
+		if err == services.ErrScheduleNotFound {
+			BadRequestResponse(c, "Flight schedule not found")
+			return
+		}
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
+		if err == services.ErrNoExchangeRate {
+			BadRequestResponse(c, "No exchange rate available for the requested currency")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to validate promo code")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// promoCodeErrorMessage maps the reasons a promo code is refused to client messages
+func promoCodeErrorMessage(err error) (string, bool) {
+	switch err {
+	case services.ErrInvalidPromoCode:
+		return "Promo code not found", true
+	case services.ErrPromoCodeExpired:
+		return "Promo code is not valid at this time", true
+	case services.ErrPromoCodeNotApplicable:
+		return "Promo code does not apply to this flight", true
+	case services.ErrPromoMinSpend:
+		return "Order total is below the promo code's minimum spend", true
+	case services.ErrPromoCodeUsedUp:
+		return "Promo code usage limit reached", true
+	}
+	return "", false
+}
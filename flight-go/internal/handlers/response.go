@@ -38,6 +38,15 @@ func BadRequestResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusBadRequest, message)
 }
 
+// ValidationErrorResponse is a 400 carrying the individual validation failures in data
+func ValidationErrorResponse(c *gin.Context, message string, details interface{}) {
+	c.JSON(http.StatusBadRequest, Response{
+		Success: false,
+		Error:   message,
+		Data:    details,
+	})
+}
+
 func UnauthorizedResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusUnauthorized, message)
 }
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/models"
 	"github.com/mirahekatiket/flight-go/internal/services"
 )
 
@@ -47,6 +50,10 @@ func (h *ScheduleHandler) Create(c *gin.Context) {
 
 	schedule, err := h.scheduleService.Create(req)
 	if err != nil {
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to create schedule: "+err.Error())
 		return
 	}
@@ -67,15 +74,14 @@ func (h *ScheduleHandler) Create(c *gin.Context) {
 func (h *ScheduleHandler) GetByID(c *gin.Context) {
 	id := c.Param("id")
 
-	// Get email from authenticated user context only
-	email := middleware.GetUserEmail(c)
-
-	// Set email in dual service if available
+	var schedule *models.Schedule
+	var err error
 	if h.dualService != nil {
-		h.dualService.SetCurrentEmail(email)
+		// Get email from authenticated user context only
+		schedule, err = h.dualService.GetFor(middleware.GetUserEmail(c), id)
+	} else {
+		schedule, err = h.scheduleService.GetByID(id)
 	}
-
-	schedule, err := h.scheduleService.GetByID(id)
 	if err != nil {
 		NotFoundResponse(c, "Schedule not found")
 		return
@@ -86,7 +92,7 @@ func (h *ScheduleHandler) GetByID(c *gin.Context) {
 
 // Update godoc
 // @Summary Update schedule
-// @Description Update an existing flight schedule (admin only)
+// @Description Update an existing flight schedule (admin only). Moving the departure or arrival by more than SCHEDULE_CHANGE_THRESHOLD, deactivating it or changing the aircraft disrupts its upcoming orders, whose passengers are notified to accept, refund or rebook.
 // @Tags Schedules
 // @Security BearerAuth
 // @Accept json
@@ -115,6 +121,10 @@ func (h *ScheduleHandler) Update(c *gin.Context) {
 			NotFoundResponse(c, "Schedule not found")
 			return
 		}
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to update schedule")
 		return
 	}
@@ -210,6 +220,7 @@ func (h *ScheduleHandler) ListByAirline(c *gin.Context) {
 // @Param departure_date query string true "Departure date (YYYY-MM-DD)"
 // @Param cabin_class query string false "Cabin class (economy, business, first)" default(economy)
 // @Param airlines query string false "Comma-separated airline IDs to filter"
+// @Param currency query string false "Currency to quote fares in (e.g., USD), defaults to each schedule's"
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Page size" default(10)
 // @Success 200 {object} Response{data=PaginatedResponse}
@@ -223,6 +234,7 @@ func (h *ScheduleHandler) Search(c *gin.Context) {
 	req.Destination = c.Query("destination")
 	req.DepartureDate = c.Query("departure_date")
 	req.CabinClass = c.DefaultQuery("cabin_class", "economy")
+	req.Currency = strings.ToUpper(c.Query("currency"))
 	req.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
 	req.PageSize, _ = strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
@@ -240,13 +252,21 @@ func (h *ScheduleHandler) Search(c *gin.Context) {
 	// Get email from authenticated user context only
 	email := middleware.GetUserEmail(c)
 
-	// Set email in dual service if available
-	if h.dualService != nil {
-		h.dualService.SetCurrentEmail(email)
-	}
-
-	result, err := h.scheduleService.Search(req)
+	result, err := h.scheduleService.SearchContext(c.Request.Context(), email, req)
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			// Client went away, nobody is left to read the response
+			c.Abort()
+			return
+		}
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
+		if err == services.ErrNoExchangeRate {
+			BadRequestResponse(c, "No exchange rate available for the requested currency")
+			return
+		}
 		InternalServerErrorResponse(c, "Failed to search flights")
 		return
 	}
@@ -256,13 +276,49 @@ func (h *ScheduleHandler) Search(c *gin.Context) {
 
 // GetFlightDetail godoc
 // @Summary Get flight detail
-// @Description Get detailed information about a specific flight
+// @Description Get detailed information about a specific flight, with its fare when a departure date is given
 // @Tags Flights
 // @Produce json
 // @Param id path string true "Flight/Schedule ID"
+// @Param departure_date query string false "Departure date (YYYY-MM-DD) to quote the fare for"
+// @Param cabin_class query string false "Cabin class (economy, business, first)" default(economy)
+// @Param currency query string false "Currency to quote the fare in (e.g., USD), defaults to the schedule's"
 // @Success 200 {object} Response{data=Schedule}
+// @Failure 400 {object} ErrorMessageResponse
 // @Failure 404 {object} ErrorMessageResponse
 // @Router /flights/{id} [get]
 func (h *ScheduleHandler) GetFlightDetail(c *gin.Context) {
-	h.GetByID(c)
+	departureDate := c.Query("departure_date")
+	if departureDate == "" || h.dualService == nil {
+		h.GetByID(c)
+		return
+	}
+
+	// Get email from authenticated user context only
+	email := middleware.GetUserEmail(c)
+
+	currency := strings.ToUpper(c.Query("currency"))
+	schedule, err := h.dualService.GetFlightDetail(email, c.Param("id"), c.DefaultQuery("cabin_class", "economy"), departureDate, currency)
+	if err != nil {
+		if err == services.ErrInvalidFlightDate {
+			BadRequestResponse(c, "Invalid departure date")
+			return
+		}
+		if err == services.ErrScheduleNotFound {
+			NotFoundResponse(c, "Schedule not found")
+			return
+		}
+		if err == services.ErrUnsupportedCurrency {
+			BadRequestResponse(c, "Unsupported currency")
+			return
+		}
+		if err == services.ErrNoExchangeRate {
+			BadRequestResponse(c, "No exchange rate available for the requested currency")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to get flight detail")
+		return
+	}
+
+	SuccessResponse(c, schedule)
 }
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type SeatMapHandler struct {
+	seatMapService services.SeatMapService
+	seatService    *services.SeatService
+}
+
+func NewSeatMapHandler(seatMapService services.SeatMapService, seatService *services.SeatService) *SeatMapHandler {
+	return &SeatMapHandler{seatMapService: seatMapService, seatService: seatService}
+}
+
+// FlightSeatMap godoc
+// @Summary Get flight seat map
+// @Description Get the seat map of a flight on a date with each seat's price and whether it is still available
+// @Tags Flights
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Param date query string true "Flight date (YYYY-MM-DD)"
+// @Param cabin query string false "Only rows of this cabin class (economy, business, first)"
+// @Success 200 {object} Response{data=models.FlightSeatMap}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /flights/{id}/seats [get]
+func (h *SeatMapHandler) FlightSeatMap(c *gin.Context) {
+	seatMap, err := h.seatService.FlightSeatMap(middleware.GetUserEmail(c), c.Param("id"), c.Query("date"), c.Query("cabin"))
+	if err != nil {
+		switch err {
+		case services.ErrScheduleNotFound:
+			NotFoundResponse(c, "Flight not found")
+		case services.ErrSeatMapNotFound:
+			NotFoundResponse(c, "No seat map for this aircraft")
+		case services.ErrInvalidFlightDate:
+			BadRequestResponse(c, "date must be YYYY-MM-DD")
+		default:
+			InternalServerErrorResponse(c, "Failed to load seat map")
+		}
+		return
+	}
+
+	SuccessResponse(c, seatMap)
+}
+
+// Create godoc
+// @Summary Create seat map
+// @Description Create the seat map template of an aircraft type (admin only)
+// @Tags Admin - Seat Maps
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateSeatMapRequest true "Seat map data"
+// @Success 201 {object} Response{data=models.SeatMap}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/seat-maps [post]
+func (h *SeatMapHandler) Create(c *gin.Context) {
+	var req services.CreateSeatMapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	seatMap, err := h.seatMapService.Create(req)
+	if err != nil {
+		seatMapErrorResponse(c, err, "Failed to create seat map")
+		return
+	}
+
+	CreatedResponse(c, seatMap)
+}
+
+// GetByID godoc
+// @Summary Get seat map
+// @Description Get a single seat map template (admin only)
+// @Tags Admin - Seat Maps
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Seat map ID"
+// @Success 200 {object} Response{data=models.SeatMap}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/seat-maps/{id} [get]
+func (h *SeatMapHandler) GetByID(c *gin.Context) {
+	seatMap, err := h.seatMapService.GetByID(c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "Seat map not found")
+		return
+	}
+
+	SuccessResponse(c, seatMap)
+}
+
+// Update godoc
+// @Summary Update seat map
+// @Description Update a seat map template, zones are replaced as a whole (admin only)
+// @Tags Admin - Seat Maps
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Seat map ID"
+// @Param request body services.UpdateSeatMapRequest true "Seat map data"
+// @Success 200 {object} Response{data=models.SeatMap}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/seat-maps/{id} [put]
+func (h *SeatMapHandler) Update(c *gin.Context) {
+	var req services.UpdateSeatMapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	seatMap, err := h.seatMapService.Update(c.Param("id"), req)
+	if err != nil {
+		seatMapErrorResponse(c, err, "Failed to update seat map")
+		return
+	}
+
+	SuccessResponse(c, seatMap)
+}
+
+// Delete godoc
+// @Summary Delete seat map
+// @Description Delete a seat map template (admin only)
+// @Tags Admin - Seat Maps
+// @Security BearerAuth
+// @Param id path string true "Seat map ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/seat-maps/{id} [delete]
+func (h *SeatMapHandler) Delete(c *gin.Context) {
+	if err := h.seatMapService.Delete(c.Param("id")); err != nil {
+		if err == services.ErrSeatMapNotFound {
+			NotFoundResponse(c, "Seat map not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete seat map")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Seat map deleted successfully"})
+}
+
+// List godoc
+// @Summary List seat maps
+// @Description Get a paginated list of seat map templates (admin only)
+// @Tags Admin - Seat Maps
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/seat-maps [get]
+func (h *SeatMapHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.seatMapService.List(page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list seat maps")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// seatMapErrorResponse responds to a failed seat map create or update
+func seatMapErrorResponse(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrSeatMapNotFound:
+		NotFoundResponse(c, "Seat map not found")
+	case services.ErrInvalidSeatMap:
+		BadRequestResponse(c, "Invalid currency, cabin class, rows, layout or exit rows, or zones overlap")
+	case services.ErrSeatMapExists:
+		BadRequestResponse(c, "A seat map already exists for this aircraft")
+	default:
+		InternalServerErrorResponse(c, message)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type TravelerHandler struct {
+	travelerService services.TravelerService
+}
+
+func NewTravelerHandler(travelerService services.TravelerService) *TravelerHandler {
+	return &TravelerHandler{travelerService: travelerService}
+}
+
+// Create godoc
+// @Summary Save traveler
+// @Description Add a traveler to the current user's address book
+// @Tags Travelers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateTravelerRequest true "Traveler data"
+// @Success 201 {object} Response{data=models.Traveler}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /me/travelers [post]
+func (h *TravelerHandler) Create(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	var req services.CreateTravelerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	traveler, err := h.travelerService.Create(userID, req)
+	if err != nil {
+		var validationErr *services.PassengerValidationError
+		if errors.As(err, &validationErr) {
+			ValidationErrorResponse(c, "Invalid traveler details", validationErr.Errors)
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to save traveler")
+		return
+	}
+
+	maskTravelerDocument(c, traveler)
+	CreatedResponse(c, traveler)
+}
+
+// GetByID godoc
+// @Summary Get saved traveler
+// @Description Get one of the current user's saved travelers
+// @Tags Travelers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Traveler ID"
+// @Success 200 {object} Response{data=models.Traveler}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /me/travelers/{id} [get]
+func (h *TravelerHandler) GetByID(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	traveler, err := h.travelerService.GetByID(userID, c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "Traveler not found")
+		return
+	}
+
+	maskTravelerDocument(c, traveler)
+	SuccessResponse(c, traveler)
+}
+
+// Update godoc
+// @Summary Update saved traveler
+// @Description Update one of the current user's saved travelers. An empty string clears an optional field
+// @Tags Travelers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Traveler ID"
+// @Param request body services.UpdateTravelerRequest true "Traveler data"
+// @Success 200 {object} Response{data=models.Traveler}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /me/travelers/{id} [put]
+func (h *TravelerHandler) Update(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	var req services.UpdateTravelerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	traveler, err := h.travelerService.Update(userID, c.Param("id"), req)
+	if err != nil {
+		if err == services.ErrTravelerNotFound {
+			NotFoundResponse(c, "Traveler not found")
+			return
+		}
+		var validationErr *services.PassengerValidationError
+		if errors.As(err, &validationErr) {
+			ValidationErrorResponse(c, "Invalid traveler details", validationErr.Errors)
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to update traveler")
+		return
+	}
+
+	maskTravelerDocument(c, traveler)
+	SuccessResponse(c, traveler)
+}
+
+// Delete godoc
+// @Summary Delete saved traveler
+// @Description Remove a traveler from the current user's address book
+// @Tags Travelers
+// @Security BearerAuth
+// @Param id path string true "Traveler ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /me/travelers/{id} [delete]
+func (h *TravelerHandler) Delete(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	if err := h.travelerService.Delete(userID, c.Param("id")); err != nil {
+		if err == services.ErrTravelerNotFound {
+			NotFoundResponse(c, "Traveler not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete traveler")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Traveler deleted successfully"})
+}
+
+// List godoc
+// @Summary List saved travelers
+// @Description Get a paginated list of the current user's saved travelers
+// @Tags Travelers
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /me/travelers [get]
+func (h *TravelerHandler) List(c *gin.Context) {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		UnauthorizedResponse(c, "Not authenticated")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.travelerService.List(userID, page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list travelers")
+		return
+	}
+
+	travelers := result.Data.([]models.Traveler)
+	for i := range travelers {
+		maskTravelerDocument(c, &travelers[i])
+	}
+
+	SuccessResponse(c, result)
+}
+
+// maskTravelerDocument hides saved document numbers from everyone but admins, like order passengers
+func maskTravelerDocument(c *gin.Context, traveler *models.Traveler) {
+	if middleware.GetUserRole(c) != models.RoleAdmin {
+		traveler.MaskDocument()
+	}
+}
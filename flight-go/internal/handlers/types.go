@@ -16,49 +16,84 @@ type RegisterRequest struct {
 	Phone    string `json:"phone" example:"+6281234567890"`
 }
 
+// RefreshRequest represents the token refresh request body
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
 // TokenResponse represents the login response
 type TokenResponse struct {
-	Token     string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	ExpiresAt string `json:"expires_at" example:"2024-12-08T00:00:00Z"`
-	User      User   `json:"user"`
+	Token            string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Access token
+	ExpiresAt        string `json:"expires_at" example:"2024-12-08T00:15:00Z"`
+	RefreshToken     string `json:"refresh_token" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"` // Single use
+	RefreshExpiresAt string `json:"refresh_expires_at" example:"2025-01-07T00:00:00Z"`
+	User             User   `json:"user"`
 }
 
 // User represents a user object
 type User struct {
-	ID        string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Email     string `json:"email" example:"user@example.com"`
-	Name      string `json:"name" example:"John Doe"`
-	Phone     string `json:"phone" example:"+6281234567890"`
-	Role      string `json:"role" example:"user"`
-	CreatedAt string `json:"created_at" example:"2024-12-07T00:00:00Z"`
-	UpdatedAt string `json:"updated_at" example:"2024-12-07T00:00:00Z"`
+	ID              string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email           string `json:"email" example:"user@example.com"`
+	Name            string `json:"name" example:"John Doe"`
+	Phone           string `json:"phone" example:"+6281234567890"`
+	Role            string `json:"role" example:"user"`
+	DisabledAt      string `json:"disabled_at,omitempty" example:"2024-12-09T00:00:00Z"`
+	EmailVerifiedAt string `json:"email_verified_at,omitempty" example:"2024-12-07T00:05:00Z"`
+	CreatedAt       string `json:"created_at" example:"2024-12-07T00:00:00Z"`
+	UpdatedAt       string `json:"updated_at" example:"2024-12-07T00:00:00Z"`
+}
+
+// VerifyEmailRequest represents the email verification request body
+type VerifyEmailRequest struct {
+	Token string `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // From the verification email
+}
+
+// VerifyEmailResponse represents a verified user
+type VerifyEmailResponse struct {
+	User          User  `json:"user"`
+	ClaimedOrders int64 `json:"claimed_orders" example:"1"` // Guest orders booked with the email, moved to the account
 }
 
 // Airline represents an airline object
 type Airline struct {
-	ID        string `json:"id" example:"ga"`
-	Code      string `json:"code" example:"GA"`
-	Name      string `json:"name" example:"Garuda Indonesia"`
-	Logo      string `json:"logo" example:"https://example.com/logo.png"`
-	IsActive  bool   `json:"is_active" example:"true"`
-	CreatedAt string `json:"created_at" example:"2024-12-07T00:00:00Z"`
-	UpdatedAt string `json:"updated_at" example:"2024-12-07T00:00:00Z"`
+	ID             string          `json:"id" example:"ga"`
+	Code           string          `json:"code" example:"GA"`
+	Name           string          `json:"name" example:"Garuda Indonesia"`
+	Logo           string          `json:"logo" example:"https://example.com/logo.png"`
+	IsActive       bool            `json:"is_active" example:"true"`
+	Currency       string          `json:"currency" example:"IDR"`
+	PassengerRules *PassengerRules `json:"passenger_rules,omitempty"`
+	CreatedAt      string          `json:"created_at" example:"2024-12-07T00:00:00Z"`
+	UpdatedAt      string          `json:"updated_at" example:"2024-12-07T00:00:00Z"`
+}
+
+// PassengerRules represents an airline's passenger-type pricing and booking limits
+type PassengerRules struct {
+	ChildPercent  float64 `json:"child_percent" example:"75"`
+	InfantPercent float64 `json:"infant_percent" example:"10"`
+	MaxPassengers int     `json:"max_passengers" example:"9"`
+	ChildMinAge   int     `json:"child_min_age" example:"2"`
+	AdultMinAge   int     `json:"adult_min_age" example:"12"`
 }
 
 // CreateAirlineRequest represents the create airline request
 type CreateAirlineRequest struct {
-	Code     string `json:"code" example:"GA"`
-	Name     string `json:"name" example:"Garuda Indonesia"`
-	Logo     string `json:"logo" example:"https://example.com/logo.png"`
-	IsActive *bool  `json:"is_active" example:"true"`
+	Code           string          `json:"code" example:"GA"`
+	Name           string          `json:"name" example:"Garuda Indonesia"`
+	Logo           string          `json:"logo" example:"https://example.com/logo.png"`
+	IsActive       *bool           `json:"is_active" example:"true"`
+	Currency       string          `json:"currency" example:"IDR"`
+	PassengerRules *PassengerRules `json:"passenger_rules"`
 }
 
 // UpdateAirlineRequest represents the update airline request
 type UpdateAirlineRequest struct {
-	Code     string `json:"code" example:"GA"`
-	Name     string `json:"name" example:"Garuda Indonesia"`
-	Logo     string `json:"logo" example:"https://example.com/logo.png"`
-	IsActive *bool  `json:"is_active" example:"true"`
+	Code           string          `json:"code" example:"GA"`
+	Name           string          `json:"name" example:"Garuda Indonesia"`
+	Logo           string          `json:"logo" example:"https://example.com/logo.png"`
+	IsActive       *bool           `json:"is_active" example:"true"`
+	Currency       string          `json:"currency" example:"IDR"`
+	PassengerRules *PassengerRules `json:"passenger_rules"`
 }
 
 // Airport represents an airport object
@@ -67,6 +102,7 @@ type Airport struct {
 	Code      string `json:"code" example:"CGK"`
 	City      string `json:"city" example:"Jakarta"`
 	Name      string `json:"name" example:"Soekarno-Hatta International Airport"`
+	Country   string `json:"country" example:"ID"`
 	CreatedAt string `json:"created_at" example:"2024-12-07T00:00:00Z"`
 	UpdatedAt string `json:"updated_at" example:"2024-12-07T00:00:00Z"`
 }
@@ -94,6 +130,7 @@ type Schedule struct {
 	EconomySeats       int      `json:"economy_seats" example:"150"`
 	BusinessSeats      int      `json:"business_seats" example:"30"`
 	FirstClassSeats    int      `json:"first_class_seats" example:"10"`
+	Currency           string   `json:"currency,omitempty" example:"IDR"`
 	IsActive           bool     `json:"is_active" example:"true"`
 	ValidFrom          string   `json:"valid_from" example:"2024-01-01"`
 	ValidUntil         string   `json:"valid_until" example:"2024-12-31"`
@@ -118,6 +155,7 @@ type CreateScheduleRequest struct {
 	EconomySeats       int     `json:"economy_seats" example:"150"`
 	BusinessSeats      int     `json:"business_seats" example:"30"`
 	FirstClassSeats    int     `json:"first_class_seats" example:"10"`
+	Currency           string  `json:"currency" example:"IDR"`
 	IsActive           *bool   `json:"is_active" example:"true"`
 	ValidFrom          string  `json:"valid_from" example:"2024-01-01"`
 	ValidUntil         string  `json:"valid_until" example:"2024-12-31"`
@@ -125,48 +163,239 @@ type CreateScheduleRequest struct {
 
 // Order represents an order object
 type Order struct {
-	ID             string      `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	UserID         string      `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	ScheduleID     string      `json:"schedule_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Schedule       *Schedule   `json:"schedule,omitempty"`
-	FlightDate     string      `json:"flight_date" example:"2024-12-20"`
-	CabinClass     string      `json:"cabin_class" example:"economy"`
-	TotalPassenger int         `json:"total_passenger" example:"2"`
-	TotalAmount    float64     `json:"total_amount" example:"3000000"`
-	Status         string      `json:"status" example:"pending"`
-	ContactName    string      `json:"contact_name" example:"John Doe"`
-	ContactEmail   string      `json:"contact_email" example:"john@example.com"`
-	ContactPhone   string      `json:"contact_phone" example:"+6281234567890"`
-	Passengers     []Passenger `json:"passengers,omitempty"`
-	CreatedAt      string      `json:"created_at" example:"2024-12-07T00:00:00Z"`
-	UpdatedAt      string      `json:"updated_at" example:"2024-12-07T00:00:00Z"`
+	ID               string              `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PNR              string              `json:"pnr" example:"K7Q2MX"`
+	UserID           string              `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Environment      string              `json:"environment" example:"staging"` // Database the schedule was booked from, staging or production
+	ScheduleID       string              `json:"schedule_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Flight           *FlightSnapshot     `json:"flight,omitempty"`
+	ScheduleChanged  bool                `json:"schedule_changed" example:"false"` // The live schedule no longer matches flight, or was deleted
+	DisruptionID     string              `json:"disruption_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Disruption       *ScheduleDisruption `json:"disruption,omitempty"`
+	DisruptionStatus string              `json:"disruption_status,omitempty" example:"action_required"` // action_required, accepted, refund_requested or rebooked
+	FlightDate       string              `json:"flight_date" example:"2024-12-20"`
+	CabinClass       string              `json:"cabin_class" example:"economy"`
+	FareFamilyID     string              `json:"fare_family_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	TotalPassenger   int                 `json:"total_passenger" example:"2"`
+	TotalAmount      int64               `json:"total_amount" example:"300000000"` // In minor units of currency
+	Currency         string              `json:"currency" example:"IDR"`
+	PriceBreakdown   *PriceBreakdown     `json:"price_breakdown,omitempty"`
+	PromoCode        string              `json:"promo_code,omitempty" example:"HOLIDAY10"`
+	Status           string              `json:"status" example:"pending"`
+	ContactName      string              `json:"contact_name" example:"John Doe"`
+	ContactEmail     string              `json:"contact_email" example:"john@example.com"`
+	ContactPhone     string              `json:"contact_phone" example:"+6281234567890"`
+	Locale           string              `json:"locale" example:"en"`
+	Passengers       []Passenger         `json:"passengers,omitempty"`
+	CreatedAt        string              `json:"created_at" example:"2024-12-07T00:00:00Z"`
+	UpdatedAt        string              `json:"updated_at" example:"2024-12-07T00:00:00Z"`
+}
+
+// ScheduleDisruption is a material change to a schedule that booked passengers must respond to
+type ScheduleDisruption struct {
+	ID             string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Environment    string            `json:"environment" example:"staging"`
+	ScheduleID     string            `json:"schedule_id" example:"schedule-cgk-dps-ga-001"`
+	Types          []string          `json:"types" example:"time_change"` // time_change, cancellation or aircraft_change
+	Before         map[string]string `json:"before"`
+	After          map[string]string `json:"after"`
+	AffectedOrders int               `json:"affected_orders" example:"12"`
+	CreatedAt      string            `json:"created_at" example:"2024-12-07T00:00:00Z"`
+}
+
+// Notification is a message to a user about one of their orders
+type Notification struct {
+	ID        string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID    string `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Email     string `json:"email" example:"john@example.com"`
+	OrderID   string `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type      string `json:"type" example:"schedule_change"`
+	Title     string `json:"title" example:"Flight GA123 on 20 Dec 2024 has changed"`
+	Message   string `json:"message" example:"Departure moves from 08:00 to 10:00."`
+	ReadAt    string `json:"read_at,omitempty" example:"2024-12-07T00:00:00Z"`
+	CreatedAt string `json:"created_at" example:"2024-12-07T00:00:00Z"`
+}
+
+// NotificationDelivery is one message sent, or being retried, to a recipient through one channel
+type NotificationDelivery struct {
+	ID            string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Channel       string `json:"channel" example:"smtp"`
+	Event         string `json:"event" example:"order_confirmed"`
+	Locale        string `json:"locale" example:"en"`
+	Recipient     string `json:"recipient" example:"john@example.com"`
+	RecipientName string `json:"recipient_name" example:"John Doe"`
+	OrderID       string `json:"order_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Subject       string `json:"subject" example:"Booking K7Q2MX confirmed"`
+	Body          string `json:"body"`
+	Status        string `json:"status" example:"sent"` // pending, sent or failed
+	Attempts      int    `json:"attempts" example:"1"`
+	NextAttemptAt string `json:"next_attempt_at,omitempty" example:"2024-12-07T00:00:30Z"`
+	LastError     string `json:"last_error,omitempty" example:"webhook returned 503 Service Unavailable"`
+	SentAt        string `json:"sent_at,omitempty" example:"2024-12-07T00:00:00Z"`
+	CreatedAt     string `json:"created_at" example:"2024-12-07T00:00:00Z"`
+}
+
+type FlightSnapshot struct {
+	ScheduleID        string          `json:"schedule_id" example:"schedule-cgk-dps-ga-001"`
+	AirlineCode       string          `json:"airline_code" example:"GA"`
+	AirlineName       string          `json:"airline_name" example:"Garuda Indonesia"`
+	FlightNumber      string          `json:"flight_number" example:"GA123"`
+	DepartureAirport  AirportSnapshot `json:"departure_airport"`
+	DepartureTerminal string          `json:"departure_terminal,omitempty" example:"3"`
+	DepartureTime     string          `json:"departure_time" example:"08:00"`
+	ArrivalAirport    AirportSnapshot `json:"arrival_airport"`
+	ArrivalTerminal   string          `json:"arrival_terminal,omitempty" example:"D"`
+	ArrivalTime       string          `json:"arrival_time" example:"10:30"`
+	Duration          int             `json:"duration" example:"150"`
+	Aircraft          string          `json:"aircraft" example:"Boeing 737-800"`
+	CabinClass        string          `json:"cabin_class" example:"economy"`
+	UnitPrice         int64           `json:"unit_price" example:"150000000"` // Cabin base fare per seat, in minor units of currency
+	Currency          string          `json:"currency" example:"IDR"`
+}
+
+// AirportSnapshot is an airport as it was when a flight was booked
+type AirportSnapshot struct {
+	Code    string `json:"code" example:"CGK"`
+	Name    string `json:"name" example:"Soekarno-Hatta International Airport"`
+	City    string `json:"city" example:"Jakarta"`
+	Country string `json:"country" example:"ID"`
+}
+
+// PriceBreakdown itemises an order total into fares, taxes and fees
+type PriceBreakdown struct {
+	Currency string          `json:"currency" example:"IDR"`
+	Items    []PriceLineItem `json:"items"`
+	Total    int64           `json:"total" example:"189810000"` // In minor units of currency
+}
+
+// PriceLineItem is one line of a price breakdown
+type PriceLineItem struct {
+	Type          string `json:"type" example:"airport_tax"` // base_fare, airport_tax, fuel_surcharge, service_fee, vat, discount, change_fee, seat, baggage, meal, insurance
+	Name          string `json:"name" example:"Soekarno-Hatta passenger service charge"`
+	FeeRuleID     string `json:"fee_rule_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AncillaryID   string `json:"ancillary_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PassengerType string `json:"passenger_type,omitempty" example:"adult"`
+	Quantity      int    `json:"quantity" example:"2"`
+	UnitAmount    int64  `json:"unit_amount" example:"16000000"`
+	Amount        int64  `json:"amount" example:"32000000"`
 }
 
 // Passenger represents a passenger
 type Passenger struct {
-	ID       string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	OrderID  string `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Title    string `json:"title" example:"Mr"`
-	FullName string `json:"full_name" example:"John Doe"`
-	Type     string `json:"type" example:"adult"`
+	ID                  string               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrderID             string               `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Title               string               `json:"title" example:"Mr"`
+	FullName            string               `json:"full_name" example:"John Doe"`
+	Type                string               `json:"type" example:"adult"`
+	DateOfBirth         string               `json:"date_of_birth,omitempty" example:"1990-05-17"`
+	Nationality         string               `json:"nationality,omitempty" example:"ID"`
+	DocumentType        string               `json:"document_type,omitempty" example:"passport"`
+	DocumentNumber      string               `json:"document_number,omitempty" example:"****5678"` // Masked for non-admins
+	DocumentExpiry      string               `json:"document_expiry,omitempty" example:"2030-01-31"`
+	FrequentFlyerNumber string               `json:"frequent_flyer_number,omitempty" example:"GA123456789"`
+	TicketNumber        string               `json:"ticket_number,omitempty" example:"GA-4821937705"` // Issued once the order is confirmed
+	Seat                *Seat                `json:"seat,omitempty"`
+	Ancillaries         []PassengerAncillary `json:"ancillaries,omitempty"`
+}
+
+// Seat represents a seat held for a passenger
+type Seat struct {
+	ScheduleID  string `json:"schedule_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FlightDate  string `json:"flight_date" example:"2024-12-20T00:00:00Z"`
+	SeatNumber  string `json:"seat_number" example:"12A"`
+	OrderID     string `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PassengerID string `json:"passenger_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Price       int64  `json:"price" example:"7500000"` // In minor units of currency
+	Currency    string `json:"currency" example:"IDR"`
+}
+
+// PassengerAncillary represents an ancillary bought for a passenger
+type PassengerAncillary struct {
+	ID          string `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrderID     string `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PassengerID string `json:"passenger_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AncillaryID string `json:"ancillary_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ScheduleID  string `json:"schedule_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FlightDate  string `json:"flight_date" example:"2024-12-20T00:00:00Z"`
+	Type        string `json:"type" example:"baggage"` // baggage, meal, insurance
+	Name        string `json:"name" example:"Extra baggage 20 kg"`
+	WeightKg    int    `json:"weight_kg,omitempty" example:"20"`
+	Price       int64  `json:"price" example:"45000000"` // In minor units of currency
+	Currency    string `json:"currency" example:"IDR"`
 }
 
 // CreateOrderRequest represents the create order request
 type CreateOrderRequest struct {
-	ScheduleID   string             `json:"schedule_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	FlightDate   string             `json:"flight_date" example:"2024-12-20"`
-	CabinClass   string             `json:"cabin_class" example:"economy"`
-	ContactName  string             `json:"contact_name" example:"John Doe"`
-	ContactEmail string             `json:"contact_email" example:"john@example.com"`
-	ContactPhone string             `json:"contact_phone" example:"+6281234567890"`
-	Passengers   []PassengerRequest `json:"passengers"`
+	ScheduleID    string             `json:"schedule_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FlightDate    string             `json:"flight_date" example:"2024-12-20"`
+	CabinClass    string             `json:"cabin_class" example:"economy"`
+	FareFamilyID  string             `json:"fare_family_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Currency      string             `json:"currency" example:"USD"`
+	PromoCode     string             `json:"promo_code" example:"HOLIDAY10"`
+	SaveTravelers bool               `json:"save_travelers" example:"true"`
+	ContactName   string             `json:"contact_name" example:"John Doe"`
+	ContactEmail  string             `json:"contact_email" example:"john@example.com"`
+	ContactPhone  string             `json:"contact_phone" example:"+6281234567890"`
+	Locale        string             `json:"locale" example:"id"` // Language the contact is notified in, en (default) or id
+	Passengers    []PassengerRequest `json:"passengers"`
+}
+
+// ChangeQuote represents the price of an order change, amounts in minor units
+type ChangeQuote struct {
+	Currency       string         `json:"currency" example:"IDR"`
+	FareDifference int64          `json:"fare_difference" example:"15000000"` // Negative when the new price is lower
+	ChangeFee      int64          `json:"change_fee" example:"10000000"`
+	AmountDue      int64          `json:"amount_due" example:"25000000"`
+	TotalAmount    int64          `json:"total_amount" example:"105000000"` // Order total after the change
+	PriceBreakdown PriceBreakdown `json:"price_breakdown"`
+}
+
+// OrderChange represents an order history entry
+type OrderChange struct {
+	ID             string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrderID        string            `json:"order_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type           string            `json:"type" example:"flight_change"` // flight_change, name_correction, status_change, contact_change, seat_change, ancillary_change
+	ChangedBy      string            `json:"changed_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Before         map[string]string `json:"before"`
+	After          map[string]string `json:"after"`
+	FareDifference int64             `json:"fare_difference" example:"15000000"`
+	ChangeFee      int64             `json:"change_fee" example:"10000000"`
+	Currency       string            `json:"currency,omitempty" example:"IDR"`
+	CreatedAt      string            `json:"created_at" example:"2024-12-08T00:00:00Z"`
+}
+
+// UserChange represents an entry in a user's audit trail
+type UserChange struct {
+	ID        string            `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID    string            `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type      string            `json:"type" example:"role_change"` // role_change, disabled, enabled
+	ChangedBy string            `json:"changed_by" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Reason    string            `json:"reason,omitempty" example:"Left the operations team"`
+	Before    map[string]string `json:"before"`
+	After     map[string]string `json:"after"`
+	CreatedAt string            `json:"created_at" example:"2024-12-08T00:00:00Z"`
+}
+
+// GuestOrderResponse represents a created guest order
+type GuestOrderResponse struct {
+	Order        Order  `json:"order"`
+	BookingToken string `json:"booking_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."` // Send as X-Booking-Token to manage the order
 }
 
 // PassengerRequest represents passenger in order request
 type PassengerRequest struct {
-	Title    string `json:"title" example:"Mr"`
-	FullName string `json:"full_name" example:"John Doe"`
-	Type     string `json:"type" example:"adult"`
+	TravelerID          string   `json:"traveler_id" example:"550e8400-e29b-41d4-a716-446655440000"` // Saved traveler filling the empty fields
+	Title               string   `json:"title" example:"Mr"`
+	FullName            string   `json:"full_name" example:"John Doe"`
+	Type                string   `json:"type" example:"adult"`
+	DateOfBirth         string   `json:"date_of_birth" example:"1990-05-17"`
+	Nationality         string   `json:"nationality" example:"ID"`
+	DocumentType        string   `json:"document_type" example:"passport"`
+	DocumentNumber      string   `json:"document_number" example:"C12345678"`
+	DocumentExpiry      string   `json:"document_expiry" example:"2030-01-31"`
+	FrequentFlyerNumber string   `json:"frequent_flyer_number" example:"GA123456789"`
+	Seat                string   `json:"seat" example:"12A"` // Optional, from the flight seat map
+	Ancillaries         []string `json:"ancillaries"`        // Optional ancillary IDs, from the flight's ancillaries
 }
 
 // PaginatedResponse represents paginated response
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/middleware"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type UserHandler struct {
+	userService services.UserService
+}
+
+func NewUserHandler(userService services.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// List godoc
+// @Summary List users
+// @Description Get a paginated list of user accounts (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse{data=[]User}}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/users [get]
+func (h *UserHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.userService.List(page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list users")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// GetByID godoc
+// @Summary Get user
+// @Description Get a single user account (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} Response{data=User}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/users/{id} [get]
+func (h *UserHandler) GetByID(c *gin.Context) {
+	user, err := h.userService.GetByID(c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "User not found")
+		return
+	}
+
+	SuccessResponse(c, user)
+}
+
+// ChangeRole godoc
+// @Summary Change user role
+// @Description Move a user to another role. Admin tokens issued before stop working straight away (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body services.ChangeRoleRequest true "New role"
+// @Success 200 {object} Response{data=User}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/users/{id}/role [put]
+func (h *UserHandler) ChangeRole(c *gin.Context) {
+	var req services.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	user, err := h.userService.ChangeRole(c.Param("id"), middleware.GetUserID(c), req)
+	if err != nil {
+		userErrorResponse(c, err, "Failed to change role")
+		return
+	}
+
+	SuccessResponse(c, user)
+}
+
+// Disable godoc
+// @Summary Disable user
+// @Description Lock a user out: they can no longer log in or refresh tokens, and every session is revoked (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body services.UserStatusRequest false "Reason for the audit trail"
+// @Success 200 {object} Response{data=User}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/users/{id}/disable [post]
+func (h *UserHandler) Disable(c *gin.Context) {
+	req, ok := bindUserStatus(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.Disable(c.Param("id"), middleware.GetUserID(c), req)
+	if err != nil {
+		userErrorResponse(c, err, "Failed to disable user")
+		return
+	}
+
+	SuccessResponse(c, user)
+}
+
+// Enable godoc
+// @Summary Enable user
+// @Description Let a disabled user log in again (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body services.UserStatusRequest false "Reason for the audit trail"
+// @Success 200 {object} Response{data=User}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/users/{id}/enable [post]
+func (h *UserHandler) Enable(c *gin.Context) {
+	req, ok := bindUserStatus(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.Enable(c.Param("id"), middleware.GetUserID(c), req)
+	if err != nil {
+		userErrorResponse(c, err, "Failed to enable user")
+		return
+	}
+
+	SuccessResponse(c, user)
+}
+
+// History godoc
+// @Summary Get user audit trail
+// @Description List every role change, disable and enable of a user with the admin who made it, oldest first (admin only)
+// @Tags Admin - Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} Response{data=[]UserChange}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/users/{id}/history [get]
+func (h *UserHandler) History(c *gin.Context) {
+	changes, err := h.userService.History(c.Param("id"))
+	if err != nil {
+		userErrorResponse(c, err, "Failed to get user history")
+		return
+	}
+
+	SuccessResponse(c, changes)
+}
+
+// bindUserStatus reads the optional reason sent with a disable or enable
+func bindUserStatus(c *gin.Context) (services.UserStatusRequest, bool) {
+	var req services.UserStatusRequest
+	if c.Request.ContentLength == 0 {
+		return req, true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return req, false
+	}
+	return req, true
+}
+
+// userErrorResponse maps user service errors to responses, failing with message otherwise
+func userErrorResponse(c *gin.Context, err error, message string) {
+	switch err {
+	case services.ErrUserNotFound:
+		NotFoundResponse(c, "User not found")
+	case services.ErrInvalidRole:
+		BadRequestResponse(c, "Role must be user or admin")
+	case services.ErrChangeOwnUser:
+		BadRequestResponse(c, "Admins cannot change their own role or disable themselves")
+	default:
+		InternalServerErrorResponse(c, message)
+	}
+}
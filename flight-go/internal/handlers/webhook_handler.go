@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/services"
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// Create godoc
+// @Summary Create webhook subscription
+// @Description Subscribe a partner URL to an airline's order and whitelist events. The response carries the signing secret, which is not shown again (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body services.CreateWebhookRequest true "Subscription data"
+// @Success 201 {object} Response{data=models.WebhookSubscription}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req services.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	subscription, err := h.webhookService.Create(req)
+	if err != nil {
+		if err == services.ErrInvalidWebhook {
+			BadRequestResponse(c, "Invalid webhook URL or event")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to create webhook subscription")
+		return
+	}
+
+	CreatedResponse(c, subscription)
+}
+
+// GetByID godoc
+// @Summary Get webhook subscription
+// @Description Get a single webhook subscription, without its secret (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} Response{data=models.WebhookSubscription}
+// @Failure 404 {object} ErrorMessageResponse
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	subscription, err := h.webhookService.GetByID(c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "Webhook subscription not found")
+		return
+	}
+
+	SuccessResponse(c, subscription)
+}
+
+// Update godoc
+// @Summary Update webhook subscription
+// @Description Update a webhook subscription, optionally rotating its signing secret (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body services.UpdateWebhookRequest true "Subscription data"
+// @Success 200 {object} Response{data=models.WebhookSubscription}
+// @Failure 400 {object} ErrorMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks/{id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var req services.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		BadRequestResponse(c, err.Error())
+		return
+	}
+
+	subscription, err := h.webhookService.Update(c.Param("id"), req)
+	if err != nil {
+		switch err {
+		case services.ErrWebhookNotFound:
+			NotFoundResponse(c, "Webhook subscription not found")
+		case services.ErrInvalidWebhook:
+			BadRequestResponse(c, "Invalid webhook URL or event")
+		default:
+			InternalServerErrorResponse(c, "Failed to update webhook subscription")
+		}
+		return
+	}
+
+	SuccessResponse(c, subscription)
+}
+
+// Delete godoc
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription, its pending deliveries go to the dead-letter list (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} SuccessMessageResponse
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	if err := h.webhookService.Delete(c.Param("id")); err != nil {
+		if err == services.ErrWebhookNotFound {
+			NotFoundResponse(c, "Webhook subscription not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to delete webhook subscription")
+		return
+	}
+
+	SuccessResponse(c, gin.H{"message": "Webhook subscription deleted successfully"})
+}
+
+// List godoc
+// @Summary List webhook subscriptions
+// @Description Get a paginated list of webhook subscriptions, without their secrets (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param airline_id query string false "Airline ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.webhookService.List(c.Query("airline_id"), page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list webhook subscriptions")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Get a paginated delivery log of one webhook subscription, newest first (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param status query string false "Delivery status (pending, delivered, dead)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	subscription, err := h.webhookService.GetByID(c.Param("id"))
+	if err != nil {
+		NotFoundResponse(c, "Webhook subscription not found")
+		return
+	}
+
+	h.listDeliveries(c, subscription.ID, models.WebhookDeliveryStatus(c.Query("status")))
+}
+
+// DeadLetters godoc
+// @Summary List dead webhook deliveries
+// @Description Get a paginated list of the deliveries that ran out of attempts or lost their subscription, newest first, across every subscription (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} Response{data=PaginatedResponse}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks/dead-letters [get]
+func (h *WebhookHandler) DeadLetters(c *gin.Context) {
+	h.listDeliveries(c, "", models.WebhookDeliveryDead)
+}
+
+func (h *WebhookHandler) listDeliveries(c *gin.Context, subscriptionID string, status models.WebhookDeliveryStatus) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	result, err := h.webhookService.ListDeliveries(subscriptionID, status, page, pageSize)
+	if err != nil {
+		InternalServerErrorResponse(c, "Failed to list webhook deliveries")
+		return
+	}
+
+	SuccessResponse(c, result)
+}
+
+// Redeliver godoc
+// @Summary Redeliver webhook
+// @Description Queue a delivery again with the same payload and a fresh set of attempts (admin only)
+// @Tags Admin - Webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} Response{data=models.WebhookDelivery}
+// @Failure 401 {object} ErrorMessageResponse
+// @Failure 403 {object} ErrorMessageResponse
+// @Failure 404 {object} ErrorMessageResponse
+// @Failure 500 {object} ErrorMessageResponse
+// @Router /admin/webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	delivery, err := h.webhookService.Redeliver(c.Param("id"))
+	if err != nil {
+		if err == services.ErrWebhookDeliveryNotFound {
+			NotFoundResponse(c, "Webhook delivery not found")
+			return
+		}
+		InternalServerErrorResponse(c, "Failed to redeliver webhook")
+		return
+	}
+
+	SuccessResponse(c, delivery)
+}
@@ -35,6 +35,11 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		}
 
 		claims, err := m.authService.ValidateToken(tokenString)
+		if err == services.ErrTokenRevoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
@@ -45,6 +50,7 @@ func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
 		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
@@ -97,6 +103,15 @@ func GetUserEmail(c *gin.Context) string {
 	return ""
 }
 
+// GetSessionID helper to get the session the access token belongs to from context
+func GetSessionID(c *gin.Context) string {
+	sessionID, _ := c.Get("session_id")
+	if id, ok := sessionID.(string); ok {
+		return id
+	}
+	return ""
+}
+
 // OptionalAuth middleware tries to extract user info but doesn't require authentication
 // This allows public endpoints to still check whitelist status if user is logged in
 func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
@@ -111,6 +126,7 @@ func (m *AuthMiddleware) OptionalAuth() gin.HandlerFunc {
 					c.Set("user_id", claims.UserID)
 					c.Set("user_email", claims.Email)
 					c.Set("user_role", claims.Role)
+					c.Set("session_id", claims.SessionID)
 				}
 			}
 		}
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/cache"
+)
+
+// IdempotencyKeyHeader is the request header clients send to make a call safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed from an earlier request with the same key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const maxIdempotencyKeyLength = 255
+
+// Idempotency replays the stored response of a request when it is retried with the same
+// Idempotency-Key, so network retries cannot create or cancel twice
+type Idempotency struct {
+	store cache.Cache
+	ttl   time.Duration
+
+	// Guards the check-and-reserve of a key, so two concurrent retries cannot both run
+	mu sync.Mutex
+}
+
+// idempotentResponse is what is stored under a key, Pending until the first request finishes
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Pending     bool   `json:"pending,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// NewIdempotency keeps responses in store for ttl after the request that produced them
+func NewIdempotency(store cache.Cache, ttl time.Duration) *Idempotency {
+	return &Idempotency{store: store, ttl: ttl}
+}
+
+// Deduplicate middleware runs a request carrying an Idempotency-Key once per key. A retry with
+// the same method, path and body gets the stored response, while reusing the key for a different
+// request, or while the first one is still running, returns 409 Conflict. Requests without the
+// header run as usual. Keys are scoped to the caller, the user when authenticated and otherwise
+// the client IP, so it must run after the auth middleware.
+func (m *Idempotency) Deduplicate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		storeKey := idempotencyStoreKey(c, key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		stored, err := m.reserve(ctx, storeKey, fingerprint)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key"})
+			c.Abort()
+			return
+		}
+		if stored != nil {
+			switch {
+			case stored.Fingerprint != fingerprint:
+				c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			case stored.Pending:
+				c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still being processed"})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(stored.Status, stored.ContentType, stored.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		// The key is released unless a response is stored, so a handler that panics or a server
+		// error doesn't leave it pending and the client can retry with the same key
+		saved := false
+		defer func() {
+			if !saved {
+				m.store.Delete(context.Background(), storeKey)
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// The response is stored even when the client has gone away, since that is when it retries
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		saved = m.save(context.Background(), storeKey, &idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}) == nil
+	}
+}
+
+// reserve returns the response stored under key, or marks key pending and returns nil when
+// there is none
+func (m *Idempotency) reserve(ctx context.Context, key, fingerprint string) (*idempotentResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok, err := m.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		var stored idempotentResponse
+		if err := json.Unmarshal(data, &stored); err == nil {
+			return &stored, nil
+		}
+	}
+
+	return nil, m.save(ctx, key, &idempotentResponse{Fingerprint: fingerprint, Pending: true})
+}
+
+func (m *Idempotency) save(ctx context.Context, key string, response *idempotentResponse) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return m.store.Set(ctx, key, data, m.ttl)
+}
+
+func idempotencyStoreKey(c *gin.Context, key string) string {
+	if userID := GetUserID(c); userID != "" {
+		return "idempotency:user:" + userID + ":" + key
+	}
+	return "idempotency:ip:" + c.ClientIP() + ":" + key
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingWriter keeps a copy of the response body as it is written
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
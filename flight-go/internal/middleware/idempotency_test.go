@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mirahekatiket/flight-go/internal/cache"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// idempotentServer serves POST /orders through Deduplicate, answering with handle
+func idempotentServer(handle gin.HandlerFunc) *gin.Engine {
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	engine.POST("/orders", NewIdempotency(cache.NewMemory(100), time.Hour).Deduplicate(), handle)
+	return engine
+}
+
+func post(engine *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls atomic.Int64
+	engine := idempotentServer(func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"call": calls.Add(1)})
+	})
+
+	first := post(engine, "key-1", `{"a":1}`)
+	retry := post(engine, "key-1", `{"a":1}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if first.Header().Get(IdempotentReplayedHeader) != "" || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("%s = %q then %q, want only the retry marked", IdempotentReplayedHeader, first.Header().Get(IdempotentReplayedHeader), retry.Header().Get(IdempotentReplayedHeader))
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want once", calls.Load())
+	}
+
+	// A different body under the same key is another request, and requests without a key always run
+	if rec := post(engine, "key-1", `{"a":2}`); rec.Code != http.StatusConflict {
+		t.Errorf("same key, different body = %d, want 409", rec.Code)
+	}
+	post(engine, "", `{"a":1}`)
+	post(engine, "", `{"a":1}`)
+	if calls.Load() != 3 {
+		t.Errorf("handler ran %d times, want 3", calls.Load())
+	}
+}
+
+func TestIdempotencyConflictWhileRunning(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	engine := idempotentServer(func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- post(engine, "key-1", `{}`) }()
+	<-started
+
+	if rec := post(engine, "key-1", `{}`); rec.Code != http.StatusConflict {
+		t.Errorf("retry while the first request runs = %d, want 409", rec.Code)
+	}
+	close(release)
+	if rec := <-done; rec.Code != http.StatusCreated {
+		t.Errorf("first request = %d, want 201", rec.Code)
+	}
+}
+
+// Neither a server error nor a panic keeps the key, the client retries with it and the handler runs again
+func TestIdempotencyReleasesKeyOnFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(c *gin.Context)
+	}{
+		{name: "server error", fail: func(c *gin.Context) { c.JSON(http.StatusServiceUnavailable, gin.H{}) }},
+		{name: "panic", fail: func(c *gin.Context) { panic("handler crashed") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			engine := idempotentServer(func(c *gin.Context) {
+				if calls.Add(1) == 1 {
+					tt.fail(c)
+					return
+				}
+				c.JSON(http.StatusCreated, gin.H{})
+			})
+
+			if rec := post(engine, "key-1", `{}`); rec.Code < http.StatusInternalServerError {
+				t.Fatalf("failing request = %d, want a server error", rec.Code)
+			}
+			if rec := post(engine, "key-1", `{}`); rec.Code != http.StatusCreated || rec.Header().Get(IdempotentReplayedHeader) != "" {
+				t.Errorf("retry = %d, replayed %q, want 201 from the handler", rec.Code, rec.Header().Get(IdempotentReplayedHeader))
+			}
+			if calls.Load() != 2 {
+				t.Errorf("handler ran %d times, want twice", calls.Load())
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter allows each client IP a fixed number of requests per window
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*rateWindow
+	swept   time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter allows limit requests per window, a limit of 0 or less allows everything
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+		swept:   time.Now(),
+	}
+}
+
+// Limit middleware rejects clients over their allowance with 429 Too Many Requests
+func (l *RateLimiter) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if l.limit <= 0 {
+			c.Next()
+			return
+		}
+
+		if retryAfter, ok := l.allow(c.ClientIP(), time.Now()); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.5)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, try again later"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// allow counts a request from client, returning how long to wait when it is over the limit
+func (l *RateLimiter) allow(client string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget clients whose window has passed, so the map only holds recent callers
+	if now.Sub(l.swept) > l.window {
+		for key, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, key)
+			}
+		}
+		l.swept = now
+	}
+
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.clients[client] = w
+	}
+	if w.count >= l.limit {
+		return w.start.Add(l.window).Sub(now), false
+	}
+	w.count++
+	return 0, true
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Ancillary is an add-on an airline sells with its tickets, bought per passenger and flight
+type Ancillary struct {
+	BaseModel
+	AirlineID   string     `json:"airline_id" gorm:"not null;uniqueIndex:idx_ancillary_code"`
+	Type        ChargeType `json:"type" gorm:"not null"` // baggage, meal, insurance
+	Code        string     `json:"code" gorm:"not null;uniqueIndex:idx_ancillary_code"`
+	Name        string     `json:"name" gorm:"not null"`
+	Description string     `json:"description"`
+	WeightKg    int        `json:"weight_kg,omitempty"` // Extra checked baggage of a baggage tier
+	Price       float64    `json:"price"`               // Per passenger and flight, in Currency
+	Currency    string     `json:"currency" gorm:"type:varchar(3);not null;default:'IDR'"`
+	SortOrder   int        `json:"sort_order" gorm:"default:0"`
+	IsActive    bool       `json:"is_active" gorm:"default:true"`
+}
+
+// IsAncillaryType reports whether t is a charge sold as an ancillary
+func IsAncillaryType(t ChargeType) bool {
+	switch t {
+	case ChargeBaggage, ChargeMeal, ChargeInsurance:
+		return true
+	}
+	return false
+}
+
+// AncillaryOffer is an ancillary priced in the currency a buyer asked for
+type AncillaryOffer struct {
+	Ancillary
+	OfferPrice    float64 `json:"offer_price"`
+	OfferCurrency string  `json:"offer_currency"`
+}
+
+// OrderAncillary is an ancillary bought for a passenger on one flight segment of an order.
+// It keeps the name and price it was sold at, later catalogue changes do not touch it.
+type OrderAncillary struct {
+	BaseModel
+	OrderID     string     `json:"order_id" gorm:"not null;index"`
+	PassengerID string     `json:"passenger_id" gorm:"not null;index"`
+	AncillaryID string     `json:"ancillary_id" gorm:"not null"`
+	ScheduleID  string     `json:"schedule_id" gorm:"not null"` // The segment, moved along with flight changes
+	FlightDate  time.Time  `json:"flight_date" gorm:"not null"`
+	Type        ChargeType `json:"type" gorm:"not null"`
+	Name        string     `json:"name" gorm:"not null"`
+	WeightKg    int        `json:"weight_kg,omitempty"`
+	Price       int64      `json:"price"` // In minor units of Currency
+	Currency    string     `json:"currency" gorm:"type:varchar(3)"`
+}
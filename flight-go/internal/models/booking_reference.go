@@ -0,0 +1,42 @@
+package models
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// pnrAlphabet leaves out 0/O and 1/I so references survive being read over the phone
+const pnrAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const pnrLength = 6
+
+// NewPNR returns a random 6-character booking reference.
+// It is not guaranteed unique, callers retry when one collides.
+func NewPNR() string {
+	b := make([]byte, pnrLength)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	for i := range b {
+		// 256 is a multiple of the alphabet size, so the modulo is unbiased
+		b[i] = pnrAlphabet[int(b[i])%len(pnrAlphabet)]
+	}
+	return string(b)
+}
+
+// NewTicketNumber returns a random e-ticket number for the airline, e.g. "GA-4821937465".
+// Like NewPNR, callers retry when one collides.
+func NewTicketNumber(airlineCode string) string {
+	serial, err := rand.Int(rand.Reader, big.NewInt(1e10))
+	if err != nil {
+		panic(err)
+	}
+	return airlineCode + "-" + leftPad(serial.String(), 10)
+}
+
+func leftPad(s string, width int) string {
+	for len(s) < width {
+		s = "0" + s
+	}
+	return s
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewPNR(t *testing.T) {
+	pattern := regexp.MustCompile(`^[A-HJ-NP-Z2-9]{6}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		pnr := NewPNR()
+		if !pattern.MatchString(pnr) {
+			t.Fatalf("NewPNR() = %q, want 6 characters without 0, O, 1 or I", pnr)
+		}
+		seen[pnr] = true
+	}
+	// 32^6 references, a handful of repeats in a thousand would mean a broken generator
+	if len(seen) < 995 {
+		t.Errorf("%d distinct PNRs in 1000", len(seen))
+	}
+}
+
+func TestNewTicketNumber(t *testing.T) {
+	pattern := regexp.MustCompile(`^GA-[0-9]{10}$`)
+	for i := 0; i < 100; i++ {
+		if number := NewTicketNumber("GA"); !pattern.MatchString(number) {
+			t.Fatalf("NewTicketNumber(GA) = %q, want GA- and 10 digits", number)
+		}
+	}
+}
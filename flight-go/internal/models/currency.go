@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCurrency is the currency of airlines and schedules without one
+const DefaultCurrency = "IDR"
+
+// currencyExponents maps supported ISO 4217 codes to their number of minor unit digits
+var currencyExponents = map[string]int{
+	"IDR": 2,
+	"USD": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"THB": 2,
+	"PHP": 2,
+	"AUD": 2,
+	"EUR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+}
+
+// IsSupportedCurrency reports whether code is a currency prices can be quoted in
+func IsSupportedCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// ToMinorUnits converts an amount in major units (e.g. dollars) to minor units (e.g. cents)
+func ToMinorUnits(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(currencyExponents[currency])))
+}
+
+// FromMinorUnits converts an amount in minor units back to major units
+func FromMinorUnits(amount int64, currency string) float64 {
+	return float64(amount) / math.Pow10(currencyExponents[currency])
+}
+
+// RoundToCurrency rounds a major unit amount to the currency's smallest unit
+func RoundToCurrency(amount float64, currency string) float64 {
+	return FromMinorUnits(ToMinorUnits(amount, currency), currency)
+}
+
+// FormatAmount renders an amount in minor units for people, e.g. "IDR 1,250,000.00"
+func FormatAmount(amount int64, currency string) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	exponent := currencyExponents[currency]
+	unit := int64(math.Pow10(exponent))
+	digits := strconv.FormatInt(amount/unit, 10)
+
+	var major strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			major.WriteByte(',')
+		}
+		major.WriteRune(d)
+	}
+
+	if exponent == 0 {
+		return currency + " " + sign + major.String()
+	}
+	return fmt.Sprintf("%s %s%s.%0*d", currency, sign, major.String(), exponent, amount%unit)
+}
+
+// ExchangeRate converts FromCurrency to ToCurrency from EffectiveFrom until a later rate for the pair takes over
+type ExchangeRate struct {
+	BaseModel
+	FromCurrency  string    `json:"from_currency" gorm:"type:varchar(3);not null;index:idx_exchange_rate_pair"`
+	ToCurrency    string    `json:"to_currency" gorm:"type:varchar(3);not null;index:idx_exchange_rate_pair"`
+	Rate          float64   `json:"rate" gorm:"not null"` // 1 FromCurrency = Rate ToCurrency
+	EffectiveFrom time.Time `json:"effective_from" gorm:"not null;index"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "testing"
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		minor    int64
+		rounded  float64
+	}{
+		{name: "rupiah keeps two decimals", amount: 1250000.5, currency: "IDR", minor: 125000050, rounded: 1250000.5},
+		{name: "dollars round half away from zero", amount: 10.005, currency: "USD", minor: 1001, rounded: 10.01},
+		{name: "dollars round down", amount: 10.004, currency: "USD", minor: 1000, rounded: 10},
+		{name: "yen has no minor unit", amount: 1234.6, currency: "JPY", minor: 1235, rounded: 1235},
+		{name: "negative amounts", amount: -19.999, currency: "USD", minor: -2000, rounded: -20},
+		{name: "float error does not leak into cents", amount: 0.1 + 0.2, currency: "USD", minor: 30, rounded: 0.3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToMinorUnits(tt.amount, tt.currency); got != tt.minor {
+				t.Errorf("ToMinorUnits(%v, %s) = %d, want %d", tt.amount, tt.currency, got, tt.minor)
+			}
+			if got := FromMinorUnits(tt.minor, tt.currency); got != RoundToCurrency(tt.amount, tt.currency) {
+				t.Errorf("FromMinorUnits(%d, %s) = %v, want %v", tt.minor, tt.currency, got, RoundToCurrency(tt.amount, tt.currency))
+			}
+			if got := RoundToCurrency(tt.amount, tt.currency); got != tt.rounded {
+				t.Errorf("RoundToCurrency(%v, %s) = %v, want %v", tt.amount, tt.currency, got, tt.rounded)
+			}
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{125000000, "IDR", "IDR 1,250,000.00"},
+		{105, "USD", "USD 1.05"},
+		{-123456, "USD", "USD -1,234.56"},
+		{1000000, "JPY", "JPY 1,000,000"},
+		{0, "SGD", "SGD 0.00"},
+		{999, "KRW", "KRW 999"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatAmount(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestIsSupportedCurrency(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"IDR", true},
+		{"JPY", true},
+		{"idr", false},
+		{"XXX", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsSupportedCurrency(tt.code); got != tt.want {
+			t.Errorf("IsSupportedCurrency(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+// FareFamily is a branded fare sold within an airline's cabin, e.g. "Economy Saver" vs "Economy Flex"
+type FareFamily struct {
+	BaseModel
+	AirlineID        string     `json:"airline_id" gorm:"not null;uniqueIndex:idx_fare_family_code"`
+	CabinClass       CabinClass `json:"cabin_class" gorm:"not null;uniqueIndex:idx_fare_family_code"`
+	Code             string     `json:"code" gorm:"not null;uniqueIndex:idx_fare_family_code"`
+	Name             string     `json:"name" gorm:"not null"`
+	PriceMultiplier  float64    `json:"price_multiplier" gorm:"not null;default:1"` // Applied to the cabin's dynamic fare
+	BaggageKg        int        `json:"baggage_kg"`
+	CabinBaggageKg   int        `json:"cabin_baggage_kg"`
+	Changeable       bool       `json:"changeable"`
+	ChangeFee        float64    `json:"change_fee"`
+	Refundable       bool       `json:"refundable"`
+	RefundFeePercent float64    `json:"refund_fee_percent"`       // % of the fare withheld on refund
+	SeatBucket       int        `json:"seat_bucket"`              // Seats sold per flight date, 0 = whole cabin
+	ChildPercent     *float64   `json:"child_percent,omitempty"`  // Overrides the airline's child_percent
+	InfantPercent    *float64   `json:"infant_percent,omitempty"` // Overrides the airline's infant_percent
+	SortOrder        int        `json:"sort_order" gorm:"default:0"`
+	IsActive         bool       `json:"is_active" gorm:"default:true"`
+}
+
+// FareFamilyPrice is the fare family step of a fare quote
+type FareFamilyPrice struct {
+	ID         string  `json:"id"`
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Multiplier float64 `json:"multiplier"`
+	Amount     float64 `json:"amount"`
+}
+
+// FareFamilyOffer is a fare family priced for one flight date
+type FareFamilyOffer struct {
+	FareFamily
+	Fare      float64 `json:"fare"`
+	SeatsLeft int     `json:"seats_left"`
+}
@@ -0,0 +1,57 @@
+package models
+
+// ChargeType identifies a line in an order's price breakdown
+type ChargeType string
+
+const (
+	ChargeBaseFare      ChargeType = "base_fare"      // Fare per passenger type
+	ChargeAirportTax    ChargeType = "airport_tax"    // Fixed per seated passenger departing an airport
+	ChargeFuelSurcharge ChargeType = "fuel_surcharge" // Fixed per seated passenger flying an airline
+	ChargeServiceFee    ChargeType = "service_fee"    // Fixed per order
+	ChargeVAT           ChargeType = "vat"            // % of fares, fuel surcharges and service fees
+	ChargeDiscount      ChargeType = "discount"       // Promo code discount, a negative amount
+	ChargeChangeFee     ChargeType = "change_fee"     // Per passenger for each change to a booked order
+	ChargeSeat          ChargeType = "seat"           // Price of a selected seat's zone
+	ChargeBaggage       ChargeType = "baggage"        // Extra checked baggage ancillary
+	ChargeMeal          ChargeType = "meal"           // Meal ancillary
+	ChargeInsurance     ChargeType = "insurance"      // Travel insurance ancillary
+)
+
+// FeeRule is a tax, fee or surcharge added on top of the fare
+type FeeRule struct {
+	BaseModel
+	Name       string     `json:"name" gorm:"not null"`
+	Type       ChargeType `json:"type" gorm:"not null"`
+	AirportID  string     `json:"airport_id"`  // Departure airport, empty applies to all
+	AirlineID  string     `json:"airline_id"`  // Empty applies to all airlines
+	CabinClass CabinClass `json:"cabin_class"` // Empty applies to all cabins
+	Amount     float64    `json:"amount"`      // Fixed fees, in Currency
+	Currency   string     `json:"currency" gorm:"type:varchar(3);not null;default:'IDR'"`
+	Percent    float64    `json:"percent"` // VAT rate
+	IsActive   bool       `json:"is_active" gorm:"default:true"`
+}
+
+// PriceLineItem is one itemised charge, amounts in minor units of the breakdown's currency
+type PriceLineItem struct {
+	Type          ChargeType    `json:"type"`
+	Name          string        `json:"name"`
+	FeeRuleID     string        `json:"fee_rule_id,omitempty"`
+	AncillaryID   string        `json:"ancillary_id,omitempty"`
+	PassengerType PassengerType `json:"passenger_type,omitempty"`
+	Quantity      int           `json:"quantity"`
+	UnitAmount    int64         `json:"unit_amount"`
+	Amount        int64         `json:"amount"`
+}
+
+// PriceBreakdown itemises a price into fares, taxes and fees
+type PriceBreakdown struct {
+	Currency string          `json:"currency"`
+	Items    []PriceLineItem `json:"items"`
+	Total    int64           `json:"total"` // In minor units of Currency
+}
+
+// Add appends a line item and adds it to the total
+func (b *PriceBreakdown) Add(item PriceLineItem) {
+	b.Items = append(b.Items, item)
+	b.Total += item.Amount
+}
@@ -0,0 +1,70 @@
+package models
+
+// FlightSnapshot is the flight an order was booked on, as it was sold. It is kept with the order,
+// so later edits to the schedule, or its deletion, do not change what the order shows.
+type FlightSnapshot struct {
+	ScheduleID        string          `json:"schedule_id"`
+	AirlineID         string          `json:"airline_id"`
+	AirlineCode       string          `json:"airline_code"`
+	AirlineName       string          `json:"airline_name"`
+	FlightNumber      string          `json:"flight_number"`
+	DepartureAirport  AirportSnapshot `json:"departure_airport"`
+	DepartureTerminal string          `json:"departure_terminal,omitempty"`
+	DepartureTime     string          `json:"departure_time"` // HH:MM format
+	ArrivalAirport    AirportSnapshot `json:"arrival_airport"`
+	ArrivalTerminal   string          `json:"arrival_terminal,omitempty"`
+	ArrivalTime       string          `json:"arrival_time"` // HH:MM format
+	Duration          int             `json:"duration"`     // in minutes
+	Aircraft          string          `json:"aircraft"`
+	CabinClass        CabinClass      `json:"cabin_class"`
+	UnitPrice         int64           `json:"unit_price"` // Base fare of the cabin per seat, in minor units of Currency
+	Currency          string          `json:"currency"`
+}
+
+// AirportSnapshot is an airport as it was when a flight was booked
+type AirportSnapshot struct {
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+// NewFlightSnapshot captures schedule as sold in cabin. The schedule's airline and airports
+// should be loaded.
+func NewFlightSnapshot(schedule *Schedule, cabin CabinClass) *FlightSnapshot {
+	currency := schedule.PriceCurrency()
+	snapshot := &FlightSnapshot{
+		ScheduleID:        schedule.ID,
+		AirlineID:         schedule.AirlineID,
+		FlightNumber:      schedule.FlightNumber,
+		DepartureAirport:  newAirportSnapshot(schedule.DepartureAirport),
+		DepartureTerminal: schedule.DepartureTerminal,
+		DepartureTime:     schedule.DepartureTime,
+		ArrivalAirport:    newAirportSnapshot(schedule.ArrivalAirport),
+		ArrivalTerminal:   schedule.ArrivalTerminal,
+		ArrivalTime:       schedule.ArrivalTime,
+		Duration:          schedule.Duration,
+		Aircraft:          schedule.Aircraft,
+		CabinClass:        cabin,
+		UnitPrice:         ToMinorUnits(schedule.PriceFor(cabin), currency),
+		Currency:          currency,
+	}
+	if schedule.Airline != nil {
+		snapshot.AirlineCode = schedule.Airline.Code
+		snapshot.AirlineName = schedule.Airline.Name
+	}
+	return snapshot
+}
+
+// ChangedFrom reports whether schedule, the live version of the snapshot's flight, no longer
+// matches it. A deleted schedule, passed as nil, has changed.
+func (f *FlightSnapshot) ChangedFrom(schedule *Schedule) bool {
+	return schedule == nil || *NewFlightSnapshot(schedule, f.CabinClass) != *f
+}
+
+func newAirportSnapshot(airport *Airport) AirportSnapshot {
+	if airport == nil {
+		return AirportSnapshot{}
+	}
+	return AirportSnapshot{Code: airport.Code, Name: airport.Name, City: airport.City, Country: airport.Country}
+}
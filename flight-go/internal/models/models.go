@@ -30,32 +30,51 @@ const (
 	RoleAdmin Role = "admin"
 )
 
+// Environment identifies which schedule database data lives in
+type Environment string
+
+const (
+	EnvStaging    Environment = "staging"
+	EnvProduction Environment = "production"
+)
+
 // User model
 type User struct {
 	BaseModel
-	Email    string `json:"email" gorm:"uniqueIndex;not null"`
-	Password string `json:"-" gorm:"not null"`
-	Name     string `json:"name" gorm:"not null"`
-	Phone    string `json:"phone"`
-	Role     Role   `json:"role" gorm:"default:user"`
+	Email        string     `json:"email" gorm:"uniqueIndex;not null"`
+	Password     string     `json:"-" gorm:"not null"`
+	Name         string     `json:"name" gorm:"not null"`
+	Phone        string     `json:"phone"`
+	Role         Role       `json:"role" gorm:"default:user"`
+	TokenVersion int        `json:"-" gorm:"not null;default:0"` // Bumped on role changes and disables, tokens carrying an older one are stale
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"` // Set once the user proved they own Email
+}
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	return r == RoleUser || r == RoleAdmin
 }
 
 // Airline model
 type Airline struct {
 	BaseModel
-	Code      string     `json:"code" gorm:"uniqueIndex;not null;type:varchar(3)"`
-	Name      string     `json:"name" gorm:"not null"`
-	Logo      string     `json:"logo"`
-	IsActive  bool       `json:"is_active" gorm:"default:true"`
-	Schedules []Schedule `json:"schedules,omitempty" gorm:"foreignKey:AirlineID"`
+	Code           string          `json:"code" gorm:"uniqueIndex;not null;type:varchar(3)"`
+	Name           string          `json:"name" gorm:"not null"`
+	Logo           string          `json:"logo"`
+	IsActive       bool            `json:"is_active" gorm:"default:true"`
+	Currency       string          `json:"currency" gorm:"type:varchar(3);not null;default:'IDR'"` // Default currency of the airline's schedules
+	PassengerRules *PassengerRules `json:"passenger_rules,omitempty" gorm:"serializer:json"` // Nil uses DefaultPassengerRules
+	Schedules      []Schedule      `json:"schedules,omitempty" gorm:"foreignKey:AirlineID"`
 }
 
 // Airport model
 type Airport struct {
 	BaseModel
-	Code string `json:"code" gorm:"uniqueIndex;not null;type:varchar(3)"`
-	Name string `json:"name" gorm:"not null"`
-	City string `json:"city" gorm:"not null"`
+	Code    string `json:"code" gorm:"uniqueIndex;not null;type:varchar(3)"`
+	Name    string `json:"name" gorm:"not null"`
+	City    string `json:"city" gorm:"not null"`
+	Country string `json:"country" gorm:"type:varchar(2);not null;default:'ID'"` // ISO 3166-1 alpha-2
 }
 
 // Schedule model (flight schedule)
@@ -78,10 +97,58 @@ type Schedule struct {
 	EconomyPrice        float64   `json:"economy_price" gorm:"default:0"`
 	BusinessPrice       float64   `json:"business_price" gorm:"default:0"`
 	FirstClassPrice     float64   `json:"first_class_price" gorm:"default:0"`
+	Currency            string    `json:"currency,omitempty" gorm:"type:varchar(3)"` // Currency of the prices, empty uses the airline's
 	EconomySeats        int       `json:"economy_seats" gorm:"default:150"`
 	BusinessSeats       int       `json:"business_seats" gorm:"default:30"`
 	FirstClassSeats     int       `json:"first_class_seats" gorm:"default:10"`
 	IsActive            bool      `json:"is_active" gorm:"default:true"`
+	Fare                *FareQuote `json:"fare,omitempty" gorm:"-"` // Quoted for a flight date, not stored
+	FareFamilies        []FareFamilyOffer `json:"fare_families,omitempty" gorm:"-"`
+	PricePreview        *PriceBreakdown   `json:"price_preview,omitempty" gorm:"-"` // Taxes and fees for one adult
+}
+
+// PriceFor returns the base fare of a cabin
+func (s *Schedule) PriceFor(cabin CabinClass) float64 {
+	switch cabin {
+	case CabinBusiness:
+		return s.BusinessPrice
+	case CabinFirst:
+		return s.FirstClassPrice
+	default:
+		return s.EconomyPrice
+	}
+}
+
+// PriceCurrency returns the currency the schedule's prices are in
+func (s *Schedule) PriceCurrency() string {
+	if s.Currency != "" {
+		return s.Currency
+	}
+	if s.Airline != nil && s.Airline.Currency != "" {
+		return s.Airline.Currency
+	}
+	return DefaultCurrency
+}
+
+// DepartureOn returns when the flight leaves on flightDate, in the same clock as flight dates
+func (s *Schedule) DepartureOn(flightDate time.Time) time.Time {
+	departure, err := time.Parse("15:04", s.DepartureTime)
+	if err != nil {
+		return flightDate
+	}
+	return flightDate.Add(time.Duration(departure.Hour())*time.Hour + time.Duration(departure.Minute())*time.Minute)
+}
+
+// SeatsFor returns the seat capacity of a cabin
+func (s *Schedule) SeatsFor(cabin CabinClass) int {
+	switch cabin {
+	case CabinBusiness:
+		return s.BusinessSeats
+	case CabinFirst:
+		return s.FirstClassSeats
+	default:
+		return s.EconomySeats
+	}
 }
 
 // CabinClass type
@@ -106,18 +173,32 @@ const (
 // Order model
 type Order struct {
 	BaseModel
-	UserID         string       `json:"user_id" gorm:"not null"`
+	PNR            string       `json:"pnr" gorm:"type:varchar(6);uniqueIndex"` // Booking reference
+	UserID         string       `json:"user_id" gorm:"not null"` // Empty for guest orders
 	User           *User        `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Environment    Environment  `json:"environment" gorm:"type:varchar(10);not null;default:staging;index"` // Database the schedule lives in
 	ScheduleID     string       `json:"schedule_id" gorm:"not null"`
-	Schedule       *Schedule    `json:"schedule,omitempty" gorm:"foreignKey:ScheduleID"`
+	Schedule       *Schedule    `json:"-" gorm:"-"` // The live schedule, loaded from the Environment's database
+	Flight         *FlightSnapshot `json:"flight,omitempty" gorm:"serializer:json"` // The flight as booked, orders show this rather than the live schedule
+	ScheduleChanged bool        `json:"schedule_changed" gorm:"-"` // The live schedule no longer matches Flight, or was deleted
+	DisruptionID   string       `json:"disruption_id,omitempty" gorm:"index"` // The latest material change to the flight
+	Disruption     *ScheduleDisruption `json:"disruption,omitempty" gorm:"foreignKey:DisruptionID"`
+	DisruptionStatus DisruptionStatus `json:"disruption_status,omitempty"`
 	FlightDate     time.Time    `json:"flight_date" gorm:"not null"`
 	CabinClass     CabinClass   `json:"cabin_class" gorm:"not null"`
+	FareFamilyID   string       `json:"fare_family_id,omitempty"`
 	TotalPassenger int          `json:"total_passenger" gorm:"not null"`
-	TotalAmount    float64      `json:"total_amount" gorm:"not null"`
+	TotalAmount    int64        `json:"total_amount" gorm:"column:total_amount_minor;not null;default:0"` // In minor units of Currency
+	Currency       string       `json:"currency" gorm:"type:varchar(3);not null;default:'IDR'"`
+	FareQuote      *FareQuote   `json:"fare_quote,omitempty" gorm:"serializer:json"` // Price breakdown at booking time
+	PriceBreakdown *PriceBreakdown `json:"price_breakdown,omitempty" gorm:"serializer:json"` // Fares, taxes and fees making up TotalAmount
+	PromotionID    string       `json:"promotion_id,omitempty" gorm:"index"`
+	PromoCode      string       `json:"promo_code,omitempty"`
 	Status         OrderStatus  `json:"status" gorm:"default:pending"`
 	ContactName    string       `json:"contact_name" gorm:"not null"`
 	ContactEmail   string       `json:"contact_email" gorm:"not null"`
 	ContactPhone   string       `json:"contact_phone" gorm:"not null"`
+	Locale         string       `json:"locale" gorm:"type:varchar(5);not null;default:'en'"` // Language the contact is notified in
 	Passengers     []Passenger  `json:"passengers,omitempty" gorm:"foreignKey:OrderID"`
 }
 
@@ -133,9 +214,21 @@ const (
 // Passenger model
 type Passenger struct {
 	BaseModel
-	OrderID  string        `json:"order_id" gorm:"not null"`
-	Title    string        `json:"title" gorm:"not null"` // Mr, Mrs, Ms
-	FullName string        `json:"full_name" gorm:"not null"`
-	Type     PassengerType `json:"type" gorm:"not null"`
+	OrderID     string        `json:"order_id" gorm:"not null"`
+	Title       string        `json:"title" gorm:"not null"` // Mr, Mrs, Ms
+	FullName    string        `json:"full_name" gorm:"not null"`
+	Type        PassengerType `json:"type" gorm:"not null"`
+	DateOfBirth *time.Time    `json:"date_of_birth,omitempty"`
+
+	// Travel document for ticketing, the number is encrypted at rest
+	Nationality         string       `json:"nationality,omitempty" gorm:"type:varchar(2)"` // ISO 3166-1 alpha-2
+	DocumentType        DocumentType `json:"document_type,omitempty"`
+	DocumentNumber      string       `json:"document_number,omitempty" gorm:"serializer:encrypted"`
+	DocumentExpiry      *time.Time   `json:"document_expiry,omitempty"`
+	FrequentFlyerNumber string       `json:"frequent_flyer_number,omitempty"`
+
+	TicketNumber *string          `json:"ticket_number,omitempty" gorm:"uniqueIndex"` // Issued when the order is confirmed
+	Seat         *SeatAssignment  `json:"seat,omitempty" gorm:"foreignKey:PassengerID"`
+	Ancillaries  []OrderAncillary `json:"ancillaries,omitempty" gorm:"foreignKey:PassengerID"`
 }
 
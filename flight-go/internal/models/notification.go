@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// NotificationType is what a notification is about
+type NotificationType string
+
+const (
+	NotificationScheduleChange NotificationType = "schedule_change" // A booked flight was disrupted
+)
+
+// Notification is a message for a user's inbox. Guests have no inbox, their notifications
+// only carry the order's contact email.
+type Notification struct {
+	BaseModel
+	UserID  string           `json:"user_id,omitempty" gorm:"index"` // Empty for guest orders
+	Email   string           `json:"email" gorm:"not null"`
+	OrderID string           `json:"order_id,omitempty" gorm:"index"`
+	Type    NotificationType `json:"type" gorm:"not null"`
+	Title   string           `json:"title" gorm:"not null"`
+	Message string           `json:"message"`
+	ReadAt  *time.Time       `json:"read_at,omitempty"`
+}
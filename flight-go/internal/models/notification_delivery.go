@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DeliveryStatus is how far a notification delivery got
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "pending" // Queued, or waiting to be retried
+	DeliverySent    DeliveryStatus = "sent"
+	DeliveryFailed  DeliveryStatus = "failed" // Gave up after the last attempt
+)
+
+// NotificationDelivery is one rendered message on its way to a recipient through one channel.
+// It doubles as the queue entry and the delivery log.
+type NotificationDelivery struct {
+	BaseModel
+	Channel       string         `json:"channel" gorm:"not null"` // smtp, webhook, file or memory
+	Event         string         `json:"event" gorm:"not null"`
+	Locale        string         `json:"locale" gorm:"not null"`
+	Recipient     string         `json:"recipient" gorm:"not null;index"` // Email address
+	RecipientName string         `json:"recipient_name"`
+	OrderID       string         `json:"order_id,omitempty" gorm:"index"`
+	Subject       string         `json:"subject" gorm:"not null"`
+	Body          string         `json:"body"`
+	Status        DeliveryStatus `json:"status" gorm:"not null;index"`
+	Attempts      int            `json:"attempts"`
+	NextAttemptAt *time.Time     `json:"next_attempt_at,omitempty" gorm:"index"` // Set while pending
+	LastError     string         `json:"last_error,omitempty"`
+	SentAt        *time.Time     `json:"sent_at,omitempty"`
+}
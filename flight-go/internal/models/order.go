@@ -0,0 +1,50 @@
+package models
+
+import "slices"
+
+// Clone returns a copy of o that shares nothing it owns, so one goroutine can change it while
+// another reads the original. The attached User, Schedule and Disruption are loaded records
+// o only points to, they are shared and neither copy may change them.
+func (o *Order) Clone() *Order {
+	clone := *o
+	clone.Flight = clonePtr(o.Flight)
+	clone.FareQuote = o.FareQuote.clone()
+	clone.PriceBreakdown = o.PriceBreakdown.clone()
+	clone.Passengers = slices.Clone(o.Passengers)
+	for i := range clone.Passengers {
+		p := &clone.Passengers[i]
+		p.DateOfBirth = clonePtr(p.DateOfBirth)
+		p.DocumentExpiry = clonePtr(p.DocumentExpiry)
+		p.TicketNumber = clonePtr(p.TicketNumber)
+		p.Seat = clonePtr(p.Seat)
+		p.Ancillaries = slices.Clone(p.Ancillaries)
+	}
+	return &clone
+}
+
+func (q *FareQuote) clone() *FareQuote {
+	if q == nil {
+		return nil
+	}
+	clone := *q
+	clone.Adjustments = slices.Clone(q.Adjustments)
+	clone.FareFamily = clonePtr(q.FareFamily)
+	return &clone
+}
+
+func (b *PriceBreakdown) clone() *PriceBreakdown {
+	if b == nil {
+		return nil
+	}
+	clone := *b
+	clone.Items = slices.Clone(b.Items)
+	return &clone
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	clone := *v
+	return &clone
+}
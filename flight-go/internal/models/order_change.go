@@ -0,0 +1,31 @@
+package models
+
+// OrderChangeType is what an order history entry records
+type OrderChangeType string
+
+const (
+	OrderChangeFlight    OrderChangeType = "flight_change"    // Moved to another flight date or schedule
+	OrderChangeName      OrderChangeType = "name_correction"  // A passenger's name corrected
+	OrderChangeSeat      OrderChangeType = "seat_change"      // Seats selected after booking
+	OrderChangeAncillary OrderChangeType = "ancillary_change" // Ancillaries bought after booking
+	OrderChangeStatus    OrderChangeType = "status_change"
+	OrderChangeContact   OrderChangeType = "contact_change"
+
+	// Schedule disruptions, see ScheduleDisruption
+	OrderChangeDisruption         OrderChangeType = "schedule_disruption" // The airline changed or cancelled the flight
+	OrderChangeDisruptionResponse OrderChangeType = "disruption_response" // The passenger accepted or took a refund
+	OrderChangeRebooking          OrderChangeType = "rebooking"           // Moved off a disrupted flight, free of charge
+)
+
+// OrderChange is one entry in an order's history
+type OrderChange struct {
+	BaseModel
+	OrderID        string            `json:"order_id" gorm:"not null;index"`
+	Type           OrderChangeType   `json:"type" gorm:"not null"`
+	ChangedBy      string            `json:"changed_by,omitempty"` // User making the change, empty for admin and guest actions
+	Before         map[string]string `json:"before" gorm:"serializer:json"`
+	After          map[string]string `json:"after" gorm:"serializer:json"`
+	FareDifference int64             `json:"fare_difference"` // In minor units of Currency
+	ChangeFee      int64             `json:"change_fee"`      // In minor units of Currency
+	Currency       string            `json:"currency,omitempty" gorm:"type:varchar(3)"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOrderClone(t *testing.T) {
+	ticket := "GA-1234567890"
+	born := time.Date(1990, 4, 1, 0, 0, 0, 0, time.UTC)
+	order := &Order{
+		PNR:            "ABC123",
+		Flight:         &FlightSnapshot{FlightNumber: "GA-402"},
+		FareQuote:      &FareQuote{Adjustments: []FareAdjustment{{Name: "early bird"}}, FareFamily: &FareFamilyPrice{Code: "LITE"}},
+		PriceBreakdown: &PriceBreakdown{Items: []PriceLineItem{{Name: "fare", Amount: 100}}, Total: 100},
+		Passengers: []Passenger{{
+			FullName:       "Budi Santoso",
+			DateOfBirth:    &born,
+			DocumentNumber: "3171000000000001",
+			TicketNumber:   &ticket,
+			Seat:           &SeatAssignment{SeatNumber: "20A"},
+			Ancillaries:    []OrderAncillary{{Name: "Baggage 10kg"}},
+		}},
+	}
+	clone := order.Clone()
+	if !reflect.DeepEqual(clone, order) {
+		t.Fatalf("clone %+v differs from the order", clone)
+	}
+
+	clone.Flight.FlightNumber = "GA-404"
+	clone.FareQuote.Adjustments[0].Name = "changed"
+	clone.FareQuote.FareFamily.Code = "FLEX"
+	clone.PriceBreakdown.Items[0].Amount = 200
+	clone.MaskDocuments()
+	p := &clone.Passengers[0]
+	*p.DateOfBirth = born.AddDate(1, 0, 0)
+	*p.TicketNumber = "GA-0000000000"
+	p.Seat.SeatNumber = "1A"
+	p.Ancillaries[0].Name = "changed"
+
+	original := order.Passengers[0]
+	if order.Flight.FlightNumber != "GA-402" || order.FareQuote.Adjustments[0].Name != "early bird" || order.FareQuote.FareFamily.Code != "LITE" ||
+		order.PriceBreakdown.Items[0].Amount != 100 || original.DocumentNumber != "3171000000000001" || !original.DateOfBirth.Equal(born) ||
+		*original.TicketNumber != ticket || original.Seat.SeatNumber != "20A" || original.Ancillaries[0].Name != "Baggage 10kg" {
+		t.Errorf("changing the clone changed the order: %+v", order)
+	}
+}
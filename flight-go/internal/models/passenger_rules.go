@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// PassengerRules are an airline's passenger-type pricing and booking limits
+type PassengerRules struct {
+	ChildPercent  float64 `json:"child_percent"`  // % of the adult fare charged for children
+	InfantPercent float64 `json:"infant_percent"` // % of the adult fare charged for infants
+	MaxPassengers int     `json:"max_passengers"` // Largest party on one order
+	ChildMinAge   int     `json:"child_min_age"`  // Younger passengers travel as infants
+	AdultMinAge   int     `json:"adult_min_age"`  // Younger passengers travel as children
+}
+
+// DefaultPassengerRules applies to airlines without their own rules
+func DefaultPassengerRules() PassengerRules {
+	return PassengerRules{
+		ChildPercent:  75,
+		InfantPercent: 0,
+		MaxPassengers: 9,
+		ChildMinAge:   2,
+		AdultMinAge:   12,
+	}
+}
+
+// TypeForAge returns the passenger type an age on the day of travel falls under
+func (r PassengerRules) TypeForAge(age int) PassengerType {
+	switch {
+	case age < r.ChildMinAge:
+		return PassengerInfant
+	case age < r.AdultMinAge:
+		return PassengerChild
+	default:
+		return PassengerAdult
+	}
+}
+
+// RulesOrDefault returns the airline's passenger rules, or the defaults when it has none
+func (a *Airline) RulesOrDefault() PassengerRules {
+	if a == nil || a.PassengerRules == nil {
+		return DefaultPassengerRules()
+	}
+	return *a.PassengerRules
+}
+
+// AgeOn returns the completed years between dateOfBirth and date
+func AgeOn(dateOfBirth, date time.Time) int {
+	age := date.Year() - dateOfBirth.Year()
+	if date.Month() < dateOfBirth.Month() || (date.Month() == dateOfBirth.Month() && date.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// WithFareFamily applies a fare family's passenger pricing overrides
+func (r PassengerRules) WithFareFamily(family *FareFamily) PassengerRules {
+	if family == nil {
+		return r
+	}
+	if family.ChildPercent != nil {
+		r.ChildPercent = *family.ChildPercent
+	}
+	if family.InfantPercent != nil {
+		r.InfantPercent = *family.InfantPercent
+	}
+	return r
+}
@@ -0,0 +1,52 @@
+package models
+
+// FareRuleType is the condition a fare rule matches on
+type FareRuleType string
+
+const (
+	FareRuleLoadFactor FareRuleType = "load_factor" // % of cabin seats sold, MinValue..MaxValue
+	FareRuleDaysBefore FareRuleType = "days_before" // days between booking and departure, MinValue..MaxValue
+	FareRuleDayOfWeek  FareRuleType = "day_of_week" // departure weekday in DaysOfWeek
+	FareRuleTimeOfDay  FareRuleType = "time_of_day" // departure time in TimeFrom..TimeTo
+)
+
+// FareRule adjusts a schedule's base fare by a percentage when its condition matches
+type FareRule struct {
+	BaseModel
+	Name              string       `json:"name" gorm:"not null"`
+	Type              FareRuleType `json:"type" gorm:"not null"`
+	AirlineID         string       `json:"airline_id"`  // Empty applies to all airlines
+	CabinClass        CabinClass   `json:"cabin_class"` // Empty applies to all cabins
+	MinValue          float64      `json:"min_value"`
+	MaxValue          float64      `json:"max_value"`
+	DaysOfWeek        string       `json:"days_of_week"`                       // 1=Mon, 7=Sun
+	TimeFrom          string       `json:"time_from"`                          // HH:MM format
+	TimeTo            string       `json:"time_to"`                            // HH:MM format, may wrap past midnight
+	AdjustmentPercent float64      `json:"adjustment_percent" gorm:"not null"` // e.g. 15 = +15%, -10 = -10%
+	Priority          int          `json:"priority" gorm:"default:0"`          // Lower applies first
+	IsActive          bool         `json:"is_active" gorm:"default:true"`
+}
+
+// FareAdjustment is a fare rule applied to a quote
+type FareAdjustment struct {
+	RuleID  string       `json:"rule_id"`
+	Name    string       `json:"name"`
+	Type    FareRuleType `json:"type"`
+	Percent float64      `json:"percent"`
+	Amount  float64      `json:"amount"`
+}
+
+// FareQuote is the computed per-adult fare for a schedule, cabin and flight date
+type FareQuote struct {
+	Environment  Environment      `json:"environment"`
+	CabinClass   CabinClass       `json:"cabin_class"`
+	FlightDate   string           `json:"flight_date"` // YYYY-MM-DD format
+	Currency     string           `json:"currency"`
+	ExchangeRate float64          `json:"exchange_rate,omitempty"` // Applied from the schedule's currency, omitted when unconverted
+	BaseFare     float64          `json:"base_fare"`
+	Adjustments  []FareAdjustment `json:"adjustments"`
+	FareFamily   *FareFamilyPrice `json:"fare_family,omitempty"`
+	Fare         float64          `json:"fare"`
+	LoadFactor   float64          `json:"load_factor"` // % of cabin seats sold
+	DaysBefore   int              `json:"days_before"`
+}
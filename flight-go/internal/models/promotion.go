@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// DiscountType is how a promotion reduces the order total
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "percent" // DiscountValue % of the order total
+	DiscountFixed   DiscountType = "fixed"   // DiscountValue in the promotion's Currency
+)
+
+// Promotion is a campaign customers redeem with a promo code when booking
+type Promotion struct {
+	BaseModel
+	Code                 string       `json:"code" gorm:"uniqueIndex;not null"` // Stored upper-case
+	Name                 string       `json:"name" gorm:"not null"`
+	Description          string       `json:"description"`
+	DiscountType         DiscountType `json:"discount_type" gorm:"not null"`
+	DiscountValue        float64      `json:"discount_value" gorm:"not null"`
+	Currency             string       `json:"currency" gorm:"type:varchar(3);not null;default:'IDR'"` // Of fixed discounts and MinSpend
+	MinSpend             float64      `json:"min_spend"`                                              // Order total before discount, 0 = none
+	AirlineID            string       `json:"airline_id"`                                             // Empty applies to all airlines
+	OriginAirportID      string       `json:"origin_airport_id"`                                      // Empty applies to all origins
+	DestinationAirportID string       `json:"destination_airport_id"`                                 // Empty applies to all destinations
+	CabinClass           CabinClass   `json:"cabin_class"`                                            // Empty applies to all cabins
+	ValidFrom            time.Time    `json:"valid_from" gorm:"not null"`
+	ValidUntil           time.Time    `json:"valid_until" gorm:"not null"`
+	UsageLimit           int          `json:"usage_limit"`    // Redemptions across all customers, 0 = unlimited
+	PerUserLimit         int          `json:"per_user_limit"` // Redemptions per customer, 0 = unlimited
+	UsedCount            int          `json:"used_count" gorm:"not null;default:0"`
+	IsActive             bool         `json:"is_active" gorm:"default:true"`
+}
+
+// AppliesTo reports whether the promotion's scope covers a flight in cabin
+func (p *Promotion) AppliesTo(schedule *Schedule, cabin CabinClass) bool {
+	if p.AirlineID != "" && p.AirlineID != schedule.AirlineID {
+		return false
+	}
+	if p.OriginAirportID != "" && p.OriginAirportID != schedule.DepartureAirportID {
+		return false
+	}
+	if p.DestinationAirportID != "" && p.DestinationAirportID != schedule.ArrivalAirportID {
+		return false
+	}
+	return p.CabinClass == "" || p.CabinClass == cabin
+}
+
+// PromotionRedemption records one use of a promotion by an order
+type PromotionRedemption struct {
+	BaseModel
+	PromotionID string `json:"promotion_id" gorm:"index;not null"`
+	UserID      string `json:"user_id" gorm:"index;not null"`
+	OrderID     string `json:"order_id" gorm:"uniqueIndex;not null"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import "slices"
+
+// Clone returns a copy of s that shares nothing it owns, as Order.Clone does. The attached Airline
+// and airports are loaded records s only points to, they are shared and neither copy may change them.
+func (s *Schedule) Clone() *Schedule {
+	clone := *s
+	clone.Fare = s.Fare.clone()
+	clone.FareFamilies = slices.Clone(s.FareFamilies)
+	for i := range clone.FareFamilies {
+		family := &clone.FareFamilies[i]
+		family.ChildPercent = clonePtr(family.ChildPercent)
+		family.InfantPercent = clonePtr(family.InfantPercent)
+	}
+	clone.PricePreview = s.PricePreview.clone()
+	return &clone
+}
@@ -0,0 +1,39 @@
+package models
+
+import "slices"
+
+// DisruptionType is a material change to a booked flight
+type DisruptionType string
+
+const (
+	DisruptionTimeChange     DisruptionType = "time_change"     // Departure or arrival moved by more than the threshold
+	DisruptionCancellation   DisruptionType = "cancellation"    // The schedule was deactivated or deleted
+	DisruptionAircraftChange DisruptionType = "aircraft_change" // Another aircraft, with its own seat map
+)
+
+// DisruptionStatus is where an order on a disrupted flight stands
+type DisruptionStatus string
+
+const (
+	DisruptionActionRequired  DisruptionStatus = "action_required" // Waiting for the passenger to respond
+	DisruptionAccepted        DisruptionStatus = "accepted"
+	DisruptionRefundRequested DisruptionStatus = "refund_requested" // Cancelled, the full amount paid is owed back
+	DisruptionRebooked        DisruptionStatus = "rebooked"
+)
+
+// ScheduleDisruption is a material change an admin made to a schedule. The upcoming orders on
+// the schedule point at it until their passengers accept it, take a refund or rebook.
+type ScheduleDisruption struct {
+	BaseModel
+	Environment    Environment       `json:"environment" gorm:"type:varchar(10);not null"`
+	ScheduleID     string            `json:"schedule_id" gorm:"not null;index"`
+	Types          []DisruptionType  `json:"types" gorm:"serializer:json"`
+	Before         map[string]string `json:"before" gorm:"serializer:json"` // The changed schedule fields
+	After          map[string]string `json:"after" gorm:"serializer:json"`
+	AffectedOrders int               `json:"affected_orders"`
+}
+
+// Cancels reports whether the flight no longer operates, leaving nothing to accept
+func (d *ScheduleDisruption) Cancels() bool {
+	return slices.Contains(d.Types, DisruptionCancellation)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScheduleClone(t *testing.T) {
+	child := 75.0
+	airline := &Airline{Code: "GA"}
+	schedule := &Schedule{
+		FlightNumber: "GA-402",
+		Airline:      airline,
+		Fare:         &FareQuote{Adjustments: []FareAdjustment{{Name: "early bird"}}, FareFamily: &FareFamilyPrice{Code: "LITE"}},
+		FareFamilies: []FareFamilyOffer{{FareFamily: FareFamily{Code: "LITE", ChildPercent: &child}, SeatsLeft: 9}},
+		PricePreview: &PriceBreakdown{Items: []PriceLineItem{{Name: "fare", Amount: 100}}, Total: 100},
+	}
+	clone := schedule.Clone()
+	if !reflect.DeepEqual(clone, schedule) {
+		t.Fatalf("clone %+v differs from the schedule", clone)
+	}
+	if clone.Airline != airline {
+		t.Error("clone has its own airline, want the loaded one shared")
+	}
+
+	clone.FlightNumber = "GA-404"
+	clone.Fare.Adjustments[0].Name = "changed"
+	clone.Fare.FareFamily.Code = "FLEX"
+	clone.FareFamilies[0].SeatsLeft = 0
+	*clone.FareFamilies[0].ChildPercent = 100
+	clone.PricePreview.Items[0].Amount = 200
+
+	if schedule.FlightNumber != "GA-402" || schedule.Fare.Adjustments[0].Name != "early bird" || schedule.Fare.FareFamily.Code != "LITE" ||
+		schedule.FareFamilies[0].SeatsLeft != 9 || *schedule.FareFamilies[0].ChildPercent != 75 || schedule.PricePreview.Items[0].Amount != 100 {
+		t.Errorf("changing the clone changed the schedule: %+v", schedule)
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SeatMap is the seat layout of an aircraft type, matched to schedules by their Aircraft
+type SeatMap struct {
+	BaseModel
+	Aircraft string     `json:"aircraft" gorm:"uniqueIndex;not null"`                   // Same string as Schedule.Aircraft, e.g. Boeing 737-800
+	Currency string     `json:"currency" gorm:"type:varchar(3);not null;default:'IDR'"` // Currency of zone prices
+	Zones    []SeatZone `json:"zones" gorm:"serializer:json"`
+	ExitRows []int      `json:"exit_rows" gorm:"serializer:json"`
+}
+
+// SeatZone is a block of consecutive rows sharing a cabin, layout and seat price
+type SeatZone struct {
+	Name       string     `json:"name"` // e.g. Standard, Extra legroom
+	CabinClass CabinClass `json:"cabin_class"`
+	FromRow    int        `json:"from_row"`
+	ToRow      int        `json:"to_row"`
+	Layout     string     `json:"layout"` // Seat letters left to right with a space for each aisle, e.g. "ABC DEF"
+	Price      float64    `json:"price"`  // Per seat, 0 = free
+}
+
+// Columns returns the zone's seat letters without aisles
+func (z *SeatZone) Columns() []string {
+	var columns []string
+	for _, column := range z.Layout {
+		if column != ' ' {
+			columns = append(columns, string(column))
+		}
+	}
+	return columns
+}
+
+// Zone returns the zone holding seat, or nil when the aircraft has no such seat
+func (m *SeatMap) Zone(seat string) *SeatZone {
+	row, column, ok := ParseSeatNumber(seat)
+	if !ok {
+		return nil
+	}
+	for i := range m.Zones {
+		zone := &m.Zones[i]
+		if row >= zone.FromRow && row <= zone.ToRow && strings.Contains(strings.ReplaceAll(zone.Layout, " ", ""), column) {
+			return zone
+		}
+	}
+	return nil
+}
+
+// IsExitRow reports whether row is an emergency exit row
+func (m *SeatMap) IsExitRow(row int) bool {
+	for _, exit := range m.ExitRows {
+		if exit == row {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSeatNumber splits a seat such as "12A" into its row and column
+func ParseSeatNumber(seat string) (int, string, bool) {
+	seat = strings.ToUpper(strings.TrimSpace(seat))
+	if len(seat) < 2 {
+		return 0, "", false
+	}
+	row, err := strconv.Atoi(seat[:len(seat)-1])
+	column := seat[len(seat)-1:]
+	if err != nil || row < 1 || column < "A" || column > "Z" {
+		return 0, "", false
+	}
+	return row, column, true
+}
+
+// SeatAssignment holds a seat for a passenger on one flight date.
+// Assignments are deleted outright when released, so the seat can be taken again.
+type SeatAssignment struct {
+	BaseModel
+	Environment Environment `json:"environment" gorm:"type:varchar(10);not null;default:staging;uniqueIndex:idx_flight_seat"`
+	ScheduleID  string      `json:"schedule_id" gorm:"not null;uniqueIndex:idx_flight_seat"`
+	FlightDate  time.Time   `json:"flight_date" gorm:"not null;uniqueIndex:idx_flight_seat"`
+	SeatNumber  string      `json:"seat_number" gorm:"not null;uniqueIndex:idx_flight_seat"`
+	OrderID     string      `json:"order_id" gorm:"not null;index"`
+	PassengerID string      `json:"passenger_id" gorm:"not null;uniqueIndex"`
+	Price       int64       `json:"price"` // In minor units of Currency
+	Currency    string      `json:"currency" gorm:"type:varchar(3)"`
+}
+
+// FlightSeatMap is an aircraft's seat map with the occupancy of one flight date
+type FlightSeatMap struct {
+	ScheduleID string    `json:"schedule_id"`
+	FlightDate string    `json:"flight_date"`
+	Aircraft   string    `json:"aircraft"`
+	Currency   string    `json:"currency"` // Currency of seat prices
+	Rows       []SeatRow `json:"rows"`
+}
+
+// SeatRow is one row of a flight seat map
+type SeatRow struct {
+	Number     int        `json:"number"`
+	CabinClass CabinClass `json:"cabin_class"`
+	Zone       string     `json:"zone"`
+	Layout     string     `json:"layout"`
+	ExitRow    bool       `json:"exit_row"`
+	Seats      []SeatInfo `json:"seats"`
+}
+
+// SeatInfo is one seat of a flight seat map
+type SeatInfo struct {
+	Number    string  `json:"number"`
+	Price     float64 `json:"price"`
+	Available bool    `json:"available"`
+}
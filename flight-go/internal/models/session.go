@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Session is one login, kept alive by refresh tokens that rotate on every use. Access tokens carry
+// its ID, so revoking the session ends them as well as its refresh token.
+type Session struct {
+	BaseModel
+	UserID            string     `json:"user_id" gorm:"not null;index"`
+	RefreshTokenHash  string     `json:"-" gorm:"uniqueIndex;not null"` // SHA-256 of the current refresh token
+	PreviousTokenHash string     `json:"-" gorm:"index"`                // Of the token it replaced, presenting that one again means it leaked
+	ExpiresAt         time.Time  `json:"expires_at"`                    // Of the current refresh token
+	RevokedAt         *time.Time `json:"revoked_at,omitempty" gorm:"index"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "strings"
+
+// DocumentType is the identity document a passenger travels on
+type DocumentType string
+
+const (
+	DocumentPassport   DocumentType = "passport"
+	DocumentNationalID DocumentType = "national_id" // Accepted on domestic routes only
+)
+
+// RouteType decides which travel documents a booking needs
+type RouteType string
+
+const (
+	RouteDomestic      RouteType = "domestic"
+	RouteInternational RouteType = "international"
+)
+
+// RouteType classifies the schedule by its airports' countries.
+// DepartureAirport and ArrivalAirport must be loaded.
+func (s *Schedule) RouteType() RouteType {
+	if s.DepartureAirport != nil && s.ArrivalAirport != nil && s.DepartureAirport.Country != s.ArrivalAirport.Country {
+		return RouteInternational
+	}
+	return RouteDomestic
+}
+
+// MaskDocumentNumber hides all but the last four characters of a document number
+func MaskDocumentNumber(number string) string {
+	visible := 4
+	if len(number) <= visible {
+		visible = 0
+	}
+	return strings.Repeat("*", len(number)-visible) + number[len(number)-visible:]
+}
+
+// MaskDocuments masks every passenger's document number for responses to non-admins
+func (o *Order) MaskDocuments() {
+	for i := range o.Passengers {
+		o.Passengers[i].DocumentNumber = MaskDocumentNumber(o.Passengers[i].DocumentNumber)
+	}
+}
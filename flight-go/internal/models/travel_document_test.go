@@ -0,0 +1,46 @@
+package models
+
+import "testing"
+
+func TestMaskDocumentNumber(t *testing.T) {
+	tests := []struct {
+		number string
+		want   string
+	}{
+		{"A1234567", "****4567"},
+		{"3171000000000001", "************0001"},
+		{"12345", "*2345"},
+		{"1234", "****"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := MaskDocumentNumber(tt.number); got != tt.want {
+			t.Errorf("MaskDocumentNumber(%q) = %q, want %q", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestScheduleRouteType(t *testing.T) {
+	jakarta := &Airport{Country: "ID"}
+	bali := &Airport{Country: "ID"}
+	singapore := &Airport{Country: "SG"}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     RouteType
+	}{
+		{name: "same country", schedule: Schedule{DepartureAirport: jakarta, ArrivalAirport: bali}, want: RouteDomestic},
+		{name: "across borders", schedule: Schedule{DepartureAirport: jakarta, ArrivalAirport: singapore}, want: RouteInternational},
+		{name: "airports not loaded", schedule: Schedule{}, want: RouteDomestic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.RouteType(); got != tt.want {
+				t.Errorf("RouteType() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
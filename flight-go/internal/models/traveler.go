@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Traveler is a passenger saved to a user's address book for reuse on later orders
+type Traveler struct {
+	BaseModel
+	UserID              string       `json:"user_id" gorm:"index;not null"`
+	Title               string       `json:"title" gorm:"not null"` // Mr, Mrs, Ms
+	FullName            string       `json:"full_name" gorm:"not null"`
+	DateOfBirth         *time.Time   `json:"date_of_birth,omitempty"`
+	Nationality         string       `json:"nationality,omitempty" gorm:"type:varchar(2)"` // ISO 3166-1 alpha-2
+	DocumentType        DocumentType `json:"document_type,omitempty"`
+	DocumentNumber      string       `json:"document_number,omitempty" gorm:"serializer:encrypted"`
+	DocumentExpiry      *time.Time   `json:"document_expiry,omitempty"`
+	FrequentFlyerNumber string       `json:"frequent_flyer_number,omitempty"`
+}
+
+// MaskDocument masks the saved document number for responses
+func (t *Traveler) MaskDocument() {
+	t.DocumentNumber = MaskDocumentNumber(t.DocumentNumber)
+}
@@ -0,0 +1,21 @@
+package models
+
+// UserChangeType is what a user audit entry records
+type UserChangeType string
+
+const (
+	UserChangeRole     UserChangeType = "role_change"
+	UserChangeDisabled UserChangeType = "disabled"
+	UserChangeEnabled  UserChangeType = "enabled"
+)
+
+// UserChange is one entry in a user account's audit trail
+type UserChange struct {
+	BaseModel
+	UserID    string            `json:"user_id" gorm:"not null;index"`
+	Type      UserChangeType    `json:"type" gorm:"not null"`
+	ChangedBy string            `json:"changed_by" gorm:"not null"` // Admin making the change
+	Reason    string            `json:"reason,omitempty"`
+	Before    map[string]string `json:"before" gorm:"serializer:json"`
+	After     map[string]string `json:"after" gorm:"serializer:json"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// WebhookEvent is what a partner webhook is told about
+type WebhookEvent string
+
+const (
+	WebhookOrderCreated     WebhookEvent = "order.created"
+	WebhookOrderCancelled   WebhookEvent = "order.cancelled"
+	WebhookWhitelistGranted WebhookEvent = "whitelist.granted" // A user was given production access to the airline
+	WebhookWhitelistRevoked WebhookEvent = "whitelist.revoked" // A user's production access to the airline was taken away
+)
+
+// WebhookEvents lists every event a subscription can filter on
+var WebhookEvents = []WebhookEvent{WebhookOrderCreated, WebhookOrderCancelled, WebhookWhitelistGranted, WebhookWhitelistRevoked}
+
+// IsValid reports whether e is a known webhook event
+func (e WebhookEvent) IsValid() bool {
+	for _, event := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription sends an airline's events to a partner URL. Payloads are signed with
+// Secret, which is only shown when it is created or rotated.
+type WebhookSubscription struct {
+	BaseModel
+	AirlineID   string         `json:"airline_id" gorm:"not null;index"`
+	URL         string         `json:"url" gorm:"not null"`
+	Description string         `json:"description"`
+	Secret      string         `json:"secret,omitempty" gorm:"serializer:encrypted;not null"`
+	Events      []WebhookEvent `json:"events" gorm:"serializer:json"` // Empty subscribes to every event
+	IsActive    bool           `json:"is_active" gorm:"default:true"`
+}
+
+// Wants reports whether the subscription filters event in
+func (s *WebhookSubscription) Wants(event WebhookEvent) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus is how far a webhook delivery got
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending" // Queued, or waiting to be retried
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead" // Gave up, waiting in the dead-letter list for a redelivery
+)
+
+// WebhookDelivery is one event on its way to one subscription, kept as its delivery log
+type WebhookDelivery struct {
+	BaseModel
+	SubscriptionID string                `json:"subscription_id" gorm:"not null;index"`
+	Subscription   *WebhookSubscription  `json:"-" gorm:"foreignKey:SubscriptionID"`
+	AirlineID      string                `json:"airline_id" gorm:"not null;index"`
+	EventID        string                `json:"event_id" gorm:"not null;index"` // Shared by the deliveries of one event, for partners to deduplicate
+	Event          WebhookEvent          `json:"event" gorm:"not null"`
+	Payload        string                `json:"payload" gorm:"type:text;not null"` // The JSON body posted
+	Status         WebhookDeliveryStatus `json:"status" gorm:"not null;index"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" gorm:"index"` // Set while pending
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,82 @@
+package notifications
+
+import "context"
+
+// Event is what a notification is about, each has a template per locale
+type Event string
+
+const (
+	EventOrderCreated     Event = "order_created"     // Booking received, data is OrderData
+	EventOrderConfirmed   Event = "order_confirmed"   // E-tickets issued, data is OrderData
+	EventOrderCancelled   Event = "order_cancelled"   // Data is OrderData, with Refund when one is owed
+	EventScheduleChange   Event = "schedule_change"   // Data is OrderData with Change
+	EventWhitelistGranted Event = "whitelist_granted" // Data is WhitelistData
+	EventVerifyEmail      Event = "verify_email"      // Data is VerifyEmailData
+)
+
+// Recipient is who a notification is for
+type Recipient struct {
+	Email  string
+	Name   string
+	Locale string // Falls back to DefaultLocale
+}
+
+// Message is a notification rendered for one recipient, ready to hand to a channel
+type Message struct {
+	Event   Event  `json:"event"`
+	Locale  string `json:"locale"`
+	To      string `json:"to"`
+	Name    string `json:"name,omitempty"`
+	OrderID string `json:"order_id,omitempty"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// Channel delivers messages by one means, such as email. Send returning an error has the
+// message retried later, so a channel should not fail after part of a message went out.
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+	Name() string
+}
+
+// OrderData is what order templates are rendered with
+type OrderData struct {
+	PNR           string
+	ContactName   string
+	Airline       string
+	FlightNumber  string
+	From          string // e.g. "Jakarta (CGK)"
+	To            string
+	FlightDate    string // e.g. "20 Dec 2024"
+	DepartureTime string
+	ArrivalTime   string
+	Passengers    []PassengerData
+	Total         string // Formatted with its currency
+	Refund        string // Amount owed back on cancellation, empty when none
+	Change        *ScheduleChange
+}
+
+// PassengerData is a passenger on an order
+type PassengerData struct {
+	Name         string
+	TicketNumber string // Empty until the order is confirmed
+}
+
+// ScheduleChange is a material change to a booked flight, keyed like ScheduleDisruption's before and after
+type ScheduleChange struct {
+	Cancelled bool
+	Before    map[string]string
+	After     map[string]string
+}
+
+// WhitelistData is what whitelist templates are rendered with
+type WhitelistData struct {
+	Name     string
+	Airlines []string // Airlines newly opened to the user
+}
+
+// VerifyEmailData is what the email verification template is rendered with
+type VerifyEmailData struct {
+	Name  string
+	Token string
+}
@@ -0,0 +1,176 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+// How often Run looks for deliveries due a retry, new ones wake it straight away
+const pollInterval = 5 * time.Second
+
+// How many due deliveries Run picks up at a time
+const batchSize = 50
+
+// errChannelNotConfigured fails deliveries queued for a channel the server no longer runs
+var errChannelNotConfigured = errors.New("channel is not configured")
+
+// DeliveryStore keeps queued deliveries, and every attempt at them as the delivery log
+type DeliveryStore interface {
+	CreateBatch(deliveries []models.NotificationDelivery) error
+	// ListDue returns pending deliveries whose next attempt is at or before now, oldest first
+	ListDue(now time.Time, limit int) ([]models.NotificationDelivery, error)
+	Update(delivery *models.NotificationDelivery) error
+}
+
+// Notifier renders notifications and queues them on every channel. Deliveries are stored before
+// they are attempted, so the queue survives restarts, and failures are retried with exponential
+// backoff until maxAttempts. A nil Notifier drops every notification.
+type Notifier struct {
+	templates   *Templates
+	store       DeliveryStore
+	channels    []Channel
+	maxAttempts int
+	retryDelay  time.Duration
+	wake        chan struct{}
+}
+
+// NewNotifier retries a failed delivery after retryDelay, doubling the wait after each further
+// failure, and gives up after maxAttempts. With no channels notifications are dropped.
+func NewNotifier(templates *Templates, store DeliveryStore, channels []Channel, maxAttempts int, retryDelay time.Duration) *Notifier {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Notifier{
+		templates:   templates,
+		store:       store,
+		channels:    channels,
+		maxAttempts: maxAttempts,
+		retryDelay:  retryDelay,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Notify renders event for to and queues it on every channel. orderID, when set, files the
+// deliveries under that order in the log.
+func (n *Notifier) Notify(event Event, to Recipient, orderID string, data any) error {
+	if n == nil || len(n.channels) == 0 {
+		return nil
+	}
+
+	locale := n.templates.Locale(to.Locale)
+	subject, body, err := n.templates.Render(event, locale, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]models.NotificationDelivery, len(n.channels))
+	for i, channel := range n.channels {
+		deliveries[i] = models.NotificationDelivery{
+			Channel:       channel.Name(),
+			Event:         string(event),
+			Locale:        locale,
+			Recipient:     to.Email,
+			RecipientName: to.Name,
+			OrderID:       orderID,
+			Subject:       subject,
+			Body:          body,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &now,
+		}
+	}
+	if err := n.store.CreateBatch(deliveries); err != nil {
+		return err
+	}
+
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers queued notifications until ctx is done. Run it once per store, so no delivery
+// is attempted twice at a time.
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		n.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-n.wake:
+		}
+	}
+}
+
+// deliverDue attempts every delivery that is due, a batch at a time
+func (n *Notifier) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := n.store.ListDue(time.Now(), batchSize)
+		if err != nil {
+			log.Printf("Listing due notifications failed: %v", err)
+			return
+		}
+		for i := range deliveries {
+			n.deliver(ctx, &deliveries[i])
+		}
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt at delivery and records how it went
+func (n *Notifier) deliver(ctx context.Context, delivery *models.NotificationDelivery) {
+	err := errChannelNotConfigured
+	if channel := n.channel(delivery.Channel); channel != nil {
+		err = channel.Send(ctx, Message{
+			Event:   Event(delivery.Event),
+			Locale:  delivery.Locale,
+			To:      delivery.Recipient,
+			Name:    delivery.RecipientName,
+			OrderID: delivery.OrderID,
+			Subject: delivery.Subject,
+			Body:    delivery.Body,
+		})
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliverySent
+		delivery.SentAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case delivery.Attempts >= n.maxAttempts || err == errChannelNotConfigured:
+		delivery.Status = models.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+	default:
+		next := now.Add(n.retryDelay << (delivery.Attempts - 1))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+
+	if err := n.store.Update(delivery); err != nil {
+		log.Printf("Recording notification delivery %s failed: %v", delivery.ID, err)
+	}
+}
+
+func (n *Notifier) channel(name string) Channel {
+	for _, channel := range n.channels {
+		if channel.Name() == name {
+			return channel
+		}
+	}
+	return nil
+}
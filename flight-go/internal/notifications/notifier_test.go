@@ -0,0 +1,253 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+// memoryStore is a DeliveryStore over a slice, in the order deliveries were queued
+type memoryStore struct {
+	mu         sync.Mutex
+	deliveries []models.NotificationDelivery
+}
+
+func (s *memoryStore) CreateBatch(deliveries []models.NotificationDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, delivery := range deliveries {
+		delivery.ID = fmt.Sprintf("delivery-%d", len(s.deliveries)+1)
+		s.deliveries = append(s.deliveries, delivery)
+	}
+	return nil
+}
+
+func (s *memoryStore) ListDue(now time.Time, limit int) ([]models.NotificationDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []models.NotificationDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryStore) Update(delivery *models.NotificationDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.deliveries {
+		if s.deliveries[i].ID == delivery.ID {
+			s.deliveries[i] = *delivery
+			return nil
+		}
+	}
+	return errors.New("unknown delivery")
+}
+
+// only returns the one delivery in the store
+func (s *memoryStore) only(t *testing.T) models.NotificationDelivery {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.deliveries) != 1 {
+		t.Fatalf("%d deliveries queued, want 1", len(s.deliveries))
+	}
+	return s.deliveries[0]
+}
+
+// flakyChannel fails as many sends as failures, then delivers to Memory
+type flakyChannel struct {
+	*Memory
+	mu       sync.Mutex
+	failures int
+}
+
+func (c *flakyChannel) Send(ctx context.Context, msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures > 0 {
+		c.failures--
+		return errors.New("mail server unavailable")
+	}
+	return c.Memory.Send(ctx, msg)
+}
+
+func newTestNotifier(t *testing.T, store DeliveryStore, maxAttempts int, channels ...Channel) *Notifier {
+	t.Helper()
+	templates, err := NewTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewNotifier(templates, store, channels, maxAttempts, time.Minute)
+}
+
+var testRecipient = Recipient{Email: "budi@example.com", Name: "Budi", Locale: "id-ID"}
+
+func TestNotifierQueuesAndDelivers(t *testing.T) {
+	store := &memoryStore{}
+	memory := NewMemory()
+	notifier := newTestNotifier(t, store, 3, memory)
+
+	if err := notifier.Notify(EventWhitelistGranted, testRecipient, "order-1", WhitelistData{Name: "Budi", Airlines: []string{"GA"}}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	queued := store.only(t)
+	if queued.Status != models.DeliveryPending || queued.Channel != "memory" || queued.Locale != "id" || queued.OrderID != "order-1" {
+		t.Errorf("queued %+v, want a pending memory delivery in id for order-1", queued)
+	}
+	if len(memory.Messages()) != 0 {
+		t.Error("Notify sent straight away, want it only queued")
+	}
+
+	notifier.deliverDue(context.Background())
+	sent := store.only(t)
+	if sent.Status != models.DeliverySent || sent.Attempts != 1 || sent.SentAt == nil || sent.NextAttemptAt != nil {
+		t.Errorf("after delivery %+v, want sent on the first attempt", sent)
+	}
+	messages := memory.Messages()
+	if len(messages) != 1 || messages[0].To != testRecipient.Email || messages[0].Subject != queued.Subject || !strings.Contains(messages[0].Body, "GA") {
+		t.Errorf("sent %+v", messages)
+	}
+}
+
+// Failures are retried after the retry delay, doubling each time, until the attempts run out
+func TestNotifierRetriesWithBackoff(t *testing.T) {
+	store := &memoryStore{}
+	channel := &flakyChannel{Memory: NewMemory(), failures: 10}
+	notifier := newTestNotifier(t, store, 3, channel)
+	if err := notifier.Notify(EventWhitelistGranted, testRecipient, "", WhitelistData{Name: "Budi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		notifier.deliverDue(context.Background())
+		delivery := store.only(t)
+		if delivery.Status != models.DeliveryPending || delivery.Attempts != attempt+1 || delivery.LastError == "" {
+			t.Fatalf("after attempt %d: %+v, want pending with the error", attempt+1, delivery)
+		}
+		if delay := delivery.NextAttemptAt.Sub(before); delay < wantDelay || delay > wantDelay+time.Second {
+			t.Errorf("after attempt %d retrying in %v, want %v", attempt+1, delay, wantDelay)
+		}
+
+		// Not due yet, so another pass leaves it alone
+		notifier.deliverDue(context.Background())
+		if store.only(t).Attempts != attempt+1 {
+			t.Fatal("retried before the delay was up")
+		}
+		store.deliveries[0].NextAttemptAt = &before
+	}
+
+	notifier.deliverDue(context.Background())
+	if delivery := store.only(t); delivery.Status != models.DeliveryFailed || delivery.Attempts != 3 || delivery.NextAttemptAt != nil {
+		t.Errorf("after the last attempt %+v, want failed", delivery)
+	}
+}
+
+func TestNotifierRetrySucceeds(t *testing.T) {
+	store := &memoryStore{}
+	channel := &flakyChannel{Memory: NewMemory(), failures: 1}
+	notifier := newTestNotifier(t, store, 3, channel)
+	if err := notifier.Notify(EventWhitelistGranted, testRecipient, "", WhitelistData{Name: "Budi"}); err != nil {
+		t.Fatal(err)
+	}
+
+	notifier.deliverDue(context.Background())
+	now := time.Now()
+	store.deliveries[0].NextAttemptAt = &now
+	notifier.deliverDue(context.Background())
+
+	if delivery := store.only(t); delivery.Status != models.DeliverySent || delivery.Attempts != 2 || delivery.LastError != "" {
+		t.Errorf("delivery %+v, want sent on the second attempt", delivery)
+	}
+	if len(channel.Messages()) != 1 {
+		t.Errorf("sent %d messages, want 1", len(channel.Messages()))
+	}
+}
+
+// A delivery queued for a channel the server no longer runs fails without retrying
+func TestNotifierChannelNotConfigured(t *testing.T) {
+	store := &memoryStore{}
+	now := time.Now()
+	store.CreateBatch([]models.NotificationDelivery{{Channel: "smtp", Event: string(EventWhitelistGranted), Status: models.DeliveryPending, NextAttemptAt: &now}})
+
+	newTestNotifier(t, store, 3, NewMemory()).deliverDue(context.Background())
+	if delivery := store.only(t); delivery.Status != models.DeliveryFailed || delivery.Attempts != 1 {
+		t.Errorf("delivery %+v, want failed on the first attempt", delivery)
+	}
+}
+
+// Run delivers as soon as a notification is queued, without waiting for the poll interval
+func TestNotifierRunWakesOnNotify(t *testing.T) {
+	store := &memoryStore{}
+	memory := NewMemory()
+	notifier := newTestNotifier(t, store, 3, memory)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+	time.Sleep(50 * time.Millisecond) // Past Run's first pass over the empty queue
+
+	if err := notifier.Notify(EventWhitelistGranted, testRecipient, "", WhitelistData{Name: "Budi"}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(pollInterval / 2)
+	for len(memory.Messages()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("notification not delivered before the next poll")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNilNotifierDrops(t *testing.T) {
+	var notifier *Notifier
+	if err := notifier.Notify(EventWhitelistGranted, testRecipient, "", WhitelistData{}); err != nil {
+		t.Errorf("Notify on a nil notifier: %v", err)
+	}
+
+	store := &memoryStore{}
+	if err := newTestNotifier(t, store, 3).Notify(EventWhitelistGranted, testRecipient, "", WhitelistData{}); err != nil || len(store.deliveries) != 0 {
+		t.Errorf("Notify without channels queued %d deliveries, err %v", len(store.deliveries), err)
+	}
+}
+
+func TestTemplatesLocale(t *testing.T) {
+	templates, err := NewTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{locale: "", want: DefaultLocale},
+		{locale: "id-ID", want: "id"},
+		{locale: " ID_id ", want: "id"},
+		{locale: "fr", want: DefaultLocale},
+	}
+	for _, tt := range tests {
+		if got := templates.Locale(tt.locale); got != tt.want {
+			t.Errorf("Locale(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	english, _, err := templates.Render(EventOrderCreated, "en", OrderData{PNR: "ABC123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	indonesian, _, err := templates.Render(EventOrderCreated, "id", OrderData{PNR: "ABC123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if english == indonesian || !strings.Contains(english, "ABC123") || !strings.Contains(indonesian, "ABC123") {
+		t.Errorf("subjects %q and %q, want each locale's own with the PNR", english, indonesian)
+	}
+}
@@ -0,0 +1,50 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP emails messages to their recipient through a mail server
+type SMTP struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTP sends through host:port as from, authenticating with PLAIN when username is set
+func NewSMTP(host string, port int, username, password, from string) *SMTP {
+	s := &SMTP{addr: net.JoinHostPort(host, fmt.Sprint(port)), from: from}
+	if username != "" {
+		s.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return s
+}
+
+func (s *SMTP) Name() string {
+	return "smtp"
+}
+
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	to := msg.To
+	if msg.Name != "" {
+		to = mime.QEncoding.Encode("utf-8", msg.Name) + " <" + msg.To + ">"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, []byte(b.String()))
+}
@@ -0,0 +1,185 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultLocale is used for recipients without a locale, or with one there are no templates for
+const DefaultLocale = "en"
+
+// Each template defines a "subject" and a "body"
+var templateSources = map[string]map[Event]string{
+	"en": {
+		EventOrderCreated: `{{define "subject"}}Booking {{.PNR}} received{{end}}
+{{define "body"}}Hi {{.ContactName}},
+
+We have received your booking {{.PNR}}.
+{{template "flight" .}}
+Total: {{.Total}}
+
+Your e-tickets will follow once the booking is confirmed.{{end}}`,
+
+		EventOrderConfirmed: `{{define "subject"}}Booking {{.PNR}} confirmed{{end}}
+{{define "body"}}Hi {{.ContactName}},
+
+Your booking {{.PNR}} is confirmed and your e-tickets are issued.
+{{template "flight" .}}
+{{range .Passengers}}{{.Name}}: e-ticket {{.TicketNumber}}
+{{end}}
+Please bring a valid travel document to check-in.{{end}}`,
+
+		EventOrderCancelled: `{{define "subject"}}Booking {{.PNR}} cancelled{{end}}
+{{define "body"}}Hi {{.ContactName}},
+
+Your booking {{.PNR}} has been cancelled.
+{{template "flight" .}}{{if .Refund}}
+A refund of {{.Refund}} is on its way.{{end}}{{end}}`,
+
+		EventScheduleChange: `{{define "subject"}}{{if .Change.Cancelled}}Flight {{.FlightNumber}} on {{.FlightDate}} has been cancelled{{else}}Flight {{.FlightNumber}} on {{.FlightDate}} has changed{{end}}{{end}}
+{{define "body"}}Hi {{.ContactName}},
+
+{{if .Change.Cancelled}}The airline has cancelled your flight {{.FlightNumber}} on {{.FlightDate}}.{{else}}The airline has changed your flight {{.FlightNumber}} on {{.FlightDate}}.{{end}}
+{{with index .Change.After "departure_time"}}Departure: {{index $.Change.Before "departure_time"}} -> {{.}}
+{{end}}{{with index .Change.After "arrival_time"}}Arrival: {{index $.Change.Before "arrival_time"}} -> {{.}}
+{{end}}{{with index .Change.After "aircraft"}}Aircraft: {{index $.Change.Before "aircraft"}} -> {{.}}, your seat may differ
+{{end}}
+{{if .Change.Cancelled}}Please request a full refund or rebook booking {{.PNR}} onto another flight free of charge.{{else}}Please accept the change, request a full refund or rebook booking {{.PNR}} onto another flight free of charge.{{end}}{{end}}`,
+
+		EventWhitelistGranted: `{{define "subject"}}You have early access to new flights{{end}}
+{{define "body"}}Hi {{.Name}},
+
+You can now search and book production flights of {{join .Airlines ", "}}.{{end}}`,
+
+		EventVerifyEmail: `{{define "subject"}}Verify your email address{{end}}
+{{define "body"}}Hi {{.Name}},
+
+Please confirm this is your email address with the verification code below. It works for 24 hours.
+
+{{.Token}}
+
+Bookings made as a guest with this email will be added to your account once it is verified.{{end}}`,
+	},
+
+	"id": {
+		EventOrderCreated: `{{define "subject"}}Pemesanan {{.PNR}} diterima{{end}}
+{{define "body"}}Halo {{.ContactName}},
+
+Kami telah menerima pemesanan {{.PNR}}.
+{{template "flight" .}}
+Total: {{.Total}}
+
+E-tiket Anda akan dikirim setelah pemesanan dikonfirmasi.{{end}}`,
+
+		EventOrderConfirmed: `{{define "subject"}}Pemesanan {{.PNR}} dikonfirmasi{{end}}
+{{define "body"}}Halo {{.ContactName}},
+
+Pemesanan {{.PNR}} telah dikonfirmasi dan e-tiket Anda telah diterbitkan.
+{{template "flight" .}}
+{{range .Passengers}}{{.Name}}: e-tiket {{.TicketNumber}}
+{{end}}
+Harap membawa dokumen perjalanan yang berlaku saat check-in.{{end}}`,
+
+		EventOrderCancelled: `{{define "subject"}}Pemesanan {{.PNR}} dibatalkan{{end}}
+{{define "body"}}Halo {{.ContactName}},
+
+Pemesanan {{.PNR}} telah dibatalkan.
+{{template "flight" .}}{{if .Refund}}
+Pengembalian dana sebesar {{.Refund}} sedang diproses.{{end}}{{end}}`,
+
+		EventScheduleChange: `{{define "subject"}}{{if .Change.Cancelled}}Penerbangan {{.FlightNumber}} tanggal {{.FlightDate}} dibatalkan{{else}}Penerbangan {{.FlightNumber}} tanggal {{.FlightDate}} berubah{{end}}{{end}}
+{{define "body"}}Halo {{.ContactName}},
+
+{{if .Change.Cancelled}}Maskapai telah membatalkan penerbangan {{.FlightNumber}} tanggal {{.FlightDate}}.{{else}}Maskapai telah mengubah penerbangan {{.FlightNumber}} tanggal {{.FlightDate}}.{{end}}
+{{with index .Change.After "departure_time"}}Keberangkatan: {{index $.Change.Before "departure_time"}} -> {{.}}
+{{end}}{{with index .Change.After "arrival_time"}}Kedatangan: {{index $.Change.Before "arrival_time"}} -> {{.}}
+{{end}}{{with index .Change.After "aircraft"}}Pesawat: {{index $.Change.Before "aircraft"}} -> {{.}}, kursi Anda dapat berubah
+{{end}}
+{{if .Change.Cancelled}}Silakan ajukan pengembalian dana penuh atau pindahkan pemesanan {{.PNR}} ke penerbangan lain tanpa biaya.{{else}}Silakan terima perubahan ini, ajukan pengembalian dana penuh atau pindahkan pemesanan {{.PNR}} ke penerbangan lain tanpa biaya.{{end}}{{end}}`,
+
+		EventWhitelistGranted: `{{define "subject"}}Anda mendapat akses awal ke penerbangan baru{{end}}
+{{define "body"}}Halo {{.Name}},
+
+Anda kini dapat mencari dan memesan penerbangan produksi {{join .Airlines ", "}}.{{end}}`,
+
+		EventVerifyEmail: `{{define "subject"}}Verifikasi alamat email Anda{{end}}
+{{define "body"}}Halo {{.Name}},
+
+Mohon konfirmasi bahwa ini adalah alamat email Anda dengan kode verifikasi di bawah ini. Kode berlaku selama 24 jam.
+
+{{.Token}}
+
+Pemesanan sebagai tamu dengan email ini akan ditambahkan ke akun Anda setelah email diverifikasi.{{end}}`,
+	},
+}
+
+// Locale returns the locale there are templates for closest to locale, e.g. "id" for "id-ID", and
+// DefaultLocale when there is none
+func Locale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i >= 0 {
+		locale = locale[:i]
+	}
+	if _, ok := templateSources[locale]; ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Shared by the order templates of every locale
+const flightPartial = `{{define "flight"}}
+{{.Airline}} {{.FlightNumber}}, {{.FlightDate}}
+{{.From}} {{.DepartureTime}} - {{.To}} {{.ArrivalTime}}
+{{end}}`
+
+// Templates renders the message for an event in a recipient's locale
+type Templates struct {
+	byLocale map[string]map[Event]*template.Template
+}
+
+// NewTemplates parses the built-in templates
+func NewTemplates() (*Templates, error) {
+	t := &Templates{byLocale: make(map[string]map[Event]*template.Template)}
+	funcs := template.FuncMap{"join": strings.Join}
+	for locale, sources := range templateSources {
+		t.byLocale[locale] = make(map[Event]*template.Template)
+		for event, source := range sources {
+			tmpl, err := template.New(string(event)).Funcs(funcs).Parse(flightPartial + source)
+			if err != nil {
+				return nil, fmt.Errorf("parsing %s template %s: %w", locale, event, err)
+			}
+			t.byLocale[locale][event] = tmpl
+		}
+	}
+	return t, nil
+}
+
+// Locale returns the supported locale closest to locale, e.g. "id" for "id-ID", and
+// DefaultLocale when there is none
+func (t *Templates) Locale(locale string) string {
+	return Locale(locale)
+}
+
+// Render returns the subject and body of event in locale, falling back to DefaultLocale
+func (t *Templates) Render(event Event, locale string, data any) (subject, body string, err error) {
+	tmpl, ok := t.byLocale[t.Locale(locale)][event]
+	if !ok {
+		if tmpl, ok = t.byLocale[DefaultLocale][event]; !ok {
+			return "", "", fmt.Errorf("no template for %s", event)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return "", "", err
+	}
+	subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := tmpl.ExecuteTemplate(&buf, "body", data); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimSpace(buf.String()) + "\n", nil
+}
@@ -0,0 +1,141 @@
+// Package pdf writes simple text documents as PDF using the standard Helvetica fonts,
+// enough for itineraries and receipts without pulling in a layout engine.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 portrait in points, with the margins text is laid out in
+const (
+	pageWidth  = 595.0
+	pageHeight = 842.0
+	margin     = 50.0
+)
+
+type font string
+
+const (
+	regular font = "F1" // Helvetica
+	bold    font = "F2" // Helvetica-Bold
+)
+
+type line struct {
+	text string
+	font font
+	size float64
+	y    float64
+}
+
+// Document lays out lines of text top to bottom, starting a new page when one fills up
+type Document struct {
+	pages [][]line
+	y     float64
+}
+
+func New() *Document {
+	d := &Document{}
+	d.newPage()
+	return d
+}
+
+// Heading adds a line of large bold text
+func (d *Document) Heading(text string) {
+	d.add(text, bold, 14)
+}
+
+// Bold adds a line of bold body text
+func (d *Document) Bold(text string) {
+	d.add(text, bold, 10)
+}
+
+// Text adds a line of body text
+func (d *Document) Text(text string) {
+	d.add(text, regular, 10)
+}
+
+// Space adds a blank line
+func (d *Document) Space() {
+	d.y -= 8
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pageHeight - margin
+}
+
+func (d *Document) add(text string, f font, size float64) {
+	leading := size * 1.4
+	if d.y-leading < margin {
+		d.newPage()
+	}
+	d.y -= leading
+	page := len(d.pages) - 1
+	d.pages[page] = append(d.pages[page], line{text: text, font: f, size: size, y: d.y})
+}
+
+// Bytes renders the document as a PDF file
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-4 are the catalog, page tree and fonts, then a page and its content stream per page
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, lines := range d.pages {
+		var content bytes.Buffer
+		for _, l := range lines {
+			fmt.Fprintf(&content, "BT /%s %.0f Tf %.0f %.1f Td (%s) Tj ET\n", l.font, l.size, margin, l.y, escape(l.text))
+		}
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// escape encodes text as a PDF string body in WinAnsiEncoding.
+// Characters outside Latin-1 have no glyph in the standard fonts and become '?'.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32:
+			b.WriteByte(' ')
+		case r < 128:
+			b.WriteRune(r)
+		case r < 256:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type AncillaryRepository interface {
+	Create(ancillary *models.Ancillary) error
+	FindByID(id string) (*models.Ancillary, error)
+	FindByCode(airlineID, code string) (*models.Ancillary, error)
+	Update(ancillary *models.Ancillary) error
+	Delete(id string) error
+	List(airlineID string, page, pageSize int) ([]models.Ancillary, int64, error)
+	ListActiveByAirline(airlineID string) ([]models.Ancillary, error)
+}
+
+type ancillaryRepository struct {
+	db *gorm.DB
+}
+
+func NewAncillaryRepository(db *gorm.DB) AncillaryRepository {
+	return &ancillaryRepository{db: db}
+}
+
+func (r *ancillaryRepository) Create(ancillary *models.Ancillary) error {
+	return createKeepingInactive(r.db, ancillary, ancillary.IsActive)
+}
+
+func (r *ancillaryRepository) FindByID(id string) (*models.Ancillary, error) {
+	var ancillary models.Ancillary
+	if err := r.db.First(&ancillary, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ancillary, nil
+}
+
+func (r *ancillaryRepository) FindByCode(airlineID, code string) (*models.Ancillary, error) {
+	var ancillary models.Ancillary
+	if err := r.db.First(&ancillary, "airline_id = ? AND code = ?", airlineID, code).Error; err != nil {
+		return nil, err
+	}
+	return &ancillary, nil
+}
+
+func (r *ancillaryRepository) Update(ancillary *models.Ancillary) error {
+	return r.db.Save(ancillary).Error
+}
+
+func (r *ancillaryRepository) Delete(id string) error {
+	return r.db.Delete(&models.Ancillary{}, "id = ?", id).Error
+}
+
+func (r *ancillaryRepository) List(airlineID string, page, pageSize int) ([]models.Ancillary, int64, error) {
+	var ancillaries []models.Ancillary
+	var total int64
+
+	query := r.db.Model(&models.Ancillary{})
+	if airlineID != "" {
+		query = query.Where("airline_id = ?", airlineID)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("airline_id ASC, type ASC, sort_order ASC").
+		Find(&ancillaries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return ancillaries, total, nil
+}
+
+// ListActiveByAirline returns the ancillaries an airline sells, grouped by type
+func (r *ancillaryRepository) ListActiveByAirline(airlineID string) ([]models.Ancillary, error) {
+	var ancillaries []models.Ancillary
+	if err := r.db.
+		Where("airline_id = ? AND is_active = ?", airlineID, true).
+		Order("type ASC, sort_order ASC, price ASC").
+		Find(&ancillaries).Error; err != nil {
+		return nil, err
+	}
+	return ancillaries, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "gorm.io/gorm"
+
+// createKeepingInactive inserts value, a model whose is_active column defaults to true.
+// GORM replaces a false IsActive with the column default on insert, so a row created
+// inactive is switched off again in the same transaction.
+func createKeepingInactive(db *gorm.DB, value interface{}, active bool) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(value).Error; err != nil {
+			return err
+		}
+		if active {
+			return nil
+		}
+		return tx.Model(value).UpdateColumn("is_active", false).Error
+	})
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+func TestCreateKeepingInactive(t *testing.T) {
+	db := newTestDB(t)
+
+	tests := []struct {
+		name   string
+		active bool
+	}{
+		{name: "created active", active: true},
+		{name: "created inactive", active: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := models.FareRule{Name: tt.name, Type: models.FareRuleDaysBefore, MaxValue: 14, AdjustmentPercent: 20, IsActive: tt.active}
+			if err := createKeepingInactive(db, &rule, rule.IsActive); err != nil {
+				t.Fatal(err)
+			}
+			if rule.IsActive != tt.active {
+				t.Errorf("created struct is_active = %v, want %v", rule.IsActive, tt.active)
+			}
+
+			var stored models.FareRule
+			if err := db.First(&stored, "id = ?", rule.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored.IsActive != tt.active {
+				t.Errorf("stored is_active = %v, want %v", stored.IsActive, tt.active)
+			}
+		})
+	}
+}
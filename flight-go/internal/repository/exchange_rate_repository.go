@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepository interface {
+	Create(rate *models.ExchangeRate) error
+	FindByID(id string) (*models.ExchangeRate, error)
+	Delete(id string) error
+	List(fromCurrency, toCurrency string, page, pageSize int) ([]models.ExchangeRate, int64, error)
+	FindEffective(fromCurrency, toCurrency string, at time.Time) (*models.ExchangeRate, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+func (r *exchangeRateRepository) Create(rate *models.ExchangeRate) error {
+	return r.db.Create(rate).Error
+}
+
+func (r *exchangeRateRepository) FindByID(id string) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.First(&rate, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) Delete(id string) error {
+	return r.db.Delete(&models.ExchangeRate{}, "id = ?", id).Error
+}
+
+func (r *exchangeRateRepository) List(fromCurrency, toCurrency string, page, pageSize int) ([]models.ExchangeRate, int64, error) {
+	var rates []models.ExchangeRate
+	var total int64
+
+	query := r.db.Model(&models.ExchangeRate{})
+	if fromCurrency != "" {
+		query = query.Where("from_currency = ?", fromCurrency)
+	}
+	if toCurrency != "" {
+		query = query.Where("to_currency = ?", toCurrency)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("from_currency ASC, to_currency ASC, effective_from DESC").
+		Find(&rates).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rates, total, nil
+}
+
+// FindEffective returns the pair's latest rate that took effect at or before at
+func (r *exchangeRateRepository) FindEffective(fromCurrency, toCurrency string, at time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	if err := r.db.
+		Where("from_currency = ? AND to_currency = ? AND effective_from <= ?", fromCurrency, toCurrency, at).
+		Order("effective_from DESC").
+		First(&rate).Error; err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
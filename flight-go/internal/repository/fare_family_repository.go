@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type FareFamilyRepository interface {
+	Create(family *models.FareFamily) error
+	FindByID(id string) (*models.FareFamily, error)
+	Update(family *models.FareFamily) error
+	Delete(id string) error
+	List(airlineID string, page, pageSize int) ([]models.FareFamily, int64, error)
+	ListActiveForCabin(airlineID string, cabin models.CabinClass) ([]models.FareFamily, error)
+}
+
+type fareFamilyRepository struct {
+	db *gorm.DB
+}
+
+func NewFareFamilyRepository(db *gorm.DB) FareFamilyRepository {
+	return &fareFamilyRepository{db: db}
+}
+
+func (r *fareFamilyRepository) Create(family *models.FareFamily) error {
+	return createKeepingInactive(r.db, family, family.IsActive)
+}
+
+func (r *fareFamilyRepository) FindByID(id string) (*models.FareFamily, error) {
+	var family models.FareFamily
+	if err := r.db.First(&family, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &family, nil
+}
+
+func (r *fareFamilyRepository) Update(family *models.FareFamily) error {
+	return r.db.Save(family).Error
+}
+
+func (r *fareFamilyRepository) Delete(id string) error {
+	return r.db.Delete(&models.FareFamily{}, "id = ?", id).Error
+}
+
+func (r *fareFamilyRepository) List(airlineID string, page, pageSize int) ([]models.FareFamily, int64, error) {
+	var families []models.FareFamily
+	var total int64
+
+	query := r.db.Model(&models.FareFamily{})
+	if airlineID != "" {
+		query = query.Where("airline_id = ?", airlineID)
+	}
+
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("airline_id ASC, cabin_class ASC, sort_order ASC").
+		Find(&families).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return families, total, nil
+}
+
+// ListActiveForCabin returns the families sold in an airline's cabin, cheapest tier first
+func (r *fareFamilyRepository) ListActiveForCabin(airlineID string, cabin models.CabinClass) ([]models.FareFamily, error) {
+	var families []models.FareFamily
+	if err := r.db.
+		Where("airline_id = ? AND cabin_class = ? AND is_active = ?", airlineID, cabin, true).
+		Order("sort_order ASC, price_multiplier ASC").
+		Find(&families).Error; err != nil {
+		return nil, err
+	}
+	return families, nil
+}
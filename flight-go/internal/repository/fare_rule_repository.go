@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type FareRuleRepository interface {
+	Create(rule *models.FareRule) error
+	FindByID(id string) (*models.FareRule, error)
+	Update(rule *models.FareRule) error
+	Delete(id string) error
+	List(page, pageSize int) ([]models.FareRule, int64, error)
+	ListActive() ([]models.FareRule, error)
+}
+
+type fareRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewFareRuleRepository(db *gorm.DB) FareRuleRepository {
+	return &fareRuleRepository{db: db}
+}
+
+func (r *fareRuleRepository) Create(rule *models.FareRule) error {
+	return createKeepingInactive(r.db, rule, rule.IsActive)
+}
+
+func (r *fareRuleRepository) FindByID(id string) (*models.FareRule, error) {
+	var rule models.FareRule
+	if err := r.db.First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *fareRuleRepository) Update(rule *models.FareRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *fareRuleRepository) Delete(id string) error {
+	return r.db.Delete(&models.FareRule{}, "id = ?", id).Error
+}
+
+func (r *fareRuleRepository) List(page, pageSize int) ([]models.FareRule, int64, error) {
+	var rules []models.FareRule
+	var total int64
+
+	r.db.Model(&models.FareRule{}).Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := r.db.
+		Offset(offset).
+		Limit(pageSize).
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rules, total, nil
+}
+
+// ListActive returns active rules in the order they apply
+func (r *fareRuleRepository) ListActive() ([]models.FareRule, error) {
+	var rules []models.FareRule
+	if err := r.db.
+		Where("is_active = ?", true).
+		Order("priority ASC, created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type FeeRuleRepository interface {
+	Create(rule *models.FeeRule) error
+	FindByID(id string) (*models.FeeRule, error)
+	Update(rule *models.FeeRule) error
+	Delete(id string) error
+	List(page, pageSize int) ([]models.FeeRule, int64, error)
+	ListActive() ([]models.FeeRule, error)
+}
+
+type feeRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewFeeRuleRepository(db *gorm.DB) FeeRuleRepository {
+	return &feeRuleRepository{db: db}
+}
+
+func (r *feeRuleRepository) Create(rule *models.FeeRule) error {
+	return createKeepingInactive(r.db, rule, rule.IsActive)
+}
+
+func (r *feeRuleRepository) FindByID(id string) (*models.FeeRule, error) {
+	var rule models.FeeRule
+	if err := r.db.First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *feeRuleRepository) Update(rule *models.FeeRule) error {
+	return r.db.Save(rule).Error
+}
+
+func (r *feeRuleRepository) Delete(id string) error {
+	return r.db.Delete(&models.FeeRule{}, "id = ?", id).Error
+}
+
+func (r *feeRuleRepository) List(page, pageSize int) ([]models.FeeRule, int64, error) {
+	var rules []models.FeeRule
+	var total int64
+
+	r.db.Model(&models.FeeRule{}).Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := r.db.
+		Offset(offset).
+		Limit(pageSize).
+		Order("type ASC, created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return rules, total, nil
+}
+
+// ListActive returns active fee rules grouped by type
+func (r *feeRuleRepository) ListActive() ([]models.FeeRule, error) {
+	var rules []models.FeeRule
+	if err := r.db.
+		Where("is_active = ?", true).
+		Order("type ASC, created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
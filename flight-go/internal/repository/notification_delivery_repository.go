@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type NotificationDeliveryRepository interface {
+	CreateBatch(deliveries []models.NotificationDelivery) error
+	ListDue(now time.Time, limit int) ([]models.NotificationDelivery, error)
+	Update(delivery *models.NotificationDelivery) error
+	List(recipient string, status models.DeliveryStatus, page, pageSize int) ([]models.NotificationDelivery, int64, error)
+}
+
+type notificationDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationDeliveryRepository(db *gorm.DB) NotificationDeliveryRepository {
+	return &notificationDeliveryRepository{db: db}
+}
+
+func (r *notificationDeliveryRepository) CreateBatch(deliveries []models.NotificationDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+// ListDue returns pending deliveries whose next attempt is at or before now, oldest first
+func (r *notificationDeliveryRepository) ListDue(now time.Time, limit int) ([]models.NotificationDelivery, error) {
+	var deliveries []models.NotificationDelivery
+	err := r.db.
+		Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *notificationDeliveryRepository) Update(delivery *models.NotificationDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// List returns the delivery log, newest first, optionally for one recipient or status
+func (r *notificationDeliveryRepository) List(recipient string, status models.DeliveryStatus, page, pageSize int) ([]models.NotificationDelivery, int64, error) {
+	var deliveries []models.NotificationDelivery
+	var total int64
+
+	query := r.db.Model(&models.NotificationDelivery{})
+	if recipient != "" {
+		query = query.Where("LOWER(recipient) = LOWER(?)", recipient)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateBatch(notifications []models.Notification) error
+	FindByID(id string) (*models.Notification, error)
+	MarkRead(notification *models.Notification, at time.Time) error
+	ListByUser(userID string, unreadOnly bool, page, pageSize int) ([]models.Notification, int64, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+func (r *notificationRepository) FindByID(id string) (*models.Notification, error) {
+	var notification models.Notification
+	if err := r.db.First(&notification, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+func (r *notificationRepository) MarkRead(notification *models.Notification, at time.Time) error {
+	if err := r.db.Model(notification).UpdateColumn("read_at", at).Error; err != nil {
+		return err
+	}
+	notification.ReadAt = &at
+	return nil
+}
+
+// ListByUser returns a user's notifications, newest first
+func (r *notificationRepository) ListByUser(userID string, unreadOnly bool, page, pageSize int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
@@ -1,18 +1,47 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCabinFull      = errors.New("not enough seats left in cabin")
+	ErrFareFamilyFull = errors.New("fare family sold out")
+)
+
+// SeatLimit is the capacity a new order or a flight change has to fit in
+type SeatLimit struct {
+	CabinSeats  int // Seats in the order's cabin
+	FamilySeats int // Seat bucket of the order's fare family, 0 = whole cabin
+}
+
 type OrderRepository interface {
 	Create(order *models.Order) error
 	FindByID(id string) (*models.Order, error)
+	FindByPNR(pnr string) (*models.Order, error)
 	Update(order *models.Order) error
 	Delete(id string) error
 	List(page, pageSize int) ([]models.Order, int64, error)
 	ListByUser(userID string, page, pageSize int) ([]models.Order, int64, error)
+	ListUpcomingBySchedule(env models.Environment, scheduleID string, from time.Time) ([]models.Order, error)
+	MarkDisrupted(orderIDs []string, disruptionID string) error
+	ClaimGuestOrder(id, userID string) (bool, error)
+	ClaimGuestOrders(userID, contactEmail string) (int64, error)
 	AddPassenger(passenger *models.Passenger) error
+	UpdatePrice(order *models.Order) error
+	IssueTickets(order *models.Order, airlineCode string) error
+	CheckSeatLimit(order *models.Order, limit SeatLimit) error
+	MoveFlight(order *models.Order, fromScheduleID string, fromDate time.Time) (bool, error)
+	RenamePassenger(passenger *models.Passenger) error
+	AddAncillaries(ancillaries []models.OrderAncillary) error
+	AddChange(change *models.OrderChange) error
+	ListChanges(orderID string) ([]models.OrderChange, error)
+	CountBookedSeats(env models.Environment, scheduleID string, flightDate time.Time, cabin models.CabinClass) (int64, error)
+	CountFareFamilySeats(env models.Environment, scheduleID string, flightDate time.Time, fareFamilyID string) (int64, error)
 }
 
 type orderRepository struct {
@@ -23,25 +52,50 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	return &orderRepository{db: db}
 }
 
+// referenceAttempts bounds how often a colliding PNR or ticket number is redrawn
+const referenceAttempts = 5
+
+// Create saves the order under a new PNR, drawing another if it collides with an existing booking.
+// Each attempt runs in its own (nested) transaction, so a collision leaves a surrounding one usable.
 func (r *orderRepository) Create(order *models.Order) error {
-	return r.db.Create(order).Error
+	for attempt := 1; ; attempt++ {
+		order.PNR = models.NewPNR()
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(order).Error
+		})
+		if err == nil || attempt == referenceAttempts || !r.isDuplicateKey(err) {
+			return err
+		}
+	}
 }
 
 func (r *orderRepository) FindByID(id string) (*models.Order, error) {
 	var order models.Order
 	if err := r.db.
 		Preload("User").
-		Preload("Schedule").
-		Preload("Schedule.Airline").
-		Preload("Schedule.DepartureAirport").
-		Preload("Schedule.ArrivalAirport").
+		Preload("Disruption").
 		Preload("Passengers").
+		Preload("Passengers.Seat").
+		Preload("Passengers.Ancillaries", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		First(&order, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
+func (r *orderRepository) FindByPNR(pnr string) (*models.Order, error) {
+	var order models.Order
+	if err := r.db.
+		Preload("Disruption").
+		Preload("Passengers").
+		Preload("Passengers.Seat").
+		Preload("Passengers.Ancillaries", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(&order, "pnr = ?", pnr).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
 func (r *orderRepository) Update(order *models.Order) error {
 	return r.db.Save(order).Error
 }
@@ -59,11 +113,10 @@ func (r *orderRepository) List(page, pageSize int) ([]models.Order, int64, error
 	offset := (page - 1) * pageSize
 	if err := r.db.
 		Preload("User").
-		Preload("Schedule").
-		Preload("Schedule.Airline").
-		Preload("Schedule.DepartureAirport").
-		Preload("Schedule.ArrivalAirport").
+		Preload("Disruption").
 		Preload("Passengers").
+		Preload("Passengers.Seat").
+		Preload("Passengers.Ancillaries", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		Offset(offset).
 		Limit(pageSize).
 		Order("created_at DESC").
@@ -83,11 +136,10 @@ func (r *orderRepository) ListByUser(userID string, page, pageSize int) ([]model
 
 	offset := (page - 1) * pageSize
 	if err := query.
-		Preload("Schedule").
-		Preload("Schedule.Airline").
-		Preload("Schedule.DepartureAirport").
-		Preload("Schedule.ArrivalAirport").
+		Preload("Disruption").
 		Preload("Passengers").
+		Preload("Passengers.Seat").
+		Preload("Passengers.Ancillaries", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
 		Offset(offset).
 		Limit(pageSize).
 		Order("created_at DESC").
@@ -98,7 +150,191 @@ func (r *orderRepository) ListByUser(userID string, page, pageSize int) ([]model
 	return orders, total, nil
 }
 
+// ListUpcomingBySchedule returns the live orders on a schedule of env flying on from or later
+func (r *orderRepository) ListUpcomingBySchedule(env models.Environment, scheduleID string, from time.Time) ([]models.Order, error) {
+	var orders []models.Order
+	err := r.db.
+		Where("environment = ? AND schedule_id = ?", env, scheduleID).
+		Where("DATE(flight_date) >= ?", from.Format("2006-01-02")).
+		Where("status IN ?", []models.OrderStatus{models.OrderPending, models.OrderConfirmed}).
+		Find(&orders).Error
+	return orders, err
+}
+
+// MarkDisrupted points orders at a disruption of their flight and waits for their passengers to respond
+func (r *orderRepository) MarkDisrupted(orderIDs []string, disruptionID string) error {
+	return r.db.Model(&models.Order{}).Where("id IN ?", orderIDs).
+		Select("DisruptionID", "DisruptionStatus").
+		Updates(&models.Order{DisruptionID: disruptionID, DisruptionStatus: models.DisruptionActionRequired}).Error
+}
+
+// ClaimGuestOrders moves the guest orders booked with contactEmail to userID, returning how many
+func (r *orderRepository) ClaimGuestOrders(userID, contactEmail string) (int64, error) {
+	result := r.db.Model(&models.Order{}).
+		Where("user_id = ''").
+		Where("LOWER(contact_email) = LOWER(?)", contactEmail).
+		Update("user_id", userID)
+	return result.RowsAffected, result.Error
+}
+
+// ClaimGuestOrder moves a guest order to userID, reporting false when it already has an owner
+func (r *orderRepository) ClaimGuestOrder(id, userID string) (bool, error) {
+	result := r.db.Model(&models.Order{}).
+		Where("id = ? AND user_id = ''", id).
+		Update("user_id", userID)
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *orderRepository) AddPassenger(passenger *models.Passenger) error {
 	return r.db.Create(passenger).Error
 }
 
+// UpdatePrice saves the order's price breakdown and total, leaving its other columns alone
+func (r *orderRepository) UpdatePrice(order *models.Order) error {
+	repriced := &models.Order{PriceBreakdown: order.PriceBreakdown, TotalAmount: order.TotalAmount}
+	return r.db.Model(&models.Order{}).Where("id = ?", order.ID).
+		Select("PriceBreakdown", "TotalAmount").
+		Updates(repriced).Error
+}
+
+// IssueTickets gives each passenger on the order without an e-ticket a new ticket number
+func (r *orderRepository) IssueTickets(order *models.Order, airlineCode string) error {
+	for i := range order.Passengers {
+		passenger := &order.Passengers[i]
+		if passenger.TicketNumber != nil {
+			continue
+		}
+
+		for attempt := 1; ; attempt++ {
+			number := models.NewTicketNumber(airlineCode)
+			err := r.db.Transaction(func(tx *gorm.DB) error {
+				return tx.Model(passenger).UpdateColumn("ticket_number", number).Error
+			})
+			if err == nil {
+				passenger.TicketNumber = &number
+				break
+			}
+			if attempt == referenceAttempts || !r.isDuplicateKey(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isDuplicateKey reports whether err is a unique constraint violation, in the driver's own terms
+func (r *orderRepository) isDuplicateKey(err error) bool {
+	if translator, ok := r.db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// MoveFlight saves the order's new schedule, flight date and price, and moves its ancillaries
+// along with the passengers. Seats stay behind, release them with the seat assignments.
+// It reports false, changing nothing, when the order is no longer pending or confirmed or no longer
+// flies fromScheduleID on fromDate, so a change priced on a stale read never lands on top of a
+// concurrent cancellation or change.
+func (r *orderRepository) MoveFlight(order *models.Order, fromScheduleID string, fromDate time.Time) (bool, error) {
+	moved := &models.Order{
+		ScheduleID:       order.ScheduleID,
+		Flight:           order.Flight,
+		FlightDate:       order.FlightDate,
+		FareFamilyID:     order.FareFamilyID,
+		FareQuote:        order.FareQuote,
+		DisruptionStatus: order.DisruptionStatus,
+		PriceBreakdown:   order.PriceBreakdown,
+		TotalAmount:      order.TotalAmount,
+	}
+	result := r.db.Model(&models.Order{}).
+		Where("id = ? AND status IN ?", order.ID, []models.OrderStatus{models.OrderPending, models.OrderConfirmed}).
+		Where("schedule_id = ? AND flight_date = ?", fromScheduleID, fromDate).
+		Select("ScheduleID", "Flight", "FlightDate", "FareFamilyID", "FareQuote", "PriceBreakdown", "TotalAmount", "DisruptionStatus").
+		Updates(moved)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, r.db.Model(&models.OrderAncillary{}).Where("order_id = ?", order.ID).
+		Select("ScheduleID", "FlightDate").
+		Updates(&models.OrderAncillary{ScheduleID: order.ScheduleID, FlightDate: order.FlightDate}).Error
+}
+
+// CheckSeatLimit fails with ErrCabinFull or ErrFareFamilyFull when the seats booked on the order's
+// flight, its own passengers included, no longer fit in limit. Run it in the transaction that writes
+// the order, after the write, so concurrent bookings cannot both pass.
+func (r *orderRepository) CheckSeatLimit(order *models.Order, limit SeatLimit) error {
+	booked, err := r.CountBookedSeats(order.Environment, order.ScheduleID, order.FlightDate, order.CabinClass)
+	if err != nil {
+		return err
+	}
+	if booked > int64(limit.CabinSeats) {
+		return ErrCabinFull
+	}
+	if order.FareFamilyID != "" && limit.FamilySeats > 0 {
+		booked, err := r.CountFareFamilySeats(order.Environment, order.ScheduleID, order.FlightDate, order.FareFamilyID)
+		if err != nil {
+			return err
+		}
+		if booked > int64(limit.FamilySeats) {
+			return ErrFareFamilyFull
+		}
+	}
+	return nil
+}
+
+// AddAncillaries saves ancillaries bought after booking
+func (r *orderRepository) AddAncillaries(ancillaries []models.OrderAncillary) error {
+	if len(ancillaries) == 0 {
+		return nil
+	}
+	return r.db.Create(&ancillaries).Error
+}
+
+// RenamePassenger saves a passenger's corrected title and name
+func (r *orderRepository) RenamePassenger(passenger *models.Passenger) error {
+	return r.db.Model(passenger).Updates(map[string]interface{}{
+		"title":     passenger.Title,
+		"full_name": passenger.FullName,
+	}).Error
+}
+
+func (r *orderRepository) AddChange(change *models.OrderChange) error {
+	return r.db.Create(change).Error
+}
+
+// ListChanges returns an order's history, oldest first
+func (r *orderRepository) ListChanges(orderID string) ([]models.OrderChange, error) {
+	var changes []models.OrderChange
+	err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&changes).Error
+	return changes, err
+}
+
+// CountBookedSeats counts seat-occupying passengers (infants sit on laps) on live orders for
+// a schedule of env
+func (r *orderRepository) CountBookedSeats(env models.Environment, scheduleID string, flightDate time.Time, cabin models.CabinClass) (int64, error) {
+	var count int64
+	err := r.bookedSeats(env, scheduleID, flightDate).
+		Where("orders.cabin_class = ?", cabin).
+		Count(&count).Error
+	return count, err
+}
+
+// CountFareFamilySeats counts seats booked in a fare family's bucket
+func (r *orderRepository) CountFareFamilySeats(env models.Environment, scheduleID string, flightDate time.Time, fareFamilyID string) (int64, error) {
+	var count int64
+	err := r.bookedSeats(env, scheduleID, flightDate).
+		Where("orders.fare_family_id = ?", fareFamilyID).
+		Count(&count).Error
+	return count, err
+}
+
+// bookedSeats scopes passengers to a flight, staging and production schedules share IDs
+func (r *orderRepository) bookedSeats(env models.Environment, scheduleID string, flightDate time.Time) *gorm.DB {
+	return r.db.Model(&models.Passenger{}).
+		Joins("JOIN orders ON orders.id = passengers.order_id AND orders.deleted_at IS NULL").
+		Where("orders.environment = ?", env).
+		Where("orders.schedule_id = ?", scheduleID).
+		Where("DATE(orders.flight_date) = ?", flightDate.Format("2006-01-02")).
+		Where("orders.status IN ?", []models.OrderStatus{models.OrderPending, models.OrderConfirmed, models.OrderCompleted}).
+		Where("passengers.type <> ?", models.PassengerInfant)
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+// errRedemptionRejected rolls back a redemption that would exceed the per-user limit
+var errRedemptionRejected = errors.New("redemption rejected")
+
+type PromotionRepository interface {
+	Create(promotion *models.Promotion) error
+	FindByID(id string) (*models.Promotion, error)
+	FindByCode(code string) (*models.Promotion, error)
+	Update(promotion *models.Promotion) error
+	Delete(id string) error
+	List(page, pageSize int) ([]models.Promotion, int64, error)
+	CountUserRedemptions(promotionID, userID string) (int64, error)
+	Redeem(redemption *models.PromotionRedemption, perUserLimit int) (bool, error)
+	Release(orderID string) error
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{db: db}
+}
+
+func (r *promotionRepository) Create(promotion *models.Promotion) error {
+	return createKeepingInactive(r.db, promotion, promotion.IsActive)
+}
+
+func (r *promotionRepository) FindByID(id string) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.First(&promotion, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+func (r *promotionRepository) FindByCode(code string) (*models.Promotion, error) {
+	var promotion models.Promotion
+	if err := r.db.First(&promotion, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// Update saves everything except UsedCount, which only Redeem and Release change
+func (r *promotionRepository) Update(promotion *models.Promotion) error {
+	return r.db.Omit("used_count").Save(promotion).Error
+}
+
+func (r *promotionRepository) Delete(id string) error {
+	return r.db.Delete(&models.Promotion{}, "id = ?", id).Error
+}
+
+func (r *promotionRepository) List(page, pageSize int) ([]models.Promotion, int64, error) {
+	var promotions []models.Promotion
+	var total int64
+
+	r.db.Model(&models.Promotion{}).Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := r.db.
+		Offset(offset).
+		Limit(pageSize).
+		Order("valid_until DESC, code ASC").
+		Find(&promotions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return promotions, total, nil
+}
+
+func (r *promotionRepository) CountUserRedemptions(promotionID, userID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PromotionRedemption{}).
+		Where("promotion_id = ? AND user_id = ?", promotionID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+// Redeem records a redemption unless it would take the promotion past its usage
+// limit or the user past perUserLimit, reporting whether it was recorded.
+// The conditional increment locks the promotion row, so concurrent redemptions of
+// one code are serialised and the limit checks never act on a stale count.
+func (r *promotionRepository) Redeem(redemption *models.PromotionRedemption, perUserLimit int) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Promotion{}).
+			Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", redemption.PromotionID).
+			UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errRedemptionRejected
+		}
+
+		if perUserLimit > 0 {
+			var used int64
+			if err := tx.Model(&models.PromotionRedemption{}).
+				Where("promotion_id = ? AND user_id = ?", redemption.PromotionID, redemption.UserID).
+				Count(&used).Error; err != nil {
+				return err
+			}
+			if used >= int64(perUserLimit) {
+				return errRedemptionRejected
+			}
+		}
+
+		return tx.Create(redemption).Error
+	})
+	if err == errRedemptionRejected {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Release gives back the redemption made by an order, if any
+func (r *promotionRepository) Release(orderID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var redemption models.PromotionRedemption
+		if err := tx.First(&redemption, "order_id = ?", orderID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		// Hard delete so the order_id unique index only covers live redemptions
+		if err := tx.Unscoped().Delete(&redemption).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Promotion{}).
+			Where("id = ? AND used_count > 0", redemption.PromotionID).
+			UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+	})
+}
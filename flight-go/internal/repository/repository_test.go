@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/crypto"
+	"github.com/mirahekatiket/flight-go/internal/database"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testDBCount atomic.Int64
+
+func init() {
+	cipher, err := crypto.NewCipher("test-document-key")
+	if err != nil {
+		panic(err)
+	}
+	crypto.RegisterSerializer(cipher)
+}
+
+// newTestDB opens an empty, migrated in-memory database
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:repository-test-%d?mode=memory&cache=shared", testDBCount.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type ScheduleDisruptionRepository interface {
+	Create(disruption *models.ScheduleDisruption) error
+	ListBySchedule(env models.Environment, scheduleID string) ([]models.ScheduleDisruption, error)
+}
+
+type scheduleDisruptionRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleDisruptionRepository(db *gorm.DB) ScheduleDisruptionRepository {
+	return &scheduleDisruptionRepository{db: db}
+}
+
+func (r *scheduleDisruptionRepository) Create(disruption *models.ScheduleDisruption) error {
+	return r.db.Create(disruption).Error
+}
+
+// ListBySchedule returns the disruptions of a schedule of env, latest first
+func (r *scheduleDisruptionRepository) ListBySchedule(env models.Environment, scheduleID string) ([]models.ScheduleDisruption, error) {
+	var disruptions []models.ScheduleDisruption
+	err := r.db.
+		Where("environment = ? AND schedule_id = ?", env, scheduleID).
+		Order("created_at DESC").
+		Find(&disruptions).Error
+	return disruptions, err
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"time"
 
 	"github.com/mirahekatiket/flight-go/internal/models"
@@ -10,11 +11,13 @@ import (
 type ScheduleRepository interface {
 	Create(schedule *models.Schedule) error
 	FindByID(id string) (*models.Schedule, error)
+	FindByIDs(ids []string) ([]models.Schedule, error)
 	Update(schedule *models.Schedule) error
 	Delete(id string) error
 	List(page, pageSize int) ([]models.Schedule, int64, error)
 	ListByAirline(airlineID string, page, pageSize int) ([]models.Schedule, int64, error)
 	Search(params SearchParams) ([]models.Schedule, int64, error)
+	SearchContext(ctx context.Context, params SearchParams) ([]models.Schedule, int64, error)
 }
 
 type SearchParams struct {
@@ -51,6 +54,18 @@ func (r *scheduleRepository) FindByID(id string) (*models.Schedule, error) {
 	return &schedule, nil
 }
 
+// FindByIDs returns the schedules among ids that exist, in no particular order
+func (r *scheduleRepository) FindByIDs(ids []string) ([]models.Schedule, error) {
+	var schedules []models.Schedule
+	err := r.db.
+		Preload("Airline").
+		Preload("DepartureAirport").
+		Preload("ArrivalAirport").
+		Where("id IN ?", ids).
+		Find(&schedules).Error
+	return schedules, err
+}
+
 func (r *scheduleRepository) Update(schedule *models.Schedule) error {
 	return r.db.Save(schedule).Error
 }
@@ -101,10 +116,15 @@ func (r *scheduleRepository) ListByAirline(airlineID string, page, pageSize int)
 }
 
 func (r *scheduleRepository) Search(params SearchParams) ([]models.Schedule, int64, error) {
+	return r.SearchContext(context.Background(), params)
+}
+
+// SearchContext runs Search bound to ctx so the query is interrupted when ctx is done
+func (r *scheduleRepository) SearchContext(ctx context.Context, params SearchParams) ([]models.Schedule, int64, error) {
 	var schedules []models.Schedule
 	var total int64
 
-	query := r.db.Model(&models.Schedule{}).
+	query := r.db.WithContext(ctx).Model(&models.Schedule{}).
 		Joins("JOIN airports dep ON dep.id = schedules.departure_airport_id").
 		Joins("JOIN airports arr ON arr.id = schedules.arrival_airport_id").
 		Where("schedules.is_active = ?", true)
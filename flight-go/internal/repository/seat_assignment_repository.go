@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+var ErrSeatTaken = errors.New("seat already taken")
+
+// SeatAssignmentRepository stores the seats held on each flight date.
+// Released assignments are deleted outright so their seats can be taken again.
+type SeatAssignmentRepository interface {
+	ListByFlight(env models.Environment, scheduleID string, flightDate time.Time) ([]models.SeatAssignment, error)
+	ListByOrder(orderID string) ([]models.SeatAssignment, error)
+	Assign(assignments []models.SeatAssignment) error
+	ReplaceForOrder(orderID string, assignments []models.SeatAssignment) error
+	ReleaseByOrder(orderID string) error
+}
+
+type seatAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatAssignmentRepository(db *gorm.DB) SeatAssignmentRepository {
+	return &seatAssignmentRepository{db: db}
+}
+
+func (r *seatAssignmentRepository) ListByFlight(env models.Environment, scheduleID string, flightDate time.Time) ([]models.SeatAssignment, error) {
+	var assignments []models.SeatAssignment
+	err := r.db.Where("environment = ? AND schedule_id = ? AND flight_date = ?", env, scheduleID, flightDate).Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *seatAssignmentRepository) ListByOrder(orderID string) ([]models.SeatAssignment, error) {
+	var assignments []models.SeatAssignment
+	err := r.db.Where("order_id = ?", orderID).Find(&assignments).Error
+	return assignments, err
+}
+
+// Assign holds every seat or none, failing with ErrSeatTaken when one is already held
+func (r *seatAssignmentRepository) Assign(assignments []models.SeatAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return r.create(tx, assignments)
+	})
+}
+
+// ReplaceForOrder swaps the order's seats for assignments, all or nothing
+func (r *seatAssignmentRepository) ReplaceForOrder(orderID string, assignments []models.SeatAssignment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("order_id = ?", orderID).Delete(&models.SeatAssignment{}).Error; err != nil {
+			return err
+		}
+		return r.create(tx, assignments)
+	})
+}
+
+func (r *seatAssignmentRepository) ReleaseByOrder(orderID string) error {
+	return r.db.Unscoped().Where("order_id = ?", orderID).Delete(&models.SeatAssignment{}).Error
+}
+
+func (r *seatAssignmentRepository) create(tx *gorm.DB, assignments []models.SeatAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	err := tx.Create(&assignments).Error
+	if err == nil {
+		return nil
+	}
+	if translator, ok := tx.Dialector.(gorm.ErrorTranslator); ok && errors.Is(translator.Translate(err), gorm.ErrDuplicatedKey) {
+		return ErrSeatTaken
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type SeatMapRepository interface {
+	Create(seatMap *models.SeatMap) error
+	FindByID(id string) (*models.SeatMap, error)
+	FindByAircraft(aircraft string) (*models.SeatMap, error)
+	Update(seatMap *models.SeatMap) error
+	Delete(id string) error
+	List(page, pageSize int) ([]models.SeatMap, int64, error)
+}
+
+type seatMapRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatMapRepository(db *gorm.DB) SeatMapRepository {
+	return &seatMapRepository{db: db}
+}
+
+func (r *seatMapRepository) Create(seatMap *models.SeatMap) error {
+	return r.db.Create(seatMap).Error
+}
+
+func (r *seatMapRepository) FindByID(id string) (*models.SeatMap, error) {
+	var seatMap models.SeatMap
+	if err := r.db.First(&seatMap, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &seatMap, nil
+}
+
+func (r *seatMapRepository) FindByAircraft(aircraft string) (*models.SeatMap, error) {
+	var seatMap models.SeatMap
+	if err := r.db.First(&seatMap, "aircraft = ?", aircraft).Error; err != nil {
+		return nil, err
+	}
+	return &seatMap, nil
+}
+
+func (r *seatMapRepository) Update(seatMap *models.SeatMap) error {
+	return r.db.Save(seatMap).Error
+}
+
+func (r *seatMapRepository) Delete(id string) error {
+	return r.db.Unscoped().Delete(&models.SeatMap{}, "id = ?", id).Error
+}
+
+func (r *seatMapRepository) List(page, pageSize int) ([]models.SeatMap, int64, error) {
+	var seatMaps []models.SeatMap
+	var total int64
+
+	r.db.Model(&models.SeatMap{}).Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := r.db.Offset(offset).Limit(pageSize).Order("aircraft ASC").Find(&seatMaps).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return seatMaps, total, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *models.Session) error
+	FindByRefreshTokenHash(hash string) (*models.Session, error)
+	Rotate(session *models.Session, newHash string, expiresAt time.Time) (bool, error)
+	Revoke(id string, at time.Time) error
+	RevokeAllByUser(userID string, at time.Time) ([]string, error)
+	ListRevokedSince(since time.Time) ([]models.Session, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+// FindByRefreshTokenHash returns the session whose current or previous refresh token hashes to hash
+func (r *sessionRepository) FindByRefreshTokenHash(hash string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.First(&session, "refresh_token_hash = ? OR previous_token_hash = ?", hash, hash).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate replaces the session's refresh token with newHash, unless the session was revoked or
+// rotated by someone else since it was read. It reports whether it did.
+func (r *sessionRepository) Rotate(session *models.Session, newHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, session.RefreshTokenHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": session.RefreshTokenHash,
+			"expires_at":          expiresAt,
+			"updated_at":          time.Now(),
+		})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	session.PreviousTokenHash = session.RefreshTokenHash
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = expiresAt
+	return true, nil
+}
+
+func (r *sessionRepository) Revoke(id string, at time.Time) error {
+	return r.db.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		UpdateColumn("revoked_at", at).Error
+}
+
+// RevokeAllByUser revokes every live session of a user, returning their IDs
+func (r *sessionRepository) RevokeAllByUser(userID string, at time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Session{}).
+			Where("id IN ?", ids).
+			UpdateColumn("revoked_at", at).Error
+	})
+	return ids, err
+}
+
+// ListRevokedSince returns the sessions revoked at or after since
+func (r *sessionRepository) ListRevokedSince(since time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.Where("revoked_at >= ?", since).Find(&sessions).Error
+	return sessions, err
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type TravelerRepository interface {
+	Create(traveler *models.Traveler) error
+	FindByID(id string) (*models.Traveler, error)
+	FindByUserAndName(userID, fullName string) ([]models.Traveler, error)
+	Update(traveler *models.Traveler) error
+	Delete(id string) error
+	ListByUser(userID string, page, pageSize int) ([]models.Traveler, int64, error)
+}
+
+type travelerRepository struct {
+	db *gorm.DB
+}
+
+func NewTravelerRepository(db *gorm.DB) TravelerRepository {
+	return &travelerRepository{db: db}
+}
+
+func (r *travelerRepository) Create(traveler *models.Traveler) error {
+	return r.db.Create(traveler).Error
+}
+
+func (r *travelerRepository) FindByID(id string) (*models.Traveler, error) {
+	var traveler models.Traveler
+	if err := r.db.First(&traveler, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &traveler, nil
+}
+
+// FindByUserAndName returns a user's travelers with the same name, ignoring case
+func (r *travelerRepository) FindByUserAndName(userID, fullName string) ([]models.Traveler, error) {
+	var travelers []models.Traveler
+	err := r.db.
+		Where("user_id = ? AND LOWER(full_name) = LOWER(?)", userID, fullName).
+		Find(&travelers).Error
+	return travelers, err
+}
+
+func (r *travelerRepository) Update(traveler *models.Traveler) error {
+	return r.db.Save(traveler).Error
+}
+
+func (r *travelerRepository) Delete(id string) error {
+	return r.db.Delete(&models.Traveler{}, "id = ?", id).Error
+}
+
+func (r *travelerRepository) ListByUser(userID string, page, pageSize int) ([]models.Traveler, int64, error) {
+	var travelers []models.Traveler
+	var total int64
+
+	query := r.db.Model(&models.Traveler{}).Where("user_id = ?", userID)
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("full_name ASC").
+		Find(&travelers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return travelers, total, nil
+}
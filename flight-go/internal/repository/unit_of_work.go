@@ -0,0 +1,41 @@
+package repository
+
+import "gorm.io/gorm"
+
+// Repositories are the repositories a multi-step write goes through, all bound to one transaction
+type Repositories struct {
+	Orders          OrderRepository
+	SeatAssignments SeatAssignmentRepository
+	Promotions      PromotionRepository
+	Disruptions     ScheduleDisruptionRepository
+	Notifications   NotificationRepository
+	Users           UserRepository
+}
+
+// UnitOfWork runs writes spanning several repositories in one database transaction
+type UnitOfWork interface {
+	// Do runs fn with repositories bound to a new transaction, committing it when fn returns nil
+	// and rolling everything back when it returns an error
+	Do(fn func(repos Repositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(fn func(repos Repositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Orders:          &orderRepository{db: tx},
+			SeatAssignments: &seatAssignmentRepository{db: tx},
+			Promotions:      &promotionRepository{db: tx},
+			Disruptions:     &scheduleDisruptionRepository{db: tx},
+			Notifications:   &notificationRepository{db: tx},
+			Users:           &userRepository{db: tx},
+		})
+	})
+}
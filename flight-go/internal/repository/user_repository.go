@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"gorm.io/gorm"
 )
@@ -10,8 +12,14 @@ type UserRepository interface {
 	FindByID(id string) (*models.User, error)
 	FindByEmail(email string) (*models.User, error)
 	Update(user *models.User) error
+	VerifyEmail(id string, at time.Time) error
+	ChangeRole(id string, role models.Role) error
+	Disable(id string, at time.Time) error
+	Enable(id string) error
 	Delete(id string) error
 	List(page, pageSize int) ([]models.User, int64, error)
+	AddChange(change *models.UserChange) error
+	ListChanges(userID string) ([]models.UserChange, error)
 }
 
 type userRepository struct {
@@ -46,6 +54,34 @@ func (r *userRepository) Update(user *models.User) error {
 	return r.db.Save(user).Error
 }
 
+// VerifyEmail records the user proved they own their email at at, keeping the first time they did
+func (r *userRepository) VerifyEmail(id string, at time.Time) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		UpdateColumn("email_verified_at", at).Error
+}
+
+// ChangeRole moves the user to role and bumps their token version, so tokens carrying the old role go stale
+func (r *userRepository) ChangeRole(id string, role models.Role) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"role":          role,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error
+}
+
+// Disable records the user was disabled at at and bumps their token version, so their tokens go stale
+func (r *userRepository) Disable(id string, at time.Time) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"disabled_at":   at,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error
+}
+
+// Enable clears the user's disabled_at, tokens staled by the disable stay stale
+func (r *userRepository) Enable(id string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("disabled_at", nil).Error
+}
+
 func (r *userRepository) Delete(id string) error {
 	return r.db.Delete(&models.User{}, "id = ?", id).Error
 }
@@ -64,3 +100,13 @@ func (r *userRepository) List(page, pageSize int) ([]models.User, int64, error)
 	return users, total, nil
 }
 
+func (r *userRepository) AddChange(change *models.UserChange) error {
+	return r.db.Create(change).Error
+}
+
+// ListChanges returns a user's audit trail, oldest first
+func (r *userRepository) ListChanges(userID string) ([]models.UserChange, error) {
+	var changes []models.UserChange
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&changes).Error
+	return changes, err
+}
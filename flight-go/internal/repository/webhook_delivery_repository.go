@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryRepository interface {
+	CreateBatch(deliveries []models.WebhookDelivery) error
+	FindByID(id string) (*models.WebhookDelivery, error)
+	ListDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	Update(delivery *models.WebhookDelivery) error
+	List(subscriptionID string, status models.WebhookDeliveryStatus, page, pageSize int) ([]models.WebhookDelivery, int64, error)
+}
+
+type webhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) CreateBatch(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+func (r *webhookDeliveryRepository) FindByID(id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDue returns pending deliveries whose next attempt is at or before now, oldest first. Their
+// subscription is nil once deleted.
+func (r *webhookDeliveryRepository) ListDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.
+		Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	return r.db.Omit("Subscription").Save(delivery).Error
+}
+
+// List returns the delivery log, newest first, optionally of one subscription or status
+func (r *webhookDeliveryRepository) List(subscriptionID string, status models.WebhookDeliveryStatus, page, pageSize int) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+
+	query := r.db.Model(&models.WebhookDelivery{})
+	if subscriptionID != "" {
+		query = query.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("created_at DESC").
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
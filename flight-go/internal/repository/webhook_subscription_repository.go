@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+type WebhookSubscriptionRepository interface {
+	Create(subscription *models.WebhookSubscription) error
+	FindByID(id string) (*models.WebhookSubscription, error)
+	Update(subscription *models.WebhookSubscription) error
+	Delete(id string) error
+	List(airlineID string, page, pageSize int) ([]models.WebhookSubscription, int64, error)
+	ListActiveByAirline(airlineID string) ([]models.WebhookSubscription, error)
+}
+
+type webhookSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookSubscriptionRepository(db *gorm.DB) WebhookSubscriptionRepository {
+	return &webhookSubscriptionRepository{db: db}
+}
+
+func (r *webhookSubscriptionRepository) Create(subscription *models.WebhookSubscription) error {
+	return createKeepingInactive(r.db, subscription, subscription.IsActive)
+}
+
+func (r *webhookSubscriptionRepository) FindByID(id string) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	if err := r.db.First(&subscription, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookSubscriptionRepository) Update(subscription *models.WebhookSubscription) error {
+	return r.db.Save(subscription).Error
+}
+
+func (r *webhookSubscriptionRepository) Delete(id string) error {
+	return r.db.Delete(&models.WebhookSubscription{}, "id = ?", id).Error
+}
+
+// List returns subscriptions, optionally of one airline, ordered by airline
+func (r *webhookSubscriptionRepository) List(airlineID string, page, pageSize int) ([]models.WebhookSubscription, int64, error) {
+	var subscriptions []models.WebhookSubscription
+	var total int64
+
+	query := r.db.Model(&models.WebhookSubscription{})
+	if airlineID != "" {
+		query = query.Where("airline_id = ?", airlineID)
+	}
+	query.Count(&total)
+
+	offset := (page - 1) * pageSize
+	if err := query.
+		Offset(offset).
+		Limit(pageSize).
+		Order("airline_id ASC, created_at ASC").
+		Find(&subscriptions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return subscriptions, total, nil
+}
+
+func (r *webhookSubscriptionRepository) ListActiveByAirline(airlineID string) ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.Where("airline_id = ? AND is_active = ?", airlineID, true).Find(&subscriptions).Error
+	return subscriptions, err
+}
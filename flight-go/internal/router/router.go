@@ -11,15 +11,28 @@ import (
 )
 
 type Router struct {
-	engine           *gin.Engine
-	authMiddleware   *middleware.AuthMiddleware
-	authHandler      *handlers.AuthHandler
-	airlineHandler   *handlers.AirlineHandler
-	airportHandler   *handlers.AirportHandler
-	scheduleHandler  *handlers.ScheduleHandler
-	orderHandler     *handlers.OrderHandler
-	whitelistHandler *handlers.WhitelistHandler
-	envHandler       *handlers.EnvAwareHandler
+	engine              *gin.Engine
+	authMiddleware      *middleware.AuthMiddleware
+	authHandler         *handlers.AuthHandler
+	airlineHandler      *handlers.AirlineHandler
+	airportHandler      *handlers.AirportHandler
+	scheduleHandler     *handlers.ScheduleHandler
+	orderHandler        *handlers.OrderHandler
+	whitelistHandler    *handlers.WhitelistHandler
+	envHandler          *handlers.EnvAwareHandler
+	cacheHandler        *handlers.CacheHandler
+	exchangeRateHandler *handlers.ExchangeRateHandler
+	promotionHandler    *handlers.PromotionHandler
+	travelerHandler     *handlers.TravelerHandler
+	guestOrderHandler   *handlers.GuestOrderHandler
+	guestRateLimiter    *middleware.RateLimiter
+	seatMapHandler      *handlers.SeatMapHandler
+	ancillaryHandler    *handlers.AncillaryHandler
+	notificationHandler *handlers.NotificationHandler
+	disruptionHandler   *handlers.DisruptionHandler
+	webhookHandler      *handlers.WebhookHandler
+	userHandler         *handlers.UserHandler
+	idempotency         *middleware.Idempotency
 }
 
 func NewRouter(
@@ -31,17 +44,43 @@ func NewRouter(
 	orderHandler *handlers.OrderHandler,
 	whitelistHandler *handlers.WhitelistHandler,
 	envHandler *handlers.EnvAwareHandler,
+	cacheHandler *handlers.CacheHandler,
+	exchangeRateHandler *handlers.ExchangeRateHandler,
+	promotionHandler *handlers.PromotionHandler,
+	travelerHandler *handlers.TravelerHandler,
+	guestOrderHandler *handlers.GuestOrderHandler,
+	guestRateLimiter *middleware.RateLimiter,
+	seatMapHandler *handlers.SeatMapHandler,
+	ancillaryHandler *handlers.AncillaryHandler,
+	notificationHandler *handlers.NotificationHandler,
+	disruptionHandler *handlers.DisruptionHandler,
+	webhookHandler *handlers.WebhookHandler,
+	userHandler *handlers.UserHandler,
+	idempotency *middleware.Idempotency,
 ) *Router {
 	return &Router{
-		engine:           gin.Default(),
-		authMiddleware:   authMiddleware,
-		authHandler:      authHandler,
-		airlineHandler:   airlineHandler,
-		airportHandler:   airportHandler,
-		scheduleHandler:  scheduleHandler,
-		orderHandler:     orderHandler,
-		whitelistHandler: whitelistHandler,
-		envHandler:       envHandler,
+		engine:              gin.Default(),
+		authMiddleware:      authMiddleware,
+		authHandler:         authHandler,
+		airlineHandler:      airlineHandler,
+		airportHandler:      airportHandler,
+		scheduleHandler:     scheduleHandler,
+		orderHandler:        orderHandler,
+		whitelistHandler:    whitelistHandler,
+		envHandler:          envHandler,
+		cacheHandler:        cacheHandler,
+		exchangeRateHandler: exchangeRateHandler,
+		promotionHandler:    promotionHandler,
+		travelerHandler:     travelerHandler,
+		guestOrderHandler:   guestOrderHandler,
+		guestRateLimiter:    guestRateLimiter,
+		seatMapHandler:      seatMapHandler,
+		ancillaryHandler:    ancillaryHandler,
+		notificationHandler: notificationHandler,
+		disruptionHandler:   disruptionHandler,
+		webhookHandler:      webhookHandler,
+		userHandler:         userHandler,
+		idempotency:         idempotency,
 	}
 }
 
@@ -64,6 +103,11 @@ func (r *Router) Setup() *gin.Engine {
 		{
 			auth.POST("/register", r.authHandler.Register)
 			auth.POST("/login", r.authHandler.Login)
+			auth.POST("/refresh", r.authHandler.Refresh)
+			auth.POST("/verify-email", r.authHandler.VerifyEmail)
+			auth.POST("/verify-email/resend", r.authMiddleware.RequireAuth(), r.authHandler.ResendVerification)
+			auth.POST("/logout", r.authMiddleware.RequireAuth(), r.authHandler.Logout)
+			auth.POST("/logout-all", r.authMiddleware.RequireAuth(), r.authHandler.LogoutAll)
 			auth.GET("/me", r.authMiddleware.RequireAuth(), r.authHandler.Me)
 		}
 
@@ -93,16 +137,74 @@ func (r *Router) Setup() *gin.Engine {
 		{
 			flights.GET("/search", r.scheduleHandler.Search)
 			flights.GET("/:id", r.scheduleHandler.GetFlightDetail)
+			flights.GET("/:id/seats", r.seatMapHandler.FlightSeatMap)
+			flights.GET("/:id/ancillaries", r.ancillaryHandler.FlightOffers)
 		}
 
 		// Orders routes (authenticated users)
+		// Creating, cancelling and refunding honour the Idempotency-Key header, so clients can retry them safely
 		orders := api.Group("/orders")
 		orders.Use(r.authMiddleware.RequireAuth())
 		{
-			orders.POST("", r.orderHandler.Create)
+			orders.POST("", r.idempotency.Deduplicate(), r.orderHandler.Create)
 			orders.GET("", r.orderHandler.ListMyOrders)
 			orders.GET("/:id", r.orderHandler.GetByID)
-			orders.POST("/:id/cancel", r.orderHandler.Cancel)
+			orders.GET("/:id/itinerary", r.orderHandler.Itinerary)
+			orders.GET("/:id/history", r.orderHandler.History)
+			orders.POST("/:id/cancel", r.idempotency.Deduplicate(), r.orderHandler.Cancel)
+			orders.POST("/:id/seats", r.orderHandler.SelectSeats)
+			orders.POST("/:id/ancillaries", r.orderHandler.AddAncillaries)
+			orders.POST("/:id/change-flight/quote", r.orderHandler.QuoteFlightChange)
+			orders.POST("/:id/change-flight", r.orderHandler.ChangeFlight)
+			orders.POST("/:id/name-correction/quote", r.orderHandler.QuoteNameCorrection)
+			orders.POST("/:id/name-correction", r.orderHandler.CorrectName)
+			orders.POST("/:id/disruption/accept", r.orderHandler.AcceptDisruption)
+			orders.POST("/:id/disruption/refund", r.idempotency.Deduplicate(), r.orderHandler.RefundDisruption)
+			orders.POST("/:id/disruption/rebook", r.orderHandler.Rebook)
+		}
+
+		// Guest checkout routes (public - managed with the booking token, rate limited per IP)
+		guest := api.Group("/guest")
+		guest.Use(r.guestRateLimiter.Limit())
+		{
+			guest.POST("/orders", r.idempotency.Deduplicate(), r.guestOrderHandler.Create)
+			guest.GET("/orders/:id", r.guestOrderHandler.GetByID)
+			guest.GET("/orders/:id/itinerary", r.guestOrderHandler.Itinerary)
+			guest.POST("/orders/:id/cancel", r.idempotency.Deduplicate(), r.guestOrderHandler.Cancel)
+			guest.POST("/orders/:id/claim", r.authMiddleware.RequireAuth(), r.guestOrderHandler.Claim)
+			guest.POST("/orders/:id/ancillaries", r.guestOrderHandler.AddAncillaries)
+			guest.POST("/orders/:id/disruption/accept", r.guestOrderHandler.AcceptDisruption)
+			guest.POST("/orders/:id/disruption/refund", r.idempotency.Deduplicate(), r.guestOrderHandler.RefundDisruption)
+			guest.POST("/orders/:id/disruption/rebook", r.guestOrderHandler.Rebook)
+		}
+
+		// Bookings routes (public - guests look up by PNR and contact email, rate limited per IP)
+		bookings := api.Group("/bookings")
+		bookings.Use(r.guestRateLimiter.Limit())
+		{
+			bookings.GET("/:pnr", r.orderHandler.GetBooking)
+			bookings.GET("/:pnr/itinerary", r.orderHandler.BookingItinerary)
+			bookings.POST("/:pnr/claim", r.authMiddleware.RequireAuth(), r.orderHandler.ClaimBooking)
+		}
+
+		// Current user's resources
+		me := api.Group("/me")
+		me.Use(r.authMiddleware.RequireAuth())
+		{
+			me.GET("/travelers", r.travelerHandler.List)
+			me.POST("/travelers", r.travelerHandler.Create)
+			me.GET("/travelers/:id", r.travelerHandler.GetByID)
+			me.PUT("/travelers/:id", r.travelerHandler.Update)
+			me.DELETE("/travelers/:id", r.travelerHandler.Delete)
+			me.GET("/notifications", r.notificationHandler.List)
+			me.POST("/notifications/:id/read", r.notificationHandler.MarkRead)
+		}
+
+		// Promotions routes (authenticated users)
+		promotions := api.Group("/promotions")
+		promotions.Use(r.authMiddleware.RequireAuth())
+		{
+			promotions.POST("/validate", r.promotionHandler.Validate)
 		}
 
 		// Whitelist routes (public - for checking)
@@ -127,11 +229,69 @@ func (r *Router) Setup() *gin.Engine {
 			admin.GET("/schedules/:id", r.envHandler.GetScheduleByID)
 			admin.PUT("/schedules/:id", r.envHandler.UpdateSchedule)
 			admin.DELETE("/schedules/:id", r.envHandler.DeleteSchedule)
+			admin.GET("/schedules/:id/disruptions", r.disruptionHandler.ListBySchedule)
 
-			// Orders management
+			// Fare rules management (environment-aware via query param ?env=staging|production)
+			admin.GET("/fare-rules", r.envHandler.ListFareRules)
+			admin.POST("/fare-rules", r.envHandler.CreateFareRule)
+			admin.GET("/fare-rules/:id", r.envHandler.GetFareRuleByID)
+			admin.PUT("/fare-rules/:id", r.envHandler.UpdateFareRule)
+			admin.DELETE("/fare-rules/:id", r.envHandler.DeleteFareRule)
+
+			// Fare families management (environment-aware via query param ?env=staging|production)
+			admin.GET("/fare-families", r.envHandler.ListFareFamilies)
+			admin.POST("/fare-families", r.envHandler.CreateFareFamily)
+			admin.GET("/fare-families/:id", r.envHandler.GetFareFamilyByID)
+			admin.PUT("/fare-families/:id", r.envHandler.UpdateFareFamily)
+			admin.DELETE("/fare-families/:id", r.envHandler.DeleteFareFamily)
+
+			// Taxes, fees and surcharges management (environment-aware via query param ?env=staging|production)
+			admin.GET("/fee-rules", r.envHandler.ListFeeRules)
+			admin.POST("/fee-rules", r.envHandler.CreateFeeRule)
+			admin.GET("/fee-rules/:id", r.envHandler.GetFeeRuleByID)
+			admin.PUT("/fee-rules/:id", r.envHandler.UpdateFeeRule)
+			admin.DELETE("/fee-rules/:id", r.envHandler.DeleteFeeRule)
+
+			// Ancillaries management (environment-aware via query param ?env=staging|production)
+			admin.GET("/ancillaries", r.envHandler.ListAncillaries)
+			admin.POST("/ancillaries", r.envHandler.CreateAncillary)
+			admin.GET("/ancillaries/:id", r.envHandler.GetAncillaryByID)
+			admin.PUT("/ancillaries/:id", r.envHandler.UpdateAncillary)
+			admin.DELETE("/ancillaries/:id", r.envHandler.DeleteAncillary)
+
+			// Exchange rates management (shared by both environments)
+			admin.GET("/exchange-rates", r.exchangeRateHandler.List)
+			admin.POST("/exchange-rates", r.exchangeRateHandler.Create)
+			admin.GET("/exchange-rates/:id", r.exchangeRateHandler.GetByID)
+			admin.DELETE("/exchange-rates/:id", r.exchangeRateHandler.Delete)
+
+			// Promotions management (shared by both environments)
+			admin.GET("/promotions", r.promotionHandler.List)
+			admin.POST("/promotions", r.promotionHandler.Create)
+			admin.GET("/promotions/:id", r.promotionHandler.GetByID)
+			admin.PUT("/promotions/:id", r.promotionHandler.Update)
+			admin.DELETE("/promotions/:id", r.promotionHandler.Delete)
+
+			// Seat maps management (shared by both environments, matched to schedules by aircraft)
+			admin.GET("/seat-maps", r.seatMapHandler.List)
+			admin.POST("/seat-maps", r.seatMapHandler.Create)
+			admin.GET("/seat-maps/:id", r.seatMapHandler.GetByID)
+			admin.PUT("/seat-maps/:id", r.seatMapHandler.Update)
+			admin.DELETE("/seat-maps/:id", r.seatMapHandler.Delete)
+
+			// Orders management, status updates honour the Idempotency-Key header too
 			admin.GET("/orders", r.orderHandler.List)
 			admin.GET("/orders/:id", r.orderHandler.GetByID)
-			admin.PUT("/orders/:id", r.orderHandler.Update)
+			admin.PUT("/orders/:id", r.idempotency.Deduplicate(), r.orderHandler.Update)
+
+			// Users, role changes and disables are audited in their history
+			admin.GET("/users", r.userHandler.List)
+			admin.GET("/users/:id", r.userHandler.GetByID)
+			admin.PUT("/users/:id/role", r.userHandler.ChangeRole)
+			admin.POST("/users/:id/disable", r.userHandler.Disable)
+			admin.POST("/users/:id/enable", r.userHandler.Enable)
+			admin.GET("/users/:id/history", r.userHandler.History)
+			admin.POST("/users/:id/revoke-sessions", r.authHandler.RevokeUserSessions)
 
 			// Whitelist management
 			admin.GET("/whitelist", r.whitelistHandler.List)
@@ -140,6 +300,23 @@ func (r *Router) Setup() *gin.Engine {
 			admin.PUT("/whitelist/:id", r.whitelistHandler.Update)
 			admin.DELETE("/whitelist/:id", r.whitelistHandler.Delete)
 			admin.POST("/whitelist/:id/toggle-airline", r.whitelistHandler.ToggleAirlineAccess)
+
+			// Notification delivery log
+			admin.GET("/notifications/deliveries", r.notificationHandler.ListDeliveries)
+
+			// Partner webhooks per airline (shared by both environments)
+			admin.GET("/webhooks", r.webhookHandler.List)
+			admin.POST("/webhooks", r.webhookHandler.Create)
+			admin.GET("/webhooks/dead-letters", r.webhookHandler.DeadLetters)
+			admin.POST("/webhooks/deliveries/:id/redeliver", r.webhookHandler.Redeliver)
+			admin.GET("/webhooks/:id", r.webhookHandler.GetByID)
+			admin.PUT("/webhooks/:id", r.webhookHandler.Update)
+			admin.DELETE("/webhooks/:id", r.webhookHandler.Delete)
+			admin.GET("/webhooks/:id/deliveries", r.webhookHandler.ListDeliveries)
+
+			// Search cache
+			admin.GET("/cache/search", r.cacheHandler.SearchStats)
+			admin.DELETE("/cache/search", r.cacheHandler.FlushSearch)
 		}
 	}
 
@@ -150,7 +327,8 @@ func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key, X-Booking-Token")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
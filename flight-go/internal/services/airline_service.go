@@ -2,14 +2,17 @@ package services
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 )
 
 var (
-	ErrAirlineNotFound     = errors.New("airline not found")
-	ErrAirlineCodeExists   = errors.New("airline code already exists")
+	ErrAirlineNotFound       = errors.New("airline not found")
+	ErrAirlineCodeExists     = errors.New("airline code already exists")
+	ErrInvalidPassengerRules = errors.New("invalid passenger rules")
 )
 
 type AirlineService interface {
@@ -22,17 +25,21 @@ type AirlineService interface {
 }
 
 type CreateAirlineRequest struct {
-	Code     string `json:"code" binding:"required,max=3"`
-	Name     string `json:"name" binding:"required"`
-	Logo     string `json:"logo"`
-	IsActive *bool  `json:"is_active"`
+	Code           string                 `json:"code" binding:"required,max=3"`
+	Name           string                 `json:"name" binding:"required"`
+	Logo           string                 `json:"logo"`
+	IsActive       *bool                  `json:"is_active"`
+	Currency       string                 `json:"currency"` // ISO 4217 code, defaults to IDR
+	PassengerRules *models.PassengerRules `json:"passenger_rules"`
 }
 
 type UpdateAirlineRequest struct {
-	Code     string `json:"code"`
-	Name     string `json:"name"`
-	Logo     string `json:"logo"`
-	IsActive *bool  `json:"is_active"`
+	Code           string                 `json:"code"`
+	Name           string                 `json:"name"`
+	Logo           string                 `json:"logo"`
+	IsActive       *bool                  `json:"is_active"`
+	Currency       string                 `json:"currency"` // ISO 4217 code, defaults to IDR
+	PassengerRules *models.PassengerRules `json:"passenger_rules"`
 }
 
 type PaginatedResponse struct {
@@ -45,10 +52,16 @@ type PaginatedResponse struct {
 
 type airlineService struct {
 	airlineRepo repository.AirlineRepository
+	events      *events.Bus
+	env         models.Environment
 }
 
-func NewAirlineService(airlineRepo repository.AirlineRepository) AirlineService {
-	return &airlineService{airlineRepo: airlineRepo}
+func NewAirlineService(airlineRepo repository.AirlineRepository, bus *events.Bus, env models.Environment) AirlineService {
+	return &airlineService{
+		airlineRepo: airlineRepo,
+		events:      bus,
+		env:         env,
+	}
 }
 
 func (s *airlineService) Create(req CreateAirlineRequest) (*models.Airline, error) {
@@ -69,9 +82,24 @@ func (s *airlineService) Create(req CreateAirlineRequest) (*models.Airline, erro
 		airline.IsActive = *req.IsActive
 	}
 
+	if req.Currency != "" {
+		if !models.IsSupportedCurrency(strings.ToUpper(req.Currency)) {
+			return nil, ErrUnsupportedCurrency
+		}
+		airline.Currency = strings.ToUpper(req.Currency)
+	}
+
+	if req.PassengerRules != nil {
+		if err := validatePassengerRules(req.PassengerRules); err != nil {
+			return nil, err
+		}
+		airline.PassengerRules = req.PassengerRules
+	}
+
 	if err := s.airlineRepo.Create(airline); err != nil {
 		return nil, err
 	}
+	s.events.Publish(events.AirlineChanged{Environment: s.env, AirlineID: airline.ID})
 
 	return airline, nil
 }
@@ -110,9 +138,24 @@ func (s *airlineService) Update(id string, req UpdateAirlineRequest) (*models.Ai
 		airline.IsActive = *req.IsActive
 	}
 
+	if req.Currency != "" {
+		if !models.IsSupportedCurrency(strings.ToUpper(req.Currency)) {
+			return nil, ErrUnsupportedCurrency
+		}
+		airline.Currency = strings.ToUpper(req.Currency)
+	}
+
+	if req.PassengerRules != nil {
+		if err := validatePassengerRules(req.PassengerRules); err != nil {
+			return nil, err
+		}
+		airline.PassengerRules = req.PassengerRules
+	}
+
 	if err := s.airlineRepo.Update(airline); err != nil {
 		return nil, err
 	}
+	s.events.Publish(events.AirlineChanged{Environment: s.env, AirlineID: airline.ID})
 
 	return airline, nil
 }
@@ -123,7 +166,12 @@ func (s *airlineService) Delete(id string) error {
 		return ErrAirlineNotFound
 	}
 
-	return s.airlineRepo.Delete(id)
+	if err := s.airlineRepo.Delete(id); err != nil {
+		return err
+	}
+	s.events.Publish(events.AirlineChanged{Environment: s.env, AirlineID: id})
+
+	return nil
 }
 
 func (s *airlineService) List(page, pageSize int, activeOnly bool) (*PaginatedResponse, error) {
@@ -157,3 +205,16 @@ func (s *airlineService) ListAll() ([]models.Airline, error) {
 	return s.airlineRepo.ListAll()
 }
 
+// validatePassengerRules checks fare percentages, party size and age bands
+func validatePassengerRules(rules *models.PassengerRules) error {
+	if rules.ChildPercent < 0 || rules.ChildPercent > 100 || rules.InfantPercent < 0 || rules.InfantPercent > 100 {
+		return ErrInvalidPassengerRules
+	}
+	if rules.MaxPassengers < 1 {
+		return ErrInvalidPassengerRules
+	}
+	if rules.ChildMinAge < 0 || rules.AdultMinAge <= rules.ChildMinAge {
+		return ErrInvalidPassengerRules
+	}
+	return nil
+}
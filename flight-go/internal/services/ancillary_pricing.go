@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var ErrAncillarySalesClosed = errors.New("ancillary sales closed for flight")
+
+// ancillaryPick is an ancillary asked for a passenger, Index locates the request for error reporting
+type ancillaryPick struct {
+	Index       int
+	Passenger   *models.Passenger
+	AncillaryID string
+}
+
+// AncillaryPricingService offers the ancillaries an airline sells on a flight and prices the
+// ones passengers buy. Sales close cutoff before departure.
+type AncillaryPricingService struct {
+	stagingAncillaryRepo    repository.AncillaryRepository
+	productionAncillaryRepo repository.AncillaryRepository
+	schedules               *ScheduleResolver
+	currencyService         *CurrencyService
+	cutoff                  time.Duration
+}
+
+func NewAncillaryPricingService(
+	stagingAncillaryRepo repository.AncillaryRepository,
+	productionAncillaryRepo repository.AncillaryRepository,
+	schedules *ScheduleResolver,
+	currencyService *CurrencyService,
+	cutoff time.Duration,
+) *AncillaryPricingService {
+	return &AncillaryPricingService{
+		stagingAncillaryRepo:    stagingAncillaryRepo,
+		productionAncillaryRepo: productionAncillaryRepo,
+		schedules:               schedules,
+		currencyService:         currencyService,
+		cutoff:                  cutoff,
+	}
+}
+
+// FlightOffers lists the ancillaries sold on a schedule in the environment email books it in,
+// priced in currency, by default the schedule's
+func (s *AncillaryPricingService) FlightOffers(email, scheduleID, currency string) ([]models.AncillaryOffer, error) {
+	schedule, env, err := s.schedules.Resolve(email, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = schedule.PriceCurrency()
+	}
+	if !models.IsSupportedCurrency(currency) {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	catalogue, err := s.repoFor(env).ListActiveByAirline(schedule.AirlineID)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]models.AncillaryOffer, 0, len(catalogue))
+	for _, ancillary := range catalogue {
+		rate, err := s.currencyService.Rate(ancillary.Currency, currency, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, models.AncillaryOffer{
+			Ancillary:     ancillary,
+			OfferPrice:    models.RoundToCurrency(ancillary.Price*rate, currency),
+			OfferCurrency: currency,
+		})
+	}
+	return offers, nil
+}
+
+// price checks picks against the airline's catalogue in env and prices them in currency.
+// A passenger holds at most one ancillary of each type per flight, counting those already bought.
+func (s *AncillaryPricingService) price(env models.Environment, schedule *models.Schedule, flightDate time.Time, orderID string, picks []ancillaryPick, currency string) ([]models.OrderAncillary, error) {
+	if len(picks) == 0 {
+		return nil, nil
+	}
+	if !time.Now().Before(schedule.DepartureOn(flightDate).Add(-s.cutoff)) {
+		return nil, ErrAncillarySalesClosed
+	}
+
+	catalogue, err := s.repoFor(env).ListActiveByAirline(schedule.AirlineID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Ancillary, len(catalogue))
+	for _, ancillary := range catalogue {
+		byID[ancillary.ID] = ancillary
+	}
+
+	held := make(map[string]map[models.ChargeType]bool)
+	holds := func(passenger *models.Passenger) map[models.ChargeType]bool {
+		if held[passenger.ID] == nil {
+			held[passenger.ID] = make(map[models.ChargeType]bool)
+			for _, bought := range passenger.Ancillaries {
+				held[passenger.ID][bought.Type] = true
+			}
+		}
+		return held[passenger.ID]
+	}
+
+	var errs []PassengerError
+	addErr := func(index int, message string) {
+		i := index
+		errs = append(errs, PassengerError{Index: &i, Field: "ancillaries", Message: message})
+	}
+
+	rates := make(map[string]float64)
+	var result []models.OrderAncillary
+	for _, pick := range picks {
+		ancillary, ok := byID[pick.AncillaryID]
+		if !ok {
+			addErr(pick.Index, fmt.Sprintf("ancillary %s is not sold on this flight", pick.AncillaryID))
+			continue
+		}
+		types := holds(pick.Passenger)
+		if types[ancillary.Type] {
+			addErr(pick.Index, fmt.Sprintf("already has a %s add-on", ancillary.Type))
+			continue
+		}
+		types[ancillary.Type] = true
+
+		rate, ok := rates[ancillary.Currency]
+		if !ok {
+			if rate, err = s.currencyService.Rate(ancillary.Currency, currency, time.Now()); err != nil {
+				return nil, err
+			}
+			rates[ancillary.Currency] = rate
+		}
+		result = append(result, models.OrderAncillary{
+			OrderID:     orderID,
+			PassengerID: pick.Passenger.ID,
+			AncillaryID: ancillary.ID,
+			ScheduleID:  schedule.ID,
+			FlightDate:  flightDate,
+			Type:        ancillary.Type,
+			Name:        ancillary.Name,
+			WeightKg:    ancillary.WeightKg,
+			Price:       models.ToMinorUnits(ancillary.Price*rate, currency),
+			Currency:    currency,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, &PassengerValidationError{Errors: errs}
+	}
+	return result, nil
+}
+
+func (s *AncillaryPricingService) repoFor(env models.Environment) repository.AncillaryRepository {
+	if env == models.EnvProduction {
+		return s.productionAncillaryRepo
+	}
+	return s.stagingAncillaryRepo
+}
+
+// withAncillaries rebuilds breakdown with one line per ancillary and price in place of its
+// current ancillary lines
+func withAncillaries(breakdown *models.PriceBreakdown, ancillaries []models.OrderAncillary) *models.PriceBreakdown {
+	result := &models.PriceBreakdown{Currency: breakdown.Currency, Items: []models.PriceLineItem{}}
+	for _, item := range breakdown.Items {
+		if item.AncillaryID == "" {
+			result.Add(item)
+		}
+	}
+
+	type lineKey struct {
+		ancillaryID string
+		price       int64
+	}
+	lines := make(map[lineKey]int)
+	var items []models.PriceLineItem
+	for _, ancillary := range ancillaries {
+		key := lineKey{ancillary.AncillaryID, ancillary.Price}
+		if i, ok := lines[key]; ok {
+			items[i].Quantity++
+			items[i].Amount += ancillary.Price
+			continue
+		}
+		lines[key] = len(items)
+		items = append(items, models.PriceLineItem{
+			Type:        ancillary.Type,
+			Name:        ancillary.Name,
+			AncillaryID: ancillary.AncillaryID,
+			Quantity:    1,
+			UnitAmount:  ancillary.Price,
+			Amount:      ancillary.Price,
+		})
+	}
+	for _, item := range items {
+		result.Add(item)
+	}
+	return result
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrAncillaryNotFound   = errors.New("ancillary not found")
+	ErrInvalidAncillary    = errors.New("invalid ancillary")
+	ErrAncillaryCodeExists = errors.New("ancillary code already exists for airline")
+)
+
+type AncillaryService interface {
+	Create(req CreateAncillaryRequest) (*models.Ancillary, error)
+	GetByID(id string) (*models.Ancillary, error)
+	Update(id string, req UpdateAncillaryRequest) (*models.Ancillary, error)
+	Delete(id string) error
+	List(airlineID string, page, pageSize int) (*PaginatedResponse, error)
+}
+
+type CreateAncillaryRequest struct {
+	AirlineID   string  `json:"airline_id" binding:"required"`
+	Type        string  `json:"type" binding:"required"` // baggage, meal, insurance
+	Code        string  `json:"code" binding:"required"` // e.g., BAG20, MEAL-NASI
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description"`
+	WeightKg    int     `json:"weight_kg"` // Required for baggage
+	Price       float64 `json:"price"`
+	Currency    string  `json:"currency"` // Currency of price, defaults to IDR
+	SortOrder   int     `json:"sort_order"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+type UpdateAncillaryRequest struct {
+	Name        string   `json:"name"`
+	Description *string  `json:"description"`
+	WeightKg    *int     `json:"weight_kg"`
+	Price       *float64 `json:"price"`
+	Currency    string   `json:"currency"`
+	SortOrder   *int     `json:"sort_order"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+type ancillaryService struct {
+	ancillaryRepo repository.AncillaryRepository
+}
+
+func NewAncillaryService(ancillaryRepo repository.AncillaryRepository) AncillaryService {
+	return &ancillaryService{ancillaryRepo: ancillaryRepo}
+}
+
+func (s *ancillaryService) Create(req CreateAncillaryRequest) (*models.Ancillary, error) {
+	ancillary := &models.Ancillary{
+		AirlineID:   req.AirlineID,
+		Type:        models.ChargeType(req.Type),
+		Code:        strings.ToUpper(strings.TrimSpace(req.Code)),
+		Name:        req.Name,
+		Description: req.Description,
+		WeightKg:    req.WeightKg,
+		Price:       req.Price,
+		Currency:    models.DefaultCurrency,
+		SortOrder:   req.SortOrder,
+		IsActive:    true,
+	}
+
+	if req.Currency != "" {
+		ancillary.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.IsActive != nil {
+		ancillary.IsActive = *req.IsActive
+	}
+
+	if err := validateAncillary(ancillary); err != nil {
+		return nil, err
+	}
+	if existing, err := s.ancillaryRepo.FindByCode(ancillary.AirlineID, ancillary.Code); err == nil && existing != nil {
+		return nil, ErrAncillaryCodeExists
+	}
+
+	if err := s.ancillaryRepo.Create(ancillary); err != nil {
+		return nil, err
+	}
+
+	return ancillary, nil
+}
+
+func (s *ancillaryService) GetByID(id string) (*models.Ancillary, error) {
+	ancillary, err := s.ancillaryRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAncillaryNotFound
+	}
+	return ancillary, nil
+}
+
+func (s *ancillaryService) Update(id string, req UpdateAncillaryRequest) (*models.Ancillary, error) {
+	ancillary, err := s.ancillaryRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrAncillaryNotFound
+	}
+
+	if req.Name != "" {
+		ancillary.Name = req.Name
+	}
+	if req.Description != nil {
+		ancillary.Description = *req.Description
+	}
+	if req.WeightKg != nil {
+		ancillary.WeightKg = *req.WeightKg
+	}
+	if req.Price != nil {
+		ancillary.Price = *req.Price
+	}
+	if req.Currency != "" {
+		ancillary.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.SortOrder != nil {
+		ancillary.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		ancillary.IsActive = *req.IsActive
+	}
+
+	if err := validateAncillary(ancillary); err != nil {
+		return nil, err
+	}
+
+	if err := s.ancillaryRepo.Update(ancillary); err != nil {
+		return nil, err
+	}
+
+	return ancillary, nil
+}
+
+func (s *ancillaryService) Delete(id string) error {
+	_, err := s.ancillaryRepo.FindByID(id)
+	if err != nil {
+		return ErrAncillaryNotFound
+	}
+
+	return s.ancillaryRepo.Delete(id)
+}
+
+func (s *ancillaryService) List(airlineID string, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	ancillaries, total, err := s.ancillaryRepo.List(airlineID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       ancillaries,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// validateAncillary checks the type, price and currency, and that baggage tiers carry a weight
+func validateAncillary(ancillary *models.Ancillary) error {
+	if !models.IsAncillaryType(ancillary.Type) || ancillary.Code == "" {
+		return ErrInvalidAncillary
+	}
+	if ancillary.Price < 0 || !models.IsSupportedCurrency(ancillary.Currency) {
+		return ErrInvalidAncillary
+	}
+	if ancillary.Type == models.ChargeBaggage && ancillary.WeightKg <= 0 {
+		return ErrInvalidAncillary
+	}
+	if ancillary.Type != models.ChargeBaggage && ancillary.WeightKg != 0 {
+		return ErrInvalidAncillary
+	}
+	return nil
+}
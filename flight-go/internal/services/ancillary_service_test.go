@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// ancillaryID returns the ID of the seeded ancillary code that GA sells on CGK-DPS
+func ancillaryID(t *testing.T, s *testServices, code string) string {
+	t.Helper()
+	offers, err := s.ancillary.FlightOffers("", "schedule-cgk-dps-ga-001", "")
+	if err != nil {
+		t.Fatalf("FlightOffers: %v", err)
+	}
+	for _, offer := range offers {
+		if offer.Code == code {
+			return offer.ID
+		}
+	}
+	t.Fatalf("GA doesn't sell %s", code)
+	return ""
+}
+
+// buying buys ancillaries for passenger at booking
+func buying(passenger PassengerRequest, ids ...string) PassengerRequest {
+	passenger.Ancillaries = ids
+	return passenger
+}
+
+func TestAncillaryServiceValidates(t *testing.T) {
+	s := newTestServices(t)
+	ancillaries := NewAncillaryService(repository.NewAncillaryRepository(s.staging))
+
+	tests := []struct {
+		name    string
+		req     CreateAncillaryRequest
+		wantErr error
+	}{
+		{name: "meal", req: CreateAncillaryRequest{AirlineID: "ga", Type: "meal", Code: "meal-kids", Name: "Kids meal", Price: 45000}},
+		{name: "code taken", req: CreateAncillaryRequest{AirlineID: "ga", Type: "baggage", Code: "bag20", Name: "Bag", WeightKg: 20, Price: 1}, wantErr: ErrAncillaryCodeExists},
+		{name: "unknown type", req: CreateAncillaryRequest{AirlineID: "ga", Type: "lounge", Code: "LOUNGE", Name: "Lounge"}, wantErr: ErrInvalidAncillary},
+		{name: "baggage without a weight", req: CreateAncillaryRequest{AirlineID: "ga", Type: "baggage", Code: "BAG0", Name: "Bag"}, wantErr: ErrInvalidAncillary},
+		{name: "meal with a weight", req: CreateAncillaryRequest{AirlineID: "ga", Type: "meal", Code: "MEAL-XL", Name: "Big meal", WeightKg: 1}, wantErr: ErrInvalidAncillary},
+		{name: "negative price", req: CreateAncillaryRequest{AirlineID: "ga", Type: "insurance", Code: "INS-X", Name: "Cover", Price: -1}, wantErr: ErrInvalidAncillary},
+		{name: "unsupported currency", req: CreateAncillaryRequest{AirlineID: "ga", Type: "insurance", Code: "INS-X", Name: "Cover", Currency: "xyz"}, wantErr: ErrInvalidAncillary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ancillary, err := ancillaries.Create(tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && (ancillary.Code != "MEAL-KIDS" || ancillary.Currency != models.DefaultCurrency || !ancillary.IsActive) {
+				t.Errorf("created %+v, want an active MEAL-KIDS in %s", ancillary, models.DefaultCurrency)
+			}
+		})
+	}
+}
+
+// Inactive ancillaries are neither offered nor sold
+func TestAncillaryFlightOffers(t *testing.T) {
+	s := newTestServices(t)
+	ancillaries := NewAncillaryService(repository.NewAncillaryRepository(s.staging))
+	offers, err := s.ancillary.FlightOffers("", "schedule-cgk-dps-ga-001", "")
+	if err != nil {
+		t.Fatalf("FlightOffers: %v", err)
+	}
+	for _, offer := range offers {
+		if offer.AirlineID != "ga" || offer.OfferCurrency != "IDR" || offer.OfferPrice != offer.Price {
+			t.Errorf("offer %s: airline %s, %v %s", offer.Code, offer.AirlineID, offer.OfferPrice, offer.OfferCurrency)
+		}
+	}
+
+	id := ancillaryID(t, s, "MEAL-VEG")
+	inactive := false
+	if _, err := ancillaries.Update(id, UpdateAncillaryRequest{IsActive: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.ancillary.FlightOffers("", "schedule-cgk-dps-ga-001", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(offers)-1 {
+		t.Errorf("%d offers after deactivating MEAL-VEG, want %d", len(after), len(offers)-1)
+	}
+	_, err = s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, buying(adult("Budi Santoso"), id)))
+	if got := passengerErrors(t, err); !sameFields(got, []string{"0.ancillaries"}) {
+		t.Errorf("booking an inactive ancillary: errors = %v", got)
+	}
+
+	if _, err := s.ancillary.FlightOffers("", "schedule-cgk-dps-ga-001", "xyz"); !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("offers in xyz: err = %v, want ErrUnsupportedCurrency", err)
+	}
+}
+
+func TestOrderCreateWithAncillaries(t *testing.T) {
+	s := newTestServices(t)
+	bag, meal := ancillaryID(t, s, "BAG20"), ancillaryID(t, s, "MEAL-NASI")
+
+	tests := []struct {
+		name       string
+		passengers []PassengerRequest
+		want       []string
+	}{
+		{name: "two bags for one passenger", passengers: []PassengerRequest{buying(adult("Budi Santoso"), bag, ancillaryID(t, s, "BAG5"))}, want: []string{"0.ancillaries"}},
+		{name: "unknown ancillary", passengers: []PassengerRequest{buying(adult("Budi Santoso"), "ancillary-missing")}, want: []string{"0.ancillaries"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, tt.passengers...))
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Both passengers' bags share one line, priced in minor units
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, buying(adult("Budi Santoso"), bag, meal), buying(adult("Siti Rahayu"), bag)))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if n := len(order.Passengers[0].Ancillaries) + len(order.Passengers[1].Ancillaries); n != 3 {
+		t.Errorf("passengers hold %d ancillaries, want 3", n)
+	}
+	for _, item := range order.PriceBreakdown.Items {
+		if item.AncillaryID == bag && (item.Quantity != 2 || item.Amount != 2*45000000) {
+			t.Errorf("baggage line = %+v, want 2 x IDR 450,000", item)
+		}
+	}
+}
+
+func TestOrderAddAncillaries(t *testing.T) {
+	s := newTestServices(t)
+	bag, insurance := ancillaryID(t, s, "BAG10"), ancillaryID(t, s, "INS-TRAVEL")
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, buying(adult("Budi Santoso"), bag)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	passenger := order.Passengers[0].ID
+
+	if _, err := s.orders.AddAncillaries(order.ID, "test", AddAncillariesRequest{Ancillaries: []AncillarySelection{{PassengerID: passenger, AncillaryID: ancillaryID(t, s, "BAG5")}}}); !sameFields(passengerErrors(t, err), []string{"0.ancillaries"}) {
+		t.Errorf("a second bag after booking: err = %v", err)
+	}
+
+	updated, err := s.orders.AddAncillaries(order.ID, "test", AddAncillariesRequest{Ancillaries: []AncillarySelection{{PassengerID: passenger, AncillaryID: insurance}}})
+	if err != nil {
+		t.Fatalf("AddAncillaries: %v", err)
+	}
+	if updated.TotalAmount != order.TotalAmount+3500000 || len(updated.Passengers[0].Ancillaries) != 2 {
+		t.Errorf("total %d with %d ancillaries, want %d with 2", updated.TotalAmount, len(updated.Passengers[0].Ancillaries), order.TotalAmount+3500000)
+	}
+
+	history, err := s.orders.History(order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := history[len(history)-1]; last.Type != models.OrderChangeAncillary || last.FareDifference != 3500000 {
+		t.Errorf("history records %+v", last)
+	}
+}
+
+// Sales close the configured cut-off before departure
+func TestAncillarySalesCutoff(t *testing.T) {
+	s := newTestServices(t)
+	schedule, _, err := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	passenger := &models.Passenger{}
+	passenger.ID = "passenger-a"
+	picks := []ancillaryPick{{Passenger: passenger, AncillaryID: ancillaryID(t, s, "MEAL-NASI")}}
+	flightDate := time.Now().AddDate(0, 0, 2).Truncate(24 * time.Hour)
+
+	if _, err := s.ancillary.price(models.EnvStaging, schedule, flightDate, "order-a", picks, "IDR"); err != nil {
+		t.Errorf("two days out: %v", err)
+	}
+	closed := NewAncillaryPricingService(repository.NewAncillaryRepository(s.staging), repository.NewAncillaryRepository(s.production), s.schedules, s.currency, 7*24*time.Hour)
+	if _, err := closed.price(models.EnvStaging, schedule, flightDate, "order-a", picks, "IDR"); !errors.Is(err, ErrAncillarySalesClosed) {
+		t.Errorf("two days out with a week's cut-off: err = %v, want ErrAncillarySalesClosed", err)
+	}
+}
@@ -1,25 +1,38 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/mirahekatiket/flight-go/internal/config"
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserDisabled        = errors.New("user disabled")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrTokenRevoked        = errors.New("token revoked")
 )
 
 type AuthService interface {
 	Register(req RegisterRequest) (*TokenResponse, error)
 	Login(req LoginRequest) (*TokenResponse, error)
+	Refresh(refreshToken string) (*TokenResponse, error)
+	Logout(sessionID string) error
+	RevokeAllSessions(userID string) error
+	RequestEmailVerification(userID string) error
+	VerifyEmail(token string) (*VerifyEmailResponse, error)
 	ValidateToken(tokenString string) (*Claims, error)
 	GetUserByID(id string) (*models.User, error)
 }
@@ -36,29 +49,57 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type TokenResponse struct {
-	Token     string       `json:"token"`
-	ExpiresAt time.Time    `json:"expires_at"`
-	User      *models.User `json:"user"`
+	Token            string       `json:"token"` // Access token
+	ExpiresAt        time.Time    `json:"expires_at"`
+	RefreshToken     string       `json:"refresh_token"` // Single use, trade it at /auth/refresh for a new pair
+	RefreshExpiresAt time.Time    `json:"refresh_expires_at"`
+	User             *models.User `json:"user"`
 }
 
 type Claims struct {
-	UserID string      `json:"user_id"`
-	Email  string      `json:"email"`
-	Role   models.Role `json:"role"`
+	UserID    string      `json:"user_id"`
+	Email     string      `json:"email"`
+	Role      models.Role `json:"role"`
+	SessionID string      `json:"sid"`
+	Version   int         `json:"ver"` // User's TokenVersion when issued
 	jwt.RegisteredClaims
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	cfg      *config.Config
+	userRepo        repository.UserRepository
+	orderRepo       repository.OrderRepository
+	sessionRepo     repository.SessionRepository
+	revocations     *TokenRevocations
+	verificationKey []byte
+	events          *events.Bus
+	cfg             *config.Config
 }
 
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) AuthService {
-	return &authService{
-		userRepo: userRepo,
-		cfg:      cfg,
+func NewAuthService(userRepo repository.UserRepository, orderRepo repository.OrderRepository, sessionRepo repository.SessionRepository, bus *events.Bus, cfg *config.Config) AuthService {
+	s := &authService{
+		userRepo:        userRepo,
+		orderRepo:       orderRepo,
+		sessionRepo:     sessionRepo,
+		revocations:     NewTokenRevocations(),
+		verificationKey: emailVerificationKey(cfg),
+		events:          bus,
+		cfg:             cfg,
+	}
+
+	// Sessions revoked before a restart may still have unexpired access tokens
+	revoked, err := sessionRepo.ListRevokedSince(time.Now().Add(-cfg.AccessTokenTTL))
+	if err != nil {
+		log.Printf("Loading revoked sessions failed: %v", err)
 	}
+	for _, session := range revoked {
+		s.revocations.Revoke(session.ID, session.RevokedAt.Add(cfg.AccessTokenTTL))
+	}
+	return s
 }
 
 func (s *authService) Register(req RegisterRequest) (*TokenResponse, error) {
@@ -86,32 +127,15 @@ func (s *authService) Register(req RegisterRequest) (*TokenResponse, error) {
 		return nil, err
 	}
 
-	// Generate JWT token
-	expiresAt := time.Now().Add(s.cfg.JWTExpiration)
-	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(s.cfg.JWTSecret))
-	if err != nil {
-		return nil, err
-	}
-
 	// Clear password before returning
 	user.Password = ""
 
-	return &TokenResponse{
-		Token:     tokenString,
-		ExpiresAt: expiresAt,
-		User:      user,
-	}, nil
+	// The account stands either way, a lost email can be sent again
+	if err := s.sendVerification(user); err != nil {
+		log.Printf("Sending email verification to %s failed: %v", user.ID, err)
+	}
+
+	return s.startSession(user)
 }
 
 func (s *authService) Login(req LoginRequest) (*TokenResponse, error) {
@@ -123,13 +147,113 @@ func (s *authService) Login(req LoginRequest) (*TokenResponse, error) {
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		return nil, ErrInvalidCredentials
 	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	return s.startSession(user)
+}
+
+// Refresh trades a refresh token for a new access and refresh token pair of the same session.
+// A refresh token works once: presenting an already rotated one means it leaked, and revokes
+// the session for whoever holds either token.
+func (s *authService) Refresh(refreshToken string) (*TokenResponse, error) {
+	hash := hashRefreshToken(refreshToken)
+	session, err := s.sessionRepo.FindByRefreshTokenHash(hash)
+	if err != nil || session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if session.RefreshTokenHash != hash {
+		if err := s.Logout(session.ID); err != nil {
+			log.Printf("Revoking session %s after refresh token reuse failed: %v", session.ID, err)
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Claims are read afresh, so role changes apply from the next refresh
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	token, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.sessionRepo.Rotate(session, hashRefreshToken(token), time.Now().Add(s.cfg.RefreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Revoked or refreshed concurrently with the same token
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.issueTokens(user, session, token)
+}
+
+// Logout revokes a session, its refresh token and the access tokens issued for it
+func (s *authService) Logout(sessionID string) error {
+	now := time.Now()
+	if err := s.sessionRepo.Revoke(sessionID, now); err != nil {
+		return err
+	}
+	s.revocations.Revoke(sessionID, now.Add(s.cfg.AccessTokenTTL))
+	return nil
+}
 
-	// Generate JWT token
-	expiresAt := time.Now().Add(s.cfg.JWTExpiration)
+// RevokeAllSessions logs a user out everywhere
+func (s *authService) RevokeAllSessions(userID string) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	now := time.Now()
+	ids, err := s.sessionRepo.RevokeAllByUser(userID, now)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		s.revocations.Revoke(id, now.Add(s.cfg.AccessTokenTTL))
+	}
+	return nil
+}
+
+// startSession logs user in on a new session
+func (s *authService) startSession(user *models.User) (*TokenResponse, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.Session{
+		UserID:           user.ID,
+		RefreshTokenHash: hashRefreshToken(token),
+		ExpiresAt:        time.Now().Add(s.cfg.RefreshTokenTTL),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(user, session, token)
+}
+
+// issueTokens signs an access token for user on session, handing it out with the session's
+// current refresh token
+func (s *authService) issueTokens(user *models.User, session *models.Session, refreshToken string) (*TokenResponse, error) {
+	expiresAt := time.Now().Add(s.cfg.AccessTokenTTL)
 	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		Role:   user.Role,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: session.ID,
+		Version:   user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -143,12 +267,29 @@ func (s *authService) Login(req LoginRequest) (*TokenResponse, error) {
 	}
 
 	return &TokenResponse{
-		Token:     tokenString,
-		ExpiresAt: expiresAt,
-		User:      user,
+		Token:            tokenString,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+		User:             user,
 	}, nil
 }
 
+// newRefreshToken returns a random refresh token
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashRefreshToken is how a refresh token is stored, so a leaked database holds no usable ones
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(s.cfg.JWTSecret), nil
@@ -159,12 +300,33 @@ func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
 	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+		// Tokens from before sessions existed cannot be revoked, and are not accepted
+		if claims.SessionID == "" || s.revocations.IsRevoked(claims.SessionID) {
+			return nil, ErrTokenRevoked
+		}
+		if err := s.checkPrivileges(claims); err != nil {
+			return nil, err
+		}
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
+// checkPrivileges re-validates the claims of a token against the database, so demoting or
+// disabling a user takes effect on their next request rather than at token expiry, even when
+// revoking their sessions failed
+func (s *authService) checkPrivileges(claims *Claims) error {
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return ErrTokenRevoked
+	}
+	if user.DisabledAt != nil || user.Role != claims.Role || user.TokenVersion != claims.Version {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
 func (s *authService) GetUserByID(id string) (*models.User, error) {
 	return s.userRepo.FindByID(id)
 }
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+func register(t *testing.T, s *testServices) *TokenResponse {
+	t.Helper()
+	tokens, err := s.auth.Register(RegisterRequest{Email: "budi@example.com", Password: "secret123", Name: "Budi Santoso"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return tokens
+}
+
+func TestAuthRefreshRotates(t *testing.T) {
+	s := newTestServices(t)
+	first := register(t, s)
+
+	second, err := s.auth.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	// Access tokens are signed to the second, one refreshed straight away may read the same
+	if second.RefreshToken == first.RefreshToken || second.Token == "" {
+		t.Error("Refresh handed back the refresh token it was given, want a new pair")
+	}
+	before, err := s.auth.ValidateToken(first.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.auth.ValidateToken(second.Token)
+	if err != nil {
+		t.Fatalf("new access token: %v", err)
+	}
+	if after.SessionID != before.SessionID || after.UserID != before.UserID {
+		t.Errorf("new access token is for session %s of %s, want session %s of %s", after.SessionID, after.UserID, before.SessionID, before.UserID)
+	}
+
+	// The pair keeps rotating, each refresh token working once
+	third, err := s.auth.Refresh(second.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh of the rotated token: %v", err)
+	}
+	if third.RefreshToken == second.RefreshToken {
+		t.Error("second refresh handed back the same refresh token")
+	}
+	if _, err := s.auth.Refresh("not-a-refresh-token"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("unknown refresh token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+}
+
+// Replaying a refresh token already traded means it leaked, whoever holds the session loses it
+func TestAuthRefreshReuseRevokesSession(t *testing.T) {
+	s := newTestServices(t)
+	first := register(t, s)
+	second, err := s.auth.Refresh(first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.auth.Refresh(first.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("replayed refresh token: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	if _, err := s.auth.Refresh(second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("current refresh token after the replay: err = %v, want ErrInvalidRefreshToken", err)
+	}
+	for name, token := range map[string]string{"first": first.Token, "second": second.Token} {
+		if _, err := s.auth.ValidateToken(token); !errors.Is(err, ErrTokenRevoked) {
+			t.Errorf("%s access token after the replay: err = %v, want ErrTokenRevoked", name, err)
+		}
+	}
+
+	// Other sessions of the user are left alone
+	other, err := s.auth.Login(LoginRequest{Email: "budi@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.auth.Refresh(other.RefreshToken); err != nil {
+		t.Errorf("refresh on another session: %v", err)
+	}
+}
+
+// readTogether holds every lookup of a session until clients have read it, so they all try to
+// rotate the same refresh token
+type readTogether struct {
+	repository.SessionRepository
+	read *sync.WaitGroup
+}
+
+func (r readTogether) FindByRefreshTokenHash(hash string) (*models.Session, error) {
+	session, err := r.SessionRepository.FindByRefreshTokenHash(hash)
+	r.read.Done()
+	r.read.Wait()
+	return session, err
+}
+
+// Of clients refreshing with the same token at once, one gets the new pair
+func TestAuthRefreshConcurrently(t *testing.T) {
+	s := newTestServicesOn(t, openPooledTestDB)
+	tokens := register(t, s)
+
+	const clients = 8
+	var read sync.WaitGroup
+	read.Add(clients)
+	auth := NewAuthService(s.userRepo, s.orderRepo, readTogether{SessionRepository: s.sessionRepo, read: &read}, s.bus, s.cfg)
+
+	var wg sync.WaitGroup
+	results := make([]*TokenResponse, clients)
+	errs := make([]error, clients)
+	for i := 0; i < clients; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = auth.Refresh(tokens.RefreshToken)
+		}(i)
+	}
+	wg.Wait()
+
+	var winners int
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			winners++
+			if results[i].RefreshToken == tokens.RefreshToken {
+				t.Error("the winner got the old refresh token back")
+			}
+		case !errors.Is(err, ErrInvalidRefreshToken):
+			t.Errorf("client %d: %v", i, err)
+		}
+	}
+	if winners != 1 {
+		t.Errorf("%d clients rotated the token, want 1", winners)
+	}
+}
+
+// failingRevoke fails to revoke sessions
+type failingRevoke struct{ AuthService }
+
+func (failingRevoke) RevokeAllSessions(string) error { return errInjected }
+
+// A role change or disable makes the user's tokens stale on their next request, whatever their
+// role, even when their sessions could not be revoked
+func TestAuthTokensFollowUserChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(users UserService, userID string) error
+	}{
+		{name: "promoted", change: func(users UserService, userID string) error {
+			_, err := users.ChangeRole(userID, "admin-1", ChangeRoleRequest{Role: string(models.RoleAdmin)})
+			return err
+		}},
+		{name: "disabled", change: func(users UserService, userID string) error {
+			_, err := users.Disable(userID, "admin-1", UserStatusRequest{Reason: "fraud"})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			tokens := register(t, s)
+			if _, err := s.auth.ValidateToken(tokens.Token); err != nil {
+				t.Fatal(err)
+			}
+
+			users := NewUserService(s.userRepo, s.uow, failingRevoke{s.auth})
+			if err := tt.change(users, tokens.User.ID); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.auth.ValidateToken(tokens.Token); !errors.Is(err, ErrTokenRevoked) {
+				t.Errorf("access token after the change: err = %v, want ErrTokenRevoked", err)
+			}
+		})
+	}
+}
+
+func TestAuthDisabledUser(t *testing.T) {
+	s := newTestServices(t)
+	tokens := register(t, s)
+	users := NewUserService(s.userRepo, s.uow, s.auth)
+	if _, err := users.Disable(tokens.User.ID, "admin-1", UserStatusRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.auth.Login(LoginRequest{Email: "budi@example.com", Password: "secret123"}); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("Login: err = %v, want ErrUserDisabled", err)
+	}
+	if _, err := s.auth.Refresh(tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("Refresh: err = %v, want ErrInvalidRefreshToken", err)
+	}
+
+	// Enabling lets them log in again, tokens from before stay stale
+	if _, err := users.Enable(tokens.User.ID, "admin-1", UserStatusRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.auth.Login(LoginRequest{Email: "budi@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login after enabling: %v", err)
+	}
+	if _, err := s.auth.ValidateToken(again.Token); err != nil {
+		t.Errorf("new access token: %v", err)
+	}
+	if _, err := s.auth.ValidateToken(tokens.Token); !errors.Is(err, ErrTokenRevoked) {
+		t.Errorf("access token from before the disable: err = %v, want ErrTokenRevoked", err)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mirahekatiket/flight-go/internal/config"
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+var ErrInvalidBookingToken = errors.New("invalid booking token")
+
+// bookingTokenGrace is how long after the flight a booking can still be managed
+const bookingTokenGrace = 30 * 24 * time.Hour
+
+// BookingClaims let the holder manage one order without an account
+type BookingClaims struct {
+	OrderID string `json:"order_id"`
+	jwt.RegisteredClaims
+}
+
+// BookingTokenService signs the booking-management tokens handed out with guest orders.
+// The signing key is derived from the JWT secret but differs from it, so a booking token
+// is never accepted as a login token or the other way round.
+type BookingTokenService struct {
+	key []byte
+}
+
+func NewBookingTokenService(cfg *config.Config) *BookingTokenService {
+	mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+	mac.Write([]byte("booking-management"))
+	return &BookingTokenService{key: mac.Sum(nil)}
+}
+
+// Issue signs a token for order, valid until a month after its flight
+func (s *BookingTokenService) Issue(order *models.Order) (string, error) {
+	claims := &BookingClaims{
+		OrderID: order.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(order.FlightDate.Add(bookingTokenGrace)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.key)
+}
+
+// Validate returns the ID of the order tokenString was issued for
+func (s *BookingTokenService) Validate(tokenString string) (string, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &BookingClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil {
+		return "", ErrInvalidBookingToken
+	}
+
+	claims, ok := token.Claims.(*BookingClaims)
+	if !ok || !token.Valid || claims.OrderID == "" {
+		return "", ErrInvalidBookingToken
+	}
+	return claims.OrderID, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrNoExchangeRate      = errors.New("no exchange rate for currency pair")
+)
+
+// CurrencyService converts prices between currencies using the admin-managed exchange rate table
+type CurrencyService struct {
+	exchangeRateRepo repository.ExchangeRateRepository
+}
+
+func NewCurrencyService(exchangeRateRepo repository.ExchangeRateRepository) *CurrencyService {
+	return &CurrencyService{exchangeRateRepo: exchangeRateRepo}
+}
+
+// Rate returns how much of currency to one unit of currency from buys at the given time.
+// A pair without its own rate falls back to the inverse of the opposite pair; when both
+// exist the one that took effect last wins.
+func (s *CurrencyService) Rate(from, to string, at time.Time) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	direct, directErr := s.exchangeRateRepo.FindEffective(from, to, at.UTC())
+	inverse, inverseErr := s.exchangeRateRepo.FindEffective(to, from, at.UTC())
+	switch {
+	case directErr == nil && (inverseErr != nil || !inverse.EffectiveFrom.After(direct.EffectiveFrom)):
+		return direct.Rate, nil
+	case inverseErr == nil:
+		return 1 / inverse.Rate, nil
+	default:
+		return 0, ErrNoExchangeRate
+	}
+}
+
+// ConvertQuote re-prices a quote in currency at the rate effective at the given time.
+// Each component is rounded to the currency's minor unit and Fare is their sum, so
+// the breakdown still adds up after conversion.
+func (s *CurrencyService) ConvertQuote(quote *models.FareQuote, currency string, at time.Time) error {
+	if currency == "" || currency == quote.Currency {
+		return nil
+	}
+	if !models.IsSupportedCurrency(currency) {
+		return ErrUnsupportedCurrency
+	}
+
+	rate, err := s.Rate(quote.Currency, currency, at)
+	if err != nil {
+		return err
+	}
+
+	quote.BaseFare = models.RoundToCurrency(quote.BaseFare*rate, currency)
+	fare := models.ToMinorUnits(quote.BaseFare, currency)
+	for i := range quote.Adjustments {
+		quote.Adjustments[i].Amount = models.RoundToCurrency(quote.Adjustments[i].Amount*rate, currency)
+		fare += models.ToMinorUnits(quote.Adjustments[i].Amount, currency)
+	}
+	if quote.FareFamily != nil {
+		quote.FareFamily.Amount = models.RoundToCurrency(quote.FareFamily.Amount*rate, currency)
+		fare += models.ToMinorUnits(quote.FareFamily.Amount, currency)
+	}
+
+	quote.Fare = models.FromMinorUnits(fare, currency)
+	quote.Currency = currency
+	quote.ExchangeRate = rate
+	return nil
+}
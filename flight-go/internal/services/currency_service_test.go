@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+func addExchangeRates(t *testing.T, s *testServices, rates ...models.ExchangeRate) {
+	t.Helper()
+	repo := repository.NewExchangeRateRepository(s.staging)
+	for i := range rates {
+		if err := repo.Create(&rates[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCurrencyServiceRate(t *testing.T) {
+	now := time.Now().UTC()
+	s := newTestServices(t)
+	addExchangeRates(t, s,
+		models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 15000, EffectiveFrom: now.AddDate(0, 0, -10)},
+		models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 16000, EffectiveFrom: now.AddDate(0, 0, -1)},
+		models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 99999, EffectiveFrom: now.AddDate(0, 0, 5)}, // not yet effective
+		models.ExchangeRate{FromCurrency: "SGD", ToCurrency: "IDR", Rate: 12000, EffectiveFrom: now.AddDate(0, 0, -10)},
+		models.ExchangeRate{FromCurrency: "IDR", ToCurrency: "SGD", Rate: 1.0 / 12500, EffectiveFrom: now.AddDate(0, 0, -2)}, // newer than SGD->IDR
+	)
+
+	tests := []struct {
+		name     string
+		from, to string
+		at       time.Time
+		want     float64
+		wantErr  error
+	}{
+		{name: "same currency", from: "IDR", to: "IDR", at: now, want: 1},
+		{name: "latest effective direct rate", from: "USD", to: "IDR", at: now, want: 16000},
+		{name: "rate effective at an earlier time", from: "USD", to: "IDR", at: now.AddDate(0, 0, -5), want: 15000},
+		{name: "inverse of the opposite pair", from: "IDR", to: "USD", at: now, want: 1.0 / 16000},
+		{name: "newer inverse beats an older direct rate", from: "SGD", to: "IDR", at: now, want: 12500},
+		{name: "no rate before the first one", from: "USD", to: "IDR", at: now.AddDate(0, 0, -30), wantErr: ErrNoExchangeRate},
+		{name: "unknown pair", from: "USD", to: "JPY", at: now, wantErr: ErrNoExchangeRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.currency.Rate(tt.from, tt.to, tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if diff := got - tt.want; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Rate(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyServiceConvertQuote(t *testing.T) {
+	s := newTestServices(t)
+	addExchangeRates(t, s,
+		models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 15700, EffectiveFrom: time.Now().AddDate(0, 0, -1)},
+		models.ExchangeRate{FromCurrency: "JPY", ToCurrency: "IDR", Rate: 104.3, EffectiveFrom: time.Now().AddDate(0, 0, -1)},
+	)
+
+	newQuote := func() *models.FareQuote {
+		return &models.FareQuote{
+			Currency: "IDR",
+			BaseFare: 800000,
+			Adjustments: []models.FareAdjustment{
+				{Name: "last minute", Amount: 160000},
+				{Name: "weekday discount", Amount: -96000},
+			},
+			FareFamily: &models.FareFamilyPrice{Code: "FLEX", Amount: 259200},
+			Fare:       1123200,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		currency string
+		wantFare float64
+		wantErr  error
+	}{
+		{name: "unchanged without a currency", currency: "", wantFare: 1123200},
+		{name: "unchanged in its own currency", currency: "IDR", wantFare: 1123200},
+		// 50.96 + 10.19 - 6.11 + 16.51, each rounded to cents before summing
+		{name: "dollars", currency: "USD", wantFare: 71.55},
+		// 7670 + 1534 - 920 + 2485, yen have no minor unit
+		{name: "yen", currency: "JPY", wantFare: 10769},
+		{name: "unsupported currency", currency: "XXX", wantErr: ErrUnsupportedCurrency},
+		{name: "no rate", currency: "EUR", wantErr: ErrNoExchangeRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote := newQuote()
+			err := s.currency.ConvertQuote(quote, tt.currency, time.Now())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if quote.Fare != tt.wantFare {
+				t.Errorf("fare = %v, want %v", quote.Fare, tt.wantFare)
+			}
+
+			// The converted components still add up to the fare exactly
+			currency := quote.Currency
+			sum := models.ToMinorUnits(quote.BaseFare, currency) + models.ToMinorUnits(quote.FareFamily.Amount, currency)
+			for _, adjustment := range quote.Adjustments {
+				sum += models.ToMinorUnits(adjustment.Amount, currency)
+			}
+			if sum != models.ToMinorUnits(quote.Fare, currency) {
+				t.Errorf("components sum to %d minor units, fare is %v", sum, quote.Fare)
+			}
+		})
+	}
+}
+
+func TestOrderCreateInForeignCurrency(t *testing.T) {
+	s := newTestServices(t)
+	addExchangeRates(t, s, models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 16000, EffectiveFrom: time.Now().AddDate(0, 0, -1)})
+
+	req := bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Parent"), child("Kid", 6))
+	req.Currency = "usd"
+	order, err := s.orders.Create("", "", req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if order.Currency != "USD" || order.PriceBreakdown.Currency != "USD" {
+		t.Fatalf("order in %s, breakdown in %s, want USD", order.Currency, order.PriceBreakdown.Currency)
+	}
+	// IDR 800,000 is USD 50.00 for the adult and 37.50 for the child at 75%
+	if order.TotalAmount != 8750 {
+		t.Errorf("total = %d cents, want 8750", order.TotalAmount)
+	}
+	var sum int64
+	for _, item := range order.PriceBreakdown.Items {
+		sum += item.Amount
+	}
+	if sum != order.TotalAmount {
+		t.Errorf("line items sum to %d, total is %d", sum, order.TotalAmount)
+	}
+}
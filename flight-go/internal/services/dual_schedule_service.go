@@ -1,8 +1,15 @@
 package services
 
 import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
-	
+
+	"github.com/mirahekatiket/flight-go/internal/config"
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 )
@@ -13,6 +20,15 @@ type DualScheduleService struct {
 	productionRepo   repository.ScheduleRepository
 	whitelistService *WhitelistService
 	airlineRepo      repository.AirlineRepository
+	searchCache      *SearchCache
+	pricingService   *PricingService
+	feeService       *FeeService
+	events           *events.Bus
+
+	// Search fan-out settings
+	searchWorkers     int
+	stagingTimeout    time.Duration
+	productionTimeout time.Duration
 }
 
 func NewDualScheduleService(
@@ -20,27 +36,37 @@ func NewDualScheduleService(
 	productionRepo repository.ScheduleRepository,
 	whitelistService *WhitelistService,
 	airlineRepo repository.AirlineRepository,
+	searchCache *SearchCache,
+	pricingService *PricingService,
+	feeService *FeeService,
+	bus *events.Bus,
+	cfg *config.Config,
 ) *DualScheduleService {
 	return &DualScheduleService{
-		stagingRepo:      stagingRepo,
-		productionRepo:   productionRepo,
-		whitelistService: whitelistService,
-		airlineRepo:      airlineRepo,
+		stagingRepo:       stagingRepo,
+		productionRepo:    productionRepo,
+		whitelistService:  whitelistService,
+		airlineRepo:       airlineRepo,
+		searchCache:       searchCache,
+		pricingService:    pricingService,
+		feeService:        feeService,
+		events:            bus,
+		searchWorkers:     cfg.SearchWorkers,
+		stagingTimeout:    cfg.StagingSearchTimeout,
+		productionTimeout: cfg.ProductionSearchTimeout,
 	}
 }
 
-// currentEmail stores the current request's email for database selection
-var currentEmail string
-
-// SetCurrentEmail sets the email for the current request
-func (s *DualScheduleService) SetCurrentEmail(email string) {
-	currentEmail = email
+// airlineSearch is a single airline lookup in the search fan-out
+type airlineSearch struct {
+	airlineID string
+	env       models.Environment
+	repo      repository.ScheduleRepository
+	timeout   time.Duration
 }
 
-// getWhitelistedAirlineIDs returns the list of whitelisted airline IDs for the current user
-func (s *DualScheduleService) getWhitelistedAirlineIDs() []string {
-	email := currentEmail
-	
+// getWhitelistedAirlineIDs returns the list of whitelisted airline IDs for email
+func (s *DualScheduleService) getWhitelistedAirlineIDs(email string) []string {
 	if email == "" {
 		return []string{}
 	}
@@ -60,66 +86,90 @@ func (s *DualScheduleService) getWhitelistedAirlineIDs() []string {
 	return whitelistedUser.EnabledAirlineIDs
 }
 
-// getRepo returns the appropriate repository based on email and whitelist status
-func (s *DualScheduleService) getRepo() repository.ScheduleRepository {
-	email := currentEmail
-	
+// getRepo returns the appropriate repository and its environment based on email and whitelist status
+func (s *DualScheduleService) getRepo(email string) (repository.ScheduleRepository, models.Environment) {
 	if email == "" {
-		return s.stagingRepo
+		return s.stagingRepo, models.EnvStaging
 	}
 
 	// Check if user is whitelisted
 	whitelisted, err := s.whitelistService.IsEmailWhitelisted(email)
 	if err != nil || !whitelisted {
-		return s.stagingRepo
+		return s.stagingRepo, models.EnvStaging
 	}
 
 	// Get whitelisted user to check enabled airlines
 	whitelistedUser, err := s.whitelistService.GetByEmail(email)
 	if err != nil {
-		return s.stagingRepo
+		return s.stagingRepo, models.EnvStaging
 	}
 
 	// If user has whitelisted airlines, use production
 	if len(whitelistedUser.EnabledAirlineIDs) > 0 {
-		return s.productionRepo
+		return s.productionRepo, models.EnvProduction
 	}
 
-	return s.stagingRepo
+	return s.stagingRepo, models.EnvStaging
 }
 
-// Search searches for flights based on criteria
-// Iterates through all airlines and combines results from staging and production
+// Search searches for flights based on criteria as a guest
 func (s *DualScheduleService) Search(req SearchFlightRequest) (*PaginatedResponse, error) {
+	return s.SearchContext(context.Background(), "", req)
+}
+
+// SearchContext searches for flights based on criteria
+// Airlines are queried concurrently by a bounded worker pool, each against staging or
+// production depending on email's whitelist, and results are combined in airline order.
+// Every lookup gets its environment's timeout, and all outstanding lookups are
+// cancelled when ctx is done (e.g. the client disconnects).
+// Complete results are cached per environment partition, see SearchCacheKey.
+func (s *DualScheduleService) SearchContext(ctx context.Context, email string, req SearchFlightRequest) (*PaginatedResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.PageSize < 1 {
+		req.PageSize = 10
+	}
+
 	// Parse departure date
 	departureDate, err := time.Parse("2006-01-02", req.DepartureDate)
 	if err != nil {
 		return nil, err
 	}
-	
+
+	if req.Currency != "" && !models.IsSupportedCurrency(req.Currency) {
+		return nil, ErrUnsupportedCurrency
+	}
+
 	// Convert cabin class string to CabinClass type
-	var cabinClass models.CabinClass
-	switch req.CabinClass {
-	case "business":
-		cabinClass = models.CabinBusiness
-	case "first":
-		cabinClass = models.CabinFirst
-	default:
-		cabinClass = models.CabinEconomy
-	}
-	
-	// Get whitelisted airline IDs for the current user
-	whitelistedAirlineIDs := s.getWhitelistedAirlineIDs()
+	cabinClass := toCabinClass(req.CabinClass)
+
+	// Get whitelisted airline IDs for the requesting user
+	whitelistedAirlineIDs := s.getWhitelistedAirlineIDs(email)
 	whitelistedMap := make(map[string]bool)
 	for _, id := range whitelistedAirlineIDs {
 		whitelistedMap[id] = true
 	}
-	
+
+	cacheKey := SearchCacheKey{
+		Origin:        req.Origin,
+		Destination:   req.Destination,
+		DepartureDate: req.DepartureDate,
+		CabinClass:    cabinClass,
+		AirlineIDs:    req.Airlines,
+		ProductionIDs: whitelistedAirlineIDs,
+	}
+	if schedules, ok := s.searchCache.Get(ctx, cacheKey); ok {
+		return s.searchResponse(schedules, req, cabinClass, departureDate, whitelistedMap)
+	}
+	generation := s.searchCache.Generation()
+
 	// Get all airlines to iterate through
 	var airlinesToQuery []string
 	if len(req.Airlines) > 0 {
-		// If specific airlines requested, use those
-		airlinesToQuery = req.Airlines
+		// If specific airlines requested, use those (sorted, so cached and fresh results agree)
+		airlinesToQuery = append([]string(nil), req.Airlines...)
+		sort.Strings(airlinesToQuery)
 	} else {
 		// Get all active airlines from staging (they should be the same in both)
 		allAirlines, err := s.airlineRepo.ListAll()
@@ -132,77 +182,215 @@ func (s *DualScheduleService) Search(req SearchFlightRequest) (*PaginatedRespons
 			}
 		}
 	}
-	
-	// Combine results from both databases
-	var allSchedules []models.Schedule
-	
-	// Query each airline
+
+	searches := make([]airlineSearch, 0, len(airlinesToQuery))
 	for _, airlineID := range airlinesToQuery {
-		var repo repository.ScheduleRepository
-		
-		// Check if this airline is whitelisted for the user
+		// Use production database for whitelisted airlines, staging for the rest
 		if whitelistedMap[airlineID] {
-			// Use production database for whitelisted airlines
-			repo = s.productionRepo
+			searches = append(searches, airlineSearch{airlineID: airlineID, env: models.EnvProduction, repo: s.productionRepo, timeout: s.productionTimeout})
 		} else {
-			// Use staging database for non-whitelisted airlines
-			repo = s.stagingRepo
-		}
-		
-		// Create search params for this specific airline
-		params := repository.SearchParams{
-			DepartureAirportCode: req.Origin,
-			ArrivalAirportCode:   req.Destination,
-			DepartureDate:        departureDate,
-			CabinClass:           cabinClass,
-			AirlineIDs:           []string{airlineID},
-			Page:                 1,
-			PageSize:             1000, // Get all results for this airline
-		}
-		
-		schedules, _, err := repo.Search(params)
-		if err != nil {
-			// Log error but continue with other airlines
-			continue
+			searches = append(searches, airlineSearch{airlineID: airlineID, env: models.EnvStaging, repo: s.stagingRepo, timeout: s.stagingTimeout})
 		}
-		
+	}
+
+	params := repository.SearchParams{
+		DepartureAirportCode: req.Origin,
+		ArrivalAirportCode:   req.Destination,
+		DepartureDate:        departureDate,
+		CabinClass:           cabinClass,
+		Page:                 1,
+		PageSize:             1000, // Get all results for each airline
+	}
+
+	results, complete := s.searchAirlines(ctx, searches, params)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Combine results from both databases
+	allSchedules := []models.Schedule{}
+	for _, schedules := range results {
 		allSchedules = append(allSchedules, schedules...)
 	}
-	
-	// Calculate pagination
-	total := int64(len(allSchedules))
-	start := (req.Page - 1) * req.PageSize
-	end := start + req.PageSize
-	
-	if start >= len(allSchedules) {
-		allSchedules = []models.Schedule{}
+
+	// Don't cache partial results from failed or timed out lookups
+	if complete {
+		s.searchCache.Set(ctx, cacheKey, generation, allSchedules)
+	}
+
+	return s.searchResponse(allSchedules, req, cabinClass, departureDate, whitelistedMap)
+}
+
+// searchResponse paginates a combined search result and prices the returned page
+// Fares depend on live seat inventory, so they are quoted after the cache, never stored in it.
+func (s *DualScheduleService) searchResponse(schedules []models.Schedule, req SearchFlightRequest, cabin models.CabinClass, departureDate time.Time, whitelisted map[string]bool) (*PaginatedResponse, error) {
+	response := paginateSchedules(schedules, req.Page, req.PageSize)
+
+	envFor := func(schedule *models.Schedule) models.Environment {
+		if whitelisted[schedule.AirlineID] {
+			return models.EnvProduction
+		}
+		return models.EnvStaging
+	}
+
+	page := response.Data.([]models.Schedule)
+	if err := s.pricingService.QuoteSchedules(page, cabin, departureDate, req.Currency, envFor); err != nil {
+		return nil, err
+	}
+	if err := s.feeService.PreviewSchedules(page, envFor); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// paginateSchedules slices an in-memory search result into the requested page
+func paginateSchedules(schedules []models.Schedule, page, pageSize int) *PaginatedResponse {
+	total := int64(len(schedules))
+	start := (page - 1) * pageSize
+	end := start + pageSize
+
+	if start >= len(schedules) {
+		schedules = []models.Schedule{}
 	} else {
-		if end > len(allSchedules) {
-			end = len(allSchedules)
+		if end > len(schedules) {
+			end = len(schedules)
 		}
-		allSchedules = allSchedules[start:end]
+		schedules = schedules[start:end]
 	}
-	
-	totalPages := int(total) / req.PageSize
-	if int(total)%req.PageSize != 0 {
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize != 0 {
 		totalPages++
 	}
 
 	return &PaginatedResponse{
-		Data:       allSchedules,
-		Page:       req.Page,
-		PageSize:   req.PageSize,
+		Data:       schedules,
+		Page:       page,
+		PageSize:   pageSize,
 		TotalItems: total,
 		TotalPages: totalPages,
-	}, nil
+	}
+}
+
+// searchAirlines runs the airline lookups on at most searchWorkers goroutines
+// Results are indexed like searches so the combined order stays deterministic.
+// complete is false when any lookup failed or was not run.
+func (s *DualScheduleService) searchAirlines(ctx context.Context, searches []airlineSearch, params repository.SearchParams) (results [][]models.Schedule, complete bool) {
+	results = make([][]models.Schedule, len(searches))
+	failed := make([]bool, len(searches))
+
+	workers := s.searchWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(searches) {
+		workers = len(searches)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], failed[i] = s.searchAirline(ctx, searches[i], params)
+			}
+		}()
+	}
+
+	dispatched := 0
+dispatch:
+	for i := range searches {
+		select {
+		case indexes <- i:
+			dispatched++
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	complete = dispatched == len(searches)
+	for _, f := range failed {
+		if f {
+			complete = false
+		}
+	}
+	return results, complete
+}
+
+// searchAirline queries one airline in its environment, bounded by the environment timeout
+func (s *DualScheduleService) searchAirline(ctx context.Context, search airlineSearch, params repository.SearchParams) (schedules []models.Schedule, failed bool) {
+	if search.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, search.timeout)
+		defer cancel()
+	}
+
+	params.AirlineIDs = []string{search.airlineID}
+	schedules, _, err := search.repo.SearchContext(ctx, params)
+	if err != nil {
+		// Log error but continue with other airlines
+		log.Printf("Search for airline %s in %s failed: %v", search.airlineID, search.env, err)
+		return nil, true
+	}
+
+	return schedules, false
 }
 
-// GetByID gets a schedule by ID
+// GetByID gets a schedule by ID as a guest sees it
 func (s *DualScheduleService) GetByID(id string) (*models.Schedule, error) {
-	repo := s.getRepo()
+	return s.GetFor("", id)
+}
+
+// GetFor gets a schedule by ID from the environment email is sold from
+func (s *DualScheduleService) GetFor(email, id string) (*models.Schedule, error) {
+	repo, _ := s.getRepo(email)
 	return repo.FindByID(id)
 }
 
+// GetFlightDetail gets a schedule by ID with its fare and fare families quoted for a cabin and departure date
+// as email sees it. An empty currency quotes in the schedule's own currency.
+func (s *DualScheduleService) GetFlightDetail(email, id string, cabinClass string, departureDate string, currency string) (*models.Schedule, error) {
+	flightDate, err := time.Parse("2006-01-02", departureDate)
+	if err != nil {
+		return nil, ErrInvalidFlightDate
+	}
+	if currency != "" && !models.IsSupportedCurrency(currency) {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	repo, env := s.getRepo(email)
+	schedule, err := repo.FindByID(id)
+	if err != nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	cabin := toCabinClass(cabinClass)
+	quote, err := s.pricingService.Quote(env, schedule, cabin, flightDate, currency)
+	if err != nil {
+		return nil, err
+	}
+	schedule.Fare = quote
+
+	offers, err := s.pricingService.FareFamilyOffers(env, schedule, cabin, flightDate, quote)
+	if err != nil {
+		return nil, err
+	}
+	schedule.FareFamilies = offers
+
+	preview, err := s.feeService.Breakdown(env, schedule, quote, PassengerCounts{Adults: 1}, schedule.Airline.RulesOrDefault())
+	if err != nil {
+		return nil, err
+	}
+	schedule.PricePreview = preview
+
+	return schedule, nil
+}
+
 // List lists all schedules (admin operation - uses staging)
 func (s *DualScheduleService) List(page, pageSize int) (*PaginatedResponse, error) {
 	schedules, total, err := s.stagingRepo.List(page, pageSize)
@@ -224,11 +412,9 @@ func (s *DualScheduleService) List(page, pageSize int) (*PaginatedResponse, erro
 	}, nil
 }
 
-// ListByAirline lists schedules for a specific airline
+// ListByAirline lists schedules for a specific airline as a guest sees them (staging)
 func (s *DualScheduleService) ListByAirline(airlineID string, page, pageSize int) (*PaginatedResponse, error) {
-	repo := s.getRepo()
-	
-	schedules, total, err := repo.ListByAirline(airlineID, page, pageSize)
+	schedules, total, err := s.stagingRepo.ListByAirline(airlineID, page, pageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -267,6 +453,11 @@ func (s *DualScheduleService) Create(req CreateScheduleRequest) (*models.Schedul
 		EconomySeats:       req.EconomySeats,
 		BusinessSeats:      req.BusinessSeats,
 		FirstClassSeats:    req.FirstClassSeats,
+		Currency:           strings.ToUpper(req.Currency),
+	}
+
+	if schedule.Currency != "" && !models.IsSupportedCurrency(schedule.Currency) {
+		return nil, ErrUnsupportedCurrency
 	}
 
 	// Handle IsActive
@@ -288,6 +479,10 @@ func (s *DualScheduleService) Create(req CreateScheduleRequest) (*models.Schedul
 		// In production, you might want to use a message queue for this
 	}
 
+	// Each environment's subscribers get their own copy, neither shares the one returned
+	s.events.Publish(events.ScheduleChanged{Environment: models.EnvStaging, After: schedule.Clone()})
+	s.events.Publish(events.ScheduleChanged{Environment: models.EnvProduction, After: schedule.Clone()})
+
 	return schedule, nil
 }
 
@@ -299,6 +494,10 @@ func (s *DualScheduleService) Update(id string, req UpdateScheduleRequest) (*mod
 		return nil, err
 	}
 
+	// As it was in each database, for subscribers telling what changed
+	stagingBefore := *schedule
+	productionBefore, _ := s.productionRepo.FindByID(id)
+
 	// Update fields
 	if req.FlightNumber != "" {
 		schedule.FlightNumber = req.FlightNumber
@@ -348,6 +547,12 @@ func (s *DualScheduleService) Update(id string, req UpdateScheduleRequest) (*mod
 	if req.FirstClassSeats >= 0 {
 		schedule.FirstClassSeats = req.FirstClassSeats
 	}
+	if req.Currency != "" {
+		if !models.IsSupportedCurrency(strings.ToUpper(req.Currency)) {
+			return nil, ErrUnsupportedCurrency
+		}
+		schedule.Currency = strings.ToUpper(req.Currency)
+	}
 	if req.IsActive != nil {
 		schedule.IsActive = *req.IsActive
 	}
@@ -360,11 +565,17 @@ func (s *DualScheduleService) Update(id string, req UpdateScheduleRequest) (*mod
 	// Also update in production
 	s.productionRepo.Update(schedule)
 
+	s.events.Publish(events.ScheduleChanged{Environment: models.EnvStaging, Before: &stagingBefore, After: schedule.Clone()})
+	s.events.Publish(events.ScheduleChanged{Environment: models.EnvProduction, Before: productionBefore, After: schedule.Clone()})
+
 	return schedule, nil
 }
 
 // Delete deletes a schedule (admin operation - deletes from both databases)
 func (s *DualScheduleService) Delete(id string) error {
+	stagingBefore, _ := s.stagingRepo.FindByID(id)
+	productionBefore, _ := s.productionRepo.FindByID(id)
+
 	// Delete from staging
 	if err := s.stagingRepo.Delete(id); err != nil {
 		return err
@@ -373,6 +584,9 @@ func (s *DualScheduleService) Delete(id string) error {
 	// Also delete from production
 	s.productionRepo.Delete(id)
 
+	s.events.Publish(events.ScheduleChanged{Environment: models.EnvStaging, Before: stagingBefore})
+	s.events.Publish(events.ScheduleChanged{Environment: models.EnvProduction, Before: productionBefore})
+
 	return nil
 }
 
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+	"gorm.io/gorm"
+)
+
+// slowScheduleRepository blocks searches for one airline until ctx is done
+type slowScheduleRepository struct {
+	repository.ScheduleRepository
+	airlineID string
+}
+
+func (r slowScheduleRepository) SearchContext(ctx context.Context, params repository.SearchParams) ([]models.Schedule, int64, error) {
+	if len(params.AirlineIDs) == 1 && params.AirlineIDs[0] == r.airlineID {
+		<-ctx.Done()
+		return nil, 0, ctx.Err()
+	}
+	return r.ScheduleRepository.SearchContext(ctx, params)
+}
+
+// searchWith returns a copy of the test search service with its own fan-out settings and no cache
+func searchWith(s *testServices, workers int) *DualScheduleService {
+	search := *s.search
+	search.searchWorkers = workers
+	search.searchCache = nil
+	return &search
+}
+
+func flightNumbers(t testing.TB, result *PaginatedResponse) []string {
+	t.Helper()
+	var numbers []string
+	for _, schedule := range result.Data.([]models.Schedule) {
+		numbers = append(numbers, schedule.FlightNumber)
+	}
+	return numbers
+}
+
+func TestDualScheduleServiceSearchFanOutIsDeterministic(t *testing.T) {
+	s := newTestServices(t)
+	s.whitelistUser(t, "partner@example.com", "ga", "jt", "qg")
+	req := SearchFlightRequest{Origin: "CGK", Destination: "DPS", DepartureDate: flightDate(7), PageSize: 100}
+
+	want, err := searchWith(s, 1).SearchContext(context.Background(), "partner@example.com", req)
+	if err != nil {
+		t.Fatalf("sequential search: %v", err)
+	}
+	wantNumbers := flightNumbers(t, want)
+	if len(wantNumbers) == 0 {
+		t.Fatal("sequential search found nothing")
+	}
+
+	tests := []struct {
+		name    string
+		workers int
+	}{
+		{name: "zero workers falls back to one", workers: 0},
+		{name: "fewer workers than airlines", workers: 3},
+		{name: "more workers than airlines", workers: 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchWith(s, tt.workers).SearchContext(context.Background(), "partner@example.com", req)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+			gotNumbers := flightNumbers(t, got)
+			if len(gotNumbers) != len(wantNumbers) {
+				t.Fatalf("got %d flights, want %d", len(gotNumbers), len(wantNumbers))
+			}
+			for i := range wantNumbers {
+				if gotNumbers[i] != wantNumbers[i] {
+					t.Fatalf("flight %d = %s, want %s", i, gotNumbers[i], wantNumbers[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDualScheduleServiceSearchTimeouts(t *testing.T) {
+	tests := []struct {
+		name    string
+		slowEnv models.Environment
+	}{
+		{name: "slow production airline", slowEnv: models.EnvProduction},
+		{name: "slow staging airline", slowEnv: models.EnvStaging},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			email := ""
+			if tt.slowEnv == models.EnvProduction {
+				email = "partner@example.com"
+				s.whitelistUser(t, email, "ga")
+			}
+
+			search := searchWith(s, 4)
+			search.searchCache = s.cache
+			search.stagingTimeout = 50 * time.Millisecond
+			search.productionTimeout = 50 * time.Millisecond
+			if tt.slowEnv == models.EnvProduction {
+				search.productionRepo = slowScheduleRepository{ScheduleRepository: search.productionRepo, airlineID: "ga"}
+			} else {
+				search.stagingRepo = slowScheduleRepository{ScheduleRepository: search.stagingRepo, airlineID: "ga"}
+			}
+
+			req := SearchFlightRequest{Origin: "CGK", Destination: "DPS", DepartureDate: flightDate(7), Airlines: []string{"ga", "jt"}, PageSize: 100}
+			result, err := search.SearchContext(context.Background(), email, req)
+			if err != nil {
+				t.Fatalf("search: %v", err)
+			}
+
+			var sawGA, sawJT bool
+			for _, schedule := range result.Data.([]models.Schedule) {
+				sawGA = sawGA || schedule.AirlineID == "ga"
+				sawJT = sawJT || schedule.AirlineID == "jt"
+			}
+			if sawGA {
+				t.Error("the timed out airline's flights should be dropped")
+			}
+			if !sawJT {
+				t.Error("the other airline's flights should still be returned")
+			}
+			if entries := s.cache.Stats().Entries; entries != 0 {
+				t.Errorf("partial result was cached, %d entries", entries)
+			}
+		})
+	}
+}
+
+func TestDualScheduleServiceSearchCancelled(t *testing.T) {
+	s := newTestServices(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := SearchFlightRequest{Origin: "CGK", Destination: "DPS", DepartureDate: flightDate(7)}
+	if _, err := searchWith(s, 4).SearchContext(ctx, "", req); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func benchmarkSearch(b *testing.B, workers int) {
+	s := newTestServices(b)
+	s.whitelistUser(b, "bench@example.com", "ga", "jt", "qg")
+
+	// Searches only read, so let each worker have its own connection
+	for _, db := range []*gorm.DB{s.staging, s.production} {
+		sqlDB, err := db.DB()
+		if err != nil {
+			b.Fatal(err)
+		}
+		sqlDB.SetMaxOpenConns(workers)
+		sqlDB.SetMaxIdleConns(workers)
+	}
+
+	search := searchWith(s, workers)
+	req := SearchFlightRequest{Origin: "CGK", Destination: "DPS", DepartureDate: flightDate(7), CabinClass: "economy", Page: 1, PageSize: 10}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := search.SearchContext(context.Background(), "bench@example.com", req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSearchSequential queries the seeded airlines one after another, mixing staging and production
+func BenchmarkSearchSequential(b *testing.B) {
+	benchmarkSearch(b, 1)
+}
+
+// BenchmarkSearchConcurrent fans the same search out over SEARCH_WORKERS' default of 4
+func BenchmarkSearchConcurrent(b *testing.B) {
+	benchmarkSearch(b, 4)
+}
+
+// Each environment's ScheduleChanged carries its own copy of the schedule, none of them the one
+// returned to the caller
+func TestDualScheduleServiceEventsCarryCopies(t *testing.T) {
+	s := newTestServices(t)
+	var published []*models.Schedule
+	events.Subscribe(s.bus, "test", events.Sync, func(e events.ScheduleChanged) error {
+		if e.After != nil {
+			published = append(published, e.After)
+		}
+		return nil
+	})
+
+	created, err := s.search.Create(CreateScheduleRequest{
+		AirlineID: "ga", FlightNumber: "GA-999", DepartureAirportID: "cgk", DepartureTime: "10:00",
+		ArrivalAirportID: "dps", ArrivalTime: "12:30", Duration: 150, EconomyPrice: 900000,
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	updated, err := s.search.Update(created.ID, UpdateScheduleRequest{Aircraft: "Airbus A320"})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	created.FlightNumber = "changed by the caller"
+	updated.FlightNumber = "changed by the caller"
+
+	if len(published) != 4 {
+		t.Fatalf("%d schedule events, want one per environment for the create and the update", len(published))
+	}
+	for i, schedule := range published {
+		if schedule == created || schedule == updated || (i%2 == 1 && schedule == published[i-1]) {
+			t.Errorf("event %d shares its schedule", i)
+		}
+		if schedule.FlightNumber != "GA-999" {
+			t.Errorf("event %d reads flight number %q, want GA-999", i, schedule.FlightNumber)
+		}
+	}
+}
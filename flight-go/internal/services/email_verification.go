@@ -0,0 +1,121 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/mirahekatiket/flight-go/internal/config"
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid email verification token")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+)
+
+// emailVerificationTTL is how long the link in a verification email works
+const emailVerificationTTL = 24 * time.Hour
+
+// VerifyEmailRequest sends back the token from a verification email
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// VerifyEmailResponse is the verified user, with the guest orders their email moved to the account
+type VerifyEmailResponse struct {
+	User          *models.User `json:"user"`
+	ClaimedOrders int64        `json:"claimed_orders"`
+}
+
+// emailVerificationClaims prove the holder received mail at Email, for the account UserID
+type emailVerificationClaims struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// emailVerificationKey signs verification tokens. It is derived from the JWT secret but
+// differs from it, so a verification token is never accepted as a login token.
+func emailVerificationKey(cfg *config.Config) []byte {
+	mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+	mac.Write([]byte("email-verification"))
+	return mac.Sum(nil)
+}
+
+// RequestEmailVerification emails the user a new verification token
+func (s *authService) RequestEmailVerification(userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+	return s.sendVerification(user)
+}
+
+// sendVerification signs a token for user's email and publishes it to be mailed
+func (s *authService) sendVerification(user *models.User) error {
+	claims := &emailVerificationClaims{
+		UserID: user.ID,
+		Email:  user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(emailVerificationTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.verificationKey)
+	if err != nil {
+		return err
+	}
+
+	recipient := *user
+	recipient.Password = ""
+	s.events.Publish(events.EmailVerificationRequested{User: &recipient, Token: token})
+	return nil
+}
+
+// VerifyEmail marks the email a verification token was sent to as the user's own, and moves the
+// guest orders booked with it to their account. Only a verified email claims orders, registering
+// with someone else's address claims nothing. Verifying again claims guest orders booked since.
+func (s *authService) VerifyEmail(tokenString string) (*VerifyEmailResponse, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &emailVerificationClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return s.verificationKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	claims, ok := token.Claims.(*emailVerificationClaims)
+	if !ok || !token.Valid || claims.UserID == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.userRepo.FindByID(claims.UserID)
+	if err != nil || !strings.EqualFold(user.Email, claims.Email) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		if err := s.userRepo.VerifyEmail(user.ID, now); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	claimed, err := s.orderRepo.ClaimGuestOrders(user.ID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+	return &VerifyEmailResponse{User: user, ClaimedOrders: claimed}, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+)
+
+// verificationTokens collects the tokens mailed to users as they register or ask again
+func verificationTokens(s *testServices) *[]string {
+	var tokens []string
+	events.Subscribe(s.bus, "test", events.Sync, func(e events.EmailVerificationRequested) error {
+		tokens = append(tokens, e.Token)
+		return nil
+	})
+	return &tokens
+}
+
+// Verifying the email a user registered with moves the guest orders booked with it to their account
+func TestAuthVerifyEmailClaimsGuestOrders(t *testing.T) {
+	s := newTestServices(t)
+	mailed := verificationTokens(s)
+
+	guest, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRequest := bookingRequest("schedule-cgk-dps-ga-001", 7)
+	otherRequest.ContactEmail = "siti@example.com"
+	other, err := s.orders.Create("", "", otherRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := s.auth.Register(RegisterRequest{Email: "Budi@Example.com", Password: "secret123", Name: "Budi Santoso"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, _ := s.orders.GetByID(guest.ID); stored.UserID != "" {
+		t.Fatalf("registering claimed the order for %q before the email was verified", stored.UserID)
+	}
+	if len(*mailed) != 1 {
+		t.Fatalf("%d verification emails sent on registering, want 1", len(*mailed))
+	}
+
+	for _, token := range []string{"", "not-a-token", tokens.Token, tokens.RefreshToken} {
+		if _, err := s.auth.VerifyEmail(token); !errors.Is(err, ErrInvalidVerificationToken) {
+			t.Errorf("VerifyEmail(%.12q): err = %v, want ErrInvalidVerificationToken", token, err)
+		}
+	}
+
+	verified, err := s.auth.VerifyEmail((*mailed)[0])
+	if err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if verified.ClaimedOrders != 1 || verified.User.EmailVerifiedAt == nil || verified.User.Password != "" {
+		t.Errorf("verified %+v claiming %d, want the verified user claiming 1 order", verified.User, verified.ClaimedOrders)
+	}
+	if stored, _ := s.orders.GetByID(guest.ID); stored.UserID != tokens.User.ID {
+		t.Errorf("order booked with the email belongs to %q, want %q", stored.UserID, tokens.User.ID)
+	}
+	if stored, _ := s.orders.GetByID(other.ID); stored.UserID != "" {
+		t.Errorf("order booked with another email was claimed by %q", stored.UserID)
+	}
+
+	// Once verified there is nothing to resend, the link keeps working for orders booked since
+	if err := s.auth.RequestEmailVerification(tokens.User.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Errorf("RequestEmailVerification: err = %v, want ErrEmailAlreadyVerified", err)
+	}
+	later, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.auth.VerifyEmail((*mailed)[0])
+	if err != nil || again.ClaimedOrders != 1 {
+		t.Fatalf("verifying again: %+v, %v, want the order booked since claimed", again, err)
+	}
+	if stored, _ := s.orders.GetByID(later.ID); stored.UserID != tokens.User.ID {
+		t.Errorf("order booked since belongs to %q, want %q", stored.UserID, tokens.User.ID)
+	}
+}
+
+// A token only verifies the account and email it was sent for
+func TestAuthVerifyEmailRejects(t *testing.T) {
+	s := newTestServices(t)
+	mailed := verificationTokens(s)
+	tokens, err := s.auth.Register(RegisterRequest{Email: "budi@example.com", Password: "secret123", Name: "Budi Santoso"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.auth.RequestEmailVerification(tokens.User.ID); err != nil {
+		t.Fatalf("RequestEmailVerification: %v", err)
+	}
+	if len(*mailed) != 2 {
+		t.Fatalf("%d verification emails sent, want 2", len(*mailed))
+	}
+	if err := s.auth.RequestEmailVerification("no-such-user"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("RequestEmailVerification of an unknown user: err = %v, want ErrUserNotFound", err)
+	}
+
+	// Signed with the login key rather than the verification key
+	forged := NewAuthService(s.userRepo, s.orderRepo, s.sessionRepo, s.bus, s.cfg).(*authService)
+	forged.verificationKey = []byte(s.cfg.JWTSecret)
+	if err := forged.sendVerification(tokens.User); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.auth.VerifyEmail((*mailed)[2]); !errors.Is(err, ErrInvalidVerificationToken) {
+		t.Errorf("token signed with the login key: err = %v, want ErrInvalidVerificationToken", err)
+	}
+
+	users := NewUserService(s.userRepo, s.uow, s.auth)
+	if _, err := users.Disable(tokens.User.ID, "admin-1", UserStatusRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.auth.VerifyEmail((*mailed)[1]); !errors.Is(err, ErrUserDisabled) {
+		t.Errorf("disabled user: err = %v, want ErrUserDisabled", err)
+	}
+	if user, _ := s.userRepo.FindByID(tokens.User.ID); user.EmailVerifiedAt != nil {
+		t.Error("a disabled user's email was verified")
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrExchangeRateNotFound = errors.New("exchange rate not found")
+	ErrInvalidExchangeRate  = errors.New("invalid exchange rate")
+)
+
+type ExchangeRateService interface {
+	Create(req CreateExchangeRateRequest) (*models.ExchangeRate, error)
+	GetByID(id string) (*models.ExchangeRate, error)
+	Delete(id string) error
+	List(fromCurrency, toCurrency string, page, pageSize int) (*PaginatedResponse, error)
+}
+
+type CreateExchangeRateRequest struct {
+	FromCurrency  string  `json:"from_currency" binding:"required"` // e.g., USD
+	ToCurrency    string  `json:"to_currency" binding:"required"`   // e.g., IDR
+	Rate          float64 `json:"rate" binding:"required"`          // 1 from_currency = rate to_currency
+	EffectiveFrom string  `json:"effective_from"`                   // RFC3339 or YYYY-MM-DD, defaults to now
+}
+
+type exchangeRateService struct {
+	exchangeRateRepo repository.ExchangeRateRepository
+}
+
+func NewExchangeRateService(exchangeRateRepo repository.ExchangeRateRepository) ExchangeRateService {
+	return &exchangeRateService{exchangeRateRepo: exchangeRateRepo}
+}
+
+func (s *exchangeRateService) Create(req CreateExchangeRateRequest) (*models.ExchangeRate, error) {
+	rate := &models.ExchangeRate{
+		FromCurrency:  strings.ToUpper(req.FromCurrency),
+		ToCurrency:    strings.ToUpper(req.ToCurrency),
+		Rate:          req.Rate,
+		EffectiveFrom: time.Now().UTC(),
+	}
+
+	if req.EffectiveFrom != "" {
+		effectiveFrom, err := time.Parse(time.RFC3339, req.EffectiveFrom)
+		if err != nil {
+			if effectiveFrom, err = time.Parse("2006-01-02", req.EffectiveFrom); err != nil {
+				return nil, ErrInvalidExchangeRate
+			}
+		}
+		rate.EffectiveFrom = effectiveFrom.UTC()
+	}
+
+	if !models.IsSupportedCurrency(rate.FromCurrency) || !models.IsSupportedCurrency(rate.ToCurrency) {
+		return nil, ErrUnsupportedCurrency
+	}
+	if rate.FromCurrency == rate.ToCurrency || rate.Rate <= 0 {
+		return nil, ErrInvalidExchangeRate
+	}
+
+	if err := s.exchangeRateRepo.Create(rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *exchangeRateService) GetByID(id string) (*models.ExchangeRate, error) {
+	rate, err := s.exchangeRateRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrExchangeRateNotFound
+	}
+	return rate, nil
+}
+
+func (s *exchangeRateService) Delete(id string) error {
+	_, err := s.exchangeRateRepo.FindByID(id)
+	if err != nil {
+		return ErrExchangeRateNotFound
+	}
+
+	return s.exchangeRateRepo.Delete(id)
+}
+
+func (s *exchangeRateService) List(fromCurrency, toCurrency string, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	rates, total, err := s.exchangeRateRepo.List(strings.ToUpper(fromCurrency), strings.ToUpper(toCurrency), page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       rates,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
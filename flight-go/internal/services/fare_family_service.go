@@ -0,0 +1,231 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrFareFamilyNotFound = errors.New("fare family not found")
+	ErrInvalidFareFamily  = errors.New("invalid fare family")
+	ErrFareFamilySoldOut  = errors.New("fare family sold out")
+	ErrInsufficientSeats  = errors.New("not enough seats left in cabin")
+)
+
+type FareFamilyService interface {
+	Create(req CreateFareFamilyRequest) (*models.FareFamily, error)
+	GetByID(id string) (*models.FareFamily, error)
+	Update(id string, req UpdateFareFamilyRequest) (*models.FareFamily, error)
+	Delete(id string) error
+	List(airlineID string, page, pageSize int) (*PaginatedResponse, error)
+}
+
+type CreateFareFamilyRequest struct {
+	AirlineID        string   `json:"airline_id" binding:"required"`
+	CabinClass       string   `json:"cabin_class" binding:"required"` // economy, business, first
+	Code             string   `json:"code" binding:"required"`        // e.g., SAVER, FLEX
+	Name             string   `json:"name" binding:"required"`
+	PriceMultiplier  float64  `json:"price_multiplier"` // Defaults to 1
+	BaggageKg        int      `json:"baggage_kg"`
+	CabinBaggageKg   *int     `json:"cabin_baggage_kg"`
+	Changeable       bool     `json:"changeable"`
+	ChangeFee        float64  `json:"change_fee"`
+	Refundable       bool     `json:"refundable"`
+	RefundFeePercent float64  `json:"refund_fee_percent"`
+	SeatBucket       int      `json:"seat_bucket"`
+	ChildPercent     *float64 `json:"child_percent"`  // Defaults to the airline's passenger rules
+	InfantPercent    *float64 `json:"infant_percent"` // Defaults to the airline's passenger rules
+	SortOrder        int      `json:"sort_order"`
+	IsActive         *bool    `json:"is_active"`
+}
+
+type UpdateFareFamilyRequest struct {
+	Name             string   `json:"name"`
+	PriceMultiplier  *float64 `json:"price_multiplier"`
+	BaggageKg        *int     `json:"baggage_kg"`
+	CabinBaggageKg   *int     `json:"cabin_baggage_kg"`
+	Changeable       *bool    `json:"changeable"`
+	ChangeFee        *float64 `json:"change_fee"`
+	Refundable       *bool    `json:"refundable"`
+	RefundFeePercent *float64 `json:"refund_fee_percent"`
+	SeatBucket       *int     `json:"seat_bucket"`
+	ChildPercent     *float64 `json:"child_percent"`
+	InfantPercent    *float64 `json:"infant_percent"`
+	SortOrder        *int     `json:"sort_order"`
+	IsActive         *bool    `json:"is_active"`
+}
+
+type fareFamilyService struct {
+	fareFamilyRepo repository.FareFamilyRepository
+}
+
+func NewFareFamilyService(fareFamilyRepo repository.FareFamilyRepository) FareFamilyService {
+	return &fareFamilyService{fareFamilyRepo: fareFamilyRepo}
+}
+
+func (s *fareFamilyService) Create(req CreateFareFamilyRequest) (*models.FareFamily, error) {
+	family := &models.FareFamily{
+		AirlineID:        req.AirlineID,
+		CabinClass:       models.CabinClass(req.CabinClass),
+		Code:             req.Code,
+		Name:             req.Name,
+		PriceMultiplier:  req.PriceMultiplier,
+		BaggageKg:        req.BaggageKg,
+		CabinBaggageKg:   7,
+		Changeable:       req.Changeable,
+		ChangeFee:        req.ChangeFee,
+		Refundable:       req.Refundable,
+		RefundFeePercent: req.RefundFeePercent,
+		SeatBucket:       req.SeatBucket,
+		ChildPercent:     req.ChildPercent,
+		InfantPercent:    req.InfantPercent,
+		SortOrder:        req.SortOrder,
+		IsActive:         true,
+	}
+
+	if family.PriceMultiplier == 0 {
+		family.PriceMultiplier = 1
+	}
+	if req.CabinBaggageKg != nil {
+		family.CabinBaggageKg = *req.CabinBaggageKg
+	}
+	if req.IsActive != nil {
+		family.IsActive = *req.IsActive
+	}
+
+	if err := validateFareFamily(family); err != nil {
+		return nil, err
+	}
+
+	if err := s.fareFamilyRepo.Create(family); err != nil {
+		return nil, err
+	}
+
+	return family, nil
+}
+
+func (s *fareFamilyService) GetByID(id string) (*models.FareFamily, error) {
+	family, err := s.fareFamilyRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFareFamilyNotFound
+	}
+	return family, nil
+}
+
+func (s *fareFamilyService) Update(id string, req UpdateFareFamilyRequest) (*models.FareFamily, error) {
+	family, err := s.fareFamilyRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFareFamilyNotFound
+	}
+
+	if req.Name != "" {
+		family.Name = req.Name
+	}
+	if req.PriceMultiplier != nil {
+		family.PriceMultiplier = *req.PriceMultiplier
+	}
+	if req.BaggageKg != nil {
+		family.BaggageKg = *req.BaggageKg
+	}
+	if req.CabinBaggageKg != nil {
+		family.CabinBaggageKg = *req.CabinBaggageKg
+	}
+	if req.Changeable != nil {
+		family.Changeable = *req.Changeable
+	}
+	if req.ChangeFee != nil {
+		family.ChangeFee = *req.ChangeFee
+	}
+	if req.Refundable != nil {
+		family.Refundable = *req.Refundable
+	}
+	if req.RefundFeePercent != nil {
+		family.RefundFeePercent = *req.RefundFeePercent
+	}
+	if req.SeatBucket != nil {
+		family.SeatBucket = *req.SeatBucket
+	}
+	if req.ChildPercent != nil {
+		family.ChildPercent = req.ChildPercent
+	}
+	if req.InfantPercent != nil {
+		family.InfantPercent = req.InfantPercent
+	}
+	if req.SortOrder != nil {
+		family.SortOrder = *req.SortOrder
+	}
+	if req.IsActive != nil {
+		family.IsActive = *req.IsActive
+	}
+
+	if err := validateFareFamily(family); err != nil {
+		return nil, err
+	}
+
+	if err := s.fareFamilyRepo.Update(family); err != nil {
+		return nil, err
+	}
+
+	return family, nil
+}
+
+func (s *fareFamilyService) Delete(id string) error {
+	_, err := s.fareFamilyRepo.FindByID(id)
+	if err != nil {
+		return ErrFareFamilyNotFound
+	}
+
+	return s.fareFamilyRepo.Delete(id)
+}
+
+func (s *fareFamilyService) List(airlineID string, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	families, total, err := s.fareFamilyRepo.List(airlineID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       families,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func validateFareFamily(family *models.FareFamily) error {
+	switch family.CabinClass {
+	case models.CabinEconomy, models.CabinBusiness, models.CabinFirst:
+	default:
+		return ErrInvalidFareFamily
+	}
+
+	if family.PriceMultiplier <= 0 || family.SeatBucket < 0 || family.ChangeFee < 0 {
+		return ErrInvalidFareFamily
+	}
+	if family.RefundFeePercent < 0 || family.RefundFeePercent > 100 {
+		return ErrInvalidFareFamily
+	}
+	if family.ChildPercent != nil && (*family.ChildPercent < 0 || *family.ChildPercent > 100) {
+		return ErrInvalidFareFamily
+	}
+	if family.InfantPercent != nil && (*family.InfantPercent < 0 || *family.InfantPercent > 100) {
+		return ErrInvalidFareFamily
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+func TestValidateFareFamily(t *testing.T) {
+	percent := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name    string
+		family  models.FareFamily
+		wantErr bool
+	}{
+		{name: "valid", family: models.FareFamily{CabinClass: models.CabinEconomy, PriceMultiplier: 1.2, RefundFeePercent: 10, ChildPercent: percent(50)}},
+		{name: "unknown cabin", family: models.FareFamily{CabinClass: "premium", PriceMultiplier: 1}, wantErr: true},
+		{name: "zero multiplier", family: models.FareFamily{CabinClass: models.CabinEconomy}, wantErr: true},
+		{name: "negative seat bucket", family: models.FareFamily{CabinClass: models.CabinEconomy, PriceMultiplier: 1, SeatBucket: -1}, wantErr: true},
+		{name: "refund fee over 100%", family: models.FareFamily{CabinClass: models.CabinEconomy, PriceMultiplier: 1, RefundFeePercent: 101}, wantErr: true},
+		{name: "child percent over 100%", family: models.FareFamily{CabinClass: models.CabinEconomy, PriceMultiplier: 1, ChildPercent: percent(120)}, wantErr: true},
+		{name: "negative infant percent", family: models.FareFamily{CabinClass: models.CabinEconomy, PriceMultiplier: 1, InfantPercent: percent(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateFareFamily(&tt.family)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateFareFamily() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// garudaFamilies adds a cheaper Saver with a small seat bucket and a Flex family to GA economy in staging
+func garudaFamilies(t *testing.T, s *testServices) (saver, flex *models.FareFamily) {
+	t.Helper()
+	childPercent := 50.0
+	families := NewFareFamilyService(repository.NewFareFamilyRepository(s.staging))
+
+	saver, err := families.Create(CreateFareFamilyRequest{AirlineID: "ga", CabinClass: "economy", Code: "SAVER", Name: "Economy Saver", SeatBucket: 2, SortOrder: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	flex, err = families.Create(CreateFareFamilyRequest{AirlineID: "ga", CabinClass: "economy", Code: "FLEX", Name: "Economy Flex", PriceMultiplier: 1.3, ChildPercent: &childPercent, SortOrder: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return saver, flex
+}
+
+func TestPricingServiceQuoteFareFamily(t *testing.T) {
+	s := newTestServices(t)
+	saver, flex := garudaFamilies(t, s)
+	inactive := false
+	retired, err := NewFareFamilyService(repository.NewFareFamilyRepository(s.staging)).Create(CreateFareFamilyRequest{AirlineID: "ga", CabinClass: "economy", Code: "OLD", Name: "Retired", IsActive: &inactive})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedule, env, err := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date, _ := time.Parse("2006-01-02", flightDate(7))
+
+	tests := []struct {
+		name       string
+		cabin      models.CabinClass
+		familyID   string
+		wantFamily string
+		wantFare   float64
+		wantErr    error
+	}{
+		{name: "defaults to the first family", cabin: models.CabinEconomy, wantFamily: saver.ID, wantFare: 800000},
+		{name: "picked family steps up the fare", cabin: models.CabinEconomy, familyID: flex.ID, wantFamily: flex.ID, wantFare: 1040000},
+		{name: "inactive family", cabin: models.CabinEconomy, familyID: retired.ID, wantErr: ErrFareFamilyNotFound},
+		{name: "unknown family", cabin: models.CabinEconomy, familyID: "missing", wantErr: ErrFareFamilyNotFound},
+		{name: "cabin without families quotes the cabin fare", cabin: models.CabinBusiness, wantFare: 2400000},
+		{name: "family asked for in a cabin without families", cabin: models.CabinBusiness, familyID: flex.ID, wantErr: ErrFareFamilyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quote, family, err := s.pricing.QuoteFareFamily(env, schedule, tt.cabin, date, "", tt.familyID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if quote.Fare != tt.wantFare {
+				t.Errorf("fare = %v, want %v", quote.Fare, tt.wantFare)
+			}
+			var gotFamily string
+			if family != nil {
+				gotFamily = family.ID
+				if quote.FareFamily == nil || quote.FareFamily.ID != family.ID {
+					t.Errorf("quote fare family = %+v, want %s", quote.FareFamily, family.ID)
+				}
+			}
+			if gotFamily != tt.wantFamily {
+				t.Errorf("family = %q, want %q", gotFamily, tt.wantFamily)
+			}
+		})
+	}
+}
+
+func TestOrderCreateFareFamilySeatBucket(t *testing.T) {
+	s := newTestServices(t)
+	saver, flex := garudaFamilies(t, s)
+
+	book := func(familyID string, passengers ...PassengerRequest) (*models.Order, error) {
+		req := bookingRequest("schedule-cgk-dps-ga-001", 7, passengers...)
+		req.FareFamilyID = familyID
+		return s.orders.Create("", "", req)
+	}
+
+	tests := []struct {
+		name       string
+		familyID   string
+		passengers []PassengerRequest
+		wantErr    error
+	}{
+		{name: "fills the saver bucket", familyID: saver.ID, passengers: []PassengerRequest{adult("One"), adult("Two")}},
+		{name: "saver is sold out", familyID: saver.ID, passengers: []PassengerRequest{adult("Three")}, wantErr: ErrFareFamilySoldOut},
+		{name: "flex still sells", familyID: flex.ID, passengers: []PassengerRequest{adult("Three")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := book(tt.familyID, tt.passengers...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && order.FareFamilyID != tt.familyID {
+				t.Errorf("fare family = %s, want %s", order.FareFamilyID, tt.familyID)
+			}
+		})
+	}
+
+	schedule, env, _ := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+	date, _ := time.Parse("2006-01-02", flightDate(7))
+	quote, _ := s.pricing.Quote(env, schedule, models.CabinEconomy, date, "")
+	offers, err := s.pricing.FareFamilyOffers(env, schedule, models.CabinEconomy, date, quote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seatsLeft := map[string]int{}
+	for _, offer := range offers {
+		seatsLeft[offer.Code] = offer.SeatsLeft
+	}
+	if seatsLeft["SAVER"] != 0 || seatsLeft["FLEX"] != 147 {
+		t.Errorf("seats left = %v, want SAVER 0 and FLEX 147", seatsLeft)
+	}
+}
+
+func TestOrderCreateFareFamilyChildPercent(t *testing.T) {
+	s := newTestServices(t)
+	saver, flex := garudaFamilies(t, s)
+
+	tests := []struct {
+		name      string
+		familyID  string
+		wantChild int64 // minor units
+	}{
+		{name: "airline default of 75%", familyID: saver.ID, wantChild: 60000000},
+		{name: "family override of 50% of the flex fare", familyID: flex.ID, wantChild: 52000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Parent"), child("Kid", 6))
+			req.FareFamilyID = tt.familyID
+			order, err := s.orders.Create("", "", req)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			line, ok := fareLine(order.PriceBreakdown, models.PassengerChild)
+			if !ok {
+				t.Fatal("no child fare line")
+			}
+			if line.UnitAmount != tt.wantChild {
+				t.Errorf("child fare = %d, want %d", line.UnitAmount, tt.wantChild)
+			}
+		})
+	}
+}
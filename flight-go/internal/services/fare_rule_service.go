@@ -0,0 +1,232 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrFareRuleNotFound = errors.New("fare rule not found")
+	ErrInvalidFareRule  = errors.New("invalid fare rule")
+)
+
+type FareRuleService interface {
+	Create(req CreateFareRuleRequest) (*models.FareRule, error)
+	GetByID(id string) (*models.FareRule, error)
+	Update(id string, req UpdateFareRuleRequest) (*models.FareRule, error)
+	Delete(id string) error
+	List(page, pageSize int) (*PaginatedResponse, error)
+}
+
+type CreateFareRuleRequest struct {
+	Name              string  `json:"name" binding:"required"`
+	Type              string  `json:"type" binding:"required"` // load_factor, days_before, day_of_week, time_of_day
+	AirlineID         string  `json:"airline_id"`
+	CabinClass        string  `json:"cabin_class"`
+	MinValue          float64 `json:"min_value"`
+	MaxValue          float64 `json:"max_value"`
+	DaysOfWeek        string  `json:"days_of_week"` // e.g., "5,6,7" for Fri-Sun
+	TimeFrom          string  `json:"time_from"`    // HH:MM format
+	TimeTo            string  `json:"time_to"`      // HH:MM format
+	AdjustmentPercent float64 `json:"adjustment_percent" binding:"required"`
+	Priority          int     `json:"priority"`
+	IsActive          *bool   `json:"is_active"`
+}
+
+type UpdateFareRuleRequest struct {
+	Name              string   `json:"name"`
+	Type              string   `json:"type"`
+	AirlineID         *string  `json:"airline_id"`
+	CabinClass        *string  `json:"cabin_class"`
+	MinValue          *float64 `json:"min_value"`
+	MaxValue          *float64 `json:"max_value"`
+	DaysOfWeek        *string  `json:"days_of_week"`
+	TimeFrom          *string  `json:"time_from"`
+	TimeTo            *string  `json:"time_to"`
+	AdjustmentPercent *float64 `json:"adjustment_percent"`
+	Priority          *int     `json:"priority"`
+	IsActive          *bool    `json:"is_active"`
+}
+
+type fareRuleService struct {
+	fareRuleRepo repository.FareRuleRepository
+}
+
+func NewFareRuleService(fareRuleRepo repository.FareRuleRepository) FareRuleService {
+	return &fareRuleService{fareRuleRepo: fareRuleRepo}
+}
+
+func (s *fareRuleService) Create(req CreateFareRuleRequest) (*models.FareRule, error) {
+	rule := &models.FareRule{
+		Name:              req.Name,
+		Type:              models.FareRuleType(req.Type),
+		AirlineID:         req.AirlineID,
+		CabinClass:        models.CabinClass(req.CabinClass),
+		MinValue:          req.MinValue,
+		MaxValue:          req.MaxValue,
+		DaysOfWeek:        req.DaysOfWeek,
+		TimeFrom:          req.TimeFrom,
+		TimeTo:            req.TimeTo,
+		AdjustmentPercent: req.AdjustmentPercent,
+		Priority:          req.Priority,
+		IsActive:          true,
+	}
+
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validateFareRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.fareRuleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *fareRuleService) GetByID(id string) (*models.FareRule, error) {
+	rule, err := s.fareRuleRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFareRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *fareRuleService) Update(id string, req UpdateFareRuleRequest) (*models.FareRule, error) {
+	rule, err := s.fareRuleRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFareRuleNotFound
+	}
+
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.Type != "" {
+		rule.Type = models.FareRuleType(req.Type)
+	}
+	if req.AirlineID != nil {
+		rule.AirlineID = *req.AirlineID
+	}
+	if req.CabinClass != nil {
+		rule.CabinClass = models.CabinClass(*req.CabinClass)
+	}
+	if req.MinValue != nil {
+		rule.MinValue = *req.MinValue
+	}
+	if req.MaxValue != nil {
+		rule.MaxValue = *req.MaxValue
+	}
+	if req.DaysOfWeek != nil {
+		rule.DaysOfWeek = *req.DaysOfWeek
+	}
+	if req.TimeFrom != nil {
+		rule.TimeFrom = *req.TimeFrom
+	}
+	if req.TimeTo != nil {
+		rule.TimeTo = *req.TimeTo
+	}
+	if req.AdjustmentPercent != nil {
+		rule.AdjustmentPercent = *req.AdjustmentPercent
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validateFareRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.fareRuleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *fareRuleService) Delete(id string) error {
+	_, err := s.fareRuleRepo.FindByID(id)
+	if err != nil {
+		return ErrFareRuleNotFound
+	}
+
+	return s.fareRuleRepo.Delete(id)
+}
+
+func (s *fareRuleService) List(page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	rules, total, err := s.fareRuleRepo.List(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       rules,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// validateFareRule checks the condition fields required by the rule type
+func validateFareRule(rule *models.FareRule) error {
+	switch rule.CabinClass {
+	case "", models.CabinEconomy, models.CabinBusiness, models.CabinFirst:
+	default:
+		return ErrInvalidFareRule
+	}
+
+	if rule.AdjustmentPercent <= -100 {
+		return ErrInvalidFareRule
+	}
+
+	switch rule.Type {
+	case models.FareRuleLoadFactor, models.FareRuleDaysBefore:
+		if rule.MinValue < 0 || rule.MaxValue < rule.MinValue {
+			return ErrInvalidFareRule
+		}
+	case models.FareRuleDayOfWeek:
+		if rule.DaysOfWeek == "" {
+			return ErrInvalidFareRule
+		}
+		for _, day := range strings.Split(rule.DaysOfWeek, ",") {
+			d, err := strconv.Atoi(strings.TrimSpace(day))
+			if err != nil || d < 1 || d > 7 {
+				return ErrInvalidFareRule
+			}
+		}
+	case models.FareRuleTimeOfDay:
+		if _, err := time.Parse("15:04", rule.TimeFrom); err != nil {
+			return ErrInvalidFareRule
+		}
+		if _, err := time.Parse("15:04", rule.TimeTo); err != nil {
+			return ErrInvalidFareRule
+		}
+	default:
+		return ErrInvalidFareRule
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrFeeRuleNotFound = errors.New("fee rule not found")
+	ErrInvalidFeeRule  = errors.New("invalid fee rule")
+)
+
+type FeeRuleService interface {
+	Create(req CreateFeeRuleRequest) (*models.FeeRule, error)
+	GetByID(id string) (*models.FeeRule, error)
+	Update(id string, req UpdateFeeRuleRequest) (*models.FeeRule, error)
+	Delete(id string) error
+	List(page, pageSize int) (*PaginatedResponse, error)
+}
+
+type CreateFeeRuleRequest struct {
+	Name       string  `json:"name" binding:"required"`
+	Type       string  `json:"type" binding:"required"` // airport_tax, fuel_surcharge, service_fee, vat
+	AirportID  string  `json:"airport_id"`
+	AirlineID  string  `json:"airline_id"`
+	CabinClass string  `json:"cabin_class"`
+	Amount     float64 `json:"amount"`   // Required for fixed fees
+	Currency   string  `json:"currency"` // Currency of amount, defaults to IDR
+	Percent    float64 `json:"percent"`  // Required for vat
+	IsActive   *bool   `json:"is_active"`
+}
+
+type UpdateFeeRuleRequest struct {
+	Name       string   `json:"name"`
+	AirportID  *string  `json:"airport_id"`
+	AirlineID  *string  `json:"airline_id"`
+	CabinClass *string  `json:"cabin_class"`
+	Amount     *float64 `json:"amount"`
+	Currency   string   `json:"currency"`
+	Percent    *float64 `json:"percent"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+type feeRuleService struct {
+	feeRuleRepo repository.FeeRuleRepository
+}
+
+func NewFeeRuleService(feeRuleRepo repository.FeeRuleRepository) FeeRuleService {
+	return &feeRuleService{feeRuleRepo: feeRuleRepo}
+}
+
+func (s *feeRuleService) Create(req CreateFeeRuleRequest) (*models.FeeRule, error) {
+	rule := &models.FeeRule{
+		Name:       req.Name,
+		Type:       models.ChargeType(req.Type),
+		AirportID:  req.AirportID,
+		AirlineID:  req.AirlineID,
+		CabinClass: models.CabinClass(req.CabinClass),
+		Amount:     req.Amount,
+		Currency:   models.DefaultCurrency,
+		Percent:    req.Percent,
+		IsActive:   true,
+	}
+
+	if req.Currency != "" {
+		rule.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validateFeeRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRuleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *feeRuleService) GetByID(id string) (*models.FeeRule, error) {
+	rule, err := s.feeRuleRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFeeRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *feeRuleService) Update(id string, req UpdateFeeRuleRequest) (*models.FeeRule, error) {
+	rule, err := s.feeRuleRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrFeeRuleNotFound
+	}
+
+	if req.Name != "" {
+		rule.Name = req.Name
+	}
+	if req.AirportID != nil {
+		rule.AirportID = *req.AirportID
+	}
+	if req.AirlineID != nil {
+		rule.AirlineID = *req.AirlineID
+	}
+	if req.CabinClass != nil {
+		rule.CabinClass = models.CabinClass(*req.CabinClass)
+	}
+	if req.Amount != nil {
+		rule.Amount = *req.Amount
+	}
+	if req.Currency != "" {
+		rule.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.Percent != nil {
+		rule.Percent = *req.Percent
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := validateFeeRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.feeRuleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *feeRuleService) Delete(id string) error {
+	_, err := s.feeRuleRepo.FindByID(id)
+	if err != nil {
+		return ErrFeeRuleNotFound
+	}
+
+	return s.feeRuleRepo.Delete(id)
+}
+
+func (s *feeRuleService) List(page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	rules, total, err := s.feeRuleRepo.List(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       rules,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// validateFeeRule checks the amount fields required by the fee type
+func validateFeeRule(rule *models.FeeRule) error {
+	switch rule.CabinClass {
+	case "", models.CabinEconomy, models.CabinBusiness, models.CabinFirst:
+	default:
+		return ErrInvalidFeeRule
+	}
+
+	if !models.IsSupportedCurrency(rule.Currency) {
+		return ErrInvalidFeeRule
+	}
+
+	switch rule.Type {
+	case models.ChargeAirportTax, models.ChargeFuelSurcharge, models.ChargeServiceFee:
+		if rule.Amount <= 0 {
+			return ErrInvalidFeeRule
+		}
+	case models.ChargeVAT:
+		if rule.Percent <= 0 || rule.Percent > 100 {
+			return ErrInvalidFeeRule
+		}
+	default:
+		return ErrInvalidFeeRule
+	}
+
+	return nil
+}
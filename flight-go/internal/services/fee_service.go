@@ -0,0 +1,219 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// PassengerCounts is the party a price breakdown is computed for
+type PassengerCounts struct {
+	Adults   int
+	Children int
+	Infants  int
+}
+
+// Seated returns the passengers that occupy a seat (infants sit on laps)
+func (c PassengerCounts) Seated() int {
+	return c.Adults + c.Children
+}
+
+// countPassengers tallies passenger requests by type
+func countPassengers(passengers []PassengerRequest) PassengerCounts {
+	var counts PassengerCounts
+	for _, p := range passengers {
+		switch models.PassengerType(p.Type) {
+		case models.PassengerAdult:
+			counts.Adults++
+		case models.PassengerChild:
+			counts.Children++
+		case models.PassengerInfant:
+			counts.Infants++
+		}
+	}
+	return counts
+}
+
+// FeeService itemises a fare quote into passenger fares plus the taxes, fees and
+// surcharges of the environment's fee rules.
+// Orders persist the breakdown and search shows a one-adult preview of it.
+type FeeService struct {
+	stagingFeeRuleRepo    repository.FeeRuleRepository
+	productionFeeRuleRepo repository.FeeRuleRepository
+	currencyService       *CurrencyService
+}
+
+func NewFeeService(
+	stagingFeeRuleRepo repository.FeeRuleRepository,
+	productionFeeRuleRepo repository.FeeRuleRepository,
+	currencyService *CurrencyService,
+) *FeeService {
+	return &FeeService{
+		stagingFeeRuleRepo:    stagingFeeRuleRepo,
+		productionFeeRuleRepo: productionFeeRuleRepo,
+		currencyService:       currencyService,
+	}
+}
+
+// Breakdown prices a party on schedule from its fare quote
+// rules sets the child and infant share of the adult fare.
+func (s *FeeService) Breakdown(env models.Environment, schedule *models.Schedule, quote *models.FareQuote, counts PassengerCounts, rules models.PassengerRules) (*models.PriceBreakdown, error) {
+	feeRules, err := s.rulesFor(env)
+	if err != nil {
+		return nil, err
+	}
+	return s.breakdown(feeRules, schedule, quote, counts, rules)
+}
+
+// PreviewSchedules sets PricePreview on every quoted schedule to the breakdown for one adult
+func (s *FeeService) PreviewSchedules(schedules []models.Schedule, envFor func(*models.Schedule) models.Environment) error {
+	rulesByEnv := make(map[models.Environment][]models.FeeRule)
+	for i := range schedules {
+		if schedules[i].Fare == nil {
+			continue
+		}
+
+		env := envFor(&schedules[i])
+		feeRules, ok := rulesByEnv[env]
+		if !ok {
+			var err error
+			if feeRules, err = s.rulesFor(env); err != nil {
+				return err
+			}
+			rulesByEnv[env] = feeRules
+		}
+
+		preview, err := s.breakdown(feeRules, &schedules[i], schedules[i].Fare, PassengerCounts{Adults: 1}, schedules[i].Airline.RulesOrDefault())
+		if err != nil {
+			return err
+		}
+		schedules[i].PricePreview = preview
+	}
+	return nil
+}
+
+// ChangeFee prices the fare family's change fee for passengers, in currency.
+// Cabins without fare families change for free and get no line item.
+func (s *FeeService) ChangeFee(schedule *models.Schedule, family *models.FareFamily, passengers int, currency string) (*models.PriceLineItem, error) {
+	if family == nil || family.ChangeFee <= 0 || passengers == 0 {
+		return nil, nil
+	}
+
+	// Fare family amounts are in the currency of the airline's prices
+	rate, err := s.currencyService.Rate(schedule.PriceCurrency(), currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	unit := models.ToMinorUnits(family.ChangeFee*rate, currency)
+	return &models.PriceLineItem{
+		Type:       models.ChargeChangeFee,
+		Name:       family.Name + " change fee",
+		Quantity:   passengers,
+		UnitAmount: unit,
+		Amount:     unit * int64(passengers),
+	}, nil
+}
+
+func (s *FeeService) rulesFor(env models.Environment) ([]models.FeeRule, error) {
+	if env == models.EnvProduction {
+		return s.productionFeeRuleRepo.ListActive()
+	}
+	return s.stagingFeeRuleRepo.ListActive()
+}
+
+func (s *FeeService) breakdown(feeRules []models.FeeRule, schedule *models.Schedule, quote *models.FareQuote, counts PassengerCounts, rules models.PassengerRules) (*models.PriceBreakdown, error) {
+	currency := quote.Currency
+	breakdown := &models.PriceBreakdown{Currency: currency, Items: []models.PriceLineItem{}}
+
+	// Passenger fares, children and infants pay their share of the adult fare
+	adultFare := models.ToMinorUnits(quote.Fare, currency)
+	fares := []struct {
+		passengerType models.PassengerType
+		count         int
+		unit          int64
+	}{
+		{models.PassengerAdult, counts.Adults, adultFare},
+		{models.PassengerChild, counts.Children, int64(math.Round(float64(adultFare) * rules.ChildPercent / 100))},
+		{models.PassengerInfant, counts.Infants, int64(math.Round(float64(adultFare) * rules.InfantPercent / 100))},
+	}
+	var vatBase int64
+	for _, fare := range fares {
+		if fare.count == 0 {
+			continue
+		}
+		item := models.PriceLineItem{
+			Type:          models.ChargeBaseFare,
+			Name:          "Base fare (" + string(fare.passengerType) + ")",
+			PassengerType: fare.passengerType,
+			Quantity:      fare.count,
+			UnitAmount:    fare.unit,
+			Amount:        fare.unit * int64(fare.count),
+		}
+		breakdown.Add(item)
+		vatBase += item.Amount
+	}
+
+	// Fixed fees, converted from the rule's currency at today's rate
+	var vatRules []models.FeeRule
+	for _, rule := range feeRules {
+		if rule.AirportID != "" && rule.AirportID != schedule.DepartureAirportID {
+			continue
+		}
+		if rule.AirlineID != "" && rule.AirlineID != schedule.AirlineID {
+			continue
+		}
+		if rule.CabinClass != "" && rule.CabinClass != quote.CabinClass {
+			continue
+		}
+
+		var quantity int
+		switch rule.Type {
+		case models.ChargeAirportTax, models.ChargeFuelSurcharge:
+			quantity = counts.Seated()
+		case models.ChargeServiceFee:
+			quantity = 1
+		case models.ChargeVAT:
+			vatRules = append(vatRules, rule)
+			continue
+		}
+		if quantity == 0 {
+			continue
+		}
+
+		rate, err := s.currencyService.Rate(rule.Currency, currency, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		unit := models.ToMinorUnits(rule.Amount*rate, currency)
+		item := models.PriceLineItem{
+			Type:       rule.Type,
+			Name:       rule.Name,
+			FeeRuleID:  rule.ID,
+			Quantity:   quantity,
+			UnitAmount: unit,
+			Amount:     unit * int64(quantity),
+		}
+		breakdown.Add(item)
+
+		// Airport taxes are levied by the airport and carry no VAT
+		if rule.Type != models.ChargeAirportTax {
+			vatBase += item.Amount
+		}
+	}
+
+	for _, rule := range vatRules {
+		amount := int64(math.Round(float64(vatBase) * rule.Percent / 100))
+		breakdown.Add(models.PriceLineItem{
+			Type:       models.ChargeVAT,
+			Name:       rule.Name,
+			FeeRuleID:  rule.ID,
+			Quantity:   1,
+			UnitAmount: amount,
+			Amount:     amount,
+		})
+	}
+
+	return breakdown, nil
+}
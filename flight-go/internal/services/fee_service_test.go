@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+func TestValidateFeeRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    models.FeeRule
+		wantErr bool
+	}{
+		{name: "airport tax", rule: models.FeeRule{Type: models.ChargeAirportTax, Amount: 50000, Currency: "IDR"}},
+		{name: "vat", rule: models.FeeRule{Type: models.ChargeVAT, Percent: 11, Currency: "IDR"}},
+		{name: "fixed fee without an amount", rule: models.FeeRule{Type: models.ChargeServiceFee, Currency: "IDR"}, wantErr: true},
+		{name: "vat over 100%", rule: models.FeeRule{Type: models.ChargeVAT, Percent: 101, Currency: "IDR"}, wantErr: true},
+		{name: "unknown type", rule: models.FeeRule{Type: models.ChargeDiscount, Amount: 1, Currency: "IDR"}, wantErr: true},
+		{name: "unknown cabin", rule: models.FeeRule{Type: models.ChargeFuelSurcharge, Amount: 1, Currency: "IDR", CabinClass: "premium"}, wantErr: true},
+		{name: "unsupported currency", rule: models.FeeRule{Type: models.ChargeFuelSurcharge, Amount: 1, Currency: "XXX"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateFeeRule(&tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("validateFeeRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// An adult, a child and an infant on schedule-cgk-dps-ga-001 at IDR 800,000:
+// IDR 1,400,000 in fares, and two seated passengers for per-passenger fees
+func TestFeeServiceBreakdown(t *testing.T) {
+	inactive := false
+	airportTaxes := []CreateFeeRuleRequest{
+		{Name: "CGK passenger service charge", Type: "airport_tax", AirportID: "cgk", Amount: 50000},
+		{Name: "DPS passenger service charge", Type: "airport_tax", AirportID: "dps", Amount: 70000},
+	}
+	fuelSurcharges := []CreateFeeRuleRequest{
+		{Name: "Garuda fuel surcharge", Type: "fuel_surcharge", AirlineID: "ga", CabinClass: "economy", Amount: 100000},
+		{Name: "Lion fuel surcharge", Type: "fuel_surcharge", AirlineID: "jt", Amount: 60000},
+		{Name: "Business fuel surcharge", Type: "fuel_surcharge", CabinClass: "business", Amount: 300000},
+	}
+	serviceFee := CreateFeeRuleRequest{Name: "Booking fee", Type: "service_fee", Amount: 5, Currency: "usd"}
+	vat := CreateFeeRuleRequest{Name: "VAT", Type: "vat", Percent: 11}
+
+	all := append(append(append([]CreateFeeRuleRequest{}, airportTaxes...), fuelSurcharges...), serviceFee, vat)
+	pausedVAT := vat
+	pausedVAT.IsActive = &inactive
+
+	tests := []struct {
+		name  string
+		rules []CreateFeeRuleRequest
+		want  map[models.ChargeType]int64 // minor units
+		total int64
+	}{
+		{
+			name:  "fares only",
+			want:  map[models.ChargeType]int64{models.ChargeBaseFare: 140000000},
+			total: 140000000,
+		},
+		{
+			name:  "airport tax of the departure airport per seated passenger",
+			rules: airportTaxes,
+			want:  map[models.ChargeType]int64{models.ChargeBaseFare: 140000000, models.ChargeAirportTax: 10000000},
+			total: 150000000,
+		},
+		{
+			name:  "fuel surcharge of the airline and cabin per seated passenger",
+			rules: fuelSurcharges,
+			want:  map[models.ChargeType]int64{models.ChargeBaseFare: 140000000, models.ChargeFuelSurcharge: 20000000},
+			total: 160000000,
+		},
+		{
+			name:  "service fee once per order, converted from dollars",
+			rules: []CreateFeeRuleRequest{serviceFee},
+			want:  map[models.ChargeType]int64{models.ChargeBaseFare: 140000000, models.ChargeServiceFee: 8000000},
+			total: 148000000,
+		},
+		{
+			// 11% of 1,400,000 fares + 200,000 fuel + 80,000 service, airport tax excluded
+			name:  "vat on everything but airport tax",
+			rules: all,
+			want: map[models.ChargeType]int64{
+				models.ChargeBaseFare:      140000000,
+				models.ChargeAirportTax:    10000000,
+				models.ChargeFuelSurcharge: 20000000,
+				models.ChargeServiceFee:    8000000,
+				models.ChargeVAT:           18480000,
+			},
+			total: 196480000,
+		},
+		{
+			name:  "rules created inactive are skipped",
+			rules: []CreateFeeRuleRequest{pausedVAT},
+			want:  map[models.ChargeType]int64{models.ChargeBaseFare: 140000000},
+			total: 140000000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			addExchangeRates(t, s, models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 16000, EffectiveFrom: time.Now().AddDate(0, 0, -1)})
+			feeRules := NewFeeRuleService(repository.NewFeeRuleRepository(s.staging))
+			for _, req := range tt.rules {
+				if _, err := feeRules.Create(req); err != nil {
+					t.Fatalf("create %s: %v", req.Name, err)
+				}
+			}
+
+			schedule, env, err := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			quote := &models.FareQuote{CabinClass: models.CabinEconomy, Currency: "IDR", Fare: 800000}
+			breakdown, err := s.fees.Breakdown(env, schedule, quote, PassengerCounts{Adults: 1, Children: 1, Infants: 1}, models.DefaultPassengerRules())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[models.ChargeType]int64{}
+			for _, item := range breakdown.Items {
+				got[item.Type] += item.Amount
+			}
+			for chargeType, amount := range tt.want {
+				if got[chargeType] != amount {
+					t.Errorf("%s = %d, want %d", chargeType, got[chargeType], amount)
+				}
+			}
+			for chargeType := range got {
+				if _, ok := tt.want[chargeType]; !ok {
+					t.Errorf("unexpected %s line of %d", chargeType, got[chargeType])
+				}
+			}
+			if breakdown.Total != tt.total {
+				t.Errorf("total = %d, want %d", breakdown.Total, tt.total)
+			}
+		})
+	}
+}
+
+// Fee rules belong to an environment; staging rules never price a production order
+func TestFeeServiceBreakdownUsesEnvironmentRules(t *testing.T) {
+	s := newTestServices(t)
+	s.whitelistUser(t, "partner@example.com", "ga")
+	if _, err := NewFeeRuleService(repository.NewFeeRuleRepository(s.production)).Create(CreateFeeRuleRequest{Name: "Production booking fee", Type: "service_fee", Amount: 25000}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		email   string
+		wantFee int64
+	}{
+		{name: "staging has no fees", email: "", wantFee: 0},
+		{name: "production booking fee", email: "partner@example.com", wantFee: 2500000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := s.orders.Create("", tt.email, bookingRequest("schedule-cgk-dps-ga-001", 7))
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			var fee int64
+			for _, item := range order.PriceBreakdown.Items {
+				if item.Type == models.ChargeServiceFee {
+					fee += item.Amount
+				}
+			}
+			if fee != tt.wantFee {
+				t.Errorf("service fee = %d, want %d", fee, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestFeeServiceBreakdownWithoutExchangeRate(t *testing.T) {
+	s := newTestServices(t)
+	if _, err := NewFeeRuleService(repository.NewFeeRuleRepository(s.staging)).Create(CreateFeeRuleRequest{Name: "Booking fee", Type: "service_fee", Amount: 5, Currency: "EUR"}); err != nil {
+		t.Fatal(err)
+	}
+	schedule, env, _ := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+	quote := &models.FareQuote{CabinClass: models.CabinEconomy, Currency: "IDR", Fare: 800000}
+	if _, err := s.fees.Breakdown(env, schedule, quote, PassengerCounts{Adults: 1}, models.DefaultPassengerRules()); !errors.Is(err, ErrNoExchangeRate) {
+		t.Errorf("err = %v, want %v", err, ErrNoExchangeRate)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// Orders show the flight as it was sold, whatever happens to its schedule afterwards
+func TestOrderKeepsFlightSnapshot(t *testing.T) {
+	s := newTestServices(t)
+	schedules := repository.NewScheduleRepository(s.staging)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sold, err := schedules.FindByID(order.ScheduleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flight := order.Flight
+	if flight == nil {
+		t.Fatal("order has no flight snapshot")
+	}
+	if flight.FlightNumber != sold.FlightNumber || flight.AirlineCode != "GA" || flight.DepartureAirport.Code != "CGK" || flight.ArrivalAirport.Code != "DPS" ||
+		flight.DepartureTime != sold.DepartureTime || flight.Aircraft != sold.Aircraft || flight.UnitPrice != models.ToMinorUnits(sold.EconomyPrice, flight.Currency) {
+		t.Errorf("snapshot %+v doesn't match the schedule sold", flight)
+	}
+	if order.ScheduleChanged {
+		t.Error("a new order shows its schedule as changed")
+	}
+
+	// Retiming and repricing the schedule leaves the order on what was sold, flagged as changed
+	retimed := *sold
+	retimed.DepartureTime = "23:55"
+	retimed.EconomyPrice = sold.EconomyPrice * 2
+	if err := schedules.Update(&retimed); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.orders.GetByID(order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got.Flight != *flight || !got.ScheduleChanged || got.Schedule == nil || got.Schedule.DepartureTime != "23:55" {
+		t.Errorf("after the schedule changed: flight %+v, changed %v, live schedule %+v", got.Flight, got.ScheduleChanged, got.Schedule)
+	}
+
+	// A deleted schedule leaves the order showing its flight, listed orders included
+	if err := schedules.Delete(order.ScheduleID); err != nil {
+		t.Fatal(err)
+	}
+	list, err := s.orders.List(1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := list.Data.([]models.Order)
+	if len(listed) != 1 {
+		t.Fatalf("listed %d orders, want 1", len(listed))
+	}
+	if got := listed[0]; got.Schedule != nil || got.Flight == nil || *got.Flight != *flight || !got.ScheduleChanged {
+		t.Errorf("after the schedule was deleted: flight %+v, changed %v, live schedule %+v", got.Flight, got.ScheduleChanged, got.Schedule)
+	}
+}
+
+func TestFlightSnapshotChangedFrom(t *testing.T) {
+	s := newTestServices(t)
+	schedule, err := repository.NewScheduleRepository(s.staging).FindByID("schedule-cgk-dps-ga-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := models.NewFlightSnapshot(schedule, models.CabinEconomy)
+
+	tests := []struct {
+		name   string
+		change func(schedule *models.Schedule)
+		want   bool
+	}{
+		{name: "unchanged", change: func(*models.Schedule) {}},
+		{name: "other cabin repriced", change: func(schedule *models.Schedule) { schedule.BusinessPrice++ }},
+		{name: "economy repriced", change: func(schedule *models.Schedule) { schedule.EconomyPrice++ }, want: true},
+		{name: "arrival retimed", change: func(schedule *models.Schedule) { schedule.ArrivalTime = "23:59" }, want: true},
+		{name: "aircraft swapped", change: func(schedule *models.Schedule) { schedule.Aircraft = "Airbus A320" }, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := *schedule
+			tt.change(&live)
+			if got := snapshot.ChangedFrom(&live); got != tt.want {
+				t.Errorf("ChangedFrom = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if !snapshot.ChangedFrom(nil) {
+		t.Error("a deleted schedule doesn't count as changed")
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/pdf"
+)
+
+// itineraryStyle is how a line of an itinerary is emphasised
+type itineraryStyle int
+
+const (
+	styleText itineraryStyle = iota
+	styleHeading
+	styleBold
+	styleBlank
+)
+
+type itineraryLine struct {
+	style itineraryStyle
+	text  string
+}
+
+// RenderItineraryText renders an order's itinerary and e-ticket receipt as plain text
+func RenderItineraryText(order *models.Order) string {
+	var b strings.Builder
+	for _, line := range itineraryLines(order) {
+		switch line.style {
+		case styleBlank:
+			b.WriteString("\n")
+		case styleHeading:
+			b.WriteString(line.text + "\n" + strings.Repeat("=", len(line.text)) + "\n")
+		default:
+			b.WriteString(line.text + "\n")
+		}
+	}
+	return b.String()
+}
+
+// RenderItineraryPDF renders the same itinerary as RenderItineraryText as a PDF document
+func RenderItineraryPDF(order *models.Order) []byte {
+	doc := pdf.New()
+	for _, line := range itineraryLines(order) {
+		switch line.style {
+		case styleBlank:
+			doc.Space()
+		case styleHeading:
+			doc.Heading(line.text)
+		case styleBold:
+			doc.Bold(line.text)
+		default:
+			doc.Text(line.text)
+		}
+	}
+	return doc.Bytes()
+}
+
+func itineraryLines(order *models.Order) []itineraryLine {
+	var lines []itineraryLine
+	add := func(style itineraryStyle, format string, args ...interface{}) {
+		lines = append(lines, itineraryLine{style: style, text: fmt.Sprintf(format, args...)})
+	}
+	ticketed := order.Status == models.OrderConfirmed || order.Status == models.OrderCompleted
+
+	if ticketed {
+		add(styleHeading, "E-Ticket Itinerary Receipt")
+	} else {
+		add(styleHeading, "Booking Itinerary")
+	}
+	add(styleBold, "Booking reference (PNR): %s", order.PNR)
+	add(styleText, "Status: %s", strings.ToUpper(string(order.Status)))
+	add(styleText, "Booked on: %s", order.CreatedAt.Format("2 Jan 2006"))
+	if !ticketed {
+		add(styleText, "E-tickets are issued once the booking is confirmed.")
+	}
+
+	if flight := order.Flight; flight != nil {
+		add(styleBlank, "")
+		add(styleHeading, "Flight")
+		add(styleBold, "%s", strings.TrimSpace(flight.FlightNumber+"  "+flight.AirlineName))
+		add(styleText, "Date: %s", order.FlightDate.Format("Mon, 2 Jan 2006"))
+		add(styleText, "Depart: %s  %s", flight.DepartureTime, describeAirport(flight.DepartureAirport, flight.DepartureTerminal))
+		add(styleText, "Arrive: %s  %s", flight.ArrivalTime, describeAirport(flight.ArrivalAirport, flight.ArrivalTerminal))
+		add(styleText, "Duration: %dh %02dm  Aircraft: %s", flight.Duration/60, flight.Duration%60, flight.Aircraft)
+		if order.ScheduleChanged {
+			add(styleText, "The airline has changed this flight since booking, please check the new schedule.")
+		}
+
+		cabin := "Cabin: " + string(order.CabinClass)
+		if order.FareQuote != nil && order.FareQuote.FareFamily != nil {
+			cabin += "  Fare: " + order.FareQuote.FareFamily.Name
+		}
+		add(styleText, "%s", cabin)
+	}
+
+	add(styleBlank, "")
+	add(styleHeading, "Passengers")
+	for i, p := range order.Passengers {
+		add(styleBold, "%d. %s %s (%s)", i+1, p.Title, p.FullName, p.Type)
+		ticket := "pending"
+		if p.TicketNumber != nil {
+			ticket = *p.TicketNumber
+		}
+		details := "E-ticket: " + ticket
+		if p.Seat != nil {
+			details += "  Seat: " + p.Seat.SeatNumber
+		}
+		if p.FrequentFlyerNumber != "" {
+			details += "  Frequent flyer: " + p.FrequentFlyerNumber
+		}
+		add(styleText, "   %s", details)
+		if len(p.Ancillaries) > 0 {
+			add(styleText, "   Add-ons: %s", ancillaryNames(p.Ancillaries))
+		}
+	}
+
+	add(styleBlank, "")
+	add(styleHeading, "Payment")
+	if order.PriceBreakdown != nil {
+		for _, item := range order.PriceBreakdown.Items {
+			add(styleText, "%-44s %s", fmt.Sprintf("%s x%d", item.Name, item.Quantity), models.FormatAmount(item.Amount, order.Currency))
+		}
+	}
+	add(styleBold, "%-44s %s", "Total", models.FormatAmount(order.TotalAmount, order.Currency))
+	if order.PromoCode != "" {
+		add(styleText, "Promo code: %s", order.PromoCode)
+	}
+
+	add(styleBlank, "")
+	add(styleHeading, "Contact")
+	add(styleText, "%s", order.ContactName)
+	add(styleText, "%s  %s", order.ContactEmail, order.ContactPhone)
+
+	return lines
+}
+
+// describeAirport renders "CGK Soekarno-Hatta International Airport, Jakarta, Terminal 3"
+func describeAirport(airport models.AirportSnapshot, terminal string) string {
+	if airport.Code == "" {
+		return ""
+	}
+	description := fmt.Sprintf("%s %s, %s", airport.Code, airport.Name, airport.City)
+	if terminal != "" {
+		description += ", Terminal " + terminal
+	}
+	return description
+}
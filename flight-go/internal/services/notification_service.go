@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationService interface {
+	List(userID string, unreadOnly bool, page, pageSize int) (*PaginatedResponse, error)
+	MarkRead(userID, id string) (*models.Notification, error)
+	ListDeliveries(recipient string, status models.DeliveryStatus, page, pageSize int) (*PaginatedResponse, error)
+}
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	deliveryRepo     repository.NotificationDeliveryRepository
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, deliveryRepo repository.NotificationDeliveryRepository) NotificationService {
+	return &notificationService{notificationRepo: notificationRepo, deliveryRepo: deliveryRepo}
+}
+
+// List returns a page of the user's notifications, newest first
+func (s *notificationService) List(userID string, unreadOnly bool, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	notifications, total, err := s.notificationRepo.ListByUser(userID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       notifications,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// MarkRead marks one of the user's notifications read, keeping the first time it was read
+func (s *notificationService) MarkRead(userID, id string) (*models.Notification, error) {
+	notification, err := s.notificationRepo.FindByID(id)
+	if err != nil || notification.UserID != userID {
+		return nil, ErrNotificationNotFound
+	}
+	if notification.ReadAt != nil {
+		return notification, nil
+	}
+
+	if err := s.notificationRepo.MarkRead(notification, time.Now()); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// ListDeliveries returns a page of the delivery log, newest first. recipient and status are
+// optional filters.
+func (s *notificationService) ListDeliveries(recipient string, status models.DeliveryStatus, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	deliveries, total, err := s.deliveryRepo.List(recipient, status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       deliveries,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/notifications"
+)
+
+// NotificationSubscriber tells order contacts and whitelisted users what happened to their
+// bookings and access, and new users how to verify their email, through the notification channels
+type NotificationSubscriber struct {
+	notifier *notifications.Notifier
+}
+
+func NewNotificationSubscriber(notifier *notifications.Notifier) *NotificationSubscriber {
+	return &NotificationSubscriber{notifier: notifier}
+}
+
+// Subscribe queues notifications after the events that call for one, off the request path
+func (n *NotificationSubscriber) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "notifications", events.Async, n.orderCreated)
+	events.Subscribe(bus, "notifications", events.Async, n.orderStatusChanged)
+	events.Subscribe(bus, "notifications", events.Async, n.scheduleDisrupted)
+	events.Subscribe(bus, "notifications", events.Async, n.whitelistChanged)
+	events.Subscribe(bus, "notifications", events.Async, n.emailVerificationRequested)
+}
+
+func (n *NotificationSubscriber) orderCreated(e events.OrderCreated) error {
+	return n.notifier.Notify(notifications.EventOrderCreated, orderRecipient(e.Order), e.Order.ID, orderData(e.Order))
+}
+
+func (n *NotificationSubscriber) orderStatusChanged(e events.OrderStatusChanged) error {
+	data := orderData(e.Order)
+	switch e.Order.Status {
+	case models.OrderConfirmed:
+		return n.notifier.Notify(notifications.EventOrderConfirmed, orderRecipient(e.Order), e.Order.ID, data)
+	case models.OrderCancelled:
+		if e.Refund > 0 {
+			data.Refund = models.FormatAmount(e.Refund, e.Order.Currency)
+		}
+		return n.notifier.Notify(notifications.EventOrderCancelled, orderRecipient(e.Order), e.Order.ID, data)
+	}
+	return nil
+}
+
+func (n *NotificationSubscriber) scheduleDisrupted(e events.ScheduleDisrupted) error {
+	change := &notifications.ScheduleChange{Cancelled: e.Disruption.Cancels(), Before: e.Disruption.Before, After: e.Disruption.After}
+	for i := range e.Orders {
+		order := &e.Orders[i]
+		data := orderData(order)
+		data.Change = change
+		if data.FlightNumber == "" {
+			data.FlightNumber = e.Schedule.FlightNumber
+		}
+		if err := n.notifier.Notify(notifications.EventScheduleChange, orderRecipient(order), order.ID, data); err != nil {
+			return fmt.Errorf("order %s: %w", order.ID, err)
+		}
+	}
+	return nil
+}
+
+// whitelistChanged tells a whitelisted user which airlines were just opened to them
+func (n *NotificationSubscriber) whitelistChanged(e events.WhitelistChanged) error {
+	if len(e.Granted) == 0 {
+		return nil
+	}
+
+	airlines := make([]string, len(e.Granted))
+	for i, id := range e.Granted {
+		airlines[i] = strings.ToUpper(id)
+	}
+	to := notifications.Recipient{Email: e.User.Email, Name: e.User.Name}
+	data := notifications.WhitelistData{Name: e.User.Name, Airlines: airlines}
+	return n.notifier.Notify(notifications.EventWhitelistGranted, to, "", data)
+}
+
+// emailVerificationRequested sends a user the token proving they own their email
+func (n *NotificationSubscriber) emailVerificationRequested(e events.EmailVerificationRequested) error {
+	to := notifications.Recipient{Email: e.User.Email, Name: e.User.Name}
+	data := notifications.VerifyEmailData{Name: e.User.Name, Token: e.Token}
+	return n.notifier.Notify(notifications.EventVerifyEmail, to, "", data)
+}
+
+// orderRecipient is the contact of order
+func orderRecipient(order *models.Order) notifications.Recipient {
+	return notifications.Recipient{Email: order.ContactEmail, Name: order.ContactName, Locale: order.Locale}
+}
+
+// orderData is what notification templates show of order, its flight as booked
+func orderData(order *models.Order) notifications.OrderData {
+	data := notifications.OrderData{
+		PNR:         order.PNR,
+		ContactName: order.ContactName,
+		FlightDate:  order.FlightDate.Format("2 Jan 2006"),
+		Total:       models.FormatAmount(order.TotalAmount, order.Currency),
+	}
+	if flight := order.Flight; flight != nil {
+		data.Airline = flight.AirlineName
+		data.FlightNumber = flight.FlightNumber
+		data.From = fmt.Sprintf("%s (%s)", flight.DepartureAirport.City, flight.DepartureAirport.Code)
+		data.To = fmt.Sprintf("%s (%s)", flight.ArrivalAirport.City, flight.ArrivalAirport.Code)
+		data.DepartureTime = flight.DepartureTime
+		data.ArrivalTime = flight.ArrivalTime
+	}
+	for _, p := range order.Passengers {
+		passenger := notifications.PassengerData{Name: p.Title + " " + p.FullName}
+		if p.TicketNumber != nil {
+			passenger.TicketNumber = *p.TicketNumber
+		}
+		data.Passengers = append(data.Passengers, passenger)
+	}
+	return data
+}
@@ -0,0 +1,686 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrOrderNotChangeable       = errors.New("order cannot be changed")
+	ErrFareNotChangeable        = errors.New("fare family does not allow changes")
+	ErrInvalidFlightChange      = errors.New("invalid flight change")
+	ErrPassengerNotFound        = errors.New("passenger not found")
+	ErrInvalidNameCorrection    = errors.New("invalid name correction")
+	ErrNameCorrectionExceedsMax = errors.New("name correction changes too many characters")
+)
+
+// NameCorrectionLimit is how many characters a name correction may add, remove or replace.
+// Anything more is a different traveler rather than a typo.
+const NameCorrectionLimit = 3
+
+// ChangeFlightRequest moves an order to another date and/or another schedule of the same
+// airline on the same route. Omitted fields keep the order's current value.
+type ChangeFlightRequest struct {
+	ScheduleID string `json:"schedule_id"`
+	FlightDate string `json:"flight_date"` // YYYY-MM-DD format
+}
+
+// CorrectNameRequest fixes a misspelt passenger name
+type CorrectNameRequest struct {
+	PassengerID string `json:"passenger_id" binding:"required"`
+	Title       string `json:"title"` // Defaults to the current title
+	FullName    string `json:"full_name" binding:"required"`
+}
+
+// SelectSeatsRequest picks seats after booking, an empty seat gives the passenger's seat up
+type SelectSeatsRequest struct {
+	Seats []SeatSelection `json:"seats" binding:"required,min=1,dive"`
+}
+
+type SeatSelection struct {
+	PassengerID string `json:"passenger_id" binding:"required"`
+	Seat        string `json:"seat"` // e.g. 12A
+}
+
+// AddAncillariesRequest buys ancillaries for an order's passengers after booking
+type AddAncillariesRequest struct {
+	Ancillaries []AncillarySelection `json:"ancillaries" binding:"required,min=1,dive"`
+}
+
+type AncillarySelection struct {
+	PassengerID string `json:"passenger_id" binding:"required"`
+	AncillaryID string `json:"ancillary_id" binding:"required"`
+}
+
+// ChangeQuote is what a change costs, amounts in minor units of Currency
+type ChangeQuote struct {
+	Currency       string                 `json:"currency"`
+	FareDifference int64                  `json:"fare_difference"` // New price less the current total, negative when cheaper
+	ChangeFee      int64                  `json:"change_fee"`
+	AmountDue      int64                  `json:"amount_due"`      // FareDifference plus ChangeFee
+	TotalAmount    int64                  `json:"total_amount"`    // Order total after the change
+	PriceBreakdown *models.PriceBreakdown `json:"price_breakdown"` // Order breakdown after the change
+}
+
+// flightChange is a priced flight change ready to apply
+type flightChange struct {
+	schedule   *models.Schedule
+	flightDate time.Time
+	family     *models.FareFamily
+	fareQuote  *models.FareQuote
+	quote      *ChangeQuote
+}
+
+// nameCorrection is a priced name correction ready to apply
+type nameCorrection struct {
+	passenger *models.Passenger
+	title     string
+	fullName  string
+	quote     *ChangeQuote
+}
+
+func (s *orderService) QuoteFlightChange(id string, req ChangeFlightRequest) (*ChangeQuote, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	change, err := s.prepareFlightChange(order, req, false)
+	if err != nil {
+		return nil, err
+	}
+	return change.quote, nil
+}
+
+// ChangeFlight reprices the order on its new flight and moves its passengers there in one step.
+// Seats picked on the old flight are given up, the new aircraft may not even have them.
+// Ancillaries are kept.
+func (s *orderService) ChangeFlight(id, changedBy string, req ChangeFlightRequest) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	change, err := s.prepareFlightChange(order, req, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.moveOrder(order, change, models.OrderChangeFlight, changedBy)
+}
+
+// moveOrder applies a priced flight change to order, recording it in the history as changeType
+func (s *orderService) moveOrder(order *models.Order, change *flightChange, changeType models.OrderChangeType, changedBy string) (*models.Order, error) {
+	flight := models.NewFlightSnapshot(change.schedule, order.CabinClass)
+	record := &models.OrderChange{
+		OrderID:        order.ID,
+		Type:           changeType,
+		ChangedBy:      changedBy,
+		Before:         flightDetails(order.Flight, order.FlightDate),
+		After:          flightDetails(flight, change.flightDate),
+		FareDifference: change.quote.FareDifference,
+		ChangeFee:      change.quote.ChangeFee,
+		Currency:       change.quote.Currency,
+	}
+
+	fromScheduleID, fromDate := order.ScheduleID, order.FlightDate
+	order.ScheduleID = change.schedule.ID
+	order.Flight = flight
+	order.FlightDate = change.flightDate
+	order.FareQuote = change.fareQuote
+	order.PriceBreakdown = change.quote.PriceBreakdown
+	order.TotalAmount = change.quote.TotalAmount
+
+	limit := repository.SeatLimit{CabinSeats: change.schedule.SeatsFor(order.CabinClass)}
+	if change.family != nil {
+		limit.FamilySeats = change.family.SeatBucket
+	}
+
+	// The order row is written first, taking the write lock, so the seat counts that follow
+	// already include its passengers and no concurrent booking can slip in between. The write
+	// only lands while the order is still changeable and on the flight the change was priced from.
+	err := s.unitOfWork.Do(func(repos repository.Repositories) error {
+		moved, err := repos.Orders.MoveFlight(order, fromScheduleID, fromDate)
+		if err != nil {
+			return err
+		}
+		if !moved {
+			return ErrOrderNotChangeable
+		}
+		if err := repos.SeatAssignments.ReleaseByOrder(order.ID); err != nil {
+			return err
+		}
+		if err := repos.Orders.CheckSeatLimit(order, limit); err != nil {
+			return seatLimitError(err)
+		}
+		return repos.Orders.AddChange(record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOrder(order.ID)
+}
+
+func (s *orderService) QuoteNameCorrection(id string, req CorrectNameRequest) (*ChangeQuote, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	correction, err := s.prepareNameCorrection(order, req)
+	if err != nil {
+		return nil, err
+	}
+	return correction.quote, nil
+}
+
+func (s *orderService) CorrectName(id, changedBy string, req CorrectNameRequest) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	correction, err := s.prepareNameCorrection(order, req)
+	if err != nil {
+		return nil, err
+	}
+
+	passenger := correction.passenger
+	record := &models.OrderChange{
+		OrderID:   order.ID,
+		Type:      models.OrderChangeName,
+		ChangedBy: changedBy,
+		Before: map[string]string{
+			"passenger_id": passenger.ID,
+			"title":        passenger.Title,
+			"full_name":    passenger.FullName,
+		},
+		After: map[string]string{
+			"passenger_id": passenger.ID,
+			"title":        correction.title,
+			"full_name":    correction.fullName,
+		},
+		ChangeFee: correction.quote.ChangeFee,
+		Currency:  correction.quote.Currency,
+	}
+
+	passenger.Title = correction.title
+	passenger.FullName = correction.fullName
+	order.PriceBreakdown = correction.quote.PriceBreakdown
+	order.TotalAmount = correction.quote.TotalAmount
+
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.UpdatePrice(order); err != nil {
+			return err
+		}
+		if err := repos.Orders.RenamePassenger(passenger); err != nil {
+			return err
+		}
+		return repos.Orders.AddChange(record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOrder(id)
+}
+
+// SelectSeats assigns, moves or gives up passengers' seats and reprices the order's seat lines.
+// Passengers left out of req keep their seats.
+func (s *orderService) SelectSeats(id, changedBy string, req SelectSeatsRequest) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := checkChangeable(order); err != nil {
+		return nil, err
+	}
+
+	before := make(map[string]string)
+	after := make(map[string]string)
+	requested := make(map[string]int)
+	for i, selection := range req.Seats {
+		var passenger *models.Passenger
+		for j := range order.Passengers {
+			if order.Passengers[j].ID == selection.PassengerID {
+				passenger = &order.Passengers[j]
+				break
+			}
+		}
+		if passenger == nil {
+			return nil, ErrPassengerNotFound
+		}
+
+		requested[passenger.ID] = i
+		if passenger.Seat != nil {
+			before[passenger.ID] = passenger.Seat.SeatNumber
+		}
+		after[passenger.ID] = strings.ToUpper(strings.TrimSpace(selection.Seat))
+	}
+
+	var picks []seatPick
+	for i := range order.Passengers {
+		passenger := &order.Passengers[i]
+		seat := ""
+		if passenger.Seat != nil {
+			seat = passenger.Seat.SeatNumber
+		}
+		index := i
+		if j, ok := requested[passenger.ID]; ok {
+			seat, index = after[passenger.ID], j
+		}
+		if seat != "" {
+			picks = append(picks, seatPick{Index: index, Passenger: passenger, Seat: seat})
+		}
+	}
+
+	assignments, err := s.seatService.price(order.Environment, order.Schedule, order.FlightDate, order.CabinClass, order.ID, picks, order.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	current := order.PriceBreakdown
+	if current == nil {
+		current = &models.PriceBreakdown{Currency: order.Currency, Items: []models.PriceLineItem{}}
+	}
+	breakdown := withSeats(current, assignments)
+
+	record := &models.OrderChange{
+		OrderID:        order.ID,
+		Type:           models.OrderChangeSeat,
+		ChangedBy:      changedBy,
+		Before:         before,
+		After:          after,
+		FareDifference: breakdown.Total - order.TotalAmount,
+		Currency:       order.Currency,
+	}
+	order.PriceBreakdown = breakdown
+	order.TotalAmount = breakdown.Total
+
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.SeatAssignments.ReplaceForOrder(order.ID, assignments); err != nil {
+			return seatError(err)
+		}
+		if err := repos.Orders.UpdatePrice(order); err != nil {
+			return err
+		}
+		return repos.Orders.AddChange(record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOrder(id)
+}
+
+// AddAncillaries buys ancillaries for passengers on the order's flight and adds them to its price.
+// Sales close a cut-off before departure.
+func (s *orderService) AddAncillaries(id, changedBy string, req AddAncillariesRequest) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if err := checkChangeable(order); err != nil {
+		return nil, err
+	}
+
+	var picks []ancillaryPick
+	before := make(map[string]string)
+	for i, selection := range req.Ancillaries {
+		var passenger *models.Passenger
+		for j := range order.Passengers {
+			if order.Passengers[j].ID == selection.PassengerID {
+				passenger = &order.Passengers[j]
+				break
+			}
+		}
+		if passenger == nil {
+			return nil, ErrPassengerNotFound
+		}
+		picks = append(picks, ancillaryPick{Index: i, Passenger: passenger, AncillaryID: selection.AncillaryID})
+		before[passenger.ID] = ancillaryNames(passenger.Ancillaries)
+	}
+
+	added, err := s.ancillaryPricing.price(order.Environment, order.Schedule, order.FlightDate, order.ID, picks, order.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	after := make(map[string]string, len(before))
+	for passengerID, names := range before {
+		after[passengerID] = names
+	}
+	all := orderAncillaries(order)
+	for _, ancillary := range added {
+		all = append(all, ancillary)
+		if after[ancillary.PassengerID] != "" {
+			after[ancillary.PassengerID] += ", "
+		}
+		after[ancillary.PassengerID] += ancillary.Name
+	}
+
+	current := order.PriceBreakdown
+	if current == nil {
+		current = &models.PriceBreakdown{Currency: order.Currency, Items: []models.PriceLineItem{}}
+	}
+	breakdown := withAncillaries(current, all)
+
+	record := &models.OrderChange{
+		OrderID:        order.ID,
+		Type:           models.OrderChangeAncillary,
+		ChangedBy:      changedBy,
+		Before:         before,
+		After:          after,
+		FareDifference: breakdown.Total - order.TotalAmount,
+		Currency:       order.Currency,
+	}
+	order.PriceBreakdown = breakdown
+	order.TotalAmount = breakdown.Total
+
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.UpdatePrice(order); err != nil {
+			return err
+		}
+		if err := repos.Orders.AddAncillaries(added); err != nil {
+			return err
+		}
+		return repos.Orders.AddChange(record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOrder(id)
+}
+
+// History returns every change made to an order, oldest first
+func (s *orderService) History(id string) ([]models.OrderChange, error) {
+	if _, err := s.findOrder(id); err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return s.orderRepo.ListChanges(id)
+}
+
+// prepareFlightChange prices moving order to the flight in req. An involuntary change, rebooking
+// off a disrupted flight, ignores the fare family's change rules and keeps the price paid.
+func (s *orderService) prepareFlightChange(order *models.Order, req ChangeFlightRequest, involuntary bool) (*flightChange, error) {
+	if err := checkChangeable(order); err != nil {
+		return nil, err
+	}
+
+	// The order's schedule is nil once deleted, the passengers can still move to another one
+	schedule := order.Schedule
+	if req.ScheduleID != "" && req.ScheduleID != order.ScheduleID {
+		var err error
+		if schedule, err = s.schedules.Find(order.Environment, req.ScheduleID); err != nil {
+			return nil, ErrScheduleNotFound
+		}
+	}
+	if schedule == nil {
+		return nil, ErrScheduleNotFound
+	}
+
+	// Tickets belong to the airline, so only its own flights on the same route qualify
+	if !sameAirlineAndRoute(order, schedule) {
+		return nil, ErrInvalidFlightChange
+	}
+
+	flightDate := order.FlightDate
+	if req.FlightDate != "" {
+		var err error
+		if flightDate, err = time.Parse("2006-01-02", req.FlightDate); err != nil {
+			return nil, ErrInvalidFlightDate
+		}
+		if flightDate.Before(time.Now().Truncate(24 * time.Hour)) {
+			return nil, ErrInvalidFlightDate
+		}
+	}
+
+	if schedule.ID == order.ScheduleID && flightDate.Equal(order.FlightDate) {
+		return nil, ErrInvalidFlightChange
+	}
+
+	// Reprice the same fare family on the new flight, in the currency the order was paid in
+	fareQuote, family, err := s.pricingService.QuoteFareFamily(order.Environment, schedule, order.CabinClass, flightDate, order.Currency, order.FareFamilyID)
+	if err != nil {
+		return nil, err
+	}
+	if involuntary {
+		// Only a flight that still operates will do, the disrupted one included if it was only retimed
+		if !schedule.IsActive {
+			return nil, ErrInvalidFlightChange
+		}
+		return &flightChange{
+			schedule:   schedule,
+			flightDate: flightDate,
+			family:     family,
+			fareQuote:  order.FareQuote,
+			quote: &ChangeQuote{
+				Currency:       order.Currency,
+				TotalAmount:    order.TotalAmount,
+				PriceBreakdown: order.PriceBreakdown,
+			},
+		}, nil
+	}
+	if family != nil && !family.Changeable {
+		return nil, ErrFareNotChangeable
+	}
+
+	counts := countOrderPassengers(order.Passengers)
+	rules := schedule.Airline.RulesOrDefault().WithFareFamily(family)
+	breakdown, err := s.feeService.Breakdown(order.Environment, schedule, fareQuote, counts, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ancillaries move to the new flight at the price they were bought for
+	breakdown = withAncillaries(breakdown, orderAncillaries(order))
+
+	quote, err := s.quoteChange(order, schedule, family, breakdown, len(order.Passengers))
+	if err != nil {
+		return nil, err
+	}
+
+	return &flightChange{
+		schedule:   schedule,
+		flightDate: flightDate,
+		family:     family,
+		fareQuote:  fareQuote,
+		quote:      quote,
+	}, nil
+}
+
+func (s *orderService) prepareNameCorrection(order *models.Order, req CorrectNameRequest) (*nameCorrection, error) {
+	if err := checkChangeable(order); err != nil {
+		return nil, err
+	}
+
+	var passenger *models.Passenger
+	for i := range order.Passengers {
+		if order.Passengers[i].ID == req.PassengerID {
+			passenger = &order.Passengers[i]
+			break
+		}
+	}
+	if passenger == nil {
+		return nil, ErrPassengerNotFound
+	}
+
+	title := passenger.Title
+	if req.Title != "" {
+		title = req.Title
+	}
+	fullName := strings.Join(strings.Fields(req.FullName), " ")
+	if fullName == "" {
+		return nil, ErrInvalidNameCorrection
+	}
+
+	edits := editDistance(strings.ToUpper(passenger.FullName), strings.ToUpper(fullName))
+	if edits == 0 && title == passenger.Title {
+		return nil, ErrInvalidNameCorrection
+	}
+	if edits > NameCorrectionLimit {
+		return nil, ErrNameCorrectionExceedsMax
+	}
+
+	var family *models.FareFamily
+	if order.FareFamilyID != "" {
+		var err error
+		if family, err = s.pricingService.FareFamily(order.Environment, order.FareFamilyID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The fare is unchanged, only the change fee for the one passenger is added
+	breakdown := &models.PriceBreakdown{Currency: order.Currency, Items: []models.PriceLineItem{}}
+	if order.PriceBreakdown != nil {
+		for _, item := range order.PriceBreakdown.Items {
+			if item.Type != models.ChargeDiscount && item.Type != models.ChargeChangeFee {
+				breakdown.Add(item)
+			}
+		}
+	}
+
+	quote, err := s.quoteChange(order, order.Schedule, family, breakdown, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nameCorrection{
+		passenger: passenger,
+		title:     title,
+		fullName:  fullName,
+		quote:     quote,
+	}, nil
+}
+
+// quoteChange completes breakdown, the order's fares, taxes and fees after a change, with the
+// discount and change fees already on the order, and adds the change fee for passengers
+func (s *orderService) quoteChange(order *models.Order, schedule *models.Schedule, family *models.FareFamily, breakdown *models.PriceBreakdown, passengers int) (*ChangeQuote, error) {
+	// Discounts and earlier change fees were settled with the original booking and carry over
+	if order.PriceBreakdown != nil {
+		for _, item := range order.PriceBreakdown.Items {
+			if item.Type == models.ChargeDiscount || item.Type == models.ChargeChangeFee {
+				breakdown.Add(item)
+			}
+		}
+	}
+
+	quote := &ChangeQuote{
+		Currency:       breakdown.Currency,
+		FareDifference: breakdown.Total - order.TotalAmount,
+	}
+
+	fee, err := s.feeService.ChangeFee(schedule, family, passengers, breakdown.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if fee != nil {
+		breakdown.Add(*fee)
+		quote.ChangeFee = fee.Amount
+	}
+
+	quote.AmountDue = quote.FareDifference + quote.ChangeFee
+	quote.TotalAmount = breakdown.Total
+	quote.PriceBreakdown = breakdown
+	return quote, nil
+}
+
+// sameAirlineAndRoute reports whether schedule is flown by the airline of order's flight, between the
+// same airports. Once the order's schedule is deleted, its flight as sold stands in for it.
+func sameAirlineAndRoute(order *models.Order, schedule *models.Schedule) bool {
+	if current := order.Schedule; current != nil {
+		return schedule.AirlineID == current.AirlineID &&
+			schedule.DepartureAirportID == current.DepartureAirportID &&
+			schedule.ArrivalAirportID == current.ArrivalAirportID
+	}
+	if order.Flight == nil || schedule.Airline == nil || schedule.DepartureAirport == nil || schedule.ArrivalAirport == nil {
+		return false
+	}
+	return strings.EqualFold(schedule.Airline.Code, order.Flight.AirlineCode) &&
+		strings.EqualFold(schedule.DepartureAirport.Code, order.Flight.DepartureAirport.Code) &&
+		strings.EqualFold(schedule.ArrivalAirport.Code, order.Flight.ArrivalAirport.Code)
+}
+
+// checkChangeable rejects changes to orders that are closed or whose flight has left
+func checkChangeable(order *models.Order) error {
+	if order.Status != models.OrderPending && order.Status != models.OrderConfirmed {
+		return ErrOrderNotChangeable
+	}
+	if order.FlightDate.Before(time.Now().Truncate(24 * time.Hour)) {
+		return ErrOrderNotChangeable
+	}
+	return nil
+}
+
+// countOrderPassengers tallies an order's passengers by type
+func countOrderPassengers(passengers []models.Passenger) PassengerCounts {
+	var counts PassengerCounts
+	for _, p := range passengers {
+		switch p.Type {
+		case models.PassengerAdult:
+			counts.Adults++
+		case models.PassengerChild:
+			counts.Children++
+		case models.PassengerInfant:
+			counts.Infants++
+		}
+	}
+	return counts
+}
+
+// orderAncillaries collects the ancillaries bought for an order's passengers
+func orderAncillaries(order *models.Order) []models.OrderAncillary {
+	var ancillaries []models.OrderAncillary
+	for _, passenger := range order.Passengers {
+		ancillaries = append(ancillaries, passenger.Ancillaries...)
+	}
+	return ancillaries
+}
+
+// ancillaryNames lists ancillaries by name for the order history and itinerary
+func ancillaryNames(ancillaries []models.OrderAncillary) string {
+	names := make([]string, len(ancillaries))
+	for i, ancillary := range ancillaries {
+		names[i] = ancillary.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// flightDetails describes a flight for the order history
+func flightDetails(flight *models.FlightSnapshot, flightDate time.Time) map[string]string {
+	details := map[string]string{"flight_date": flightDate.Format("2006-01-02")}
+	if flight != nil {
+		details["schedule_id"] = flight.ScheduleID
+		details["flight_number"] = flight.FlightNumber
+		details["departure_time"] = flight.DepartureTime
+	}
+	return details
+}
+
+// editDistance counts the single-character insertions, deletions and substitutions turning a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// changeableOrder books two adults on GA CGK-DPS a week out, in a fare family charging
+// IDR 150,000 per passenger for changes, or in one that allows none
+func changeableOrder(t *testing.T, s *testServices, changeable bool) *models.Order {
+	t.Helper()
+	families := NewFareFamilyService(repository.NewFareFamilyRepository(s.staging))
+	family, err := families.Create(CreateFareFamilyRequest{AirlineID: "ga", CabinClass: "economy", Code: "FLEX", Name: "Economy Flex", Changeable: changeable, ChangeFee: 150000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Budi Santoso"), adult("Siti Rahayu"))
+	req.FareFamilyID = family.ID
+	order, err := s.orders.Create("", "", req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return order
+}
+
+func TestOrderChangeFlight(t *testing.T) {
+	s := newTestServices(t)
+	order := changeableOrder(t, s, true)
+
+	tests := []struct {
+		name    string
+		req     ChangeFlightRequest
+		wantErr error
+	}{
+		{name: "same flight and date", req: ChangeFlightRequest{FlightDate: flightDate(7)}, wantErr: ErrInvalidFlightChange},
+		{name: "another airline", req: ChangeFlightRequest{ScheduleID: "schedule-cgk-dps-jt-001"}, wantErr: ErrInvalidFlightChange},
+		{name: "another route", req: ChangeFlightRequest{ScheduleID: "schedule-cgk-sub-ga-001"}, wantErr: ErrInvalidFlightChange},
+		{name: "date in the past", req: ChangeFlightRequest{FlightDate: flightDate(-1)}, wantErr: ErrInvalidFlightDate},
+		{name: "unknown schedule", req: ChangeFlightRequest{ScheduleID: "schedule-missing"}, wantErr: ErrScheduleNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.orders.QuoteFlightChange(order.ID, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	req := ChangeFlightRequest{ScheduleID: "schedule-cgk-dps-ga-002", FlightDate: flightDate(14)}
+	quote, err := s.orders.QuoteFlightChange(order.ID, req)
+	if err != nil {
+		t.Fatalf("QuoteFlightChange: %v", err)
+	}
+	if quote.ChangeFee != 2*15000000 {
+		t.Errorf("change fee = %d, want IDR 150,000 for each of 2 passengers", quote.ChangeFee)
+	}
+	if quote.AmountDue != quote.FareDifference+quote.ChangeFee || quote.TotalAmount != order.TotalAmount+quote.AmountDue {
+		t.Errorf("quote %+v does not add up from total %d", quote, order.TotalAmount)
+	}
+
+	changed, err := s.orders.ChangeFlight(order.ID, "test", req)
+	if err != nil {
+		t.Fatalf("ChangeFlight: %v", err)
+	}
+	if changed.ScheduleID != req.ScheduleID || changed.FlightDate.Format("2006-01-02") != req.FlightDate {
+		t.Errorf("order on %s %s, want %s %s", changed.ScheduleID, changed.FlightDate.Format("2006-01-02"), req.ScheduleID, req.FlightDate)
+	}
+	if changed.TotalAmount != quote.TotalAmount || changed.Flight == nil || changed.Flight.ScheduleID != req.ScheduleID {
+		t.Errorf("order total %d and flight %+v, want the quoted %d on the new flight", changed.TotalAmount, changed.Flight, quote.TotalAmount)
+	}
+
+	history, err := s.orders.History(order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := history[len(history)-1]
+	if last.Type != models.OrderChangeFlight || last.Before["schedule_id"] != "schedule-cgk-dps-ga-001" || last.After["flight_date"] != req.FlightDate || last.ChangeFee != quote.ChangeFee {
+		t.Errorf("history records %+v", last)
+	}
+}
+
+func TestOrderChangeFlightNotAllowed(t *testing.T) {
+	s := newTestServices(t)
+
+	fixed := changeableOrder(t, s, false)
+	if _, err := s.orders.ChangeFlight(fixed.ID, "test", ChangeFlightRequest{FlightDate: flightDate(14)}); !errors.Is(err, ErrFareNotChangeable) {
+		t.Errorf("changing a non-changeable fare: err = %v, want ErrFareNotChangeable", err)
+	}
+
+	cancelled, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.orders.Cancel(cancelled.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.orders.ChangeFlight(cancelled.ID, "test", ChangeFlightRequest{FlightDate: flightDate(14)}); !errors.Is(err, ErrOrderNotChangeable) {
+		t.Errorf("changing a cancelled order: err = %v, want ErrOrderNotChangeable", err)
+	}
+}
+
+// changedMeanwhile changes the order just before its flight is moved, as a request committing
+// between the change being priced and written would
+type changedMeanwhile struct {
+	repository.OrderRepository
+	change func(order *models.Order)
+}
+
+func (r changedMeanwhile) MoveFlight(order *models.Order, fromScheduleID string, fromDate time.Time) (bool, error) {
+	stored, err := r.FindByID(order.ID)
+	if err != nil {
+		return false, err
+	}
+	r.change(stored)
+	if err := r.Update(stored); err != nil {
+		return false, err
+	}
+	return r.OrderRepository.MoveFlight(order, fromScheduleID, fromDate)
+}
+
+// A flight change checks the order is still changeable, and where it was priced from, as it writes
+func TestOrderChangeFlightChangedMeanwhile(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(order *models.Order)
+	}{
+		{name: "cancelled", change: func(order *models.Order) { order.Status = models.OrderCancelled }},
+		{name: "moved to another date", change: func(order *models.Order) { order.FlightDate = order.FlightDate.AddDate(0, 0, 1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			order := changeableOrder(t, s, true)
+			orders := s.ordersFailingWith(func(repos repository.Repositories) repository.Repositories {
+				repos.Orders = changedMeanwhile{OrderRepository: repos.Orders, change: tt.change}
+				return repos
+			})
+
+			req := ChangeFlightRequest{ScheduleID: "schedule-cgk-dps-ga-002", FlightDate: flightDate(14)}
+			if _, err := orders.ChangeFlight(order.ID, "test", req); !errors.Is(err, ErrOrderNotChangeable) {
+				t.Fatalf("err = %v, want ErrOrderNotChangeable", err)
+			}
+			if stored, _ := s.orders.GetByID(order.ID); stored.ScheduleID != order.ScheduleID || !stored.FlightDate.Equal(order.FlightDate) {
+				t.Errorf("order moved to %s %s", stored.ScheduleID, stored.FlightDate.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestOrderCorrectName(t *testing.T) {
+	s := newTestServices(t)
+	order := changeableOrder(t, s, true)
+	passenger := order.Passengers[0] // Budi Santoso
+
+	tests := []struct {
+		name    string
+		req     CorrectNameRequest
+		wantErr error
+	}{
+		{name: "unknown passenger", req: CorrectNameRequest{PassengerID: "missing", FullName: "Budi Santosa"}, wantErr: ErrPassengerNotFound},
+		{name: "nothing to correct", req: CorrectNameRequest{PassengerID: passenger.ID, FullName: " budi  santoso "}, wantErr: ErrInvalidNameCorrection},
+		{name: "another person", req: CorrectNameRequest{PassengerID: passenger.ID, FullName: "Agus Hartono"}, wantErr: ErrNameCorrectionExceedsMax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.orders.QuoteNameCorrection(order.ID, tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Three characters is the most a correction may change
+	req := CorrectNameRequest{PassengerID: passenger.ID, FullName: "Budhi Santossa"}
+	quote, err := s.orders.QuoteNameCorrection(order.ID, req)
+	if err != nil {
+		t.Fatalf("QuoteNameCorrection: %v", err)
+	}
+	if quote.FareDifference != 0 || quote.ChangeFee != 15000000 {
+		t.Errorf("quote charges fare difference %d and fee %d, want only one passenger's fee", quote.FareDifference, quote.ChangeFee)
+	}
+
+	corrected, err := s.orders.CorrectName(order.ID, "test", req)
+	if err != nil {
+		t.Fatalf("CorrectName: %v", err)
+	}
+	for _, p := range corrected.Passengers {
+		if p.ID == passenger.ID && p.FullName != "Budhi Santossa" {
+			t.Errorf("passenger is named %q", p.FullName)
+		}
+	}
+	if corrected.TotalAmount != quote.TotalAmount {
+		t.Errorf("total = %d, want the quoted %d", corrected.TotalAmount, quote.TotalAmount)
+	}
+}
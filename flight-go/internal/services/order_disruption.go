@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrNoDisruption            = errors.New("order has no disruption awaiting a response")
+	ErrDisruptionNotAcceptable = errors.New("a cancelled flight cannot be accepted")
+)
+
+// AcceptDisruption keeps the order on its disrupted flight as the airline changed it. The order's
+// flight snapshot is updated to the new schedule.
+func (s *orderService) AcceptDisruption(id, changedBy string) (*models.Order, error) {
+	order, err := s.disruptedOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Disruption != nil && order.Disruption.Cancels() {
+		return nil, ErrDisruptionNotAcceptable
+	}
+
+	change := disruptionResponse(order, models.DisruptionAccepted, changedBy)
+	order.DisruptionStatus = models.DisruptionAccepted
+	if order.Schedule != nil {
+		order.Flight = models.NewFlightSnapshot(order.Schedule, order.CabinClass)
+	}
+
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.Update(order); err != nil {
+			return err
+		}
+		return repos.Orders.AddChange(change)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findOrder(id)
+}
+
+// RefundDisruption cancels the order on a disrupted flight with the full amount paid owed back,
+// releasing its seats and promo code use like a cancellation
+func (s *orderService) RefundDisruption(id, changedBy string) (*models.Order, error) {
+	order, err := s.disruptedOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	change := disruptionResponse(order, models.DisruptionRefundRequested, changedBy)
+	change.Before["status"] = string(order.Status)
+	change.After["status"] = string(models.OrderCancelled)
+	change.After["refund_amount"] = models.FormatAmount(order.TotalAmount, order.Currency)
+	from := order.Status
+	order.Status = models.OrderCancelled
+	order.DisruptionStatus = models.DisruptionRefundRequested
+
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.Update(order); err != nil {
+			return err
+		}
+		if err := repos.Orders.AddChange(change); err != nil {
+			return err
+		}
+		return releaseOrder(repos, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refunded, err := s.findOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(events.OrderStatusChanged{Order: refunded.Clone(), From: from, ChangedBy: changedBy, Refund: refunded.TotalAmount})
+	return refunded, nil
+}
+
+// Rebook moves the order off its disrupted flight to another date or schedule of the same airline
+// on the same route, free of charge whatever its fare family allows
+func (s *orderService) Rebook(id, changedBy string, req ChangeFlightRequest) (*models.Order, error) {
+	order, err := s.disruptedOrder(id)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := s.prepareFlightChange(order, req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	order.DisruptionStatus = models.DisruptionRebooked
+	return s.moveOrder(order, change, models.OrderChangeRebooking, changedBy)
+}
+
+// disruptedOrder loads an order that is waiting for its passengers to respond to a disruption
+func (s *orderService) disruptedOrder(id string) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.DisruptionStatus != models.DisruptionActionRequired {
+		return nil, ErrNoDisruption
+	}
+	if err := checkChangeable(order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// disruptionResponse records the passengers answering the disruption of order with status
+func disruptionResponse(order *models.Order, status models.DisruptionStatus, changedBy string) *models.OrderChange {
+	return &models.OrderChange{
+		OrderID:   order.ID,
+		Type:      models.OrderChangeDisruptionResponse,
+		ChangedBy: changedBy,
+		Before:    map[string]string{"disruption_status": string(order.DisruptionStatus)},
+		After:     map[string]string{"disruption_status": string(status)},
+	}
+}
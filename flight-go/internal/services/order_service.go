@@ -2,40 +2,83 @@ package services
 
 import (
 	"errors"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/notifications"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 )
 
 var (
-	ErrOrderNotFound     = errors.New("order not found")
-	ErrInvalidFlightDate = errors.New("invalid flight date")
+	ErrOrderNotFound       = errors.New("order not found")
+	ErrInvalidFlightDate   = errors.New("invalid flight date")
+	ErrOrderNotCancellable = errors.New("order cannot be cancelled")
 )
 
 type OrderService interface {
-	Create(userID string, req CreateOrderRequest) (*models.Order, error)
+	Create(userID, userEmail string, req CreateOrderRequest) (*models.Order, error)
 	GetByID(id string) (*models.Order, error)
-	Update(id string, req UpdateOrderRequest) (*models.Order, error)
-	Cancel(id string) error
+	GetByPNR(pnr, contactEmail string) (*models.Order, error)
+	Claim(id, userID string) (*models.Order, error)
+	ClaimBooking(pnr, userID string, req ClaimBookingRequest) (*models.Order, error)
+	Update(id, changedBy string, req UpdateOrderRequest) (*models.Order, error)
+	Cancel(id, changedBy string) error
+	QuoteFlightChange(id string, req ChangeFlightRequest) (*ChangeQuote, error)
+	ChangeFlight(id, changedBy string, req ChangeFlightRequest) (*models.Order, error)
+	QuoteNameCorrection(id string, req CorrectNameRequest) (*ChangeQuote, error)
+	CorrectName(id, changedBy string, req CorrectNameRequest) (*models.Order, error)
+	SelectSeats(id, changedBy string, req SelectSeatsRequest) (*models.Order, error)
+	AddAncillaries(id, changedBy string, req AddAncillariesRequest) (*models.Order, error)
+	AcceptDisruption(id, changedBy string) (*models.Order, error)
+	RefundDisruption(id, changedBy string) (*models.Order, error)
+	Rebook(id, changedBy string, req ChangeFlightRequest) (*models.Order, error)
+	History(id string) ([]models.OrderChange, error)
 	List(page, pageSize int) (*PaginatedResponse, error)
 	ListByUser(userID string, page, pageSize int) (*PaginatedResponse, error)
 }
 
 type CreateOrderRequest struct {
-	ScheduleID   string             `json:"schedule_id" binding:"required"`
-	FlightDate   string             `json:"flight_date" binding:"required"` // YYYY-MM-DD format
-	CabinClass   string             `json:"cabin_class" binding:"required"` // economy, business, first
-	ContactName  string             `json:"contact_name" binding:"required"`
-	ContactEmail string             `json:"contact_email" binding:"required,email"`
-	ContactPhone string             `json:"contact_phone" binding:"required"`
-	Passengers   []PassengerRequest `json:"passengers" binding:"required,min=1"`
+	ScheduleID    string             `json:"schedule_id" binding:"required"`
+	FlightDate    string             `json:"flight_date" binding:"required"` // YYYY-MM-DD format
+	CabinClass    string             `json:"cabin_class" binding:"required"` // economy, business, first
+	FareFamilyID  string             `json:"fare_family_id"`                 // Defaults to the cabin's first fare family
+	Currency      string             `json:"currency"`                       // Defaults to the schedule's currency
+	PromoCode     string             `json:"promo_code"`
+	SaveTravelers bool               `json:"save_travelers"` // Add passengers not booked from a traveler_id to the user's saved travelers
+	ContactName   string             `json:"contact_name" binding:"required"`
+	ContactEmail  string             `json:"contact_email" binding:"required,email"`
+	ContactPhone  string             `json:"contact_phone" binding:"required"`
+	Locale        string             `json:"locale"` // Language the contact is notified in, e.g. en or id, defaults to en
+	Passengers    []PassengerRequest `json:"passengers" binding:"required,min=1"`
 }
 
+// PassengerRequest is one passenger on an order. With TravelerID the saved traveler fills in
+// every field left empty, and Type defaults to the traveler's age on the flight date.
 type PassengerRequest struct {
-	Title    string `json:"title" binding:"required"`     // Mr, Mrs, Ms
-	FullName string `json:"full_name" binding:"required"`
-	Type     string `json:"type" binding:"required"` // adult, child, infant
+	TravelerID  string `json:"traveler_id"`
+	Title       string `json:"title" binding:"required_without=TravelerID"` // Mr, Mrs, Ms
+	FullName    string `json:"full_name" binding:"required_without=TravelerID"`
+	Type        string `json:"type"`          // adult, child, infant
+	DateOfBirth string `json:"date_of_birth"` // YYYY-MM-DD format, required for children and infants
+
+	// Travel document, see validateTravelDocument for what each route type requires
+	Nationality         string `json:"nationality"`   // ISO 3166-1 alpha-2, e.g. ID
+	DocumentType        string `json:"document_type"` // passport, national_id
+	DocumentNumber      string `json:"document_number"`
+	DocumentExpiry      string `json:"document_expiry"` // YYYY-MM-DD format, required for passports
+	FrequentFlyerNumber string `json:"frequent_flyer_number"`
+
+	Seat        string   `json:"seat"`        // e.g. 12A, optional, see GET /flights/:id/seats
+	Ancillaries []string `json:"ancillaries"` // Ancillary IDs for this flight, see GET /flights/:id/ancillaries
+}
+
+// ClaimBookingRequest proves a signed-in user travels on a guest booking they look up by PNR
+type ClaimBookingRequest struct {
+	LastName string `json:"last_name" binding:"required"` // Surname of any passenger on the booking
 }
 
 type UpdateOrderRequest struct {
@@ -46,22 +89,40 @@ type UpdateOrderRequest struct {
 }
 
 type orderService struct {
-	orderRepo    repository.OrderRepository
-	scheduleRepo repository.ScheduleRepository
+	orderRepo        repository.OrderRepository
+	schedules        *ScheduleResolver
+	pricingService   *PricingService
+	feeService       *FeeService
+	promotionService PromotionService
+	travelerService  TravelerService
+	seatService      *SeatService
+	ancillaryPricing *AncillaryPricingService
+	unitOfWork       repository.UnitOfWork
+	events           *events.Bus
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, scheduleRepo repository.ScheduleRepository) OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, schedules *ScheduleResolver, pricingService *PricingService, feeService *FeeService, promotionService PromotionService, travelerService TravelerService, seatService *SeatService, ancillaryPricing *AncillaryPricingService, unitOfWork repository.UnitOfWork, bus *events.Bus) OrderService {
 	return &orderService{
-		orderRepo:    orderRepo,
-		scheduleRepo: scheduleRepo,
+		orderRepo:        orderRepo,
+		schedules:        schedules,
+		pricingService:   pricingService,
+		feeService:       feeService,
+		promotionService: promotionService,
+		travelerService:  travelerService,
+		seatService:      seatService,
+		ancillaryPricing: ancillaryPricing,
+		unitOfWork:       unitOfWork,
+		events:           bus,
 	}
 }
 
-func (s *orderService) Create(userID string, req CreateOrderRequest) (*models.Order, error) {
+// Create books a schedule for userID (empty for guests). The order is priced and saved in the
+// environment the schedule is sold to userEmail from, see ScheduleResolver.
+func (s *orderService) Create(userID, userEmail string, req CreateOrderRequest) (*models.Order, error) {
 	// Get schedule to calculate price
-	schedule, err := s.scheduleRepo.FindByID(req.ScheduleID)
+	schedule, env, err := s.schedules.Resolve(userEmail, req.ScheduleID)
 	if err != nil {
-		return nil, ErrScheduleNotFound
+		return nil, err
 	}
 
 	// Parse flight date
@@ -75,110 +136,539 @@ func (s *orderService) Create(userID string, req CreateOrderRequest) (*models.Or
 		return nil, ErrInvalidFlightDate
 	}
 
-	// Calculate total amount based on cabin class and passengers
-	var pricePerPerson float64
-	cabinClass := models.CabinClass(req.CabinClass)
-	switch cabinClass {
-	case models.CabinEconomy:
-		pricePerPerson = schedule.EconomyPrice
-	case models.CabinBusiness:
-		pricePerPerson = schedule.BusinessPrice
-	case models.CabinFirst:
-		pricePerPerson = schedule.FirstClassPrice
-	default:
-		pricePerPerson = schedule.EconomyPrice
-		cabinClass = models.CabinEconomy
+	// Enforce the airline's booking rules and the route's document requirements before pricing
+	rules := schedule.Airline.RulesOrDefault()
+	requests, err := s.resolveTravelers(userID, req.Passengers, rules, flightDate)
+	if err != nil {
+		return nil, err
+	}
+	passengers, err := validatePassengers(requests, rules, schedule.RouteType(), flightDate)
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate total (adults full price, children 75%, infants free)
-	var totalAmount float64
-	for _, p := range req.Passengers {
-		switch models.PassengerType(p.Type) {
-		case models.PassengerAdult:
-			totalAmount += pricePerPerson
-		case models.PassengerChild:
-			totalAmount += pricePerPerson * 0.75
-		case models.PassengerInfant:
-			// Infants are free
+	// Quote the same dynamic fare search and flight detail show
+	cabinClass := toCabinClass(req.CabinClass)
+	quote, family, err := s.pricingService.QuoteFareFamily(env, schedule, cabinClass, flightDate, strings.ToUpper(req.Currency), req.FareFamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Children and infants pay the airline's share of the adult fare, unless the fare family overrides it
+	rules = rules.WithFareFamily(family)
+	var fareFamilyID string
+	if family != nil {
+		fareFamilyID = family.ID
+	}
+
+	// Itemise fares, taxes and fees, summed in integer minor units so totals never pick up float rounding errors
+	counts := countPassengers(requests)
+	breakdown, err := s.feeService.Breakdown(env, schedule, quote, counts, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// Price the seats and ancillaries picked at booking, seats are only held once the order is ready to save
+	orderID := uuid.New().String()
+	var picks []seatPick
+	var ancillaryPicks []ancillaryPick
+	for i, p := range requests {
+		if p.Seat == "" && len(p.Ancillaries) == 0 {
+			continue
+		}
+		passengers[i].ID = uuid.New().String()
+		if p.Seat != "" {
+			picks = append(picks, seatPick{Index: i, Passenger: &passengers[i], Seat: p.Seat})
+		}
+		for _, ancillaryID := range p.Ancillaries {
+			ancillaryPicks = append(ancillaryPicks, ancillaryPick{Index: i, Passenger: &passengers[i], AncillaryID: ancillaryID})
 		}
 	}
+	seats, err := s.seatService.price(env, schedule, flightDate, cabinClass, orderID, picks, breakdown.Currency)
+	if err != nil {
+		return nil, err
+	}
+	breakdown = withSeats(breakdown, seats)
+
+	ancillaries, err := s.ancillaryPricing.price(env, schedule, flightDate, orderID, ancillaryPicks, breakdown.Currency)
+	if err != nil {
+		return nil, err
+	}
+	breakdown = withAncillaries(breakdown, ancillaries)
+	// Saved along with their passengers
+	for _, ancillary := range ancillaries {
+		for i := range passengers {
+			if passengers[i].ID == ancillary.PassengerID {
+				passengers[i].Ancillaries = append(passengers[i].Ancillaries, ancillary)
+			}
+		}
+	}
+
+	// Discount the itemised total, the code is only redeemed once the order is ready to save.
+	// Guests have no account, so their per-customer limits count by contact email.
+	customerID := userID
+	if customerID == "" {
+		customerID = guestCustomerID(req.ContactEmail)
+	}
+	var promotion *models.Promotion
+	if req.PromoCode != "" {
+		if promotion, err = s.promotionService.Apply(customerID, req.PromoCode, schedule, cabinClass, breakdown); err != nil {
+			return nil, err
+		}
+	}
+
+	// Checked up front for a quick answer, and again once the passengers are written below
+	if err := s.pricingService.CheckAvailability(env, schedule, cabinClass, flightDate, family, counts.Seated()); err != nil {
+		return nil, err
+	}
 
 	order := &models.Order{
+		BaseModel:      models.BaseModel{ID: orderID},
 		UserID:         userID,
+		Environment:    env,
 		ScheduleID:     req.ScheduleID,
+		Flight:         models.NewFlightSnapshot(schedule, cabinClass),
 		FlightDate:     flightDate,
 		CabinClass:     cabinClass,
-		TotalPassenger: len(req.Passengers),
-		TotalAmount:    totalAmount,
+		TotalPassenger: len(passengers),
+		TotalAmount:    breakdown.Total,
+		Currency:       breakdown.Currency,
+		FareQuote:      quote,
+		PriceBreakdown: breakdown,
+		FareFamilyID:   fareFamilyID,
 		Status:         models.OrderPending,
 		ContactName:    req.ContactName,
 		ContactEmail:   req.ContactEmail,
 		ContactPhone:   req.ContactPhone,
+		Locale:         notifications.Locale(req.Locale),
 	}
 
-	if err := s.orderRepo.Create(order); err != nil {
+	if promotion != nil {
+		order.PromotionID = promotion.ID
+		order.PromoCode = promotion.Code
+	}
+	limit := repository.SeatLimit{CabinSeats: schedule.SeatsFor(cabinClass)}
+	if family != nil {
+		limit.FamilySeats = family.SeatBucket
+	}
+
+	// Save the order with its passengers, seats and promo code use, all or nothing. Capacity is
+	// counted after the passengers are written, so concurrent orders can never oversell the cabin,
+	// book a code past its limits or sit two passengers in one seat.
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.Create(order); err != nil {
+			return err
+		}
+		for i := range passengers {
+			passengers[i].OrderID = order.ID
+			if err := repos.Orders.AddPassenger(&passengers[i]); err != nil {
+				return err
+			}
+		}
+		if err := repos.Orders.CheckSeatLimit(order, limit); err != nil {
+			return seatLimitError(err)
+		}
+		if err := repos.SeatAssignments.Assign(seats); err != nil {
+			return seatError(err)
+		}
+		if promotion != nil {
+			return redeemPromotion(repos.Promotions, promotion, customerID, order.ID)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	// Add passengers
-	for _, p := range req.Passengers {
-		passenger := &models.Passenger{
-			OrderID:  order.ID,
-			Title:    p.Title,
-			FullName: p.FullName,
-			Type:     models.PassengerType(p.Type),
+	if req.SaveTravelers && userID != "" {
+		var unsaved []models.Passenger
+		for i, p := range req.Passengers {
+			if p.TravelerID == "" {
+				unsaved = append(unsaved, passengers[i])
+			}
 		}
-		if err := s.orderRepo.AddPassenger(passenger); err != nil {
-			return nil, err
+		// The order already stands, so a failure here only costs the user retyping next time
+		if err := s.travelerService.SaveFromOrder(userID, unsaved); err != nil {
+			log.Printf("Saving travelers from order %s failed: %v", order.ID, err)
 		}
 	}
 
 	// Reload with relations
-	return s.orderRepo.FindByID(order.ID)
+	created, err := s.findOrder(order.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(events.OrderCreated{Order: created.Clone()})
+	return created, nil
+}
+
+// guestCustomerID identifies a guest by the contact email they book with
+func guestCustomerID(contactEmail string) string {
+	return "guest:" + strings.ToLower(contactEmail)
 }
 
 func (s *orderService) GetByID(id string) (*models.Order, error) {
-	order, err := s.orderRepo.FindByID(id)
+	order, err := s.findOrder(id)
 	if err != nil {
 		return nil, ErrOrderNotFound
 	}
 	return order, nil
 }
 
-func (s *orderService) Update(id string, req UpdateOrderRequest) (*models.Order, error) {
+// findOrder loads an order with its schedule from the database of the order's environment
+func (s *orderService) findOrder(id string) (*models.Order, error) {
 	order, err := s.orderRepo.FindByID(id)
 	if err != nil {
+		return nil, err
+	}
+	// A deleted schedule leaves it nil, the order still shows its flight snapshot
+	schedule, _ := s.schedules.Find(order.Environment, order.ScheduleID)
+	attachSchedule(order, schedule)
+	return order, nil
+}
+
+// withSchedules attaches each order's schedule, looked up per environment in one query each
+func (s *orderService) withSchedules(orders []models.Order) error {
+	idsByEnv := make(map[models.Environment][]string)
+	for _, order := range orders {
+		idsByEnv[order.Environment] = append(idsByEnv[order.Environment], order.ScheduleID)
+	}
+	for env, ids := range idsByEnv {
+		schedules, err := s.schedules.FindMany(env, ids)
+		if err != nil {
+			return err
+		}
+		for i := range orders {
+			if orders[i].Environment == env {
+				attachSchedule(&orders[i], schedules[orders[i].ScheduleID])
+			}
+		}
+	}
+	return nil
+}
+
+// attachSchedule sets the live schedule of order, nil when it was deleted, and flags whether it
+// has changed since booking. Orders booked before flights were snapshotted show the live schedule,
+// and snapshots taken before they kept the airline ID take it from the live schedule.
+func attachSchedule(order *models.Order, schedule *models.Schedule) {
+	order.Schedule = schedule
+	if order.Flight == nil {
+		if schedule != nil {
+			order.Flight = models.NewFlightSnapshot(schedule, order.CabinClass)
+		}
+		return
+	}
+	if order.Flight.AirlineID == "" && schedule != nil {
+		order.Flight.AirlineID = schedule.AirlineID
+	}
+	order.ScheduleChanged = order.Flight.ChangedFrom(schedule)
+}
+
+// GetByPNR finds a booking for a guest who knows its reference and contact email.
+// A wrong email reads the same as an unknown PNR, so references cannot be probed.
+func (s *orderService) GetByPNR(pnr, contactEmail string) (*models.Order, error) {
+	order, err := s.orderRepo.FindByPNR(strings.ToUpper(strings.TrimSpace(pnr)))
+	if err != nil || !strings.EqualFold(order.ContactEmail, strings.TrimSpace(contactEmail)) {
+		return nil, ErrOrderNotFound
+	}
+	return s.findOrder(order.ID)
+}
+
+// Claim moves a guest order to userID's account. Callers check the user owns the booking first,
+// with its booking token or ClaimBooking. Orders of another account read as not found.
+func (s *orderService) Claim(id, userID string) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.UserID == userID {
+		return order, nil
+	}
+
+	claimed, err := s.orderRepo.ClaimGuestOrder(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
 		return nil, ErrOrderNotFound
 	}
+	return s.findOrder(id)
+}
 
-	if req.Status != "" {
+// ClaimBooking claims the guest booking under pnr for userID when req names one of its passengers.
+// A wrong name reads the same as an unknown PNR, like GetByPNR.
+func (s *orderService) ClaimBooking(pnr, userID string, req ClaimBookingRequest) (*models.Order, error) {
+	order, err := s.orderRepo.FindByPNR(strings.ToUpper(strings.TrimSpace(pnr)))
+	if err != nil || !hasPassengerSurname(order, req.LastName) {
+		return nil, ErrOrderNotFound
+	}
+	return s.Claim(order.ID, userID)
+}
+
+// hasPassengerSurname reports whether lastName is the last word of a passenger's name on order
+func hasPassengerSurname(order *models.Order, lastName string) bool {
+	lastName = strings.TrimSpace(lastName)
+	if lastName == "" {
+		return false
+	}
+	for _, p := range order.Passengers {
+		names := strings.Fields(p.FullName)
+		if len(names) > 0 && strings.EqualFold(names[len(names)-1], lastName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *orderService) Update(id, changedBy string, req UpdateOrderRequest) (*models.Order, error) {
+	order, err := s.findOrder(id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+
+	var changes []*models.OrderChange
+	from := order.Status
+	statusChanged := req.Status != "" && models.OrderStatus(req.Status) != order.Status
+	if statusChanged && models.OrderStatus(req.Status) == models.OrderCancelled {
+		if err := checkCancellable(order); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reviving a cancelled order books its passengers on the flight again, so they have to fit
+	reviving := statusChanged && from == models.OrderCancelled
+	var limit repository.SeatLimit
+	if reviving {
+		if limit, err = s.seatLimit(order); err != nil {
+			return nil, err
+		}
+	}
+	if statusChanged {
+		changes = append(changes, statusChange(order, models.OrderStatus(req.Status), changedBy))
 		order.Status = models.OrderStatus(req.Status)
 	}
-	if req.ContactName != "" {
-		order.ContactName = req.ContactName
+
+	contact := &models.OrderChange{
+		OrderID:   order.ID,
+		Type:      models.OrderChangeContact,
+		ChangedBy: changedBy,
+		Before:    map[string]string{},
+		After:     map[string]string{},
 	}
-	if req.ContactEmail != "" {
-		order.ContactEmail = req.ContactEmail
+	for _, field := range []struct {
+		name  string
+		value *string
+		new   string
+	}{
+		{"contact_name", &order.ContactName, req.ContactName},
+		{"contact_email", &order.ContactEmail, req.ContactEmail},
+		{"contact_phone", &order.ContactPhone, req.ContactPhone},
+	} {
+		if field.new != "" && field.new != *field.value {
+			contact.Before[field.name] = *field.value
+			contact.After[field.name] = field.new
+			*field.value = field.new
+		}
 	}
-	if req.ContactPhone != "" {
-		order.ContactPhone = req.ContactPhone
+	if len(contact.After) > 0 {
+		changes = append(changes, contact)
 	}
 
-	if err := s.orderRepo.Update(order); err != nil {
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.Update(order); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if err := repos.Orders.AddChange(change); err != nil {
+				return err
+			}
+		}
+
+		if statusChanged && order.Status == models.OrderCancelled {
+			return releaseOrder(repos, order)
+		}
+		if reviving {
+			if err := reviveOrder(repos, order, limit); err != nil {
+				return err
+			}
+		}
+		if order.Status == models.OrderConfirmed || order.Status == models.OrderCompleted {
+			// E-tickets are issued once the booking is confirmed
+			var airlineCode string
+			if order.Flight != nil {
+				airlineCode = order.Flight.AirlineCode
+			}
+			return repos.Orders.IssueTickets(order, airlineCode)
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	return s.orderRepo.FindByID(id)
+	updated, err := s.findOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	if statusChanged {
+		s.events.Publish(events.OrderStatusChanged{Order: updated.Clone(), From: from, ChangedBy: changedBy})
+	}
+	return updated, nil
 }
 
-func (s *orderService) Cancel(id string) error {
-	order, err := s.orderRepo.FindByID(id)
+func (s *orderService) Cancel(id, changedBy string) error {
+	order, err := s.findOrder(id)
 	if err != nil {
 		return ErrOrderNotFound
 	}
+	if err := checkCancellable(order); err != nil {
+		return err
+	}
 
+	from := order.Status
+	change := statusChange(order, models.OrderCancelled, changedBy)
 	order.Status = models.OrderCancelled
-	return s.orderRepo.Update(order)
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Orders.Update(order); err != nil {
+			return err
+		}
+		if err := repos.Orders.AddChange(change); err != nil {
+			return err
+		}
+		return releaseOrder(repos, order)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.events.Publish(events.OrderStatusChanged{Order: order.Clone(), From: from, ChangedBy: changedBy})
+	return nil
+}
+
+// statusChange records order moving to status
+func statusChange(order *models.Order, status models.OrderStatus, changedBy string) *models.OrderChange {
+	return &models.OrderChange{
+		OrderID:   order.ID,
+		Type:      models.OrderChangeStatus,
+		ChangedBy: changedBy,
+		Before:    map[string]string{"status": string(order.Status)},
+		After:     map[string]string{"status": string(status)},
+	}
+}
+
+// resolveTravelers fills passengers booked by traveler_id from the user's saved travelers.
+// Fields sent inline take precedence over the saved ones.
+func (s *orderService) resolveTravelers(userID string, passengers []PassengerRequest, rules models.PassengerRules, flightDate time.Time) ([]PassengerRequest, error) {
+	var errs []PassengerError
+	resolved := make([]PassengerRequest, len(passengers))
+	for i, p := range passengers {
+		resolved[i] = p
+		if p.TravelerID == "" {
+			continue
+		}
+
+		traveler, err := s.travelerService.GetByID(userID, p.TravelerID)
+		if err != nil {
+			index := i
+			errs = append(errs, PassengerError{Index: &index, Field: "traveler_id", Message: "is not one of your saved travelers"})
+			continue
+		}
+
+		saved := fieldsOfTraveler(traveler)
+		fill := func(value *string, savedValue string) {
+			if *value == "" {
+				*value = savedValue
+			}
+		}
+		fill(&resolved[i].Title, saved.Title)
+		fill(&resolved[i].FullName, saved.FullName)
+		fill(&resolved[i].DateOfBirth, saved.DateOfBirth)
+		fill(&resolved[i].Nationality, saved.Nationality)
+		fill(&resolved[i].DocumentType, saved.DocumentType)
+		fill(&resolved[i].DocumentNumber, saved.DocumentNumber)
+		fill(&resolved[i].DocumentExpiry, saved.DocumentExpiry)
+		fill(&resolved[i].FrequentFlyerNumber, saved.FrequentFlyerNumber)
+
+		if resolved[i].Type == "" {
+			resolved[i].Type = string(models.PassengerAdult)
+			if traveler.DateOfBirth != nil {
+				resolved[i].Type = string(rules.TypeForAge(models.AgeOn(*traveler.DateOfBirth, flightDate)))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, &PassengerValidationError{Errors: errs}
+	}
+	return resolved, nil
+}
+
+// releaseOrder frees the seats a cancelled order holds and gives its promo code use back to the campaign
+func releaseOrder(repos repository.Repositories, order *models.Order) error {
+	if err := repos.SeatAssignments.ReleaseByOrder(order.ID); err != nil {
+		return err
+	}
+	if order.PromotionID == "" {
+		return nil
+	}
+	return repos.Promotions.Release(order.ID)
+}
+
+// checkCancellable allows cancelling pending and confirmed orders only, so an order's seats and
+// promo code use are given back once
+func checkCancellable(order *models.Order) error {
+	if order.Status != models.OrderPending && order.Status != models.OrderConfirmed {
+		return ErrOrderNotCancellable
+	}
+	return nil
+}
+
+// seatLimit is the capacity order has to fit in on its flight
+func (s *orderService) seatLimit(order *models.Order) (repository.SeatLimit, error) {
+	if order.Schedule == nil {
+		return repository.SeatLimit{}, ErrScheduleNotFound
+	}
+	limit := repository.SeatLimit{CabinSeats: order.Schedule.SeatsFor(order.CabinClass)}
+	if order.FareFamilyID != "" {
+		family, err := s.pricingService.FareFamily(order.Environment, order.FareFamilyID)
+		if err != nil {
+			return limit, err
+		}
+		limit.FamilySeats = family.SeatBucket
+	}
+	return limit, nil
+}
+
+// reviveOrder takes back the cabin seats and promo code use a cancelled order gave up, failing
+// when the flight or the code has none left. Run it after the order is written with its new
+// status. Seats picked on the flight were released for good and are not held again.
+func reviveOrder(repos repository.Repositories, order *models.Order, limit repository.SeatLimit) error {
+	if err := repos.Orders.CheckSeatLimit(order, limit); err != nil {
+		return seatLimitError(err)
+	}
+	if order.PromotionID == "" {
+		return nil
+	}
+	promotion, err := repos.Promotions.FindByID(order.PromotionID)
+	if err != nil {
+		return err
+	}
+	customerID := order.UserID
+	if customerID == "" {
+		customerID = guestCustomerID(order.ContactEmail)
+	}
+	return redeemPromotion(repos.Promotions, promotion, customerID, order.ID)
+}
+
+// seatLimitError reports a flight that filled up while an order was being written in the services' terms
+func seatLimitError(err error) error {
+	switch err {
+	case repository.ErrCabinFull:
+		return ErrInsufficientSeats
+	case repository.ErrFareFamilyFull:
+		return ErrFareFamilySoldOut
+	}
+	return err
 }
 
 func (s *orderService) List(page, pageSize int) (*PaginatedResponse, error) {
@@ -193,6 +683,9 @@ func (s *orderService) List(page, pageSize int) (*PaginatedResponse, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := s.withSchedules(orders); err != nil {
+		return nil, err
+	}
 
 	totalPages := int(total) / pageSize
 	if int(total)%pageSize > 0 {
@@ -220,6 +713,9 @@ func (s *orderService) ListByUser(userID string, page, pageSize int) (*Paginated
 	if err != nil {
 		return nil, err
 	}
+	if err := s.withSchedules(orders); err != nil {
+		return nil, err
+	}
 
 	totalPages := int(total) / pageSize
 	if int(total)%pageSize > 0 {
@@ -234,4 +730,3 @@ func (s *orderService) ListByUser(userID string, page, pageSize int) (*Paginated
 		TotalPages: totalPages,
 	}, nil
 }
-
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// Every order gets its own PNR, and the database refuses a second booking under the same one
+func TestOrderCreateAssignsUniquePNRs(t *testing.T) {
+	s := newTestServices(t)
+	var orders []*models.Order
+	seen := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if len(order.PNR) != 6 || seen[order.PNR] {
+			t.Fatalf("order %d got PNR %q, want a new 6-character reference", i, order.PNR)
+		}
+		seen[order.PNR] = true
+		orders = append(orders, order)
+	}
+
+	if err := s.staging.Model(&models.Order{}).Where("id = ?", orders[1].ID).UpdateColumn("pnr", orders[0].PNR).Error; err == nil {
+		t.Error("two orders saved under one PNR")
+	}
+}
+
+// Confirming an order issues every passenger a distinct e-ticket, once
+func TestOrderConfirmIssuesTickets(t *testing.T) {
+	s := newTestServices(t)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Budi Santoso"), adult("Siti Rahayu")))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, p := range order.Passengers {
+		if p.TicketNumber != nil {
+			t.Fatalf("%s ticketed before the order is confirmed", p.FullName)
+		}
+	}
+
+	confirmed, err := s.orders.Update(order.ID, "test", UpdateOrderRequest{Status: string(models.OrderConfirmed)})
+	if err != nil {
+		t.Fatalf("confirm: %v", err)
+	}
+	tickets := make(map[string]bool)
+	for _, p := range confirmed.Passengers {
+		if p.TicketNumber == nil || !strings.HasPrefix(*p.TicketNumber, "GA-") {
+			t.Fatalf("%s ticket = %v, want a GA e-ticket", p.FullName, p.TicketNumber)
+		}
+		tickets[*p.TicketNumber] = true
+	}
+	if len(tickets) != len(confirmed.Passengers) {
+		t.Errorf("%d distinct tickets for %d passengers", len(tickets), len(confirmed.Passengers))
+	}
+
+	// Completing the trip keeps the tickets already issued
+	completed, err := s.orders.Update(order.ID, "test", UpdateOrderRequest{Status: string(models.OrderCompleted)})
+	if err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	for _, p := range completed.Passengers {
+		if p.TicketNumber == nil || !tickets[*p.TicketNumber] {
+			t.Errorf("%s ticket reissued as %v", p.FullName, p.TicketNumber)
+		}
+	}
+
+	itinerary := RenderItineraryText(completed)
+	for _, want := range []string{order.PNR, *completed.Passengers[0].TicketNumber, "Budi Santoso"} {
+		if !strings.Contains(itinerary, want) {
+			t.Errorf("itinerary is missing %q", want)
+		}
+	}
+}
+
+// Guests look a booking up by PNR and contact email, a wrong email reads like an unknown PNR
+func TestOrderGetByPNR(t *testing.T) {
+	s := newTestServices(t)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		pnr     string
+		email   string
+		wantErr error
+	}{
+		{name: "reference and email", pnr: order.PNR, email: "budi@example.com"},
+		{name: "typed loosely", pnr: " " + strings.ToLower(order.PNR) + " ", email: "BUDI@example.com"},
+		{name: "wrong email", pnr: order.PNR, email: "someone@example.com", wantErr: ErrOrderNotFound},
+		{name: "unknown reference", pnr: "ZZZZZZ", email: "budi@example.com", wantErr: ErrOrderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := s.orders.GetByPNR(tt.pnr, tt.email)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && found.ID != order.ID {
+				t.Errorf("found order %s, want %s", found.ID, order.ID)
+			}
+		})
+	}
+}
+
+// Guest orders move to an account only with proof of the booking, never by contact email alone
+func TestOrderClaimGuestOrder(t *testing.T) {
+	s := newTestServices(t)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Budi Santoso"), adult("Siti Rahayu")))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Anyone can register with the contact email, that alone proves nothing
+	if _, err := s.auth.Register(RegisterRequest{Email: "budi@example.com", Password: "secret123", Name: "Not Budi"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if stored, _ := s.orders.GetByID(order.ID); stored.UserID != "" {
+		t.Fatalf("registering with the contact email claimed the order for %q", stored.UserID)
+	}
+
+	tests := []struct {
+		name     string
+		lastName string
+		userID   string
+		wantErr  error
+	}{
+		{name: "wrong surname", lastName: "Wijaya", userID: "user-a", wantErr: ErrOrderNotFound},
+		{name: "first name is not enough", lastName: "Siti", userID: "user-a", wantErr: ErrOrderNotFound},
+		{name: "any passenger's surname", lastName: " rahayu ", userID: "user-a"},
+		{name: "claiming again is a no-op", lastName: "Santoso", userID: "user-a"},
+		{name: "already in another account", lastName: "Santoso", userID: "user-b", wantErr: ErrOrderNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claimed, err := s.orders.ClaimBooking(strings.ToLower(order.PNR), tt.userID, ClaimBookingRequest{LastName: tt.lastName})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && claimed.UserID != tt.userID {
+				t.Errorf("order belongs to %q, want %q", claimed.UserID, tt.userID)
+			}
+		})
+	}
+
+	if _, err := s.orders.Claim(order.ID, "user-b"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("Claim of another account's order: err = %v, want ErrOrderNotFound", err)
+	}
+	if stored, _ := s.orders.GetByID(order.ID); stored.UserID != "user-a" {
+		t.Errorf("order belongs to %q, want user-a", stored.UserID)
+	}
+}
+
+// Only pending and confirmed orders can be cancelled, so seats and promo code uses are given back once
+func TestOrderCancelOnce(t *testing.T) {
+	s := newTestServices(t)
+	var published int
+	events.Subscribe(s.bus, "test", events.Sync, func(e events.OrderStatusChanged) error {
+		published++
+		return nil
+	})
+
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.orders.Cancel(order.ID, "test"); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	if err := s.orders.Cancel(order.ID, "test"); !errors.Is(err, ErrOrderNotCancellable) {
+		t.Errorf("cancelling again: err = %v, want ErrOrderNotCancellable", err)
+	}
+	if _, err := s.orders.Update(order.ID, "test", UpdateOrderRequest{Status: string(models.OrderCancelled)}); err != nil {
+		t.Errorf("Update to the status it has: %v", err)
+	}
+	if published != 1 {
+		t.Errorf("%d status changes published, want 1", published)
+	}
+
+	completed, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.orders.Update(completed.ID, "test", UpdateOrderRequest{Status: string(models.OrderCompleted)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.orders.Cancel(completed.ID, "test"); !errors.Is(err, ErrOrderNotCancellable) {
+		t.Errorf("Cancel of a completed order: err = %v, want ErrOrderNotCancellable", err)
+	}
+	if _, err := s.orders.Update(completed.ID, "test", UpdateOrderRequest{Status: string(models.OrderCancelled)}); !errors.Is(err, ErrOrderNotCancellable) {
+		t.Errorf("Update of a completed order to cancelled: err = %v, want ErrOrderNotCancellable", err)
+	}
+}
+
+// An admin reviving a cancelled order takes its cabin seats back, if the flight still has them
+func TestOrderReviveRechecksSeats(t *testing.T) {
+	s := newTestServices(t)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Budi Santoso"), adult("Siti Rahayu")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.orders.Cancel(order.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+	revived, err := s.orders.Update(order.ID, "test", UpdateOrderRequest{Status: string(models.OrderConfirmed)})
+	if err != nil {
+		t.Fatalf("reviving with seats left: %v", err)
+	}
+	if revived.Status != models.OrderConfirmed || revived.Passengers[0].TicketNumber == nil {
+		t.Errorf("revived order is %s, ticket %v, want confirmed and ticketed", revived.Status, revived.Passengers[0].TicketNumber)
+	}
+
+	// Another booking takes the seats given back while the order is cancelled
+	if err := s.orders.Cancel(order.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+	schedules := repository.NewScheduleRepository(s.staging)
+	schedule, err := schedules.FindByID(order.ScheduleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule.EconomySeats = 2
+	if err := schedules.Update(schedule); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.orders.Create("", "", bookingRequest(order.ScheduleID, 7, adult("Agus Hartono"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.orders.Update(order.ID, "test", UpdateOrderRequest{Status: string(models.OrderPending)}); !errors.Is(err, ErrInsufficientSeats) {
+		t.Fatalf("reviving into a full cabin: err = %v, want ErrInsufficientSeats", err)
+	}
+	if stored, _ := s.orders.GetByID(order.ID); stored.Status != models.OrderCancelled {
+		t.Errorf("order is %s after the failed revival, want it left cancelled", stored.Status)
+	}
+}
+
+// Async subscribers get their own copy of the order, the caller masking documents on the one
+// returned doesn't race with them or change what they read
+func TestOrderEventsCarryACopy(t *testing.T) {
+	s := newTestServices(t)
+	seen := make(chan string, 1)
+	events.Subscribe(s.bus, "test", events.Async, func(e events.OrderCreated) error {
+		seen <- e.Order.Passengers[0].DocumentNumber
+		return nil
+	})
+
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	number := order.Passengers[0].DocumentNumber
+	order.MaskDocuments()
+	if got := <-seen; got != number {
+		t.Errorf("subscriber read document number %q, want %q", got, number)
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+// PassengerError is one booking rule a passenger list breaks.
+// Index points at the offending passenger and is omitted for party-wide rules.
+type PassengerError struct {
+	Index   *int   `json:"index,omitempty"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// PassengerValidationError collects every booking rule a CreateOrderRequest breaks
+type PassengerValidationError struct {
+	Errors []PassengerError
+}
+
+func (e *PassengerValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, pe := range e.Errors {
+		if pe.Index != nil {
+			messages[i] = fmt.Sprintf("passengers[%d].%s: %s", *pe.Index, pe.Field, pe.Message)
+		} else {
+			messages[i] = fmt.Sprintf("%s: %s", pe.Field, pe.Message)
+		}
+	}
+	return "invalid passengers: " + strings.Join(messages, "; ")
+}
+
+// passportValidityMonths is how long past the flight date a passport must stay valid on international routes
+const passportValidityMonths = 6
+
+var (
+	countryCodePattern    = regexp.MustCompile(`^[A-Z]{2}$`)
+	documentNumberPattern = regexp.MustCompile(`^[A-Z0-9]{5,20}$`)
+	frequentFlyerPattern  = regexp.MustCompile(`^[A-Z0-9]{4,20}$`)
+)
+
+// validatePassengers checks passengers against the airline's booking rules and the route's
+// document requirements for a flight on flightDate, and returns them ready to store by index
+func validatePassengers(passengers []PassengerRequest, rules models.PassengerRules, route models.RouteType, flightDate time.Time) ([]models.Passenger, error) {
+	var errs []PassengerError
+	addErr := func(index int, field, message string) {
+		i := index
+		errs = append(errs, PassengerError{Index: &i, Field: field, Message: message})
+	}
+
+	result := make([]models.Passenger, len(passengers))
+	var adults, infants int
+	for i, p := range passengers {
+		passengerType := models.PassengerType(p.Type)
+		switch passengerType {
+		case models.PassengerAdult:
+			adults++
+		case models.PassengerChild:
+		case models.PassengerInfant:
+			infants++
+		default:
+			addErr(i, "type", "must be adult, child or infant")
+			continue
+		}
+
+		result[i] = models.Passenger{
+			Title:    p.Title,
+			FullName: p.FullName,
+			Type:     passengerType,
+		}
+
+		// Children and infants are priced by age, and international tickets carry everyone's date of birth
+		if p.DateOfBirth == "" {
+			if route == models.RouteInternational {
+				addErr(i, "date_of_birth", "is required on international routes")
+			} else if passengerType != models.PassengerAdult {
+				addErr(i, "date_of_birth", "is required for "+p.Type+" passengers")
+			}
+		} else if dob, err := time.Parse("2006-01-02", p.DateOfBirth); err != nil {
+			addErr(i, "date_of_birth", "must be in YYYY-MM-DD format")
+		} else if dob.After(flightDate) {
+			addErr(i, "date_of_birth", "is after the flight date")
+		} else {
+			result[i].DateOfBirth = &dob
+
+			age := models.AgeOn(dob, flightDate)
+			if expected := rules.TypeForAge(age); expected != passengerType {
+				addErr(i, "type", fmt.Sprintf("passenger aged %d on the flight date must travel as %s", age, expected))
+			}
+		}
+
+		validateTravelDocument(p, &result[i], route, flightDate, func(field, message string) {
+			addErr(i, field, message)
+		})
+	}
+
+	if adults == 0 {
+		errs = append(errs, PassengerError{Field: "passengers", Message: "at least one adult is required"})
+	}
+	if infants > adults {
+		errs = append(errs, PassengerError{Field: "passengers", Message: "each infant must travel with an adult"})
+	}
+	if rules.MaxPassengers > 0 && len(passengers) > rules.MaxPassengers {
+		errs = append(errs, PassengerError{Field: "passengers", Message: fmt.Sprintf("at most %d passengers per order", rules.MaxPassengers)})
+	}
+
+	if len(errs) > 0 {
+		return nil, &PassengerValidationError{Errors: errs}
+	}
+	return result, nil
+}
+
+// validateTravelDocument checks a passenger's nationality, document and frequent flyer number
+// and copies the normalised values onto passenger.
+// Domestic routes need an ID card or passport for adults, international routes a passport
+// for everyone that is still valid passportValidityMonths after the flight.
+func validateTravelDocument(p PassengerRequest, passenger *models.Passenger, route models.RouteType, flightDate time.Time, addErr func(field, message string)) {
+	international := route == models.RouteInternational
+
+	if p.Nationality != "" {
+		passenger.Nationality = strings.ToUpper(p.Nationality)
+		if !countryCodePattern.MatchString(passenger.Nationality) {
+			addErr("nationality", "must be a two-letter ISO 3166 country code")
+		}
+	} else if international {
+		addErr("nationality", "is required on international routes")
+	}
+
+	if p.FrequentFlyerNumber != "" {
+		passenger.FrequentFlyerNumber = strings.ToUpper(strings.TrimSpace(p.FrequentFlyerNumber))
+		if !frequentFlyerPattern.MatchString(passenger.FrequentFlyerNumber) {
+			addErr("frequent_flyer_number", "must be 4 to 20 letters or digits")
+		}
+	}
+
+	if p.DocumentType == "" && p.DocumentNumber == "" {
+		if international {
+			addErr("document_number", "a passport is required on international routes")
+		} else if passenger.Type == models.PassengerAdult {
+			addErr("document_number", "an ID card or passport is required for adult passengers")
+		}
+		return
+	}
+
+	passenger.DocumentType = models.DocumentType(p.DocumentType)
+	switch passenger.DocumentType {
+	case models.DocumentPassport:
+	case models.DocumentNationalID:
+		if international {
+			addErr("document_type", "must be passport on international routes")
+		}
+	default:
+		addErr("document_type", "must be passport or national_id")
+	}
+
+	passenger.DocumentNumber = strings.ToUpper(strings.ReplaceAll(p.DocumentNumber, " ", ""))
+	if !documentNumberPattern.MatchString(passenger.DocumentNumber) {
+		addErr("document_number", "must be 5 to 20 letters or digits")
+	}
+
+	if p.DocumentExpiry == "" {
+		if passenger.DocumentType == models.DocumentPassport {
+			addErr("document_expiry", "is required for passports")
+		}
+		return
+	}
+	expiry, err := time.Parse("2006-01-02", p.DocumentExpiry)
+	if err != nil {
+		addErr("document_expiry", "must be in YYYY-MM-DD format")
+		return
+	}
+	passenger.DocumentExpiry = &expiry
+
+	if international && expiry.Before(flightDate.AddDate(0, passportValidityMonths, 0)) {
+		addErr("document_expiry", fmt.Sprintf("must be valid for at least %d months after the flight date", passportValidityMonths))
+	} else if expiry.Before(flightDate) {
+		addErr("document_expiry", "expires before the flight date")
+	}
+}
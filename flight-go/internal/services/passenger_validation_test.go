@@ -0,0 +1,240 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// passengerErrors renders validation errors as "index.field" ("field" for party-wide rules)
+func passengerErrors(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var validationErr *PassengerValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %v, want a PassengerValidationError", err)
+	}
+	var fields []string
+	for _, e := range validationErr.Errors {
+		if e.Index != nil {
+			fields = append(fields, fmt.Sprintf("%d.%s", *e.Index, e.Field))
+		} else {
+			fields = append(fields, e.Field)
+		}
+	}
+	return fields
+}
+
+func sameFields(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestValidatePassengersBookingRules(t *testing.T) {
+	flight := time.Date(2030, 6, 15, 0, 0, 0, 0, time.UTC)
+	bornYearsBefore := func(years int) string {
+		return flight.AddDate(-years, 0, 0).Format("2006-01-02")
+	}
+	withType := func(p PassengerRequest, passengerType, dob string) PassengerRequest {
+		p.Type = passengerType
+		p.DateOfBirth = dob
+		return p
+	}
+	strict := models.PassengerRules{ChildPercent: 50, MaxPassengers: 3, ChildMinAge: 3, AdultMinAge: 14}
+
+	tests := []struct {
+		name       string
+		rules      models.PassengerRules
+		passengers []PassengerRequest
+		want       []string
+	}{
+		{
+			name:       "adult with child and infant",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{adult("Parent"), withType(PassengerRequest{FullName: "Kid"}, "child", bornYearsBefore(5)), withType(PassengerRequest{FullName: "Baby"}, "infant", bornYearsBefore(1))},
+		},
+		{
+			name:       "unknown passenger type",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{adult("Parent"), withType(adult("Pet"), "pet", "")},
+			want:       []string{"1.type"},
+		},
+		{
+			name:       "children need a date of birth",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{adult("Parent"), withType(PassengerRequest{FullName: "Kid"}, "child", "")},
+			want:       []string{"1.date_of_birth"},
+		},
+		{
+			name:       "type must match the age on the flight date",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{adult("Parent"), withType(PassengerRequest{FullName: "Teen"}, "child", bornYearsBefore(12))},
+			want:       []string{"1.type"},
+		},
+		{
+			name:       "airline age bands",
+			rules:      strict,
+			passengers: []PassengerRequest{adult("Parent"), withType(PassengerRequest{FullName: "Teen"}, "child", bornYearsBefore(13)), withType(PassengerRequest{FullName: "Toddler"}, "infant", bornYearsBefore(2))},
+		},
+		{
+			name:       "born after the flight",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{adult("Parent"), withType(PassengerRequest{FullName: "Baby"}, "infant", flight.AddDate(0, 0, 1).Format("2006-01-02"))},
+			want:       []string{"1.date_of_birth"},
+		},
+		{
+			name:       "children cannot travel alone",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{withType(PassengerRequest{FullName: "Kid"}, "child", bornYearsBefore(8))},
+			want:       []string{"passengers"},
+		},
+		{
+			name:       "one infant per adult",
+			rules:      models.DefaultPassengerRules(),
+			passengers: []PassengerRequest{adult("Parent"), withType(PassengerRequest{FullName: "Twin A"}, "infant", bornYearsBefore(1)), withType(PassengerRequest{FullName: "Twin B"}, "infant", bornYearsBefore(1))},
+			want:       []string{"passengers"},
+		},
+		{
+			name:       "party larger than the airline allows",
+			rules:      strict,
+			passengers: []PassengerRequest{adult("A"), adult("B"), adult("C"), adult("D")},
+			want:       []string{"passengers"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passengers, err := validatePassengers(tt.passengers, tt.rules, models.RouteDomestic, flight)
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			if err == nil && len(passengers) != len(tt.passengers) {
+				t.Errorf("got %d passengers, want %d", len(passengers), len(tt.passengers))
+			}
+		})
+	}
+}
+
+func TestValidatePassengerRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   models.PassengerRules
+		wantErr bool
+	}{
+		{name: "defaults", rules: models.DefaultPassengerRules()},
+		{name: "child percent over 100", rules: models.PassengerRules{ChildPercent: 101, MaxPassengers: 9, ChildMinAge: 2, AdultMinAge: 12}, wantErr: true},
+		{name: "negative infant percent", rules: models.PassengerRules{InfantPercent: -1, MaxPassengers: 9, ChildMinAge: 2, AdultMinAge: 12}, wantErr: true},
+		{name: "no passengers allowed", rules: models.PassengerRules{ChildPercent: 75, ChildMinAge: 2, AdultMinAge: 12}, wantErr: true},
+		{name: "age bands overlap", rules: models.PassengerRules{ChildPercent: 75, MaxPassengers: 9, ChildMinAge: 12, AdultMinAge: 12}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePassengerRules(&tt.rules); (err != nil) != tt.wantErr {
+				t.Errorf("validatePassengerRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeeServiceBreakdownPassengerTypes(t *testing.T) {
+	s := newTestServices(t)
+	schedule, env, err := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	quote := &models.FareQuote{CabinClass: models.CabinEconomy, Currency: "IDR", Fare: 800000}
+
+	tests := []struct {
+		name   string
+		counts PassengerCounts
+		rules  models.PassengerRules
+		want   map[models.PassengerType]int64 // unit fare in minor units
+		total  int64
+	}{
+		{
+			name:   "default rules",
+			counts: PassengerCounts{Adults: 2, Children: 1, Infants: 1},
+			rules:  models.DefaultPassengerRules(),
+			want:   map[models.PassengerType]int64{models.PassengerAdult: 80000000, models.PassengerChild: 60000000, models.PassengerInfant: 0},
+			total:  220000000,
+		},
+		{
+			name:   "airline charges infants",
+			counts: PassengerCounts{Adults: 1, Infants: 1},
+			rules:  models.PassengerRules{ChildPercent: 100, InfantPercent: 10},
+			want:   map[models.PassengerType]int64{models.PassengerAdult: 80000000, models.PassengerInfant: 8000000},
+			total:  88000000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown, err := s.fees.Breakdown(env, schedule, quote, tt.counts, tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for passengerType, unit := range tt.want {
+				line, ok := fareLine(breakdown, passengerType)
+				if !ok && unit > 0 {
+					t.Errorf("no %s fare line", passengerType)
+					continue
+				}
+				if line.UnitAmount != unit {
+					t.Errorf("%s fare = %d, want %d", passengerType, line.UnitAmount, unit)
+				}
+			}
+			if breakdown.Total != tt.total {
+				t.Errorf("total = %d, want %d", breakdown.Total, tt.total)
+			}
+		})
+	}
+}
+
+// Airlines' own passenger rules decide how orders are validated and priced
+func TestOrderCreateUsesAirlinePassengerRules(t *testing.T) {
+	s := newTestServices(t)
+	rules := models.PassengerRules{ChildPercent: 40, InfantPercent: 0, MaxPassengers: 2, ChildMinAge: 2, AdultMinAge: 12}
+	airlines := NewAirlineService(repository.NewAirlineRepository(s.staging), s.bus, models.EnvStaging)
+	if _, err := airlines.Update("ga", UpdateAirlineRequest{PassengerRules: &rules}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		passengers []PassengerRequest
+		want       []string
+		wantChild  int64
+	}{
+		{name: "child at the airline's share", passengers: []PassengerRequest{adult("Parent"), child("Kid", 6)}, wantChild: 32000000},
+		{name: "party over the airline's limit", passengers: []PassengerRequest{adult("A"), adult("B"), adult("C")}, want: []string{"passengers"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, tt.passengers...))
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			if line, _ := fareLine(order.PriceBreakdown, models.PassengerChild); line.UnitAmount != tt.wantChild {
+				t.Errorf("child fare = %d, want %d", line.UnitAmount, tt.wantChild)
+			}
+		})
+	}
+}
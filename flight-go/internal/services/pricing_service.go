@@ -0,0 +1,317 @@
+package services
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// PricingService computes dynamic fares from a schedule's base price and the
+// fare rules of the environment the schedule is served from.
+// Search, flight detail and order creation all quote through Quote so they agree.
+type PricingService struct {
+	stagingRuleRepo      repository.FareRuleRepository
+	productionRuleRepo   repository.FareRuleRepository
+	stagingFamilyRepo    repository.FareFamilyRepository
+	productionFamilyRepo repository.FareFamilyRepository
+	orderRepo            repository.OrderRepository
+	currencyService      *CurrencyService
+}
+
+func NewPricingService(
+	stagingRuleRepo repository.FareRuleRepository,
+	productionRuleRepo repository.FareRuleRepository,
+	stagingFamilyRepo repository.FareFamilyRepository,
+	productionFamilyRepo repository.FareFamilyRepository,
+	orderRepo repository.OrderRepository,
+	currencyService *CurrencyService,
+) *PricingService {
+	return &PricingService{
+		stagingRuleRepo:      stagingRuleRepo,
+		productionRuleRepo:   productionRuleRepo,
+		stagingFamilyRepo:    stagingFamilyRepo,
+		productionFamilyRepo: productionFamilyRepo,
+		orderRepo:            orderRepo,
+		currencyService:      currencyService,
+	}
+}
+
+// Quote prices one adult seat on schedule in cabin for flightDate
+// An empty currency quotes in the schedule's own currency.
+func (s *PricingService) Quote(env models.Environment, schedule *models.Schedule, cabin models.CabinClass, flightDate time.Time, currency string) (*models.FareQuote, error) {
+	rules, err := s.rulesFor(env)
+	if err != nil {
+		return nil, err
+	}
+	return s.quote(env, rules, schedule, cabin, flightDate, currency)
+}
+
+// QuoteSchedules sets Fare on every schedule, loading the environment's rules once
+// envFor reports which environment each schedule was read from.
+func (s *PricingService) QuoteSchedules(schedules []models.Schedule, cabin models.CabinClass, flightDate time.Time, currency string, envFor func(*models.Schedule) models.Environment) error {
+	rulesByEnv := make(map[models.Environment][]models.FareRule)
+	for i := range schedules {
+		env := envFor(&schedules[i])
+		rules, ok := rulesByEnv[env]
+		if !ok {
+			var err error
+			if rules, err = s.rulesFor(env); err != nil {
+				return err
+			}
+			rulesByEnv[env] = rules
+		}
+
+		quote, err := s.quote(env, rules, &schedules[i], cabin, flightDate, currency)
+		if err != nil {
+			return err
+		}
+		schedules[i].Fare = quote
+	}
+	return nil
+}
+
+// QuoteFareFamily quotes one adult seat in a fare family of the cabin.
+// An empty fareFamilyID picks the cabin's first family; cabins without families
+// are quoted at the plain cabin fare and a nil family is returned.
+func (s *PricingService) QuoteFareFamily(env models.Environment, schedule *models.Schedule, cabin models.CabinClass, flightDate time.Time, currency string, fareFamilyID string) (*models.FareQuote, *models.FareFamily, error) {
+	quote, err := s.Quote(env, schedule, cabin, flightDate, currency)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	families, err := s.familiesFor(env).ListActiveForCabin(schedule.AirlineID, cabin)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(families) == 0 {
+		if fareFamilyID != "" {
+			return nil, nil, ErrFareFamilyNotFound
+		}
+		return quote, nil, nil
+	}
+
+	family := &families[0]
+	if fareFamilyID != "" {
+		family = nil
+		for i := range families {
+			if families[i].ID == fareFamilyID {
+				family = &families[i]
+				break
+			}
+		}
+		if family == nil {
+			return nil, nil, ErrFareFamilyNotFound
+		}
+	}
+
+	applyFareFamily(quote, family)
+	return quote, family, nil
+}
+
+// FareFamily returns a fare family by ID, whether or not it is still on sale
+func (s *PricingService) FareFamily(env models.Environment, id string) (*models.FareFamily, error) {
+	family, err := s.familiesFor(env).FindByID(id)
+	if err != nil {
+		return nil, ErrFareFamilyNotFound
+	}
+	return family, nil
+}
+
+// FareFamilyOffers prices every active fare family of the cabin off quote and
+// reports the seats each can still sell on the quote's flight date
+func (s *PricingService) FareFamilyOffers(env models.Environment, schedule *models.Schedule, cabin models.CabinClass, flightDate time.Time, quote *models.FareQuote) ([]models.FareFamilyOffer, error) {
+	families, err := s.familiesFor(env).ListActiveForCabin(schedule.AirlineID, cabin)
+	if err != nil {
+		return nil, err
+	}
+
+	cabinLeft, err := s.cabinSeatsLeft(env, schedule, cabin, flightDate)
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]models.FareFamilyOffer, 0, len(families))
+	for _, family := range families {
+		seatsLeft, err := s.familySeatsLeft(env, schedule, &family, flightDate, cabinLeft)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, models.FareFamilyOffer{
+			FareFamily: family,
+			Fare:       quote.Fare + fareFamilyAmount(quote, &family),
+			SeatsLeft:  seatsLeft,
+		})
+	}
+	return offers, nil
+}
+
+// CheckAvailability reports whether seats more seat-occupying passengers fit in
+// the cabin of a schedule of env and, when family is set, in the family's seat bucket
+func (s *PricingService) CheckAvailability(env models.Environment, schedule *models.Schedule, cabin models.CabinClass, flightDate time.Time, family *models.FareFamily, seats int) error {
+	cabinLeft, err := s.cabinSeatsLeft(env, schedule, cabin, flightDate)
+	if err != nil {
+		return err
+	}
+	if seats > cabinLeft {
+		return ErrInsufficientSeats
+	}
+
+	if family != nil {
+		familyLeft, err := s.familySeatsLeft(env, schedule, family, flightDate, cabinLeft)
+		if err != nil {
+			return err
+		}
+		if seats > familyLeft {
+			return ErrFareFamilySoldOut
+		}
+	}
+	return nil
+}
+
+func (s *PricingService) rulesFor(env models.Environment) ([]models.FareRule, error) {
+	if env == models.EnvProduction {
+		return s.productionRuleRepo.ListActive()
+	}
+	return s.stagingRuleRepo.ListActive()
+}
+
+func (s *PricingService) familiesFor(env models.Environment) repository.FareFamilyRepository {
+	if env == models.EnvProduction {
+		return s.productionFamilyRepo
+	}
+	return s.stagingFamilyRepo
+}
+
+func (s *PricingService) cabinSeatsLeft(env models.Environment, schedule *models.Schedule, cabin models.CabinClass, flightDate time.Time) (int, error) {
+	booked, err := s.orderRepo.CountBookedSeats(env, schedule.ID, flightDate, cabin)
+	if err != nil {
+		return 0, err
+	}
+	return max(schedule.SeatsFor(cabin)-int(booked), 0), nil
+}
+
+// familySeatsLeft caps a family's remaining bucket by what is left in the cabin
+func (s *PricingService) familySeatsLeft(env models.Environment, schedule *models.Schedule, family *models.FareFamily, flightDate time.Time, cabinLeft int) (int, error) {
+	if family.SeatBucket == 0 {
+		return cabinLeft, nil
+	}
+	booked, err := s.orderRepo.CountFareFamilySeats(env, schedule.ID, flightDate, family.ID)
+	if err != nil {
+		return 0, err
+	}
+	return min(max(family.SeatBucket-int(booked), 0), cabinLeft), nil
+}
+
+// applyFareFamily adds the family's step on top of the rule-adjusted fare
+func applyFareFamily(quote *models.FareQuote, family *models.FareFamily) {
+	amount := fareFamilyAmount(quote, family)
+	quote.FareFamily = &models.FareFamilyPrice{
+		ID:         family.ID,
+		Code:       family.Code,
+		Name:       family.Name,
+		Multiplier: family.PriceMultiplier,
+		Amount:     amount,
+	}
+	quote.Fare += amount
+}
+
+func fareFamilyAmount(quote *models.FareQuote, family *models.FareFamily) float64 {
+	return models.RoundToCurrency(quote.Fare*(family.PriceMultiplier-1), quote.Currency)
+}
+
+func (s *PricingService) quote(env models.Environment, rules []models.FareRule, schedule *models.Schedule, cabin models.CabinClass, flightDate time.Time, currency string) (*models.FareQuote, error) {
+	booked, err := s.orderRepo.CountBookedSeats(env, schedule.ID, flightDate, cabin)
+	if err != nil {
+		return nil, err
+	}
+
+	var loadFactor float64
+	if seats := schedule.SeatsFor(cabin); seats > 0 {
+		loadFactor = math.Round(float64(booked)/float64(seats)*10000) / 100
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	daysBefore := int(flightDate.UTC().Truncate(24*time.Hour).Sub(today).Hours() / 24)
+
+	weekday := int(flightDate.Weekday())
+	if weekday == 0 {
+		weekday = 7 // Convert Sunday from 0 to 7
+	}
+
+	baseFare := schedule.PriceFor(cabin)
+	quote := &models.FareQuote{
+		Environment: env,
+		CabinClass:  cabin,
+		FlightDate:  flightDate.Format("2006-01-02"),
+		Currency:    schedule.PriceCurrency(),
+		BaseFare:    baseFare,
+		Adjustments: []models.FareAdjustment{},
+		Fare:        baseFare,
+		LoadFactor:  loadFactor,
+		DaysBefore:  daysBefore,
+	}
+
+	for _, rule := range rules {
+		if rule.AirlineID != "" && rule.AirlineID != schedule.AirlineID {
+			continue
+		}
+		if rule.CabinClass != "" && rule.CabinClass != cabin {
+			continue
+		}
+
+		var matched bool
+		switch rule.Type {
+		case models.FareRuleLoadFactor:
+			matched = loadFactor >= rule.MinValue && loadFactor <= rule.MaxValue
+		case models.FareRuleDaysBefore:
+			matched = float64(daysBefore) >= rule.MinValue && float64(daysBefore) <= rule.MaxValue
+		case models.FareRuleDayOfWeek:
+			matched = containsDay(rule.DaysOfWeek, weekday)
+		case models.FareRuleTimeOfDay:
+			matched = inTimeBand(schedule.DepartureTime, rule.TimeFrom, rule.TimeTo)
+		}
+		if !matched {
+			continue
+		}
+
+		// Adjustments compound in priority order and are rounded to the currency's minor unit
+		amount := models.RoundToCurrency(quote.Fare*rule.AdjustmentPercent/100, quote.Currency)
+		quote.Fare += amount
+		quote.Adjustments = append(quote.Adjustments, models.FareAdjustment{
+			RuleID:  rule.ID,
+			Name:    rule.Name,
+			Type:    rule.Type,
+			Percent: rule.AdjustmentPercent,
+			Amount:  amount,
+		})
+	}
+
+	// Fare families are applied after conversion, so their step is rounded in the quoted currency
+	if err := s.currencyService.ConvertQuote(quote, currency, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// containsDay reports whether a "1,2,3" style day list contains day
+func containsDay(days string, day int) bool {
+	for _, d := range strings.Split(days, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(d)); err == nil && n == day {
+			return true
+		}
+	}
+	return false
+}
+
+// inTimeBand reports whether HH:MM t falls in [from, to), wrapping past midnight when to < from
+func inTimeBand(t, from, to string) bool {
+	if from <= to {
+		return t >= from && t < to
+	}
+	return t >= from || t < to
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"gorm.io/gorm"
+)
+
+func TestInTimeBand(t *testing.T) {
+	tests := []struct {
+		t, from, to string
+		want        bool
+	}{
+		{"06:00", "05:00", "09:00", true},
+		{"09:00", "05:00", "09:00", false}, // to is exclusive
+		{"05:00", "05:00", "09:00", true},
+		{"23:30", "22:00", "02:00", true}, // wraps past midnight
+		{"01:59", "22:00", "02:00", true},
+		{"02:00", "22:00", "02:00", false},
+		{"12:00", "22:00", "02:00", false},
+	}
+
+	for _, tt := range tests {
+		if got := inTimeBand(tt.t, tt.from, tt.to); got != tt.want {
+			t.Errorf("inTimeBand(%s, %s, %s) = %v, want %v", tt.t, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestContainsDay(t *testing.T) {
+	tests := []struct {
+		days string
+		day  int
+		want bool
+	}{
+		{"1,2,3", 2, true},
+		{"1, 2, 3", 3, true},
+		{"6,7", 1, false},
+		{"", 1, false},
+		{"x,7", 7, true},
+	}
+
+	for _, tt := range tests {
+		if got := containsDay(tt.days, tt.day); got != tt.want {
+			t.Errorf("containsDay(%q, %d) = %v, want %v", tt.days, tt.day, got, tt.want)
+		}
+	}
+}
+
+// schedule-cgk-dps-ga-001 departs 06:00 at an economy base fare of IDR 800,000
+func TestPricingServiceQuote(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     []models.FareRule
+		booked    int // economy passengers already booked on the flight
+		wantFare  float64
+		wantRules []string
+	}{
+		{
+			name:     "no rules quotes the base fare",
+			wantFare: 800000,
+		},
+		{
+			name: "matching days before",
+			rules: []models.FareRule{
+				{Name: "last minute", Type: models.FareRuleDaysBefore, MinValue: 0, MaxValue: 14, AdjustmentPercent: 20},
+				{Name: "early bird", Type: models.FareRuleDaysBefore, MinValue: 30, MaxValue: 365, AdjustmentPercent: -15},
+			},
+			wantFare:  960000,
+			wantRules: []string{"last minute"},
+		},
+		{
+			name: "adjustments compound in priority order",
+			rules: []models.FareRule{
+				{Name: "weekday discount", Type: models.FareRuleDayOfWeek, DaysOfWeek: "1,2,3,4,5,6,7", AdjustmentPercent: -10, Priority: 2},
+				{Name: "last minute", Type: models.FareRuleDaysBefore, MinValue: 0, MaxValue: 14, AdjustmentPercent: 20, Priority: 1},
+			},
+			wantFare:  864000,
+			wantRules: []string{"last minute", "weekday discount"},
+		},
+		{
+			name: "time of day bands",
+			rules: []models.FareRule{
+				{Name: "morning peak", Type: models.FareRuleTimeOfDay, TimeFrom: "05:00", TimeTo: "09:00", AdjustmentPercent: 5},
+				{Name: "red eye", Type: models.FareRuleTimeOfDay, TimeFrom: "22:00", TimeTo: "02:00", AdjustmentPercent: -30},
+			},
+			wantFare:  840000,
+			wantRules: []string{"morning peak"},
+		},
+		{
+			name: "other airlines and cabins are skipped",
+			rules: []models.FareRule{
+				{Name: "lion only", Type: models.FareRuleDaysBefore, AirlineID: "jt", MinValue: 0, MaxValue: 365, AdjustmentPercent: 50},
+				{Name: "business only", Type: models.FareRuleDaysBefore, CabinClass: models.CabinBusiness, MinValue: 0, MaxValue: 365, AdjustmentPercent: 50},
+				{Name: "garuda economy", Type: models.FareRuleDaysBefore, AirlineID: "ga", CabinClass: models.CabinEconomy, MinValue: 0, MaxValue: 365, AdjustmentPercent: 10},
+			},
+			wantFare:  880000,
+			wantRules: []string{"garuda economy"},
+		},
+		{
+			name: "load factor counts booked seats",
+			rules: []models.FareRule{
+				{Name: "filling up", Type: models.FareRuleLoadFactor, MinValue: 1, MaxValue: 100, AdjustmentPercent: 25},
+			},
+			booked:    2, // 2 of 150 seats is 1.33%
+			wantFare:  1000000,
+			wantRules: []string{"filling up"},
+		},
+		{
+			name: "load factor below the band",
+			rules: []models.FareRule{
+				{Name: "filling up", Type: models.FareRuleLoadFactor, MinValue: 1, MaxValue: 100, AdjustmentPercent: 25},
+			},
+			wantFare: 800000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			createFareRules(t, s.staging, tt.rules)
+
+			if tt.booked > 0 {
+				var passengers []PassengerRequest
+				for i := 0; i < tt.booked; i++ {
+					passengers = append(passengers, adult("Passenger"))
+				}
+				if _, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, passengers...)); err != nil {
+					t.Fatalf("book seats: %v", err)
+				}
+			}
+
+			schedule, env, err := s.schedules.Resolve("", "schedule-cgk-dps-ga-001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			date, _ := time.Parse("2006-01-02", flightDate(7))
+			quote, err := s.pricing.Quote(env, schedule, models.CabinEconomy, date, "")
+			if err != nil {
+				t.Fatalf("Quote: %v", err)
+			}
+
+			if quote.Fare != tt.wantFare {
+				t.Errorf("fare = %v, want %v", quote.Fare, tt.wantFare)
+			}
+			if quote.BaseFare != 800000 || quote.Currency != "IDR" {
+				t.Errorf("base fare = %v %s, want 800000 IDR", quote.BaseFare, quote.Currency)
+			}
+			var applied []string
+			for _, adjustment := range quote.Adjustments {
+				applied = append(applied, adjustment.Name)
+			}
+			if len(applied) != len(tt.wantRules) {
+				t.Fatalf("applied rules %v, want %v", applied, tt.wantRules)
+			}
+			for i := range applied {
+				if applied[i] != tt.wantRules[i] {
+					t.Errorf("applied rules %v, want %v", applied, tt.wantRules)
+				}
+			}
+		})
+	}
+}
+
+// Orders must be priced with the fare rules of the environment their schedule is sold from,
+// the same quote search and flight detail show the user
+func TestOrderCreateQuotesResolvedEnvironment(t *testing.T) {
+	s := newTestServices(t)
+	s.whitelistUser(t, "partner@example.com", "ga")
+	createFareRules(t, s.production, []models.FareRule{
+		{Name: "production surcharge", Type: models.FareRuleDaysBefore, MinValue: 0, MaxValue: 365, AdjustmentPercent: 50},
+	})
+
+	tests := []struct {
+		name     string
+		email    string
+		wantEnv  models.Environment
+		wantFare float64
+	}{
+		{name: "guest books staging", email: "", wantEnv: models.EnvStaging, wantFare: 800000},
+		{name: "whitelisted user books production", email: "partner@example.com", wantEnv: models.EnvProduction, wantFare: 1200000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := s.orders.Create("", tt.email, bookingRequest("schedule-cgk-dps-ga-001", 7))
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if order.Environment != tt.wantEnv {
+				t.Errorf("environment = %s, want %s", order.Environment, tt.wantEnv)
+			}
+			if order.FareQuote.Fare != tt.wantFare || order.FareQuote.Environment != tt.wantEnv {
+				t.Errorf("quoted %v from %s, want %v from %s", order.FareQuote.Fare, order.FareQuote.Environment, tt.wantFare, tt.wantEnv)
+			}
+
+			detail, err := s.search.GetFlightDetail(tt.email, "schedule-cgk-dps-ga-001", "economy", flightDate(7), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if detail.Fare.Fare != order.FareQuote.Fare {
+				t.Errorf("order fare %v differs from flight detail fare %v", order.FareQuote.Fare, detail.Fare.Fare)
+			}
+		})
+	}
+}
+
+func createFareRules(t *testing.T, db *gorm.DB, rules []models.FareRule) {
+	t.Helper()
+	for i := range rules {
+		rules[i].IsActive = true
+		if err := db.Create(&rules[i]).Error; err != nil {
+			t.Fatalf("create fare rule %s: %v", rules[i].Name, err)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrPromotionNotFound      = errors.New("promotion not found")
+	ErrInvalidPromotion       = errors.New("invalid promotion")
+	ErrPromoCodeExists        = errors.New("promo code already exists")
+	ErrInvalidPromoCode       = errors.New("promo code not found")
+	ErrPromoCodeExpired       = errors.New("promo code is not valid at this time")
+	ErrPromoCodeNotApplicable = errors.New("promo code does not apply to this flight")
+	ErrPromoMinSpend          = errors.New("order total is below the promo code's minimum spend")
+	ErrPromoCodeUsedUp        = errors.New("promo code usage limit reached")
+)
+
+type PromotionService interface {
+	Create(req CreatePromotionRequest) (*models.Promotion, error)
+	GetByID(id string) (*models.Promotion, error)
+	Update(id string, req UpdatePromotionRequest) (*models.Promotion, error)
+	Delete(id string) error
+	List(page, pageSize int) (*PaginatedResponse, error)
+	Validate(userID, userEmail string, req ValidatePromoCodeRequest) (*PromoCodeValidation, error)
+	Apply(userID, code string, schedule *models.Schedule, cabin models.CabinClass, breakdown *models.PriceBreakdown) (*models.Promotion, error)
+}
+
+type CreatePromotionRequest struct {
+	Code                 string  `json:"code" binding:"required"`
+	Name                 string  `json:"name" binding:"required"`
+	Description          string  `json:"description"`
+	DiscountType         string  `json:"discount_type" binding:"required"`  // percent, fixed
+	DiscountValue        float64 `json:"discount_value" binding:"required"` // Percent, or amount in currency
+	Currency             string  `json:"currency"`                          // Of fixed discounts and min_spend, defaults to IDR
+	MinSpend             float64 `json:"min_spend"`
+	AirlineID            string  `json:"airline_id"`
+	OriginAirportID      string  `json:"origin_airport_id"`
+	DestinationAirportID string  `json:"destination_airport_id"`
+	CabinClass           string  `json:"cabin_class"`
+	ValidFrom            string  `json:"valid_from" binding:"required"`  // RFC3339 or YYYY-MM-DD
+	ValidUntil           string  `json:"valid_until" binding:"required"` // RFC3339 or YYYY-MM-DD (inclusive)
+	UsageLimit           int     `json:"usage_limit"`
+	PerUserLimit         int     `json:"per_user_limit"`
+	IsActive             *bool   `json:"is_active"`
+}
+
+type UpdatePromotionRequest struct {
+	Name                 string   `json:"name"`
+	Description          *string  `json:"description"`
+	DiscountType         string   `json:"discount_type"`
+	DiscountValue        *float64 `json:"discount_value"`
+	Currency             string   `json:"currency"`
+	MinSpend             *float64 `json:"min_spend"`
+	AirlineID            *string  `json:"airline_id"`
+	OriginAirportID      *string  `json:"origin_airport_id"`
+	DestinationAirportID *string  `json:"destination_airport_id"`
+	CabinClass           *string  `json:"cabin_class"`
+	ValidFrom            string   `json:"valid_from"`
+	ValidUntil           string   `json:"valid_until"`
+	UsageLimit           *int     `json:"usage_limit"`
+	PerUserLimit         *int     `json:"per_user_limit"`
+	IsActive             *bool    `json:"is_active"`
+}
+
+type ValidatePromoCodeRequest struct {
+	Code       string `json:"code" binding:"required"`
+	ScheduleID string `json:"schedule_id" binding:"required"`
+	CabinClass string `json:"cabin_class" binding:"required"`
+	Amount     int64  `json:"amount" binding:"required,min=1"` // Order total before discount, in minor units of currency
+	Currency   string `json:"currency"`                        // Defaults to the schedule's currency
+}
+
+// PromoCodeValidation is the discount a promo code would give an order
+type PromoCodeValidation struct {
+	Code         string              `json:"code"`
+	Name         string              `json:"name"`
+	DiscountType models.DiscountType `json:"discount_type"`
+	Discount     int64               `json:"discount"` // In minor units of Currency
+	Total        int64               `json:"total"`    // Amount after discount
+	Currency     string              `json:"currency"`
+}
+
+type promotionService struct {
+	promotionRepo   repository.PromotionRepository
+	schedules       *ScheduleResolver
+	currencyService *CurrencyService
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepository, schedules *ScheduleResolver, currencyService *CurrencyService) PromotionService {
+	return &promotionService{
+		promotionRepo:   promotionRepo,
+		schedules:       schedules,
+		currencyService: currencyService,
+	}
+}
+
+func (s *promotionService) Create(req CreatePromotionRequest) (*models.Promotion, error) {
+	promotion := &models.Promotion{
+		Code:                 normalizePromoCode(req.Code),
+		Name:                 req.Name,
+		Description:          req.Description,
+		DiscountType:         models.DiscountType(req.DiscountType),
+		DiscountValue:        req.DiscountValue,
+		Currency:             models.DefaultCurrency,
+		MinSpend:             req.MinSpend,
+		AirlineID:            req.AirlineID,
+		OriginAirportID:      req.OriginAirportID,
+		DestinationAirportID: req.DestinationAirportID,
+		CabinClass:           models.CabinClass(req.CabinClass),
+		UsageLimit:           req.UsageLimit,
+		PerUserLimit:         req.PerUserLimit,
+		IsActive:             true,
+	}
+
+	if req.Currency != "" {
+		promotion.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+
+	var err error
+	if promotion.ValidFrom, err = parsePromotionTime(req.ValidFrom, false); err != nil {
+		return nil, ErrInvalidPromotion
+	}
+	if promotion.ValidUntil, err = parsePromotionTime(req.ValidUntil, true); err != nil {
+		return nil, ErrInvalidPromotion
+	}
+
+	if err := validatePromotion(promotion); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.promotionRepo.FindByCode(promotion.Code); err == nil {
+		return nil, ErrPromoCodeExists
+	}
+
+	if err := s.promotionRepo.Create(promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionService) GetByID(id string) (*models.Promotion, error) {
+	promotion, err := s.promotionRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPromotionNotFound
+	}
+	return promotion, nil
+}
+
+func (s *promotionService) Update(id string, req UpdatePromotionRequest) (*models.Promotion, error) {
+	promotion, err := s.promotionRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrPromotionNotFound
+	}
+
+	if req.Name != "" {
+		promotion.Name = req.Name
+	}
+	if req.Description != nil {
+		promotion.Description = *req.Description
+	}
+	if req.DiscountType != "" {
+		promotion.DiscountType = models.DiscountType(req.DiscountType)
+	}
+	if req.DiscountValue != nil {
+		promotion.DiscountValue = *req.DiscountValue
+	}
+	if req.Currency != "" {
+		promotion.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.MinSpend != nil {
+		promotion.MinSpend = *req.MinSpend
+	}
+	if req.AirlineID != nil {
+		promotion.AirlineID = *req.AirlineID
+	}
+	if req.OriginAirportID != nil {
+		promotion.OriginAirportID = *req.OriginAirportID
+	}
+	if req.DestinationAirportID != nil {
+		promotion.DestinationAirportID = *req.DestinationAirportID
+	}
+	if req.CabinClass != nil {
+		promotion.CabinClass = models.CabinClass(*req.CabinClass)
+	}
+	if req.ValidFrom != "" {
+		if promotion.ValidFrom, err = parsePromotionTime(req.ValidFrom, false); err != nil {
+			return nil, ErrInvalidPromotion
+		}
+	}
+	if req.ValidUntil != "" {
+		if promotion.ValidUntil, err = parsePromotionTime(req.ValidUntil, true); err != nil {
+			return nil, ErrInvalidPromotion
+		}
+	}
+	if req.UsageLimit != nil {
+		promotion.UsageLimit = *req.UsageLimit
+	}
+	if req.PerUserLimit != nil {
+		promotion.PerUserLimit = *req.PerUserLimit
+	}
+	if req.IsActive != nil {
+		promotion.IsActive = *req.IsActive
+	}
+
+	if err := validatePromotion(promotion); err != nil {
+		return nil, err
+	}
+
+	if err := s.promotionRepo.Update(promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+func (s *promotionService) Delete(id string) error {
+	_, err := s.promotionRepo.FindByID(id)
+	if err != nil {
+		return ErrPromotionNotFound
+	}
+
+	return s.promotionRepo.Delete(id)
+}
+
+func (s *promotionService) List(page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	promotions, total, err := s.promotionRepo.List(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       promotions,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Validate reports the discount a code would give an order without redeeming it
+func (s *promotionService) Validate(userID, userEmail string, req ValidatePromoCodeRequest) (*PromoCodeValidation, error) {
+	schedule, _, err := s.schedules.Resolve(userEmail, req.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := schedule.PriceCurrency()
+	if req.Currency != "" {
+		currency = strings.ToUpper(req.Currency)
+	}
+	if !models.IsSupportedCurrency(currency) {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	promotion, discount, err := s.check(userID, req.Code, schedule, toCabinClass(req.CabinClass), req.Amount, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PromoCodeValidation{
+		Code:         promotion.Code,
+		Name:         promotion.Name,
+		DiscountType: promotion.DiscountType,
+		Discount:     discount,
+		Total:        req.Amount - discount,
+		Currency:     currency,
+	}, nil
+}
+
+// Apply checks code against an order and adds its discount to the breakdown.
+// The code is not redeemed until redeemPromotion.
+func (s *promotionService) Apply(userID, code string, schedule *models.Schedule, cabin models.CabinClass, breakdown *models.PriceBreakdown) (*models.Promotion, error) {
+	promotion, discount, err := s.check(userID, code, schedule, cabin, breakdown.Total, breakdown.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown.Add(models.PriceLineItem{
+		Type:       models.ChargeDiscount,
+		Name:       promotion.Name,
+		Quantity:   1,
+		UnitAmount: -discount,
+		Amount:     -discount,
+	})
+	return promotion, nil
+}
+
+// redeemPromotion counts one use of promotion against its limits for orderID, writing through
+// promotionRepo so it can take part in the transaction that saves the order
+func redeemPromotion(promotionRepo repository.PromotionRepository, promotion *models.Promotion, userID, orderID string) error {
+	redeemed, err := promotionRepo.Redeem(&models.PromotionRedemption{
+		PromotionID: promotion.ID,
+		UserID:      userID,
+		OrderID:     orderID,
+	}, promotion.PerUserLimit)
+	if err != nil {
+		return err
+	}
+	if !redeemed {
+		return ErrPromoCodeUsedUp
+	}
+	return nil
+}
+
+// check looks up code and works out its discount on amount, in minor units of currency.
+// Usage limits are checked here for a helpful error, redeemPromotion enforces them atomically.
+func (s *promotionService) check(userID, code string, schedule *models.Schedule, cabin models.CabinClass, amount int64, currency string) (*models.Promotion, int64, error) {
+	promotion, err := s.promotionRepo.FindByCode(normalizePromoCode(code))
+	if err != nil || !promotion.IsActive {
+		return nil, 0, ErrInvalidPromoCode
+	}
+
+	now := time.Now()
+	if now.Before(promotion.ValidFrom) || now.After(promotion.ValidUntil) {
+		return nil, 0, ErrPromoCodeExpired
+	}
+	if !promotion.AppliesTo(schedule, cabin) {
+		return nil, 0, ErrPromoCodeNotApplicable
+	}
+
+	if promotion.UsageLimit > 0 && promotion.UsedCount >= promotion.UsageLimit {
+		return nil, 0, ErrPromoCodeUsedUp
+	}
+	if promotion.PerUserLimit > 0 {
+		used, err := s.promotionRepo.CountUserRedemptions(promotion.ID, userID)
+		if err != nil {
+			return nil, 0, err
+		}
+		if used >= int64(promotion.PerUserLimit) {
+			return nil, 0, ErrPromoCodeUsedUp
+		}
+	}
+
+	// Fixed amounts are set in the promotion's currency
+	rate := 1.0
+	if promotion.MinSpend > 0 || promotion.DiscountType == models.DiscountFixed {
+		if rate, err = s.currencyService.Rate(promotion.Currency, currency, now); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if amount < models.ToMinorUnits(promotion.MinSpend*rate, currency) {
+		return nil, 0, ErrPromoMinSpend
+	}
+
+	var discount int64
+	if promotion.DiscountType == models.DiscountPercent {
+		discount = int64(math.Round(float64(amount) * promotion.DiscountValue / 100))
+	} else {
+		discount = models.ToMinorUnits(promotion.DiscountValue*rate, currency)
+	}
+
+	return promotion, min(discount, amount), nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// parsePromotionTime parses an RFC3339 time or a date. A date ending a validity
+// window runs to the end of that day.
+func parsePromotionTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
+// validatePromotion checks the discount, scope and validity window
+func validatePromotion(promotion *models.Promotion) error {
+	if promotion.Code == "" || strings.ContainsAny(promotion.Code, " \t") {
+		return ErrInvalidPromotion
+	}
+
+	switch promotion.CabinClass {
+	case "", models.CabinEconomy, models.CabinBusiness, models.CabinFirst:
+	default:
+		return ErrInvalidPromotion
+	}
+
+	if !models.IsSupportedCurrency(promotion.Currency) {
+		return ErrInvalidPromotion
+	}
+
+	switch promotion.DiscountType {
+	case models.DiscountPercent:
+		if promotion.DiscountValue <= 0 || promotion.DiscountValue > 100 {
+			return ErrInvalidPromotion
+		}
+	case models.DiscountFixed:
+		if promotion.DiscountValue <= 0 {
+			return ErrInvalidPromotion
+		}
+	default:
+		return ErrInvalidPromotion
+	}
+
+	if promotion.MinSpend < 0 || promotion.UsageLimit < 0 || promotion.PerUserLimit < 0 {
+		return ErrInvalidPromotion
+	}
+	if !promotion.ValidUntil.After(promotion.ValidFrom) {
+		return ErrInvalidPromotion
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+func TestValidatePromotion(t *testing.T) {
+	from := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	valid := func() models.Promotion {
+		return models.Promotion{Code: "HEMAT10", DiscountType: models.DiscountPercent, DiscountValue: 10, Currency: "IDR", ValidFrom: from, ValidUntil: from.AddDate(0, 1, 0)}
+	}
+
+	tests := []struct {
+		name    string
+		change  func(p *models.Promotion)
+		wantErr bool
+	}{
+		{name: "valid", change: func(p *models.Promotion) {}},
+		{name: "code with spaces", change: func(p *models.Promotion) { p.Code = "HEMAT 10" }, wantErr: true},
+		{name: "percent over 100", change: func(p *models.Promotion) { p.DiscountValue = 101 }, wantErr: true},
+		{name: "fixed discount", change: func(p *models.Promotion) { p.DiscountType = models.DiscountFixed; p.DiscountValue = 50000 }},
+		{name: "unknown discount type", change: func(p *models.Promotion) { p.DiscountType = "bogo" }, wantErr: true},
+		{name: "unknown cabin", change: func(p *models.Promotion) { p.CabinClass = "premium" }, wantErr: true},
+		{name: "negative usage limit", change: func(p *models.Promotion) { p.UsageLimit = -1 }, wantErr: true},
+		{name: "window ends before it starts", change: func(p *models.Promotion) { p.ValidUntil = from.AddDate(0, 0, -1) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotion := valid()
+			tt.change(&promotion)
+			if err := validatePromotion(&promotion); (err != nil) != tt.wantErr {
+				t.Errorf("validatePromotion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// createPromotion adds a promo code valid from yesterday for a month, overriding fields with req
+func createPromotion(t *testing.T, s *testServices, req CreatePromotionRequest) *models.Promotion {
+	t.Helper()
+	if req.Name == "" {
+		req.Name = req.Code
+	}
+	if req.DiscountType == "" {
+		req.DiscountType = "percent"
+		req.DiscountValue = 10
+	}
+	if req.ValidFrom == "" {
+		req.ValidFrom = flightDate(-1)
+		req.ValidUntil = flightDate(30)
+	}
+	promotion, err := s.promotions.Create(req)
+	if err != nil {
+		t.Fatalf("create promotion %s: %v", req.Code, err)
+	}
+	return promotion
+}
+
+func TestPromotionServiceValidate(t *testing.T) {
+	s := newTestServices(t)
+	inactive := false
+	createPromotion(t, s, CreatePromotionRequest{Code: "HEMAT10"})
+	createPromotion(t, s, CreatePromotionRequest{Code: "POTONG50", DiscountType: "fixed", DiscountValue: 50000, MinSpend: 500000})
+	createPromotion(t, s, CreatePromotionRequest{Code: "DOLLAR", DiscountType: "fixed", DiscountValue: 5, Currency: "USD"})
+	createPromotion(t, s, CreatePromotionRequest{Code: "LIONONLY", AirlineID: "jt"})
+	createPromotion(t, s, CreatePromotionRequest{Code: "BALI", DestinationAirportID: "dps", CabinClass: "business"})
+	createPromotion(t, s, CreatePromotionRequest{Code: "PAUSED", IsActive: &inactive})
+	createPromotion(t, s, CreatePromotionRequest{Code: "NEXTMONTH", ValidFrom: flightDate(30), ValidUntil: flightDate(60)})
+	addExchangeRates(t, s, models.ExchangeRate{FromCurrency: "USD", ToCurrency: "IDR", Rate: 16000, EffectiveFrom: time.Now().AddDate(0, 0, -1)})
+
+	tests := []struct {
+		name         string
+		code         string
+		cabin        string
+		amount       int64 // minor units
+		wantDiscount int64
+		wantErr      error
+	}{
+		{name: "percent discount, code is case-insensitive", code: " hemat10 ", cabin: "economy", amount: 80000000, wantDiscount: 8000000},
+		{name: "fixed discount above the minimum spend", code: "POTONG50", cabin: "economy", amount: 80000000, wantDiscount: 5000000},
+		{name: "below the minimum spend", code: "POTONG50", cabin: "economy", amount: 40000000, wantErr: ErrPromoMinSpend},
+		{name: "fixed discount in another currency", code: "DOLLAR", cabin: "economy", amount: 80000000, wantDiscount: 8000000},
+		{name: "discount never exceeds the amount", code: "DOLLAR", cabin: "economy", amount: 5000000, wantDiscount: 5000000},
+		{name: "other airline", code: "LIONONLY", cabin: "economy", amount: 80000000, wantErr: ErrPromoCodeNotApplicable},
+		{name: "route and cabin", code: "BALI", cabin: "business", amount: 240000000, wantDiscount: 24000000},
+		{name: "route but not cabin", code: "BALI", cabin: "economy", amount: 80000000, wantErr: ErrPromoCodeNotApplicable},
+		{name: "created inactive", code: "PAUSED", cabin: "economy", amount: 80000000, wantErr: ErrInvalidPromoCode},
+		{name: "not yet valid", code: "NEXTMONTH", cabin: "economy", amount: 80000000, wantErr: ErrPromoCodeExpired},
+		{name: "unknown code", code: "NOPE", cabin: "economy", amount: 80000000, wantErr: ErrInvalidPromoCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation, err := s.promotions.Validate("", "", ValidatePromoCodeRequest{Code: tt.code, ScheduleID: "schedule-cgk-dps-ga-001", CabinClass: tt.cabin, Amount: tt.amount})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if validation.Discount != tt.wantDiscount || validation.Total != tt.amount-tt.wantDiscount {
+				t.Errorf("discount %d leaving %d, want %d off %d", validation.Discount, validation.Total, tt.wantDiscount, tt.amount)
+			}
+		})
+	}
+}
+
+// Redemption limits hold when many orders redeem one code at once, and a cancelled order gives its use back.
+// The databases have a pool of connections, so the orders really do write at the same time.
+func TestOrderCreatePromoCodeLimitsUnderConcurrency(t *testing.T) {
+	tests := []struct {
+		name         string
+		promotion    CreatePromotionRequest
+		orders       int
+		sameCustomer bool
+		wantRedeemed int
+	}{
+		{name: "usage limit across customers", promotion: CreatePromotionRequest{Code: "FIRST3", UsageLimit: 3}, orders: 10, wantRedeemed: 3},
+		{name: "per-customer limit", promotion: CreatePromotionRequest{Code: "ONCEEACH", PerUserLimit: 1}, orders: 6, sameCustomer: true, wantRedeemed: 1},
+		{name: "unlimited", promotion: CreatePromotionRequest{Code: "EVERYONE"}, orders: 5, wantRedeemed: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServicesOn(t, openPooledTestDB)
+			promotion := createPromotion(t, s, tt.promotion)
+
+			var wg sync.WaitGroup
+			errs := make([]error, tt.orders)
+			orderIDs := make([]string, tt.orders)
+			for i := 0; i < tt.orders; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					req := bookingRequest("schedule-cgk-dps-ga-001", 7)
+					req.PromoCode = promotion.Code
+					if !tt.sameCustomer {
+						req.ContactEmail = fmt.Sprintf("customer%d@example.com", i)
+					}
+					order, err := s.orders.Create("", "", req)
+					errs[i] = err
+					if err == nil {
+						orderIDs[i] = order.ID
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			var redeemed int
+			var redeemedOrder string
+			for i, err := range errs {
+				switch {
+				case err == nil:
+					redeemed++
+					redeemedOrder = orderIDs[i]
+				case !errors.Is(err, ErrPromoCodeUsedUp):
+					t.Errorf("order %d: %v", i, err)
+				}
+			}
+			if redeemed != tt.wantRedeemed {
+				t.Errorf("%d orders redeemed the code, want %d", redeemed, tt.wantRedeemed)
+			}
+			stored, err := s.promotions.GetByID(promotion.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.UsedCount != tt.wantRedeemed {
+				t.Errorf("used count = %d, want %d", stored.UsedCount, tt.wantRedeemed)
+			}
+
+			if err := s.orders.Cancel(redeemedOrder, "test"); err != nil {
+				t.Fatalf("Cancel: %v", err)
+			}
+			if stored, _ = s.promotions.GetByID(promotion.ID); stored.UsedCount != tt.wantRedeemed-1 {
+				t.Errorf("used count after cancelling = %d, want %d", stored.UsedCount, tt.wantRedeemed-1)
+			}
+		})
+	}
+}
+
+func TestOrderCreateAppliesPromoCode(t *testing.T) {
+	s := newTestServices(t)
+	createPromotion(t, s, CreatePromotionRequest{Code: "HEMAT10"})
+
+	req := bookingRequest("schedule-cgk-dps-ga-001", 7, adult("Parent"), child("Kid", 6))
+	req.PromoCode = "hemat10"
+	order, err := s.orders.Create("", "", req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// 10% off IDR 800,000 + 600,000
+	var discount int64
+	for _, item := range order.PriceBreakdown.Items {
+		if item.Type == models.ChargeDiscount {
+			discount += item.Amount
+		}
+	}
+	if discount != -14000000 || order.TotalAmount != 126000000 {
+		t.Errorf("discount %d for a total of %d, want -14000000 for 126000000", discount, order.TotalAmount)
+	}
+	if order.PromoCode != "HEMAT10" {
+		t.Errorf("promo code = %q, want HEMAT10", order.PromoCode)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// DisruptionService turns material schedule changes into disruptions of the orders booked on
+// them. Retimings within the threshold and changes to prices or seat counts pass silently.
+type DisruptionService struct {
+	orderRepo      repository.OrderRepository
+	disruptionRepo repository.ScheduleDisruptionRepository
+	unitOfWork     repository.UnitOfWork
+	threshold      time.Duration
+	events         *events.Bus
+}
+
+// NewDisruptionService treats moving a departure or arrival by more than threshold as material
+func NewDisruptionService(orderRepo repository.OrderRepository, disruptionRepo repository.ScheduleDisruptionRepository, unitOfWork repository.UnitOfWork, threshold time.Duration, bus *events.Bus) *DisruptionService {
+	return &DisruptionService{
+		orderRepo:      orderRepo,
+		disruptionRepo: disruptionRepo,
+		unitOfWork:     unitOfWork,
+		threshold:      threshold,
+		events:         bus,
+	}
+}
+
+// Subscribe detects disruptions as schedules are updated or deleted, before the change returns so
+// its caller sees the affected orders flagged. A deleted schedule no longer flies, which cancels it.
+func (s *DisruptionService) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "disruptions", events.Sync, func(e events.ScheduleChanged) error {
+		if e.Before == nil {
+			return nil
+		}
+		after := e.After
+		if after == nil {
+			deleted := *e.Before
+			deleted.IsActive = false
+			after = &deleted
+		}
+		_, err := s.Detect(e.Environment, e.Before, after)
+		return err
+	})
+}
+
+// Detect compares a schedule of env before and after an update. When the change is material it
+// records a disruption, flags the orders flying on the schedule from today onwards as needing
+// action and leaves a notification in their owners' inboxes, all in one transaction, then
+// publishes ScheduleDisrupted. It returns nil when the change is not material.
+func (s *DisruptionService) Detect(env models.Environment, before, after *models.Schedule) (*models.ScheduleDisruption, error) {
+	disruption := s.compare(before, after)
+	if disruption == nil {
+		return nil, nil
+	}
+	disruption.Environment = env
+	disruption.ScheduleID = after.ID
+
+	orders, err := s.orderRepo.ListUpcomingBySchedule(env, after.ID, time.Now().Truncate(24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	disruption.AffectedOrders = len(orders)
+
+	err = s.unitOfWork.Do(func(repos repository.Repositories) error {
+		if err := repos.Disruptions.Create(disruption); err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		orderIDs := make([]string, len(orders))
+		inbox := make([]models.Notification, len(orders))
+		for i, order := range orders {
+			orderIDs[i] = order.ID
+			inbox[i] = disruptionNotification(&order, after, disruption)
+			if err := repos.Orders.AddChange(&models.OrderChange{
+				OrderID: order.ID,
+				Type:    models.OrderChangeDisruption,
+				Before:  disruption.Before,
+				After:   disruption.After,
+			}); err != nil {
+				return err
+			}
+		}
+		if err := repos.Orders.MarkDisrupted(orderIDs, disruption.ID); err != nil {
+			return err
+		}
+		return repos.Notifications.CreateBatch(inbox)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.Publish(events.ScheduleDisrupted{Disruption: disruption, Schedule: after, Orders: orders})
+	return disruption, nil
+}
+
+// ListBySchedule returns the disruptions recorded for a schedule of env, latest first
+func (s *DisruptionService) ListBySchedule(env models.Environment, scheduleID string) ([]models.ScheduleDisruption, error) {
+	return s.disruptionRepo.ListBySchedule(env, scheduleID)
+}
+
+// compare returns the disruption before turning into after causes, nil when there is none
+func (s *DisruptionService) compare(before, after *models.Schedule) *models.ScheduleDisruption {
+	disruption := &models.ScheduleDisruption{Before: map[string]string{}, After: map[string]string{}}
+
+	if before.IsActive && !after.IsActive {
+		disruption.Types = append(disruption.Types, models.DisruptionCancellation)
+		disruption.Before["is_active"] = "true"
+		disruption.After["is_active"] = "false"
+	}
+
+	departureShift := timeShift(before.DepartureTime, after.DepartureTime)
+	arrivalShift := timeShift(before.ArrivalTime, after.ArrivalTime)
+	if departureShift > s.threshold || arrivalShift > s.threshold {
+		disruption.Types = append(disruption.Types, models.DisruptionTimeChange)
+		if departureShift > 0 {
+			disruption.Before["departure_time"] = before.DepartureTime
+			disruption.After["departure_time"] = after.DepartureTime
+		}
+		if arrivalShift > 0 {
+			disruption.Before["arrival_time"] = before.ArrivalTime
+			disruption.After["arrival_time"] = after.ArrivalTime
+		}
+	}
+
+	if before.Aircraft != after.Aircraft {
+		disruption.Types = append(disruption.Types, models.DisruptionAircraftChange)
+		disruption.Before["aircraft"] = before.Aircraft
+		disruption.After["aircraft"] = after.Aircraft
+	}
+
+	if len(disruption.Types) == 0 {
+		return nil
+	}
+	return disruption
+}
+
+// timeShift is how far an HH:MM time moved, the shorter way round the clock
+func timeShift(before, after string) time.Duration {
+	from, err := time.Parse("15:04", before)
+	if err != nil {
+		return 0
+	}
+	to, err := time.Parse("15:04", after)
+	if err != nil {
+		return 0
+	}
+
+	shift := to.Sub(from)
+	if shift < 0 {
+		shift = -shift
+	}
+	if shift > 12*time.Hour {
+		shift = 24*time.Hour - shift
+	}
+	return shift
+}
+
+// disruptionNotification tells the contact of order what happened to its flight and what they can do
+func disruptionNotification(order *models.Order, schedule *models.Schedule, disruption *models.ScheduleDisruption) models.Notification {
+	flight := fmt.Sprintf("%s on %s", schedule.FlightNumber, order.FlightDate.Format("2 Jan 2006"))
+
+	var title string
+	var details []string
+	if disruption.Cancels() {
+		title = fmt.Sprintf("Flight %s has been cancelled", flight)
+		details = append(details, "The airline has cancelled your flight.")
+	} else {
+		title = fmt.Sprintf("Flight %s has changed", flight)
+	}
+	if departure, ok := disruption.After["departure_time"]; ok {
+		details = append(details, fmt.Sprintf("Departure moves from %s to %s.", disruption.Before["departure_time"], departure))
+	}
+	if arrival, ok := disruption.After["arrival_time"]; ok {
+		details = append(details, fmt.Sprintf("Arrival moves from %s to %s.", disruption.Before["arrival_time"], arrival))
+	}
+	if aircraft, ok := disruption.After["aircraft"]; ok {
+		details = append(details, fmt.Sprintf("The aircraft changes from %s to %s, your seat may differ.", disruption.Before["aircraft"], aircraft))
+	}
+	if disruption.Cancels() {
+		details = append(details, fmt.Sprintf("Please request a full refund or rebook booking %s onto another flight free of charge.", order.PNR))
+	} else {
+		details = append(details, fmt.Sprintf("Please accept the change, request a full refund or rebook booking %s onto another flight free of charge.", order.PNR))
+	}
+
+	return models.Notification{
+		UserID:  order.UserID,
+		Email:   order.ContactEmail,
+		OrderID: order.ID,
+		Type:    models.NotificationScheduleChange,
+		Title:   title,
+		Message: strings.Join(details, " "),
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// disruptionOf returns the latest disruption flagged on order, nil when there is none
+func disruptionOf(t *testing.T, s *testServices, order *models.Order) *models.ScheduleDisruption {
+	t.Helper()
+	stored, err := s.orderRepo.FindByID(order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.DisruptionID == "" {
+		return nil
+	}
+	if stored.DisruptionStatus != models.DisruptionActionRequired {
+		t.Errorf("disruption status = %q, want %q", stored.DisruptionStatus, models.DisruptionActionRequired)
+	}
+	disruptions, err := s.disruption.ListBySchedule(order.Environment, order.ScheduleID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range disruptions {
+		if disruptions[i].ID == stored.DisruptionID {
+			return &disruptions[i]
+		}
+	}
+	t.Fatalf("order flagged with unknown disruption %s", stored.DisruptionID)
+	return nil
+}
+
+func TestScheduleUpdateDisruptsOrders(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       UpdateScheduleRequest
+		wantTypes []models.DisruptionType
+	}{
+		{name: "repriced", req: UpdateScheduleRequest{EconomyPrice: 999000}},
+		{name: "retimed within the threshold", req: UpdateScheduleRequest{DepartureTime: "06:30", ArrivalTime: "09:00"}},
+		{name: "retimed past the threshold", req: UpdateScheduleRequest{DepartureTime: "09:00", ArrivalTime: "11:30"}, wantTypes: []models.DisruptionType{models.DisruptionTimeChange}},
+		{name: "aircraft swapped", req: UpdateScheduleRequest{Aircraft: "Airbus A320"}, wantTypes: []models.DisruptionType{models.DisruptionAircraftChange}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			schedules := NewScheduleService(repository.NewScheduleRepository(s.staging), s.bus, models.EnvStaging)
+			order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if schedule, _ := schedules.GetByID(order.ScheduleID); schedule.DepartureTime != "06:00" {
+				t.Fatalf("seeded departure %s, the cases assume 06:00", schedule.DepartureTime)
+			}
+
+			if _, err := schedules.Update(order.ScheduleID, tt.req); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			disruption := disruptionOf(t, s, order)
+			switch {
+			case tt.wantTypes == nil && disruption != nil:
+				t.Errorf("order disrupted by %v", disruption.Types)
+			case tt.wantTypes != nil && (disruption == nil || !slices.Equal(disruption.Types, tt.wantTypes)):
+				t.Errorf("disruption = %+v, want %v", disruption, tt.wantTypes)
+			}
+		})
+	}
+}
+
+// Deleting a schedule cancels the orders booked on it, as deactivating it does
+func TestScheduleDeleteCancelsOrders(t *testing.T) {
+	s := newTestServices(t)
+	schedules := NewScheduleService(repository.NewScheduleRepository(s.staging), s.bus, models.EnvStaging)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-002", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schedules.Delete(order.ScheduleID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	disruption := disruptionOf(t, s, order)
+	if disruption == nil || !disruption.Cancels() || disruption.AffectedOrders != 1 {
+		t.Fatalf("disruption = %+v, want a cancellation of 1 order", disruption)
+	}
+	if disruptionOf(t, s, other) != nil {
+		t.Error("an order on another schedule was disrupted")
+	}
+
+	var inbox []models.Notification
+	if err := s.staging.Where("order_id = ? AND type = ?", order.ID, models.NotificationScheduleChange).Find(&inbox).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(inbox) != 1 {
+		t.Errorf("%d schedule change notifications, want 1", len(inbox))
+	}
+
+	// A cancelled flight can be refunded in full
+	refunded, err := s.orders.RefundDisruption(order.ID, "test")
+	if err != nil {
+		t.Fatalf("RefundDisruption: %v", err)
+	}
+	if refunded.DisruptionStatus != models.DisruptionRefundRequested {
+		t.Errorf("disruption status = %q after refund", refunded.DisruptionStatus)
+	}
+}
+
+// Passengers of a deleted schedule can still rebook onto another flight of the airline on the
+// route, matched by the flight their order was sold as
+func TestScheduleDeleteRebooks(t *testing.T) {
+	s := newTestServices(t)
+	schedules := NewScheduleService(repository.NewScheduleRepository(s.staging), s.bus, models.EnvStaging)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := schedules.Delete(order.ScheduleID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		req     ChangeFlightRequest
+		wantErr error
+	}{
+		{name: "the deleted flight", req: ChangeFlightRequest{FlightDate: flightDate(8)}, wantErr: ErrScheduleNotFound},
+		{name: "another airline", req: ChangeFlightRequest{ScheduleID: "schedule-cgk-dps-jt-001"}, wantErr: ErrInvalidFlightChange},
+		{name: "another route", req: ChangeFlightRequest{ScheduleID: "schedule-cgk-sub-ga-001"}, wantErr: ErrInvalidFlightChange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.orders.Rebook(order.ID, "test", tt.req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	rebooked, err := s.orders.Rebook(order.ID, "test", ChangeFlightRequest{ScheduleID: "schedule-cgk-dps-ga-002"})
+	if err != nil {
+		t.Fatalf("Rebook: %v", err)
+	}
+	if rebooked.ScheduleID != "schedule-cgk-dps-ga-002" || rebooked.DisruptionStatus != models.DisruptionRebooked || rebooked.TotalAmount != order.TotalAmount {
+		t.Errorf("rebooked onto %s (%s) for %d, want schedule-cgk-dps-ga-002 at the price paid %d",
+			rebooked.ScheduleID, rebooked.DisruptionStatus, rebooked.TotalAmount, order.TotalAmount)
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"slices"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// ScheduleResolver finds schedules in the environment they are sold from. As in flight search,
+// airlines whitelisted for a user's email are sold from production and the rest from staging.
+type ScheduleResolver struct {
+	stagingRepo      repository.ScheduleRepository
+	productionRepo   repository.ScheduleRepository
+	whitelistService *WhitelistService
+}
+
+func NewScheduleResolver(stagingRepo, productionRepo repository.ScheduleRepository, whitelistService *WhitelistService) *ScheduleResolver {
+	return &ScheduleResolver{
+		stagingRepo:      stagingRepo,
+		productionRepo:   productionRepo,
+		whitelistService: whitelistService,
+	}
+}
+
+// Resolve finds schedule id as email sees it, along with the environment it comes from.
+// An empty email, like a guest's, always gets staging.
+func (r *ScheduleResolver) Resolve(email, id string) (*models.Schedule, models.Environment, error) {
+	if airlineIDs := r.whitelistedAirlines(email); len(airlineIDs) > 0 {
+		schedule, err := r.productionRepo.FindByID(id)
+		if err == nil && slices.Contains(airlineIDs, schedule.AirlineID) {
+			return schedule, models.EnvProduction, nil
+		}
+	}
+
+	schedule, err := r.stagingRepo.FindByID(id)
+	if err != nil {
+		return nil, "", ErrScheduleNotFound
+	}
+	return schedule, models.EnvStaging, nil
+}
+
+// Find finds schedule id in env
+func (r *ScheduleResolver) Find(env models.Environment, id string) (*models.Schedule, error) {
+	schedule, err := r.repoFor(env).FindByID(id)
+	if err != nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// FindMany finds the schedules among ids in env, keyed by ID. Missing schedules are left out.
+func (r *ScheduleResolver) FindMany(env models.Environment, ids []string) (map[string]*models.Schedule, error) {
+	schedules, err := r.repoFor(env).FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Schedule, len(schedules))
+	for i := range schedules {
+		byID[schedules[i].ID] = &schedules[i]
+	}
+	return byID, nil
+}
+
+// whitelistedAirlines returns the airlines email may book from production
+func (r *ScheduleResolver) whitelistedAirlines(email string) []string {
+	if email == "" {
+		return nil
+	}
+	whitelisted, err := r.whitelistService.IsEmailWhitelisted(email)
+	if err != nil || !whitelisted {
+		return nil
+	}
+	whitelistedUser, err := r.whitelistService.GetByEmail(email)
+	if err != nil {
+		return nil
+	}
+	return whitelistedUser.EnabledAirlineIDs
+}
+
+func (r *ScheduleResolver) repoFor(env models.Environment) repository.ScheduleRepository {
+	if env == models.EnvProduction {
+		return r.productionRepo
+	}
+	return r.stagingRepo
+}
@@ -1,9 +1,12 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"time"
 
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 )
@@ -20,6 +23,7 @@ type ScheduleService interface {
 	List(page, pageSize int) (*PaginatedResponse, error)
 	ListByAirline(airlineID string, page, pageSize int) (*PaginatedResponse, error)
 	Search(req SearchFlightRequest) (*PaginatedResponse, error)
+	SearchContext(ctx context.Context, email string, req SearchFlightRequest) (*PaginatedResponse, error)
 }
 
 type CreateScheduleRequest struct {
@@ -40,6 +44,7 @@ type CreateScheduleRequest struct {
 	EconomySeats       int     `json:"economy_seats"`
 	BusinessSeats      int     `json:"business_seats"`
 	FirstClassSeats    int     `json:"first_class_seats"`
+	Currency           string  `json:"currency"` // Currency of the prices, defaults to the airline's
 	IsActive           *bool   `json:"is_active"`
 }
 
@@ -61,6 +66,7 @@ type UpdateScheduleRequest struct {
 	EconomySeats       int     `json:"economy_seats"`
 	BusinessSeats      int     `json:"business_seats"`
 	FirstClassSeats    int     `json:"first_class_seats"`
+	Currency           string  `json:"currency"`
 	IsActive           *bool   `json:"is_active"`
 }
 
@@ -70,16 +76,23 @@ type SearchFlightRequest struct {
 	DepartureDate string   `form:"departure_date" binding:"required"` // YYYY-MM-DD format
 	CabinClass    string   `form:"cabin_class"`                      // economy, business, first
 	Airlines      []string `form:"airlines"`                         // Filter by airline IDs
+	Currency      string   `form:"currency"`                         // Quote fares in this currency, empty uses each schedule's
 	Page          int      `form:"page"`
 	PageSize      int      `form:"page_size"`
 }
 
 type scheduleService struct {
 	scheduleRepo repository.ScheduleRepository
+	events       *events.Bus
+	env          models.Environment
 }
 
-func NewScheduleService(scheduleRepo repository.ScheduleRepository) ScheduleService {
-	return &scheduleService{scheduleRepo: scheduleRepo}
+func NewScheduleService(scheduleRepo repository.ScheduleRepository, bus *events.Bus, env models.Environment) ScheduleService {
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+		events:       bus,
+		env:          env,
+	}
 }
 
 func (s *scheduleService) Create(req CreateScheduleRequest) (*models.Schedule, error) {
@@ -101,6 +114,7 @@ func (s *scheduleService) Create(req CreateScheduleRequest) (*models.Schedule, e
 		EconomySeats:       req.EconomySeats,
 		BusinessSeats:      req.BusinessSeats,
 		FirstClassSeats:    req.FirstClassSeats,
+		Currency:           strings.ToUpper(req.Currency),
 		IsActive:           true,
 	}
 
@@ -108,6 +122,10 @@ func (s *scheduleService) Create(req CreateScheduleRequest) (*models.Schedule, e
 		schedule.IsActive = *req.IsActive
 	}
 
+	if schedule.Currency != "" && !models.IsSupportedCurrency(schedule.Currency) {
+		return nil, ErrUnsupportedCurrency
+	}
+
 	if req.DaysOfWeek == "" {
 		schedule.DaysOfWeek = "1,2,3,4,5,6,7"
 	}
@@ -115,6 +133,7 @@ func (s *scheduleService) Create(req CreateScheduleRequest) (*models.Schedule, e
 	if err := s.scheduleRepo.Create(schedule); err != nil {
 		return nil, err
 	}
+	s.events.Publish(events.ScheduleChanged{Environment: s.env, After: schedule})
 
 	// Reload with relations
 	return s.scheduleRepo.FindByID(schedule.ID)
@@ -128,11 +147,14 @@ func (s *scheduleService) GetByID(id string) (*models.Schedule, error) {
 	return schedule, nil
 }
 
+// Update saves the changes in req and, when they are material, disrupts the orders booked on the
+// schedule, see DisruptionService.Detect
 func (s *scheduleService) Update(id string, req UpdateScheduleRequest) (*models.Schedule, error) {
 	schedule, err := s.scheduleRepo.FindByID(id)
 	if err != nil {
 		return nil, ErrScheduleNotFound
 	}
+	before := *schedule
 
 	if req.AirlineID != "" {
 		schedule.AirlineID = req.AirlineID
@@ -185,6 +207,12 @@ func (s *scheduleService) Update(id string, req UpdateScheduleRequest) (*models.
 	if req.FirstClassSeats > 0 {
 		schedule.FirstClassSeats = req.FirstClassSeats
 	}
+	if req.Currency != "" {
+		if !models.IsSupportedCurrency(strings.ToUpper(req.Currency)) {
+			return nil, ErrUnsupportedCurrency
+		}
+		schedule.Currency = strings.ToUpper(req.Currency)
+	}
 	if req.IsActive != nil {
 		schedule.IsActive = *req.IsActive
 	}
@@ -192,17 +220,23 @@ func (s *scheduleService) Update(id string, req UpdateScheduleRequest) (*models.
 	if err := s.scheduleRepo.Update(schedule); err != nil {
 		return nil, err
 	}
+	s.events.Publish(events.ScheduleChanged{Environment: s.env, Before: &before, After: schedule})
 
 	return s.scheduleRepo.FindByID(id)
 }
 
 func (s *scheduleService) Delete(id string) error {
-	_, err := s.scheduleRepo.FindByID(id)
+	schedule, err := s.scheduleRepo.FindByID(id)
 	if err != nil {
 		return ErrScheduleNotFound
 	}
 
-	return s.scheduleRepo.Delete(id)
+	if err := s.scheduleRepo.Delete(id); err != nil {
+		return err
+	}
+	s.events.Publish(events.ScheduleChanged{Environment: s.env, Before: schedule})
+
+	return nil
 }
 
 func (s *scheduleService) List(page, pageSize int) (*PaginatedResponse, error) {
@@ -260,6 +294,11 @@ func (s *scheduleService) ListByAirline(airlineID string, page, pageSize int) (*
 }
 
 func (s *scheduleService) Search(req SearchFlightRequest) (*PaginatedResponse, error) {
+	return s.SearchContext(context.Background(), "", req)
+}
+
+// SearchContext searches the service's single environment, so email does not change the result
+func (s *scheduleService) SearchContext(ctx context.Context, email string, req SearchFlightRequest) (*PaginatedResponse, error) {
 	if req.Page < 1 {
 		req.Page = 1
 	}
@@ -279,7 +318,7 @@ func (s *scheduleService) Search(req SearchFlightRequest) (*PaginatedResponse, e
 		PageSize:             req.PageSize,
 	}
 
-	schedules, total, err := s.scheduleRepo.Search(params)
+	schedules, total, err := s.scheduleRepo.SearchContext(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -298,3 +337,15 @@ func (s *scheduleService) Search(req SearchFlightRequest) (*PaginatedResponse, e
 	}, nil
 }
 
+
+// toCabinClass converts a cabin class string, defaulting to economy
+func toCabinClass(cabinClass string) models.CabinClass {
+	switch models.CabinClass(cabinClass) {
+	case models.CabinBusiness:
+		return models.CabinBusiness
+	case models.CabinFirst:
+		return models.CabinFirst
+	default:
+		return models.CabinEconomy
+	}
+}
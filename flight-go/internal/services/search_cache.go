@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/cache"
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+const searchCachePrefix = "search:"
+
+// SearchCacheKey identifies a cached flight search result
+type SearchCacheKey struct {
+	Origin        string
+	Destination   string
+	DepartureDate string
+	CabinClass    models.CabinClass
+	AirlineIDs    []string // Requested airline filter, empty means all active airlines
+	ProductionIDs []string // Airlines served from production for the user
+}
+
+// String renders the key as "search:<partition>:<origin>:<destination>:<date>:<cabin>:<airlines>"
+// The partition is "staging" when nothing is read from production, or
+// "production:<airline ids>" otherwise, so each environment can be invalidated by prefix.
+func (k SearchCacheKey) String() string {
+	partition := string(models.EnvStaging)
+	if len(k.ProductionIDs) > 0 {
+		partition = string(models.EnvProduction) + ":" + sortedJoin(k.ProductionIDs)
+	}
+
+	airlines := "*"
+	if len(k.AirlineIDs) > 0 {
+		airlines = sortedJoin(k.AirlineIDs)
+	}
+
+	return searchCachePrefix + strings.Join([]string{
+		partition,
+		k.Origin,
+		k.Destination,
+		k.DepartureDate,
+		string(k.CabinClass),
+		airlines,
+	}, ":")
+}
+
+// SearchCacheStats reports cache effectiveness
+type SearchCacheStats struct {
+	Backend       string  `json:"backend"`
+	Enabled       bool    `json:"enabled"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRate       float64 `json:"hit_rate"`
+	Invalidations int64   `json:"invalidations"`
+	Entries       int     `json:"entries"`
+}
+
+// SearchGeneration counts the invalidations of each environment, taken before a search reads
+// the databases so a result read before a write is never cached after it
+type SearchGeneration struct {
+	staging    int64
+	production int64
+}
+
+// SearchCache caches combined flight search results in front of the schedule databases.
+// A nil *SearchCache is valid and never caches anything.
+type SearchCache struct {
+	backend cache.Cache
+	ttl     time.Duration
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+
+	stagingGeneration    atomic.Int64
+	productionGeneration atomic.Int64
+}
+
+func NewSearchCache(backend cache.Cache, ttl time.Duration) *SearchCache {
+	return &SearchCache{
+		backend: backend,
+		ttl:     ttl,
+	}
+}
+
+// Get returns the cached schedules for key
+func (c *SearchCache) Get(ctx context.Context, key SearchCacheKey) ([]models.Schedule, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	data, ok, err := c.backend.Get(ctx, key.String())
+	if err != nil || !ok {
+		if err != nil {
+			log.Printf("Search cache get failed: %v", err)
+		}
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	var schedules []models.Schedule
+	if err := json.Unmarshal(data, &schedules); err != nil {
+		log.Printf("Search cache entry %s is corrupt: %v", key, err)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.hits.Add(1)
+	return schedules, true
+}
+
+// Generation returns the current generation, take it before searching the databases and pass
+// it to Set with the result
+func (c *SearchCache) Generation() SearchGeneration {
+	if c == nil {
+		return SearchGeneration{}
+	}
+	return SearchGeneration{staging: c.stagingGeneration.Load(), production: c.productionGeneration.Load()}
+}
+
+// Set stores the schedules for key, searched at generation gen. A result an environment it read
+// from was invalidated under since is dropped, it may predate the write.
+func (c *SearchCache) Set(ctx context.Context, key SearchCacheKey, gen SearchGeneration, schedules []models.Schedule) {
+	if c == nil || c.stale(key, gen) {
+		return
+	}
+
+	data, err := json.Marshal(schedules)
+	if err != nil {
+		log.Printf("Search cache encode failed: %v", err)
+		return
+	}
+
+	if err := c.backend.Set(ctx, key.String(), data, c.ttl); err != nil {
+		log.Printf("Search cache set failed: %v", err)
+		return
+	}
+	// An invalidation between the check and the write may have missed the entry
+	if c.stale(key, gen) {
+		if err := c.backend.Delete(ctx, key.String()); err != nil {
+			log.Printf("Search cache delete failed: %v", err)
+		}
+	}
+}
+
+// stale reports whether an environment key reads from was invalidated since gen
+func (c *SearchCache) stale(key SearchCacheKey, gen SearchGeneration) bool {
+	current := c.Generation()
+	if current.staging != gen.staging {
+		return true
+	}
+	return len(key.ProductionIDs) > 0 && current.production != gen.production
+}
+
+// Subscribe invalidates the searches of an environment as its schedules and airlines change,
+// before the change returns so the next search sees it
+func (c *SearchCache) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "search cache", events.Sync, func(e events.ScheduleChanged) error {
+		c.InvalidateEnv(e.Environment)
+		return nil
+	})
+	events.Subscribe(bus, "search cache", events.Sync, func(e events.AirlineChanged) error {
+		c.InvalidateEnv(e.Environment)
+		return nil
+	})
+}
+
+// InvalidateEnv drops every cached search that may have read from env
+// Staging serves all non-whitelisted airlines, so a staging write clears everything.
+func (c *SearchCache) InvalidateEnv(env models.Environment) {
+	if c == nil {
+		return
+	}
+
+	// Bumped first, so searches still running can't cache what they read before the write
+	prefix := searchCachePrefix
+	if env == models.EnvProduction {
+		c.productionGeneration.Add(1)
+		prefix += string(models.EnvProduction) + ":"
+	} else {
+		c.stagingGeneration.Add(1)
+	}
+
+	if err := c.backend.DeletePrefix(context.Background(), prefix); err != nil {
+		log.Printf("Search cache invalidation for %s failed: %v", env, err)
+		return
+	}
+	c.invalidations.Add(1)
+}
+
+// Flush drops every cached search
+func (c *SearchCache) Flush() {
+	c.InvalidateEnv(models.EnvStaging)
+}
+
+// Stats returns hit/miss counters since startup
+func (c *SearchCache) Stats() SearchCacheStats {
+	if c == nil {
+		return SearchCacheStats{Backend: "none"}
+	}
+
+	stats := SearchCacheStats{
+		Backend:       c.backend.Name(),
+		Enabled:       true,
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Invalidations: c.invalidations.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	if sized, ok := c.backend.(interface{ Len() int }); ok {
+		stats.Entries = sized.Len()
+	}
+	return stats
+}
+
+func sortedJoin(ids []string) string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/cache"
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+func TestSearchCacheKeyString(t *testing.T) {
+	tests := []struct {
+		name string
+		key  SearchCacheKey
+		want string
+	}{
+		{
+			name: "guest search of all airlines",
+			key:  SearchCacheKey{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-01-02", CabinClass: models.CabinEconomy},
+			want: "search:staging:CGK:DPS:2030-01-02:economy:*",
+		},
+		{
+			name: "airline filter is sorted",
+			key:  SearchCacheKey{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-01-02", CabinClass: models.CabinBusiness, AirlineIDs: []string{"jt", "ga"}},
+			want: "search:staging:CGK:DPS:2030-01-02:business:ga,jt",
+		},
+		{
+			name: "whitelisted airlines partition production",
+			key:  SearchCacheKey{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-01-02", CabinClass: models.CabinEconomy, ProductionIDs: []string{"qg", "ga"}},
+			want: "search:production:ga,qg:CGK:DPS:2030-01-02:economy:*",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.key.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchCacheInvalidateEnv(t *testing.T) {
+	stagingKey := SearchCacheKey{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-01-02", CabinClass: models.CabinEconomy}
+	productionKey := stagingKey
+	productionKey.ProductionIDs = []string{"ga"}
+
+	tests := []struct {
+		name           string
+		env            models.Environment
+		wantStaging    bool
+		wantProduction bool
+	}{
+		{name: "staging write drops every search", env: models.EnvStaging, wantStaging: false, wantProduction: false},
+		{name: "production write keeps staging searches", env: models.EnvProduction, wantStaging: true, wantProduction: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewSearchCache(cache.NewMemory(10), time.Minute)
+			c.Set(ctx, stagingKey, c.Generation(), []models.Schedule{{FlightNumber: "GA101"}})
+			c.Set(ctx, productionKey, c.Generation(), []models.Schedule{{FlightNumber: "GA101 (PROD)"}})
+
+			c.InvalidateEnv(tt.env)
+
+			if _, ok := c.Get(ctx, stagingKey); ok != tt.wantStaging {
+				t.Errorf("staging entry cached = %v, want %v", ok, tt.wantStaging)
+			}
+			if _, ok := c.Get(ctx, productionKey); ok != tt.wantProduction {
+				t.Errorf("production entry cached = %v, want %v", ok, tt.wantProduction)
+			}
+		})
+	}
+}
+
+// invalidatingBackend runs invalidate as an entry is written, before it is stored
+type invalidatingBackend struct {
+	cache.Cache
+	invalidate func()
+}
+
+func (b *invalidatingBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if b.invalidate != nil {
+		b.invalidate()
+		b.invalidate = nil
+	}
+	return b.Cache.Set(ctx, key, value, ttl)
+}
+
+// A search that read the databases before a write must not cache what it read after the write
+// invalidated the cache
+func TestSearchCacheDropsStaleResults(t *testing.T) {
+	stagingKey := SearchCacheKey{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-01-02", CabinClass: models.CabinEconomy}
+	productionKey := stagingKey
+	productionKey.ProductionIDs = []string{"ga"}
+
+	tests := []struct {
+		name        string
+		key         SearchCacheKey
+		env         models.Environment
+		duringWrite bool
+		wantCached  bool
+	}{
+		{name: "staging write drops a staging search", key: stagingKey, env: models.EnvStaging},
+		{name: "staging write drops a production search", key: productionKey, env: models.EnvStaging},
+		{name: "production write drops a production search", key: productionKey, env: models.EnvProduction},
+		{name: "production write keeps a staging search", key: stagingKey, env: models.EnvProduction, wantCached: true},
+		{name: "write while the entry is stored", key: stagingKey, env: models.EnvStaging, duringWrite: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			backend := &invalidatingBackend{Cache: cache.NewMemory(10)}
+			c := NewSearchCache(backend, time.Minute)
+
+			gen := c.Generation()
+			if tt.duringWrite {
+				backend.invalidate = func() { c.InvalidateEnv(tt.env) }
+			} else {
+				c.InvalidateEnv(tt.env)
+			}
+			c.Set(ctx, tt.key, gen, []models.Schedule{{FlightNumber: "GA101"}})
+
+			if _, ok := c.Get(ctx, tt.key); ok != tt.wantCached {
+				t.Errorf("cached = %v, want %v", ok, tt.wantCached)
+			}
+		})
+	}
+
+	// Searches started after the write are cached again
+	c := NewSearchCache(cache.NewMemory(10), time.Minute)
+	c.InvalidateEnv(models.EnvStaging)
+	c.Set(context.Background(), stagingKey, c.Generation(), []models.Schedule{{FlightNumber: "GA101"}})
+	if _, ok := c.Get(context.Background(), stagingKey); !ok {
+		t.Error("a search started after the write was not cached")
+	}
+}
+
+// Concurrent searches by a whitelisted user and guests must each see their own environment,
+// both on the first (uncached) search and on cache hits
+func TestDualScheduleServiceSearchIsolatesUsers(t *testing.T) {
+	s := newTestServices(t)
+	s.whitelistUser(t, "partner@example.com", "ga")
+
+	req := SearchFlightRequest{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: time.Now().AddDate(0, 1, 0).Format("2006-01-02"),
+		Airlines:      []string{"ga"},
+		PageSize:      100,
+	}
+
+	tests := []struct {
+		name     string
+		email    string
+		wantProd bool
+	}{
+		{name: "guest", email: "", wantProd: false},
+		{name: "registered user without whitelist", email: "someone@example.com", wantProd: false},
+		{name: "whitelisted user", email: "partner@example.com", wantProd: true},
+	}
+
+	errs := make(chan string, 100)
+	check := func(email string, wantProd bool, name string) {
+		result, err := s.search.SearchContext(context.Background(), email, req)
+		if err != nil {
+			errs <- name + ": " + err.Error()
+			return
+		}
+		schedules := result.Data.([]models.Schedule)
+		if len(schedules) == 0 {
+			errs <- name + ": no schedules found"
+			return
+		}
+		for _, schedule := range schedules {
+			if isProd := strings.HasSuffix(schedule.FlightNumber, "(PROD)"); isProd != wantProd {
+				errs <- name + ": got " + schedule.FlightNumber
+				return
+			}
+		}
+	}
+
+	// Fill the cache, then race cache hits against each other
+	for _, tt := range tests {
+		check(tt.email, tt.wantProd, tt.name)
+	}
+	var wg sync.WaitGroup
+	for round := 0; round < 10; round++ {
+		for _, tt := range tests {
+			wg.Add(1)
+			go func(email string, wantProd bool, name string) {
+				defer wg.Done()
+				check(email, wantProd, name)
+			}(tt.email, tt.wantProd, tt.name)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for msg := range errs {
+		t.Error(msg)
+	}
+	if stats := s.cache.Stats(); stats.Hits == 0 {
+		t.Errorf("expected repeated searches to hit the cache, got %+v", stats)
+	}
+}
+
+func TestDualScheduleServiceGetFlightDetailUsesEmailEnvironment(t *testing.T) {
+	s := newTestServices(t)
+	s.whitelistUser(t, "partner@example.com", "ga")
+	date := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+
+	tests := []struct {
+		name     string
+		email    string
+		wantProd bool
+	}{
+		{name: "guest gets staging", email: "", wantProd: false},
+		{name: "whitelisted user gets production", email: "partner@example.com", wantProd: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := s.search.GetFlightDetail(tt.email, "schedule-cgk-dps-ga-001", "economy", date, "")
+			if err != nil {
+				t.Fatalf("GetFlightDetail: %v", err)
+			}
+			if isProd := strings.HasSuffix(schedule.FlightNumber, "(PROD)"); isProd != tt.wantProd {
+				t.Errorf("flight number %q, want production %v", schedule.FlightNumber, tt.wantProd)
+			}
+			if schedule.Fare == nil {
+				t.Error("expected the fare to be quoted")
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrSeatMapNotFound = errors.New("seat map not found")
+	ErrInvalidSeatMap  = errors.New("invalid seat map")
+	ErrSeatMapExists   = errors.New("seat map already exists for aircraft")
+)
+
+type SeatMapService interface {
+	Create(req CreateSeatMapRequest) (*models.SeatMap, error)
+	GetByID(id string) (*models.SeatMap, error)
+	Update(id string, req UpdateSeatMapRequest) (*models.SeatMap, error)
+	Delete(id string) error
+	List(page, pageSize int) (*PaginatedResponse, error)
+}
+
+type CreateSeatMapRequest struct {
+	Aircraft string            `json:"aircraft" binding:"required"` // Matches Schedule.Aircraft
+	Currency string            `json:"currency"`                    // Currency of zone prices, defaults to IDR
+	Zones    []models.SeatZone `json:"zones" binding:"required,min=1"`
+	ExitRows []int             `json:"exit_rows"`
+}
+
+type UpdateSeatMapRequest struct {
+	Aircraft string            `json:"aircraft"`
+	Currency string            `json:"currency"`
+	Zones    []models.SeatZone `json:"zones"` // Replaces every zone when set
+	ExitRows *[]int            `json:"exit_rows"`
+}
+
+type seatMapService struct {
+	seatMapRepo repository.SeatMapRepository
+}
+
+func NewSeatMapService(seatMapRepo repository.SeatMapRepository) SeatMapService {
+	return &seatMapService{seatMapRepo: seatMapRepo}
+}
+
+func (s *seatMapService) Create(req CreateSeatMapRequest) (*models.SeatMap, error) {
+	seatMap := &models.SeatMap{
+		Aircraft: strings.TrimSpace(req.Aircraft),
+		Currency: models.DefaultCurrency,
+		Zones:    req.Zones,
+		ExitRows: req.ExitRows,
+	}
+	if req.Currency != "" {
+		seatMap.Currency = strings.ToUpper(req.Currency)
+	}
+
+	if err := validateSeatMap(seatMap); err != nil {
+		return nil, err
+	}
+	if existing, err := s.seatMapRepo.FindByAircraft(seatMap.Aircraft); err == nil && existing != nil {
+		return nil, ErrSeatMapExists
+	}
+
+	if err := s.seatMapRepo.Create(seatMap); err != nil {
+		return nil, err
+	}
+
+	return seatMap, nil
+}
+
+func (s *seatMapService) GetByID(id string) (*models.SeatMap, error) {
+	seatMap, err := s.seatMapRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrSeatMapNotFound
+	}
+	return seatMap, nil
+}
+
+func (s *seatMapService) Update(id string, req UpdateSeatMapRequest) (*models.SeatMap, error) {
+	seatMap, err := s.seatMapRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrSeatMapNotFound
+	}
+
+	if req.Aircraft != "" {
+		seatMap.Aircraft = strings.TrimSpace(req.Aircraft)
+	}
+	if req.Currency != "" {
+		seatMap.Currency = strings.ToUpper(req.Currency)
+	}
+	if req.Zones != nil {
+		seatMap.Zones = req.Zones
+	}
+	if req.ExitRows != nil {
+		seatMap.ExitRows = *req.ExitRows
+	}
+
+	if err := validateSeatMap(seatMap); err != nil {
+		return nil, err
+	}
+	if existing, err := s.seatMapRepo.FindByAircraft(seatMap.Aircraft); err == nil && existing.ID != seatMap.ID {
+		return nil, ErrSeatMapExists
+	}
+
+	if err := s.seatMapRepo.Update(seatMap); err != nil {
+		return nil, err
+	}
+
+	return seatMap, nil
+}
+
+func (s *seatMapService) Delete(id string) error {
+	_, err := s.seatMapRepo.FindByID(id)
+	if err != nil {
+		return ErrSeatMapNotFound
+	}
+
+	return s.seatMapRepo.Delete(id)
+}
+
+func (s *seatMapService) List(page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	seatMaps, total, err := s.seatMapRepo.List(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       seatMaps,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// validateSeatMap checks that zones are well formed and cover disjoint rows, and that exit rows exist
+func validateSeatMap(seatMap *models.SeatMap) error {
+	if seatMap.Aircraft == "" || !models.IsSupportedCurrency(seatMap.Currency) {
+		return ErrInvalidSeatMap
+	}
+
+	for i := range seatMap.Zones {
+		zone := &seatMap.Zones[i]
+		zone.Layout = strings.ToUpper(strings.TrimSpace(zone.Layout))
+
+		switch zone.CabinClass {
+		case models.CabinEconomy, models.CabinBusiness, models.CabinFirst:
+		default:
+			return ErrInvalidSeatMap
+		}
+		if zone.Name == "" || zone.FromRow < 1 || zone.ToRow < zone.FromRow || zone.Price < 0 {
+			return ErrInvalidSeatMap
+		}
+
+		columns := zone.Columns()
+		if len(columns) == 0 {
+			return ErrInvalidSeatMap
+		}
+		seen := make(map[string]bool)
+		for _, column := range columns {
+			if column < "A" || column > "Z" || seen[column] {
+				return ErrInvalidSeatMap
+			}
+			seen[column] = true
+		}
+	}
+
+	zones := make([]models.SeatZone, len(seatMap.Zones))
+	copy(zones, seatMap.Zones)
+	sort.Slice(zones, func(i, j int) bool { return zones[i].FromRow < zones[j].FromRow })
+	for i := 1; i < len(zones); i++ {
+		if zones[i].FromRow <= zones[i-1].ToRow {
+			return ErrInvalidSeatMap
+		}
+	}
+
+	for _, row := range seatMap.ExitRows {
+		found := false
+		for _, zone := range zones {
+			if row >= zone.FromRow && row <= zone.ToRow {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidSeatMap
+		}
+	}
+
+	return nil
+}
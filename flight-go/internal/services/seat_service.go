@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var ErrSeatTaken = errors.New("seat already taken")
+
+// seatPick is a seat asked for a passenger, Index locates the request for error reporting
+type seatPick struct {
+	Index     int
+	Passenger *models.Passenger
+	Seat      string
+}
+
+// SeatService lays out flight seat maps from the aircraft's template and the seats held
+// on the flight date, and prices and holds the seats passengers pick
+type SeatService struct {
+	seatMapRepo     repository.SeatMapRepository
+	seatRepo        repository.SeatAssignmentRepository
+	schedules       *ScheduleResolver
+	currencyService *CurrencyService
+}
+
+func NewSeatService(
+	seatMapRepo repository.SeatMapRepository,
+	seatRepo repository.SeatAssignmentRepository,
+	schedules *ScheduleResolver,
+	currencyService *CurrencyService,
+) *SeatService {
+	return &SeatService{
+		seatMapRepo:     seatMapRepo,
+		seatRepo:        seatRepo,
+		schedules:       schedules,
+		currencyService: currencyService,
+	}
+}
+
+// FlightSeatMap returns the seat map of a schedule on flightDate (YYYY-MM-DD) with every
+// seat's price and availability, in the environment email books it in. A cabin limits it
+// to that cabin's rows.
+func (s *SeatService) FlightSeatMap(email, scheduleID, flightDate, cabin string) (*models.FlightSeatMap, error) {
+	schedule, env, err := s.schedules.Resolve(email, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	date, err := time.Parse("2006-01-02", flightDate)
+	if err != nil {
+		return nil, ErrInvalidFlightDate
+	}
+
+	seatMap, err := s.seatMapRepo.FindByAircraft(schedule.Aircraft)
+	if err != nil {
+		return nil, ErrSeatMapNotFound
+	}
+	taken, err := s.takenSeats(env, schedule.ID, date, "")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.FlightSeatMap{
+		ScheduleID: schedule.ID,
+		FlightDate: flightDate,
+		Aircraft:   seatMap.Aircraft,
+		Currency:   seatMap.Currency,
+		Rows:       []models.SeatRow{},
+	}
+	for _, zone := range seatMap.Zones {
+		if cabin != "" && zone.CabinClass != models.CabinClass(cabin) {
+			continue
+		}
+		for row := zone.FromRow; row <= zone.ToRow; row++ {
+			seatRow := models.SeatRow{
+				Number:     row,
+				CabinClass: zone.CabinClass,
+				Zone:       zone.Name,
+				Layout:     zone.Layout,
+				ExitRow:    seatMap.IsExitRow(row),
+			}
+			for _, column := range zone.Columns() {
+				number := fmt.Sprintf("%d%s", row, column)
+				seatRow.Seats = append(seatRow.Seats, models.SeatInfo{
+					Number:    number,
+					Price:     zone.Price,
+					Available: !taken[number],
+				})
+			}
+			result.Rows = append(result.Rows, seatRow)
+		}
+	}
+	return result, nil
+}
+
+// price checks picks against the seat map of schedule and the seats other orders hold on
+// flightDate in env, and prices them in currency. Passengers of orderID may keep their own seats.
+func (s *SeatService) price(env models.Environment, schedule *models.Schedule, flightDate time.Time, cabin models.CabinClass, orderID string, picks []seatPick, currency string) ([]models.SeatAssignment, error) {
+	if len(picks) == 0 {
+		return nil, nil
+	}
+
+	seatMap, err := s.seatMapRepo.FindByAircraft(schedule.Aircraft)
+	if err != nil {
+		return nil, ErrSeatMapNotFound
+	}
+	taken, err := s.takenSeats(env, schedule.ID, flightDate, orderID)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := s.currencyService.Rate(seatMap.Currency, currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []PassengerError
+	addErr := func(index int, message string) {
+		i := index
+		errs = append(errs, PassengerError{Index: &i, Field: "seat", Message: message})
+	}
+
+	picked := make(map[string]bool)
+	var assignments []models.SeatAssignment
+	for _, pick := range picks {
+		seat := strings.ToUpper(strings.TrimSpace(pick.Seat))
+		zone := seatMap.Zone(seat)
+		switch {
+		case pick.Passenger.Type == models.PassengerInfant:
+			addErr(pick.Index, "infants sit on an adult's lap and cannot have a seat")
+		case zone == nil || zone.CabinClass != cabin:
+			addErr(pick.Index, fmt.Sprintf("seat %s is not in the %s cabin", seat, cabin))
+		case taken[seat] || picked[seat]:
+			addErr(pick.Index, fmt.Sprintf("seat %s is already taken", seat))
+		default:
+			picked[seat] = true
+			assignments = append(assignments, models.SeatAssignment{
+				Environment: env,
+				ScheduleID:  schedule.ID,
+				FlightDate:  flightDate,
+				SeatNumber:  seat,
+				OrderID:     orderID,
+				PassengerID: pick.Passenger.ID,
+				Price:       models.ToMinorUnits(zone.Price*rate, currency),
+				Currency:    currency,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, &PassengerValidationError{Errors: errs}
+	}
+	return assignments, nil
+}
+
+// seatError reports a seat held by another order in the services' terms
+func seatError(err error) error {
+	if err == repository.ErrSeatTaken {
+		return ErrSeatTaken
+	}
+	return err
+}
+
+// takenSeats returns the seats held on a flight date in env, leaving out those of exceptOrderID
+func (s *SeatService) takenSeats(env models.Environment, scheduleID string, flightDate time.Time, exceptOrderID string) (map[string]bool, error) {
+	assignments, err := s.seatRepo.ListByFlight(env, scheduleID, flightDate)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(assignments))
+	for _, assignment := range assignments {
+		if exceptOrderID == "" || assignment.OrderID != exceptOrderID {
+			taken[assignment.SeatNumber] = true
+		}
+	}
+	return taken, nil
+}
+
+// withSeats rebuilds breakdown with one seat line per assignment in place of its current seat lines
+func withSeats(breakdown *models.PriceBreakdown, assignments []models.SeatAssignment) *models.PriceBreakdown {
+	result := &models.PriceBreakdown{Currency: breakdown.Currency, Items: []models.PriceLineItem{}}
+	for _, item := range breakdown.Items {
+		if item.Type != models.ChargeSeat {
+			result.Add(item)
+		}
+	}
+	for _, assignment := range assignments {
+		result.Add(models.PriceLineItem{
+			Type:       models.ChargeSeat,
+			Name:       "Seat " + assignment.SeatNumber,
+			Quantity:   1,
+			UnitAmount: assignment.Price,
+			Amount:     assignment.Price,
+		})
+	}
+	return result
+}
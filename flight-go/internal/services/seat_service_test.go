@@ -0,0 +1,176 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// withSeat picks seat for passenger at booking
+func withSeat(passenger PassengerRequest, seat string) PassengerRequest {
+	passenger.Seat = seat
+	return passenger
+}
+
+// seatTaken reports whether seat shows as taken on the economy map of order's flight
+func seatTaken(t *testing.T, s *testServices, order *models.Order, seat string) bool {
+	t.Helper()
+	seatMap, err := s.seats.FlightSeatMap("", order.ScheduleID, order.FlightDate.Format("2006-01-02"), "economy")
+	if err != nil {
+		t.Fatalf("FlightSeatMap: %v", err)
+	}
+	for _, row := range seatMap.Rows {
+		for _, info := range row.Seats {
+			if info.Number == seat {
+				return !info.Available
+			}
+		}
+	}
+	t.Fatalf("seat %s is not on the map", seat)
+	return false
+}
+
+func TestOrderCreateWithSeats(t *testing.T) {
+	s := newTestServices(t)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, withSeat(adult("Budi Santoso"), " 15a "), adult("Siti Rahayu")))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	seat := order.Passengers[0].Seat
+	if seat == nil || seat.SeatNumber != "15A" || seat.Price != 15000000 {
+		t.Fatalf("first passenger's seat = %+v, want exit row 15A at IDR 150,000", seat)
+	}
+	if order.Passengers[1].Seat != nil {
+		t.Errorf("second passenger got seat %s without picking one", order.Passengers[1].Seat.SeatNumber)
+	}
+	var seatLines int64
+	for _, item := range order.PriceBreakdown.Items {
+		if item.Type == models.ChargeSeat {
+			seatLines += item.Amount
+		}
+	}
+	if seatLines != seat.Price {
+		t.Errorf("seat lines add up to %d, want %d", seatLines, seat.Price)
+	}
+	if !seatTaken(t, s, order, "15A") || seatTaken(t, s, order, "15B") {
+		t.Error("seat map doesn't show only 15A as taken")
+	}
+}
+
+func TestOrderCreateRejectsSeats(t *testing.T) {
+	s := newTestServices(t)
+	if _, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, withSeat(adult("Budi Santoso"), "20C"))); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	infant := PassengerRequest{Title: "Ms", FullName: "Putri Santoso", Type: "infant", DateOfBirth: time.Now().AddDate(-1, 0, 0).Format("2006-01-02")}
+	tests := []struct {
+		name       string
+		passengers []PassengerRequest
+		want       []string
+	}{
+		{name: "seat held by another order", passengers: []PassengerRequest{withSeat(adult("Siti Rahayu"), "20C")}, want: []string{"0.seat"}},
+		{name: "one seat for two passengers", passengers: []PassengerRequest{withSeat(adult("Siti Rahayu"), "21A"), withSeat(adult("Agus Wijaya"), "21a")}, want: []string{"1.seat"}},
+		{name: "seat in another cabin", passengers: []PassengerRequest{withSeat(adult("Siti Rahayu"), "3A")}, want: []string{"0.seat"}},
+		{name: "seat that doesn't exist", passengers: []PassengerRequest{withSeat(adult("Siti Rahayu"), "99Z")}, want: []string{"0.seat"}},
+		{name: "infant on a seat", passengers: []PassengerRequest{adult("Siti Rahayu"), withSeat(infant, "22A")}, want: []string{"1.seat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, tt.passengers...))
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// idx_flight_seat holds a seat once per flight date, whatever the services checked before writing
+func TestSeatAssignmentUniquePerFlight(t *testing.T) {
+	s := newTestServices(t)
+	seats := repository.NewSeatAssignmentRepository(s.staging)
+	date := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	held := func(orderID, passengerID string, env models.Environment, seat string) models.SeatAssignment {
+		return models.SeatAssignment{Environment: env, ScheduleID: "schedule-cgk-dps-ga-001", FlightDate: date, SeatNumber: seat, OrderID: orderID, PassengerID: passengerID}
+	}
+
+	if err := seats.Assign([]models.SeatAssignment{held("order-a", "passenger-a", models.EnvStaging, "12A")}); err != nil {
+		t.Fatalf("Assign: %v", err)
+	}
+	err := seats.Assign([]models.SeatAssignment{
+		held("order-b", "passenger-b", models.EnvStaging, "12B"),
+		held("order-b", "passenger-c", models.EnvStaging, "12A"),
+	})
+	if !errors.Is(err, repository.ErrSeatTaken) {
+		t.Fatalf("double-booking 12A: err = %v, want ErrSeatTaken", err)
+	}
+	if list, _ := seats.ListByOrder("order-b"); len(list) != 0 {
+		t.Errorf("order-b holds %d seats after the failed assignment, want none", len(list))
+	}
+
+	// The same seat on another date or in the other environment is a different seat
+	other := held("order-c", "passenger-d", models.EnvProduction, "12A")
+	later := held("order-d", "passenger-e", models.EnvStaging, "12A")
+	later.FlightDate = date.AddDate(0, 0, 1)
+	if err := seats.Assign([]models.SeatAssignment{other, later}); err != nil {
+		t.Errorf("Assign 12A elsewhere: %v", err)
+	}
+
+	if err := seats.ReleaseByOrder("order-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := seats.Assign([]models.SeatAssignment{held("order-b", "passenger-c", models.EnvStaging, "12A")}); err != nil {
+		t.Errorf("Assign 12A after it was released: %v", err)
+	}
+}
+
+func TestOrderSelectSeats(t *testing.T) {
+	s := newTestServices(t)
+	order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, withSeat(adult("Budi Santoso"), "20A"), adult("Siti Rahayu")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7, withSeat(adult("Agus Wijaya"), "20C")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	budi, siti := order.Passengers[0].ID, order.Passengers[1].ID
+
+	if _, err := s.orders.SelectSeats(order.ID, "test", SelectSeatsRequest{Seats: []SeatSelection{{PassengerID: siti, Seat: "20C"}}}); !sameFields(passengerErrors(t, err), []string{"0.seat"}) {
+		t.Errorf("taking another order's seat: err = %v", err)
+	}
+	if _, err := s.orders.SelectSeats(order.ID, "test", SelectSeatsRequest{Seats: []SeatSelection{{PassengerID: "missing", Seat: "20B"}}}); !errors.Is(err, ErrPassengerNotFound) {
+		t.Errorf("unknown passenger: err = %v, want ErrPassengerNotFound", err)
+	}
+
+	// Budi gives 20A up and Siti takes it, in one request
+	updated, err := s.orders.SelectSeats(order.ID, "test", SelectSeatsRequest{Seats: []SeatSelection{{PassengerID: budi, Seat: ""}, {PassengerID: siti, Seat: "20A"}}})
+	if err != nil {
+		t.Fatalf("SelectSeats: %v", err)
+	}
+	for _, p := range updated.Passengers {
+		switch {
+		case p.ID == budi && p.Seat != nil:
+			t.Errorf("Budi still holds %s", p.Seat.SeatNumber)
+		case p.ID == siti && (p.Seat == nil || p.Seat.SeatNumber != "20A"):
+			t.Errorf("Siti holds %+v, want 20A", p.Seat)
+		}
+	}
+	if updated.TotalAmount != order.TotalAmount {
+		t.Errorf("total = %d, want %d for the same seat price", updated.TotalAmount, order.TotalAmount)
+	}
+	if !seatTaken(t, s, other, "20C") {
+		t.Error("the other order lost 20C")
+	}
+
+	if err := s.orders.Cancel(order.ID, "test"); err != nil {
+		t.Fatal(err)
+	}
+	if seatTaken(t, s, order, "20A") {
+		t.Error("20A is still taken after the order was cancelled")
+	}
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/cache"
+	"github.com/mirahekatiket/flight-go/internal/config"
+	"github.com/mirahekatiket/flight-go/internal/crypto"
+	"github.com/mirahekatiket/flight-go/internal/database"
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var testDBCount atomic.Int64
+
+func init() {
+	cipher, err := crypto.NewCipher("test-document-key")
+	if err != nil {
+		panic(err)
+	}
+	crypto.RegisterSerializer(cipher)
+}
+
+// openTestDB opens a migrated, seeded in-memory database for env
+// A single connection keeps the in-memory database alive and serializes writers like SQLite does.
+func openTestDB(tb testing.TB, cfg *config.Config, env models.Environment) *gorm.DB {
+	tb.Helper()
+
+	dsn := fmt.Sprintf("file:services-test-%d?mode=memory&cache=shared", testDBCount.Add(1))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		tb.Fatalf("open %s database: %v", env, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	tb.Cleanup(func() { sqlDB.Close() })
+
+	migrateTestDB(tb, db, cfg, env)
+	return db
+}
+
+// openPooledTestDB opens a migrated, seeded database file for env the way ConnectDual does,
+// with a pool of connections, so concurrent writers really race each other
+func openPooledTestDB(tb testing.TB, cfg *config.Config, env models.Environment) *gorm.DB {
+	tb.Helper()
+
+	path := filepath.Join(tb.TempDir(), string(env)+".db")
+	db, err := gorm.Open(sqlite.Open(database.SQLiteDSN(path)), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		tb.Fatalf("open %s database: %v", env, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		tb.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(8)
+	tb.Cleanup(func() { sqlDB.Close() })
+
+	migrateTestDB(tb, db, cfg, env)
+	return db
+}
+
+func migrateTestDB(tb testing.TB, db *gorm.DB, cfg *config.Config, env models.Environment) {
+	tb.Helper()
+	if err := database.Migrate(db); err != nil {
+		tb.Fatalf("migrate %s database: %v", env, err)
+	}
+	if err := database.SeedDefaultData(db, cfg, string(env)); err != nil {
+		tb.Fatalf("seed %s database: %v", env, err)
+	}
+}
+
+// testServices is the service graph cmd/server wires up, over seeded in-memory databases.
+// Staging doubles as the main database, as it does in ConnectDual.
+type testServices struct {
+	cfg        *config.Config
+	staging    *gorm.DB
+	production *gorm.DB
+	bus        *events.Bus
+
+	orderRepo   repository.OrderRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	uow         repository.UnitOfWork
+
+	whitelist  *WhitelistService
+	currency   *CurrencyService
+	pricing    *PricingService
+	fees       *FeeService
+	schedules  *ScheduleResolver
+	search     *DualScheduleService
+	cache      *SearchCache
+	promotions PromotionService
+	travelers  TravelerService
+	seats      *SeatService
+	ancillary  *AncillaryPricingService
+	orders     OrderService
+	disruption *DisruptionService
+	auth       AuthService
+}
+
+func newTestServices(tb testing.TB) *testServices {
+	tb.Helper()
+	return newTestServicesOn(tb, openTestDB)
+}
+
+// newTestServicesOn wires the service graph over databases opened by open
+func newTestServicesOn(tb testing.TB, open func(testing.TB, *config.Config, models.Environment) *gorm.DB) *testServices {
+	tb.Helper()
+
+	cfg := config.Load()
+	cfg.JWTSecret = "test-secret"
+	staging := open(tb, cfg, models.EnvStaging)
+	production := open(tb, cfg, models.EnvProduction)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tb.Cleanup(cancel)
+	bus := events.NewBus(1, 100)
+	go bus.Run(ctx)
+
+	s := &testServices{
+		cfg:         cfg,
+		staging:     staging,
+		production:  production,
+		bus:         bus,
+		orderRepo:   repository.NewOrderRepository(staging),
+		userRepo:    repository.NewUserRepository(staging),
+		sessionRepo: repository.NewSessionRepository(staging),
+		uow:         repository.NewUnitOfWork(staging),
+	}
+
+	stagingSchedules := repository.NewScheduleRepository(staging)
+	productionSchedules := repository.NewScheduleRepository(production)
+
+	s.whitelist = NewWhitelistService(repository.NewWhitelistRepository(staging), bus)
+	s.currency = NewCurrencyService(repository.NewExchangeRateRepository(staging))
+	s.pricing = NewPricingService(
+		repository.NewFareRuleRepository(staging),
+		repository.NewFareRuleRepository(production),
+		repository.NewFareFamilyRepository(staging),
+		repository.NewFareFamilyRepository(production),
+		s.orderRepo,
+		s.currency,
+	)
+	s.fees = NewFeeService(repository.NewFeeRuleRepository(staging), repository.NewFeeRuleRepository(production), s.currency)
+	s.cache = NewSearchCache(cache.NewMemory(100), cfg.SearchCacheTTL)
+	s.search = NewDualScheduleService(
+		stagingSchedules,
+		productionSchedules,
+		s.whitelist,
+		repository.NewAirlineRepository(staging),
+		s.cache,
+		s.pricing,
+		s.fees,
+		bus,
+		cfg,
+	)
+	s.schedules = NewScheduleResolver(stagingSchedules, productionSchedules, s.whitelist)
+	s.promotions = NewPromotionService(repository.NewPromotionRepository(staging), s.schedules, s.currency)
+	s.travelers = NewTravelerService(repository.NewTravelerRepository(staging))
+	s.seats = NewSeatService(repository.NewSeatMapRepository(staging), repository.NewSeatAssignmentRepository(staging), s.schedules, s.currency)
+	s.ancillary = NewAncillaryPricingService(
+		repository.NewAncillaryRepository(staging),
+		repository.NewAncillaryRepository(production),
+		s.schedules,
+		s.currency,
+		cfg.AncillaryCutoff,
+	)
+	s.orders = NewOrderService(s.orderRepo, s.schedules, s.pricing, s.fees, s.promotions, s.travelers, s.seats, s.ancillary, s.uow, bus)
+	s.auth = NewAuthService(s.userRepo, s.orderRepo, s.sessionRepo, bus, cfg)
+	s.disruption = NewDisruptionService(s.orderRepo, repository.NewScheduleDisruptionRepository(staging), s.uow, cfg.ScheduleChangeThreshold, bus)
+
+	s.cache.Subscribe(bus)
+	s.disruption.Subscribe(bus)
+	return s
+}
+
+// whitelistUser lets email book airlineIDs from production
+func (s *testServices) whitelistUser(tb testing.TB, email string, airlineIDs ...string) {
+	tb.Helper()
+	if _, err := s.whitelist.Create(CreateWhitelistRequest{Email: email, Name: email, EnabledAirlines: airlineIDs}); err != nil {
+		tb.Fatalf("whitelist %s: %v", email, err)
+	}
+}
+
+// flightDate is days from today, as an order or search date
+func flightDate(days int) string {
+	return time.Now().AddDate(0, 0, days).Format("2006-01-02")
+}
+
+// adult is a domestic adult passenger with an ID card
+func adult(name string) PassengerRequest {
+	return PassengerRequest{Title: "Mr", FullName: name, Type: "adult", DocumentType: "national_id", DocumentNumber: "3171000000000001"}
+}
+
+// bookingRequest books passengers on schedule id in economy, days from today
+func bookingRequest(scheduleID string, days int, passengers ...PassengerRequest) CreateOrderRequest {
+	if len(passengers) == 0 {
+		passengers = []PassengerRequest{adult("Budi Santoso")}
+	}
+	return CreateOrderRequest{
+		ScheduleID:   scheduleID,
+		FlightDate:   flightDate(days),
+		CabinClass:   "economy",
+		ContactName:  "Budi Santoso",
+		ContactEmail: "budi@example.com",
+		ContactPhone: "+628123456789",
+		Passengers:   passengers,
+	}
+}
+
+// child is a passenger aged years on today's date
+func child(name string, years int) PassengerRequest {
+	return PassengerRequest{Title: "Ms", FullName: name, Type: "child", DateOfBirth: time.Now().AddDate(-years, 0, -1).Format("2006-01-02")}
+}
+
+// fareLine returns the base fare line item for passengerType in breakdown
+func fareLine(breakdown *models.PriceBreakdown, passengerType models.PassengerType) (models.PriceLineItem, bool) {
+	for _, item := range breakdown.Items {
+		if item.Type == models.ChargeBaseFare && item.PassengerType == passengerType {
+			return item, true
+		}
+	}
+	return models.PriceLineItem{}, false
+}
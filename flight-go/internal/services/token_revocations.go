@@ -0,0 +1,39 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenRevocations is the revocation list of sessions whose access tokens may not have expired
+// yet. An entry is dropped once every access token of its session has.
+type TokenRevocations struct {
+	mu       sync.RWMutex
+	sessions map[string]time.Time // Session ID to when its last access token expires
+}
+
+func NewTokenRevocations() *TokenRevocations {
+	return &TokenRevocations{sessions: make(map[string]time.Time)}
+}
+
+// Revoke rejects the access tokens of a session until they expire
+func (r *TokenRevocations) Revoke(sessionID string, until time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, expiry := range r.sessions {
+		if now.After(expiry) {
+			delete(r.sessions, id)
+		}
+	}
+	r.sessions[sessionID] = until
+}
+
+// IsRevoked reports whether the access tokens of a session are rejected
+func (r *TokenRevocations) IsRevoked(sessionID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.sessions[sessionID]
+	return ok
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+func TestValidateTravelDocument(t *testing.T) {
+	flight := time.Date(2030, 6, 15, 0, 0, 0, 0, time.UTC)
+	expiresIn := func(months int) string {
+		return flight.AddDate(0, months, 0).Format("2006-01-02")
+	}
+	traveller := func(change func(p *PassengerRequest)) PassengerRequest {
+		p := PassengerRequest{Title: "Ms", FullName: "Siti Rahayu", Type: "adult", DateOfBirth: "1990-04-01", Nationality: "id",
+			DocumentType: "passport", DocumentNumber: "a 1234567", DocumentExpiry: expiresIn(24)}
+		change(&p)
+		return p
+	}
+
+	tests := []struct {
+		name      string
+		route     models.RouteType
+		passenger PassengerRequest
+		want      []string
+	}{
+		{name: "passport abroad", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) {})},
+		{name: "id card abroad", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.DocumentType = "national_id"; p.DocumentExpiry = "" }), want: []string{"0.document_type"}},
+		{name: "no document abroad", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.DocumentType, p.DocumentNumber, p.DocumentExpiry = "", "", "" }), want: []string{"0.document_number"}},
+		{name: "nationality required abroad", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.Nationality = "" }), want: []string{"0.nationality"}},
+		{name: "date of birth required abroad", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.DateOfBirth = "" }), want: []string{"0.date_of_birth"}},
+		{name: "passport valid for six months", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.DocumentExpiry = expiresIn(6) })},
+		{name: "passport valid for less than six months", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.DocumentExpiry = expiresIn(5) }), want: []string{"0.document_expiry"}},
+		{name: "passport without expiry", route: models.RouteInternational, passenger: traveller(func(p *PassengerRequest) { p.DocumentExpiry = "" }), want: []string{"0.document_expiry"}},
+		{name: "id card at home", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) {
+			p.DocumentType, p.DocumentNumber, p.DocumentExpiry, p.Nationality = "national_id", "3171000000000001", "", ""
+		})},
+		{name: "passport near expiry at home", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.DocumentExpiry = expiresIn(1) })},
+		{name: "expired before the flight", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.DocumentExpiry = flight.AddDate(0, 0, -1).Format("2006-01-02") }), want: []string{"0.document_expiry"}},
+		{name: "adult without a document at home", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.DocumentType, p.DocumentNumber, p.DocumentExpiry = "", "", "" }), want: []string{"0.document_number"}},
+		{name: "unknown document type", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.DocumentType = "driving_licence" }), want: []string{"0.document_type"}},
+		{name: "document number too short", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.DocumentNumber = "A12" }), want: []string{"0.document_number"}},
+		{name: "nationality not a country code", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.Nationality = "IDN" }), want: []string{"0.nationality"}},
+		{name: "frequent flyer number", route: models.RouteDomestic, passenger: traveller(func(p *PassengerRequest) { p.FrequentFlyerNumber = " ga-12 " }), want: []string{"0.frequent_flyer_number"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passengers, err := validatePassengers([]PassengerRequest{tt.passenger}, models.DefaultPassengerRules(), tt.route, flight)
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+			// Normalised for storage
+			if number := passengers[0].DocumentNumber; number != "A1234567" && number != "3171000000000001" {
+				t.Errorf("document number = %q", number)
+			}
+			if passengers[0].Nationality != "" && passengers[0].Nationality != "ID" {
+				t.Errorf("nationality = %q, want ID", passengers[0].Nationality)
+			}
+		})
+	}
+}
+
+// Document numbers are encrypted in the passengers table and come back decrypted
+func TestOrderCreateEncryptsDocumentNumbers(t *testing.T) {
+	s := newTestServices(t)
+	singapore := models.Schedule{
+		BaseModel: models.BaseModel{ID: "schedule-cgk-sin-ga-test"}, AirlineID: "ga", FlightNumber: "GA820",
+		DepartureAirportID: "cgk", DepartureTime: "08:00", ArrivalAirportID: "sin", ArrivalTime: "10:50",
+		Duration: 110, EconomyPrice: 1500000, EconomySeats: 150, IsActive: true,
+	}
+	if err := s.staging.Create(&singapore).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	passport := PassengerRequest{Title: "Ms", FullName: "Siti Rahayu", Type: "adult", DateOfBirth: "1990-04-01", Nationality: "ID",
+		DocumentType: "passport", DocumentNumber: "A1234567", DocumentExpiry: time.Now().AddDate(2, 0, 0).Format("2006-01-02")}
+	idCard := adult("Budi Santoso")
+
+	tests := []struct {
+		name       string
+		scheduleID string
+		passenger  PassengerRequest
+		want       []string
+	}{
+		{name: "international flight on a passport", scheduleID: singapore.ID, passenger: passport},
+		{name: "international flight on an id card", scheduleID: singapore.ID, passenger: idCard, want: []string{"0.date_of_birth", "0.nationality", "0.document_type"}},
+		{name: "domestic flight on an id card", scheduleID: "schedule-cgk-dps-ga-001", passenger: idCard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := s.orders.Create("", "", bookingRequest(tt.scheduleID, 7, tt.passenger))
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+
+			if got := order.Passengers[0].DocumentNumber; got != tt.passenger.DocumentNumber {
+				t.Errorf("document number read back as %q, want %q", got, tt.passenger.DocumentNumber)
+			}
+			var stored string
+			if err := s.staging.Raw("SELECT document_number FROM passengers WHERE order_id = ?", order.ID).Scan(&stored).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored == tt.passenger.DocumentNumber || stored == "" {
+				t.Errorf("document number stored as %q, want it encrypted", stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var ErrTravelerNotFound = errors.New("traveler not found")
+
+type TravelerService interface {
+	Create(userID string, req CreateTravelerRequest) (*models.Traveler, error)
+	GetByID(userID, id string) (*models.Traveler, error)
+	Update(userID, id string, req UpdateTravelerRequest) (*models.Traveler, error)
+	Delete(userID, id string) error
+	List(userID string, page, pageSize int) (*PaginatedResponse, error)
+	SaveFromOrder(userID string, passengers []models.Passenger) error
+}
+
+type CreateTravelerRequest struct {
+	Title               string `json:"title" binding:"required"` // Mr, Mrs, Ms
+	FullName            string `json:"full_name" binding:"required"`
+	DateOfBirth         string `json:"date_of_birth"` // YYYY-MM-DD format
+	Nationality         string `json:"nationality"`   // ISO 3166-1 alpha-2, e.g. ID
+	DocumentType        string `json:"document_type"` // passport, national_id
+	DocumentNumber      string `json:"document_number"`
+	DocumentExpiry      string `json:"document_expiry"` // YYYY-MM-DD format
+	FrequentFlyerNumber string `json:"frequent_flyer_number"`
+}
+
+// UpdateTravelerRequest changes the fields that are set, an empty string clears an optional field
+type UpdateTravelerRequest struct {
+	Title               string  `json:"title"`
+	FullName            string  `json:"full_name"`
+	DateOfBirth         *string `json:"date_of_birth"`
+	Nationality         *string `json:"nationality"`
+	DocumentType        *string `json:"document_type"`
+	DocumentNumber      *string `json:"document_number"`
+	DocumentExpiry      *string `json:"document_expiry"`
+	FrequentFlyerNumber *string `json:"frequent_flyer_number"`
+}
+
+type travelerService struct {
+	travelerRepo repository.TravelerRepository
+}
+
+func NewTravelerService(travelerRepo repository.TravelerRepository) TravelerService {
+	return &travelerService{travelerRepo: travelerRepo}
+}
+
+func (s *travelerService) Create(userID string, req CreateTravelerRequest) (*models.Traveler, error) {
+	traveler := &models.Traveler{UserID: userID}
+	if err := travelerFields(req).apply(traveler); err != nil {
+		return nil, err
+	}
+
+	if err := s.travelerRepo.Create(traveler); err != nil {
+		return nil, err
+	}
+
+	return traveler, nil
+}
+
+// GetByID returns one of the user's travelers, other users' travelers are reported as not found
+func (s *travelerService) GetByID(userID, id string) (*models.Traveler, error) {
+	traveler, err := s.travelerRepo.FindByID(id)
+	if err != nil || traveler.UserID != userID {
+		return nil, ErrTravelerNotFound
+	}
+	return traveler, nil
+}
+
+func (s *travelerService) Update(userID, id string, req UpdateTravelerRequest) (*models.Traveler, error) {
+	traveler, err := s.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := fieldsOfTraveler(traveler)
+	if req.Title != "" {
+		fields.Title = req.Title
+	}
+	if req.FullName != "" {
+		fields.FullName = req.FullName
+	}
+	if req.DateOfBirth != nil {
+		fields.DateOfBirth = *req.DateOfBirth
+	}
+	if req.Nationality != nil {
+		fields.Nationality = *req.Nationality
+	}
+	if req.DocumentType != nil {
+		fields.DocumentType = *req.DocumentType
+	}
+	if req.DocumentNumber != nil {
+		fields.DocumentNumber = *req.DocumentNumber
+	}
+	if req.DocumentExpiry != nil {
+		fields.DocumentExpiry = *req.DocumentExpiry
+	}
+	if req.FrequentFlyerNumber != nil {
+		fields.FrequentFlyerNumber = *req.FrequentFlyerNumber
+	}
+
+	if err := fields.apply(traveler); err != nil {
+		return nil, err
+	}
+
+	if err := s.travelerRepo.Update(traveler); err != nil {
+		return nil, err
+	}
+
+	return traveler, nil
+}
+
+func (s *travelerService) Delete(userID, id string) error {
+	if _, err := s.GetByID(userID, id); err != nil {
+		return err
+	}
+
+	return s.travelerRepo.Delete(id)
+}
+
+func (s *travelerService) List(userID string, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	travelers, total, err := s.travelerRepo.ListByUser(userID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       travelers,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// SaveFromOrder adds booked passengers to the user's address book.
+// Passengers already saved under the same name and date of birth are skipped.
+func (s *travelerService) SaveFromOrder(userID string, passengers []models.Passenger) error {
+	for _, p := range passengers {
+		existing, err := s.travelerRepo.FindByUserAndName(userID, p.FullName)
+		if err != nil {
+			return err
+		}
+		if containsTraveler(existing, p.DateOfBirth) {
+			continue
+		}
+
+		traveler := &models.Traveler{
+			UserID:              userID,
+			Title:               p.Title,
+			FullName:            p.FullName,
+			DateOfBirth:         p.DateOfBirth,
+			Nationality:         p.Nationality,
+			DocumentType:        p.DocumentType,
+			DocumentNumber:      p.DocumentNumber,
+			DocumentExpiry:      p.DocumentExpiry,
+			FrequentFlyerNumber: p.FrequentFlyerNumber,
+		}
+		if err := s.travelerRepo.Create(traveler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsTraveler(travelers []models.Traveler, dateOfBirth *time.Time) bool {
+	for _, t := range travelers {
+		if t.DateOfBirth == nil && dateOfBirth == nil {
+			return true
+		}
+		if t.DateOfBirth != nil && dateOfBirth != nil && t.DateOfBirth.Equal(*dateOfBirth) {
+			return true
+		}
+	}
+	return false
+}
+
+// travelerFields is a traveler as the API sends it, shared by create and update
+type travelerFields CreateTravelerRequest
+
+func fieldsOfTraveler(t *models.Traveler) travelerFields {
+	return travelerFields{
+		Title:               t.Title,
+		FullName:            t.FullName,
+		DateOfBirth:         formatDate(t.DateOfBirth),
+		Nationality:         t.Nationality,
+		DocumentType:        string(t.DocumentType),
+		DocumentNumber:      t.DocumentNumber,
+		DocumentExpiry:      formatDate(t.DocumentExpiry),
+		FrequentFlyerNumber: t.FrequentFlyerNumber,
+	}
+}
+
+// apply validates the fields' formats and copies them onto traveler.
+// Whether a document is required depends on the route, so that is checked when booking.
+func (f travelerFields) apply(traveler *models.Traveler) error {
+	var errs []PassengerError
+	addErr := func(field, message string) {
+		errs = append(errs, PassengerError{Field: field, Message: message})
+	}
+
+	traveler.Title = f.Title
+	traveler.FullName = strings.TrimSpace(f.FullName)
+	if traveler.FullName == "" {
+		addErr("full_name", "is required")
+	}
+
+	traveler.DateOfBirth = nil
+	if f.DateOfBirth != "" {
+		if dob, err := time.Parse("2006-01-02", f.DateOfBirth); err != nil {
+			addErr("date_of_birth", "must be in YYYY-MM-DD format")
+		} else if dob.After(time.Now()) {
+			addErr("date_of_birth", "is in the future")
+		} else {
+			traveler.DateOfBirth = &dob
+		}
+	}
+
+	traveler.Nationality = strings.ToUpper(f.Nationality)
+	if traveler.Nationality != "" && !countryCodePattern.MatchString(traveler.Nationality) {
+		addErr("nationality", "must be a two-letter ISO 3166 country code")
+	}
+
+	traveler.DocumentType = models.DocumentType(f.DocumentType)
+	traveler.DocumentNumber = strings.ToUpper(strings.ReplaceAll(f.DocumentNumber, " ", ""))
+	switch traveler.DocumentType {
+	case "":
+		if traveler.DocumentNumber != "" {
+			addErr("document_type", "is required with a document number")
+		}
+	case models.DocumentPassport, models.DocumentNationalID:
+		if !documentNumberPattern.MatchString(traveler.DocumentNumber) {
+			addErr("document_number", "must be 5 to 20 letters or digits")
+		}
+	default:
+		addErr("document_type", "must be passport or national_id")
+	}
+
+	traveler.DocumentExpiry = nil
+	if f.DocumentExpiry != "" {
+		if expiry, err := time.Parse("2006-01-02", f.DocumentExpiry); err != nil {
+			addErr("document_expiry", "must be in YYYY-MM-DD format")
+		} else {
+			traveler.DocumentExpiry = &expiry
+		}
+	}
+
+	traveler.FrequentFlyerNumber = strings.ToUpper(strings.TrimSpace(f.FrequentFlyerNumber))
+	if traveler.FrequentFlyerNumber != "" && !frequentFlyerPattern.MatchString(traveler.FrequentFlyerNumber) {
+		addErr("frequent_flyer_number", "must be 4 to 20 letters or digits")
+	}
+
+	if len(errs) > 0 {
+		return &PassengerValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// formatDate renders an optional date in the API's YYYY-MM-DD format
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+// Travelers belong to the user who saved them, everyone else sees them as not found
+func TestTravelerServiceOwnership(t *testing.T) {
+	s := newTestServices(t)
+	traveler, err := s.travelers.Create("user-a", CreateTravelerRequest{
+		Title: "Ms", FullName: " Siti Rahayu ", DateOfBirth: "1990-04-01", Nationality: "id",
+		DocumentType: "passport", DocumentNumber: "a 1234567", DocumentExpiry: "2035-01-01",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if traveler.FullName != "Siti Rahayu" || traveler.Nationality != "ID" || traveler.DocumentNumber != "A1234567" {
+		t.Errorf("saved %q, %q, %q, want the fields normalised", traveler.FullName, traveler.Nationality, traveler.DocumentNumber)
+	}
+
+	if _, err := s.travelers.GetByID("user-b", traveler.ID); !errors.Is(err, ErrTravelerNotFound) {
+		t.Errorf("GetByID as another user: err = %v, want ErrTravelerNotFound", err)
+	}
+	if _, err := s.travelers.Update("user-b", traveler.ID, UpdateTravelerRequest{FullName: "Someone Else"}); !errors.Is(err, ErrTravelerNotFound) {
+		t.Errorf("Update as another user: err = %v, want ErrTravelerNotFound", err)
+	}
+	if err := s.travelers.Delete("user-b", traveler.ID); !errors.Is(err, ErrTravelerNotFound) {
+		t.Errorf("Delete as another user: err = %v, want ErrTravelerNotFound", err)
+	}
+	if list, err := s.travelers.List("user-b", 1, 10); err != nil || list.TotalItems != 0 {
+		t.Errorf("List as another user = %v, %v, want nothing", list, err)
+	}
+
+	// An empty string clears an optional field, a missing one keeps it
+	empty := ""
+	if _, err := s.travelers.Update("user-a", traveler.ID, UpdateTravelerRequest{Title: "Mrs", DocumentExpiry: &empty}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	stored, err := s.travelers.GetByID("user-a", traveler.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Title != "Mrs" || stored.DocumentExpiry != nil || stored.DocumentNumber != "A1234567" {
+		t.Errorf("after update: title %q, expiry %v, document %q", stored.Title, stored.DocumentExpiry, stored.DocumentNumber)
+	}
+
+	if err := s.travelers.Delete("user-a", traveler.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.travelers.GetByID("user-a", traveler.ID); !errors.Is(err, ErrTravelerNotFound) {
+		t.Errorf("GetByID after delete: err = %v, want ErrTravelerNotFound", err)
+	}
+}
+
+func TestTravelerServiceValidatesFields(t *testing.T) {
+	s := newTestServices(t)
+	tests := []struct {
+		name string
+		req  CreateTravelerRequest
+		want []string
+	}{
+		{name: "name only", req: CreateTravelerRequest{Title: "Mr", FullName: "Budi Santoso"}},
+		{name: "blank name", req: CreateTravelerRequest{Title: "Mr", FullName: "  "}, want: []string{"full_name"}},
+		{name: "born in the future", req: CreateTravelerRequest{Title: "Mr", FullName: "Budi", DateOfBirth: flightDate(1)}, want: []string{"date_of_birth"}},
+		{name: "document number without a type", req: CreateTravelerRequest{Title: "Mr", FullName: "Budi", DocumentNumber: "A1234567"}, want: []string{"document_type"}},
+		{name: "bad nationality and frequent flyer number", req: CreateTravelerRequest{Title: "Mr", FullName: "Budi", Nationality: "IDN", FrequentFlyerNumber: "GA"}, want: []string{"nationality", "frequent_flyer_number"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.travelers.Create("user-a", tt.req)
+			if got := passengerErrors(t, err); !sameFields(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Orders book saved travelers by traveler_id, only the owner's, and save new passengers on request
+func TestOrderCreateWithSavedTravelers(t *testing.T) {
+	s := newTestServices(t)
+	traveler, err := s.travelers.Create("user-a", CreateTravelerRequest{Title: "Ms", FullName: "Siti Rahayu", DocumentType: "national_id", DocumentNumber: "3171000000000002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := bookingRequest("schedule-cgk-dps-ga-001", 7, PassengerRequest{TravelerID: traveler.ID}, adult("Budi Santoso"))
+	if _, err := s.orders.Create("user-b", "", req); !sameFields(passengerErrors(t, err), []string{"0.traveler_id"}) {
+		t.Fatalf("booking another user's traveler: err = %v", err)
+	}
+
+	req.SaveTravelers = true
+	order, err := s.orders.Create("user-a", "", req)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := order.Passengers[0]; got.FullName != "Siti Rahayu" || got.Type != "adult" {
+		t.Errorf("passenger booked from traveler = %q (%s)", got.FullName, got.Type)
+	}
+
+	// Only the inline passenger is new, and booking again doesn't save them twice
+	if _, err := s.orders.Create("user-a", "", req); err != nil {
+		t.Fatalf("Create again: %v", err)
+	}
+	list, err := s.travelers.List("user-a", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.TotalItems != 2 {
+		t.Errorf("user has %d saved travelers, want 2", list.TotalItems)
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var errInjected = errors.New("injected failure")
+
+// failingUnitOfWork runs transactions on uow with the repositories swapped by wrap, to fail a
+// write partway through
+type failingUnitOfWork struct {
+	uow  repository.UnitOfWork
+	wrap func(repos repository.Repositories) repository.Repositories
+}
+
+func (u failingUnitOfWork) Do(fn func(repos repository.Repositories) error) error {
+	return u.uow.Do(func(repos repository.Repositories) error {
+		return fn(u.wrap(repos))
+	})
+}
+
+// failingSeats fails to hold seats
+type failingSeats struct {
+	repository.SeatAssignmentRepository
+}
+
+func (failingSeats) Assign([]models.SeatAssignment) error { return errInjected }
+
+// failingHistory fails to record changes
+type failingHistory struct{ repository.OrderRepository }
+
+func (failingHistory) AddChange(*models.OrderChange) error { return errInjected }
+
+// ordersFailingWith is the test order service with its writes going through wrap
+func (s *testServices) ordersFailingWith(wrap func(repos repository.Repositories) repository.Repositories) OrderService {
+	uow := failingUnitOfWork{uow: s.uow, wrap: wrap}
+	return NewOrderService(s.orderRepo, s.schedules, s.pricing, s.fees, s.promotions, s.travelers, s.seats, s.ancillary, uow, s.bus)
+}
+
+// A booking that fails after its order and passengers are written leaves nothing behind
+func TestOrderCreateRollsBack(t *testing.T) {
+	s := newTestServices(t)
+	orders := s.ordersFailingWith(func(repos repository.Repositories) repository.Repositories {
+		repos.SeatAssignments = failingSeats{repos.SeatAssignments}
+		return repos
+	})
+
+	req := bookingRequest("schedule-cgk-dps-ga-001", 7, withSeat(adult("Budi Santoso"), "20A"), adult("Siti Rahayu"))
+	date, err := time.Parse("2006-01-02", req.FlightDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := orders.Create("", "", req); !errors.Is(err, errInjected) {
+		t.Fatalf("Create: err = %v, want the injected failure", err)
+	}
+
+	var orderCount, passengerCount int64
+	s.staging.Model(&models.Order{}).Count(&orderCount)
+	s.staging.Model(&models.Passenger{}).Count(&passengerCount)
+	if orderCount != 0 || passengerCount != 0 {
+		t.Errorf("%d orders and %d passengers saved, want none", orderCount, passengerCount)
+	}
+	booked, err := s.orderRepo.CountBookedSeats(models.EnvStaging, req.ScheduleID, date, models.CabinEconomy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if booked != 0 {
+		t.Errorf("%d seats booked, want the cabin untouched", booked)
+	}
+}
+
+// Changes after booking are all or nothing, failing to record them undoes the change itself
+func TestOrderChangesRollBack(t *testing.T) {
+	s := newTestServices(t)
+	order := changeableOrder(t, s, true)
+	passenger := order.Passengers[0]
+	if _, err := s.orders.SelectSeats(order.ID, "test", SelectSeatsRequest{Seats: []SeatSelection{{PassengerID: passenger.ID, Seat: "20A"}}}); err != nil {
+		t.Fatal(err)
+	}
+	orders := s.ordersFailingWith(func(repos repository.Repositories) repository.Repositories {
+		repos.Orders = failingHistory{repos.Orders}
+		return repos
+	})
+
+	tests := []struct {
+		name   string
+		change func() error
+	}{
+		{name: "flight change", change: func() error {
+			_, err := orders.ChangeFlight(order.ID, "test", ChangeFlightRequest{ScheduleID: "schedule-cgk-dps-ga-002", FlightDate: flightDate(14)})
+			return err
+		}},
+		{name: "name correction", change: func() error {
+			_, err := orders.CorrectName(order.ID, "test", CorrectNameRequest{PassengerID: passenger.ID, FullName: "Budhi Santoso"})
+			return err
+		}},
+		{name: "ancillaries", change: func() error {
+			_, err := orders.AddAncillaries(order.ID, "test", AddAncillariesRequest{Ancillaries: []AncillarySelection{{PassengerID: passenger.ID, AncillaryID: ancillaryID(t, s, "BAG10")}}})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := s.orders.GetByID(order.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.change(); !errors.Is(err, errInjected) {
+				t.Fatalf("err = %v, want the injected failure", err)
+			}
+
+			after, err := s.orders.GetByID(order.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if after.ScheduleID != before.ScheduleID || !after.FlightDate.Equal(before.FlightDate) || after.TotalAmount != before.TotalAmount {
+				t.Errorf("order moved to %s %s at %d, want it left on %s %s at %d",
+					after.ScheduleID, after.FlightDate.Format("2006-01-02"), after.TotalAmount,
+					before.ScheduleID, before.FlightDate.Format("2006-01-02"), before.TotalAmount)
+			}
+			for _, got := range after.Passengers {
+				if got.ID == passenger.ID && (got.FullName != passenger.FullName || len(got.Ancillaries) != 0 || got.Seat == nil || got.Seat.SeatNumber != "20A") {
+					t.Errorf("passenger is %q with %d ancillaries and seat %+v, want them unchanged", got.FullName, len(got.Ancillaries), got.Seat)
+				}
+			}
+		})
+	}
+}
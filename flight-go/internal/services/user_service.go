@@ -0,0 +1,193 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+var (
+	ErrInvalidRole   = errors.New("invalid role")
+	ErrChangeOwnUser = errors.New("admins cannot change their own role or disable themselves")
+)
+
+// UserService is how admins manage accounts. Every role change, disable and enable is recorded
+// in the user's audit trail along with the admin who made it.
+type UserService interface {
+	List(page, pageSize int) (*PaginatedResponse, error)
+	GetByID(id string) (*models.User, error)
+	ChangeRole(id, changedBy string, req ChangeRoleRequest) (*models.User, error)
+	Disable(id, changedBy string, req UserStatusRequest) (*models.User, error)
+	Enable(id, changedBy string, req UserStatusRequest) (*models.User, error)
+	History(id string) ([]models.UserChange, error)
+}
+
+type ChangeRoleRequest struct {
+	Role   string `json:"role" binding:"required"` // user or admin
+	Reason string `json:"reason"`
+}
+
+type UserStatusRequest struct {
+	Reason string `json:"reason"`
+}
+
+type userService struct {
+	userRepo    repository.UserRepository
+	unitOfWork  repository.UnitOfWork
+	authService AuthService
+}
+
+func NewUserService(userRepo repository.UserRepository, unitOfWork repository.UnitOfWork, authService AuthService) UserService {
+	return &userService{
+		userRepo:    userRepo,
+		unitOfWork:  unitOfWork,
+		authService: authService,
+	}
+}
+
+func (s *userService) List(page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	users, total, err := s.userRepo.List(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       users,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (s *userService) GetByID(id string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// ChangeRole moves a user to another role. Their tokens carry the old role, so the token version
+// is bumped and admin tokens issued before stop working straight away.
+func (s *userService) ChangeRole(id, changedBy string, req ChangeRoleRequest) (*models.User, error) {
+	role := models.Role(req.Role)
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	if id == changedBy {
+		return nil, ErrChangeOwnUser
+	}
+
+	return s.update(id, func(users repository.UserRepository, user *models.User) (*models.UserChange, error) {
+		if user.Role == role {
+			return nil, nil
+		}
+		change := userChange(user, models.UserChangeRole, changedBy, req.Reason)
+		change.Before["role"] = string(user.Role)
+		change.After["role"] = string(role)
+		return change, users.ChangeRole(id, role)
+	})
+}
+
+// Disable locks a user out: they can no longer log in or refresh, and every session is revoked
+func (s *userService) Disable(id, changedBy string, req UserStatusRequest) (*models.User, error) {
+	if id == changedBy {
+		return nil, ErrChangeOwnUser
+	}
+
+	var disabled bool
+	user, err := s.update(id, func(users repository.UserRepository, user *models.User) (*models.UserChange, error) {
+		if user.DisabledAt != nil {
+			return nil, nil
+		}
+		now := time.Now()
+		change := userChange(user, models.UserChangeDisabled, changedBy, req.Reason)
+		change.After["disabled_at"] = now.Format(time.RFC3339)
+		disabled = true
+		return change, users.Disable(id, now)
+	})
+	if err != nil || !disabled {
+		return user, err
+	}
+
+	// The user is disabled by now, and their tokens are stale whatever happens here
+	if err := s.authService.RevokeAllSessions(id); err != nil {
+		log.Printf("Revoking sessions of disabled user %s failed: %v", id, err)
+	}
+	return user, nil
+}
+
+// Enable lets a disabled user log in again
+func (s *userService) Enable(id, changedBy string, req UserStatusRequest) (*models.User, error) {
+	return s.update(id, func(users repository.UserRepository, user *models.User) (*models.UserChange, error) {
+		if user.DisabledAt == nil {
+			return nil, nil
+		}
+		change := userChange(user, models.UserChangeEnabled, changedBy, req.Reason)
+		change.Before["disabled_at"] = user.DisabledAt.Format(time.RFC3339)
+		return change, users.Enable(id)
+	})
+}
+
+// History returns a user's audit trail, oldest first
+func (s *userService) History(id string) ([]models.UserChange, error) {
+	if _, err := s.GetByID(id); err != nil {
+		return nil, err
+	}
+	return s.userRepo.ListChanges(id)
+}
+
+// userChange starts an audit entry for user
+func userChange(user *models.User, changeType models.UserChangeType, changedBy, reason string) *models.UserChange {
+	return &models.UserChange{
+		UserID:    user.ID,
+		Type:      changeType,
+		ChangedBy: changedBy,
+		Reason:    reason,
+		Before:    map[string]string{},
+		After:     map[string]string{},
+	}
+}
+
+// update reads the user and applies change to it in one transaction, so concurrent admin actions
+// each see the other's result. change writes its columns and returns the audit entry to record
+// with them, nil when the user is already as asked and nothing was written. It returns the user
+// as stored afterwards.
+func (s *userService) update(id string, change func(users repository.UserRepository, user *models.User) (*models.UserChange, error)) (*models.User, error) {
+	var user *models.User
+	err := s.unitOfWork.Do(func(repos repository.Repositories) error {
+		var err error
+		if user, err = repos.Users.FindByID(id); err != nil {
+			return ErrUserNotFound
+		}
+		record, err := change(repos.Users, user)
+		if err != nil || record == nil {
+			return err
+		}
+		if err := repos.Users.AddChange(record); err != nil {
+			return err
+		}
+		user, err = repos.Users.FindByID(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
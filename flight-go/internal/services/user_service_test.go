@@ -0,0 +1,71 @@
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+)
+
+// usersReadTogether holds every lookup of a user until both admins have read it, so each acts
+// on the user as it was before the other
+type usersReadTogether struct {
+	repository.UserRepository
+	read *sync.WaitGroup
+}
+
+func (r usersReadTogether) FindByID(id string) (*models.User, error) {
+	user, err := r.UserRepository.FindByID(id)
+	r.read.Done()
+	r.read.Wait()
+	return user, err
+}
+
+// Two admins changing the same user at once both land, neither overwrites the other
+func TestUserChangesConcurrently(t *testing.T) {
+	s := newTestServicesOn(t, openPooledTestDB)
+	tokens := register(t, s)
+	before, err := s.userRepo.FindByID(tokens.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var read sync.WaitGroup
+	read.Add(2)
+	users := NewUserService(usersReadTogether{UserRepository: s.userRepo, read: &read}, s.uow, s.auth)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, errs[0] = users.ChangeRole(tokens.User.ID, "admin-1", ChangeRoleRequest{Role: string(models.RoleAdmin)})
+	}()
+	go func() {
+		defer wg.Done()
+		_, errs[1] = users.Disable(tokens.User.ID, "admin-2", UserStatusRequest{Reason: "fraud"})
+	}()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	after, err := s.userRepo.FindByID(tokens.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Role != models.RoleAdmin || after.DisabledAt == nil || after.TokenVersion != before.TokenVersion+2 {
+		t.Errorf("user is %s, disabled at %v, token version %d, want a disabled admin at version %d",
+			after.Role, after.DisabledAt, after.TokenVersion, before.TokenVersion+2)
+	}
+	history, err := s.userRepo.ListChanges(tokens.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 {
+		t.Errorf("%d changes recorded, want 2", len(history))
+	}
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/url"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+	"github.com/mirahekatiket/flight-go/internal/webhooks"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrInvalidWebhook          = errors.New("invalid webhook subscription")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+type WebhookService interface {
+	Create(req CreateWebhookRequest) (*models.WebhookSubscription, error)
+	GetByID(id string) (*models.WebhookSubscription, error)
+	Update(id string, req UpdateWebhookRequest) (*models.WebhookSubscription, error)
+	Delete(id string) error
+	List(airlineID string, page, pageSize int) (*PaginatedResponse, error)
+	ListDeliveries(subscriptionID string, status models.WebhookDeliveryStatus, page, pageSize int) (*PaginatedResponse, error)
+	Redeliver(deliveryID string) (*models.WebhookDelivery, error)
+}
+
+type CreateWebhookRequest struct {
+	AirlineID   string   `json:"airline_id" binding:"required"`
+	URL         string   `json:"url" binding:"required"` // http or https
+	Description string   `json:"description"`
+	Events      []string `json:"events"` // order.created, order.cancelled, whitelist.granted, whitelist.revoked, empty for all
+	IsActive    *bool    `json:"is_active"`
+}
+
+type UpdateWebhookRequest struct {
+	URL          string   `json:"url"`
+	Description  *string  `json:"description"`
+	Events       []string `json:"events"` // Replaces the filter when sent, [] for all events
+	IsActive     *bool    `json:"is_active"`
+	RotateSecret bool     `json:"rotate_secret"` // Issue a new signing secret, returned in the response
+}
+
+type webhookService struct {
+	subscriptionRepo repository.WebhookSubscriptionRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	dispatcher       *webhooks.Dispatcher
+}
+
+func NewWebhookService(subscriptionRepo repository.WebhookSubscriptionRepository, deliveryRepo repository.WebhookDeliveryRepository, dispatcher *webhooks.Dispatcher) WebhookService {
+	return &webhookService{
+		subscriptionRepo: subscriptionRepo,
+		deliveryRepo:     deliveryRepo,
+		dispatcher:       dispatcher,
+	}
+}
+
+// Create subscribes a partner URL to an airline's events. The response carries the signing
+// secret, which is not shown again.
+func (s *webhookService) Create(req CreateWebhookRequest) (*models.WebhookSubscription, error) {
+	subscription := &models.WebhookSubscription{
+		AirlineID:   req.AirlineID,
+		URL:         req.URL,
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+
+	events, err := toWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Events = events
+	if !validWebhookURL(subscription.URL) {
+		return nil, ErrInvalidWebhook
+	}
+
+	if subscription.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscriptionRepo.Create(subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (s *webhookService) GetByID(id string) (*models.WebhookSubscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// Update changes a subscription. The signing secret is only returned when rotated.
+func (s *webhookService) Update(id string, req UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	if req.URL != "" {
+		if !validWebhookURL(req.URL) {
+			return nil, ErrInvalidWebhook
+		}
+		subscription.URL = req.URL
+	}
+	if req.Description != nil {
+		subscription.Description = *req.Description
+	}
+	if req.Events != nil {
+		if subscription.Events, err = toWebhookEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+	if req.RotateSecret {
+		if subscription.Secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.subscriptionRepo.Update(subscription); err != nil {
+		return nil, err
+	}
+
+	if !req.RotateSecret {
+		subscription.Secret = ""
+	}
+	return subscription, nil
+}
+
+// Delete removes a subscription. Its deliveries still pending go to the dead-letter list.
+func (s *webhookService) Delete(id string) error {
+	if _, err := s.subscriptionRepo.FindByID(id); err != nil {
+		return ErrWebhookNotFound
+	}
+	return s.subscriptionRepo.Delete(id)
+}
+
+// List returns a page of subscriptions, optionally of one airline
+func (s *webhookService) List(airlineID string, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	subscriptions, total, err := s.subscriptionRepo.List(airlineID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       subscriptions,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// ListDeliveries returns a page of the delivery log, newest first. subscriptionID and status are
+// optional filters, status dead gives the dead-letter list.
+func (s *webhookService) ListDeliveries(subscriptionID string, status models.WebhookDeliveryStatus, page, pageSize int) (*PaginatedResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+
+	deliveries, total, err := s.deliveryRepo.List(subscriptionID, status, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize > 0 {
+		totalPages++
+	}
+
+	return &PaginatedResponse{
+		Data:       deliveries,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Redeliver queues a delivery again with the same payload and event ID and a fresh set of
+// attempts, typically one from the dead-letter list once the partner is back up
+func (s *webhookService) Redeliver(deliveryID string) (*models.WebhookDelivery, error) {
+	delivery, err := s.deliveryRepo.FindByID(deliveryID)
+	if err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err := s.dispatcher.Redeliver(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// toWebhookEvents checks an event filter
+func toWebhookEvents(names []string) ([]models.WebhookEvent, error) {
+	events := make([]models.WebhookEvent, len(names))
+	for i, name := range names {
+		events[i] = models.WebhookEvent(name)
+		if !events[i].IsValid() {
+			return nil, ErrInvalidWebhook
+		}
+	}
+	return events, nil
+}
+
+// validWebhookURL reports whether raw is an absolute http or https URL
+func validWebhookURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// newWebhookSecret returns a random signing secret
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
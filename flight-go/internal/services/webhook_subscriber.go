@@ -0,0 +1,120 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/mirahekatiket/flight-go/internal/events"
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/webhooks"
+)
+
+// WebhookSubscriber tells partners about the orders on their flights and the users whose access
+// to their airline changed, through their webhook subscriptions
+type WebhookSubscriber struct {
+	dispatcher *webhooks.Dispatcher
+}
+
+func NewWebhookSubscriber(dispatcher *webhooks.Dispatcher) *WebhookSubscriber {
+	return &WebhookSubscriber{dispatcher: dispatcher}
+}
+
+// Subscribe queues webhook deliveries after the events partners are sent, off the request path
+func (w *WebhookSubscriber) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, "webhooks", events.Async, func(e events.OrderCreated) error {
+		return w.publishOrder(models.WebhookOrderCreated, e.Order)
+	})
+	events.Subscribe(bus, "webhooks", events.Async, func(e events.OrderStatusChanged) error {
+		if e.Order.Status != models.OrderCancelled {
+			return nil
+		}
+		return w.publishOrder(models.WebhookOrderCancelled, e.Order)
+	})
+	events.Subscribe(bus, "webhooks", events.Async, w.whitelistChanged)
+}
+
+// webhookOrder is what partners are told about an order on one of their flights
+type webhookOrder struct {
+	OrderID      string             `json:"order_id"`
+	PNR          string             `json:"pnr"`
+	Environment  models.Environment `json:"environment"`
+	Status       models.OrderStatus `json:"status"`
+	ScheduleID   string             `json:"schedule_id"`
+	FlightNumber string             `json:"flight_number"`
+	FlightDate   string             `json:"flight_date"` // YYYY-MM-DD
+	CabinClass   models.CabinClass  `json:"cabin_class"`
+	TotalAmount  int64              `json:"total_amount"` // In minor units of currency
+	Currency     string             `json:"currency"`
+	Passengers   []webhookPassenger `json:"passengers"`
+}
+
+type webhookPassenger struct {
+	Title        string               `json:"title"`
+	FullName     string               `json:"full_name"`
+	Type         models.PassengerType `json:"type"`
+	TicketNumber string               `json:"ticket_number,omitempty"`
+}
+
+// publishOrder queues event about order for the partner webhooks of its airline, taken from the
+// flight it was sold as once its schedule is deleted. Orders that cannot be tied to an airline,
+// snapshotted before the airline ID was kept and their schedule since deleted, are skipped.
+func (w *WebhookSubscriber) publishOrder(event models.WebhookEvent, order *models.Order) error {
+	var airlineID string
+	switch {
+	case order.Schedule != nil:
+		airlineID = order.Schedule.AirlineID
+	case order.Flight != nil:
+		airlineID = order.Flight.AirlineID
+	}
+	if airlineID == "" {
+		return nil
+	}
+
+	data := webhookOrder{
+		OrderID:     order.ID,
+		PNR:         order.PNR,
+		Environment: order.Environment,
+		Status:      order.Status,
+		ScheduleID:  order.ScheduleID,
+		FlightDate:  order.FlightDate.Format("2006-01-02"),
+		CabinClass:  order.CabinClass,
+		TotalAmount: order.TotalAmount,
+		Currency:    order.Currency,
+		Passengers:  make([]webhookPassenger, len(order.Passengers)),
+	}
+	if order.Flight != nil {
+		data.FlightNumber = order.Flight.FlightNumber
+	}
+	for i, p := range order.Passengers {
+		data.Passengers[i] = webhookPassenger{Title: p.Title, FullName: p.FullName, Type: p.Type}
+		if p.TicketNumber != nil {
+			data.Passengers[i].TicketNumber = *p.TicketNumber
+		}
+	}
+
+	return w.dispatcher.Publish(airlineID, event, data)
+}
+
+// webhookWhitelistAccess is what partners are told about a user whose access to their airline changed
+type webhookWhitelistAccess struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// whitelistChanged tells every airline whose access for the user changed
+func (w *WebhookSubscriber) whitelistChanged(e events.WhitelistChanged) error {
+	data := webhookWhitelistAccess{Email: e.User.Email, Name: e.User.Name}
+	for _, changes := range []struct {
+		event    models.WebhookEvent
+		airlines []string
+	}{
+		{models.WebhookWhitelistGranted, e.Granted},
+		{models.WebhookWhitelistRevoked, e.Revoked},
+	} {
+		for _, airlineID := range changes.airlines {
+			if err := w.dispatcher.Publish(airlineID, changes.event, data); err != nil {
+				return fmt.Errorf("%s for airline %s: %w", changes.event, airlineID, err)
+			}
+		}
+	}
+	return nil
+}
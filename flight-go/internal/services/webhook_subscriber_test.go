@@ -0,0 +1,75 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+	"github.com/mirahekatiket/flight-go/internal/repository"
+	"github.com/mirahekatiket/flight-go/internal/webhooks"
+)
+
+// Orders are sent to the webhooks of their airline, taken from the flight they were sold as once
+// their schedule is deleted
+func TestWebhookSubscriberRoutesOrders(t *testing.T) {
+	tests := []struct {
+		name          string
+		deleted       bool
+		oldSnapshot   bool // Taken before snapshots kept the airline ID
+		wantDelivered bool
+	}{
+		{name: "live schedule", wantDelivered: true},
+		{name: "deleted schedule", deleted: true, wantDelivered: true},
+		{name: "old snapshot of a live schedule", oldSnapshot: true, wantDelivered: true},
+		{name: "old snapshot of a deleted schedule", deleted: true, oldSnapshot: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServices(t)
+			subscriptions := repository.NewWebhookSubscriptionRepository(s.staging)
+			deliveries := repository.NewWebhookDeliveryRepository(s.staging)
+			if err := subscriptions.Create(&models.WebhookSubscription{AirlineID: "ga", URL: "https://partner.example.com/hooks", Secret: "whsec_test", IsActive: true}); err != nil {
+				t.Fatal(err)
+			}
+			subscriber := NewWebhookSubscriber(webhooks.NewDispatcher(subscriptions, deliveries, time.Second, 3, time.Minute))
+
+			order, err := s.orders.Create("", "", bookingRequest("schedule-cgk-dps-ga-001", 7))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.oldSnapshot {
+				if err := s.staging.Exec(`UPDATE orders SET flight = json_remove(flight, '$.airline_id') WHERE id = ?`, order.ID).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.deleted {
+				schedules := NewScheduleService(repository.NewScheduleRepository(s.staging), s.bus, models.EnvStaging)
+				if err := schedules.Delete(order.ScheduleID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			stored, err := s.orders.GetByID(order.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (stored.Schedule == nil) != tt.deleted || stored.ScheduleChanged != tt.deleted {
+				t.Fatalf("order has schedule %v, changed = %v, want deleted = %v", stored.Schedule, stored.ScheduleChanged, tt.deleted)
+			}
+
+			if err := subscriber.publishOrder(models.WebhookOrderCancelled, stored); err != nil {
+				t.Fatalf("publishOrder: %v", err)
+			}
+			queued, _, err := deliveries.List("", "", 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case !tt.wantDelivered && len(queued) != 0:
+				t.Errorf("%d deliveries queued for an order tied to no airline", len(queued))
+			case tt.wantDelivered && (len(queued) != 1 || queued[0].AirlineID != "ga" || !strings.Contains(queued[0].Payload, order.PNR)):
+				t.Errorf("queued %+v, want one delivery of %s to ga", queued, order.PNR)
+			}
+		})
+	}
+}
@@ -4,17 +4,19 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/mirahekatiket/flight-go/internal/events"
 	"github.com/mirahekatiket/flight-go/internal/models"
 	"github.com/mirahekatiket/flight-go/internal/repository"
 	"gorm.io/gorm"
 )
 
 type WhitelistService struct {
-	repo *repository.WhitelistRepository
+	repo   *repository.WhitelistRepository
+	events *events.Bus
 }
 
-func NewWhitelistService(repo *repository.WhitelistRepository) *WhitelistService {
-	return &WhitelistService{repo: repo}
+func NewWhitelistService(repo *repository.WhitelistRepository, bus *events.Bus) *WhitelistService {
+	return &WhitelistService{repo: repo, events: bus}
 }
 
 type CreateWhitelistRequest struct {
@@ -51,6 +53,8 @@ func (s *WhitelistService) Create(req CreateWhitelistRequest) (*models.Whitelist
 	// Parse enabled airlines for response
 	whitelistedUser.EnabledAirlineIDs = req.EnabledAirlines
 
+	s.accessChanged(whitelistedUser, "")
+
 	return whitelistedUser, nil
 }
 
@@ -83,6 +87,7 @@ func (s *WhitelistService) Update(id string, req UpdateWhitelistRequest) (*model
 		whitelistedUser.Name = req.Name
 	}
 
+	before := whitelistedUser.EnabledAirlines
 	if req.EnabledAirlines != nil {
 		whitelistedUser.EnabledAirlines = strings.Join(req.EnabledAirlines, ",")
 		whitelistedUser.EnabledAirlineIDs = req.EnabledAirlines
@@ -92,11 +97,26 @@ func (s *WhitelistService) Update(id string, req UpdateWhitelistRequest) (*model
 		return nil, err
 	}
 
+	s.accessChanged(whitelistedUser, before)
+
 	return whitelistedUser, nil
 }
 
 func (s *WhitelistService) Delete(id string) error {
-	return s.repo.Delete(id)
+	whitelistedUser, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	before := whitelistedUser.EnabledAirlines
+	whitelistedUser.EnabledAirlines = ""
+	s.accessChanged(whitelistedUser, before)
+
+	return nil
 }
 
 func (s *WhitelistService) ToggleAirlineAccess(id string, airlineID string) (*models.WhitelistedUser, error) {
@@ -127,6 +147,7 @@ func (s *WhitelistService) ToggleAirlineAccess(id string, airlineID string) (*mo
 		newEnabledAirlines = append(newEnabledAirlines, airlineID)
 	}
 
+	before := whitelistedUser.EnabledAirlines
 	whitelistedUser.EnabledAirlines = strings.Join(newEnabledAirlines, ",")
 	whitelistedUser.EnabledAirlineIDs = newEnabledAirlines
 
@@ -134,6 +155,8 @@ func (s *WhitelistService) ToggleAirlineAccess(id string, airlineID string) (*mo
 		return nil, err
 	}
 
+	s.accessChanged(whitelistedUser, before)
+
 	return whitelistedUser, nil
 }
 
@@ -145,3 +168,36 @@ func (s *WhitelistService) HasAirlineAccess(email string, airlineID string) (boo
 	return s.repo.HasAirlineAccess(email, airlineID)
 }
 
+// accessChanged publishes which airlines a whitelisted user gained and lost from before, the
+// previous comma separated list
+func (s *WhitelistService) accessChanged(whitelistedUser *models.WhitelistedUser, before string) {
+	s.events.Publish(events.WhitelistChanged{
+		User:    whitelistedUser,
+		Granted: newAirlines(before, splitAirlines(whitelistedUser.EnabledAirlines)),
+		Revoked: newAirlines(whitelistedUser.EnabledAirlines, splitAirlines(before)),
+	})
+}
+
+// newAirlines returns the airlines in enabled that the comma separated current list lacks
+func newAirlines(current string, enabled []string) []string {
+	have := make(map[string]bool)
+	for _, id := range splitAirlines(current) {
+		have[id] = true
+	}
+
+	var added []string
+	for _, id := range enabled {
+		if !have[id] {
+			added = append(added, id)
+		}
+	}
+	return added
+}
+
+// splitAirlines parses a comma separated list of airline IDs
+func splitAirlines(airlines string) []string {
+	if airlines == "" {
+		return nil
+	}
+	return strings.Split(airlines, ",")
+}
@@ -0,0 +1,251 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+// How often Run looks for deliveries due a retry, new ones wake it straight away
+const pollInterval = 5 * time.Second
+
+// How many due deliveries Run picks up at a time
+const batchSize = 50
+
+var (
+	errSubscriptionGone     = errors.New("subscription was deleted")
+	errSubscriptionInactive = errors.New("subscription is inactive")
+)
+
+// Subscriptions finds who to send an airline's events to
+type Subscriptions interface {
+	ListActiveByAirline(airlineID string) ([]models.WebhookSubscription, error)
+}
+
+// Deliveries keeps queued deliveries, and every attempt at them as the delivery log
+type Deliveries interface {
+	CreateBatch(deliveries []models.WebhookDelivery) error
+	// ListDue returns pending deliveries whose next attempt is at or before now, oldest first,
+	// with their subscription
+	ListDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	Update(delivery *models.WebhookDelivery) error
+}
+
+// Payload is the JSON body of every delivery
+type Payload struct {
+	ID        string              `json:"id"` // The event's, the same on a retry or redelivery
+	Event     models.WebhookEvent `json:"event"`
+	AirlineID string              `json:"airline_id"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      any                 `json:"data"`
+}
+
+// Dispatcher posts airline events to the partner subscriptions filtering them in. Deliveries
+// are stored before they are attempted, failures are retried with exponential backoff, and
+// after maxAttempts they are dead until redelivered. A nil Dispatcher drops every event.
+type Dispatcher struct {
+	subscriptions Subscriptions
+	deliveries    Deliveries
+	client        *http.Client
+	maxAttempts   int
+	retryDelay    time.Duration
+	wake          chan struct{}
+}
+
+// NewDispatcher retries a failed delivery after retryDelay, doubling the wait after each
+// further failure, and gives up after maxAttempts. Partners have timeout to respond.
+func NewDispatcher(subscriptions Subscriptions, deliveries Deliveries, timeout time.Duration, maxAttempts int, retryDelay time.Duration) *Dispatcher {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &Dispatcher{
+		subscriptions: subscriptions,
+		deliveries:    deliveries,
+		client:        &http.Client{Timeout: timeout},
+		maxAttempts:   maxAttempts,
+		retryDelay:    retryDelay,
+		wake:          make(chan struct{}, 1),
+	}
+}
+
+// Publish queues event about airlineID, carrying data, for every active subscription of the
+// airline that filters it in
+func (d *Dispatcher) Publish(airlineID string, event models.WebhookEvent, data any) error {
+	if d == nil || airlineID == "" {
+		return nil
+	}
+
+	subscriptions, err := d.subscriptions.ListActiveByAirline(airlineID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	payload := Payload{ID: uuid.New().String(), Event: event, AirlineID: airlineID, CreatedAt: now, Data: data}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(event) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			AirlineID:      airlineID,
+			EventID:        payload.ID,
+			Event:          event,
+			Payload:        string(body),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.deliveries.CreateBatch(deliveries); err != nil {
+		return err
+	}
+
+	d.poke()
+	return nil
+}
+
+// Redeliver queues delivery again with a fresh set of attempts, whatever became of it
+func (d *Dispatcher) Redeliver(delivery *models.WebhookDelivery) error {
+	now := time.Now()
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	delivery.LastError = ""
+	delivery.LastStatusCode = 0
+	if err := d.deliveries.Update(delivery); err != nil {
+		return err
+	}
+
+	d.poke()
+	return nil
+}
+
+// Run delivers queued events until ctx is done. Run it once per store, so no delivery is
+// attempted twice at a time.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// poke wakes Run for deliveries that were just queued
+func (d *Dispatcher) poke() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// deliverDue attempts every delivery that is due, a batch at a time
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		deliveries, err := d.deliveries.ListDue(time.Now(), batchSize)
+		if err != nil {
+			log.Printf("Listing due webhooks failed: %v", err)
+			return
+		}
+		for i := range deliveries {
+			d.deliver(ctx, &deliveries[i])
+		}
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one attempt at delivery and records how it went. Deliveries to deleted or
+// inactive subscriptions go straight to the dead-letter list.
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) {
+	var status int
+	var err error
+	switch subscription := delivery.Subscription; {
+	case subscription == nil:
+		err = errSubscriptionGone
+	case !subscription.IsActive:
+		err = errSubscriptionInactive
+	default:
+		status, err = d.post(ctx, subscription, delivery)
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastStatusCode = status
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+	case delivery.Attempts >= d.maxAttempts || err == errSubscriptionGone || err == errSubscriptionInactive:
+		delivery.Status = models.WebhookDeliveryDead
+		delivery.NextAttemptAt = nil
+		delivery.LastError = err.Error()
+	default:
+		next := now.Add(d.retryDelay << (delivery.Attempts - 1))
+		delivery.NextAttemptAt = &next
+		delivery.LastError = err.Error()
+	}
+
+	if err := d.deliveries.Update(delivery); err != nil {
+		log.Printf("Recording webhook delivery %s failed: %v", delivery.ID, err)
+	}
+}
+
+// post sends the signed payload of delivery to subscription and returns the response status.
+// Anything other than 2xx is an error.
+func (d *Dispatcher) post(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, string(delivery.Event))
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drained so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("partner returned %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,279 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mirahekatiket/flight-go/internal/models"
+)
+
+// memoryStore keeps subscriptions and deliveries in memory, deliveries in the order they were queued
+type memoryStore struct {
+	mu            sync.Mutex
+	subscriptions []models.WebhookSubscription
+	deliveries    []models.WebhookDelivery
+}
+
+func (s *memoryStore) ListActiveByAirline(airlineID string) ([]models.WebhookSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var active []models.WebhookSubscription
+	for _, subscription := range s.subscriptions {
+		if subscription.AirlineID == airlineID && subscription.IsActive {
+			active = append(active, subscription)
+		}
+	}
+	return active, nil
+}
+
+func (s *memoryStore) CreateBatch(deliveries []models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, delivery := range deliveries {
+		delivery.ID = fmt.Sprintf("delivery-%d", len(s.deliveries)+1)
+		s.deliveries = append(s.deliveries, delivery)
+	}
+	return nil
+}
+
+func (s *memoryStore) ListDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []models.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) || len(due) == limit {
+			continue
+		}
+		for i := range s.subscriptions {
+			if s.subscriptions[i].ID == delivery.SubscriptionID {
+				subscription := s.subscriptions[i]
+				delivery.Subscription = &subscription
+			}
+		}
+		due = append(due, delivery)
+	}
+	return due, nil
+}
+
+func (s *memoryStore) Update(delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.deliveries {
+		if s.deliveries[i].ID == delivery.ID {
+			s.deliveries[i] = *delivery
+			s.deliveries[i].Subscription = nil
+			return nil
+		}
+	}
+	return errors.New("unknown delivery")
+}
+
+// only returns the one delivery in the store
+func (s *memoryStore) only(t *testing.T) models.WebhookDelivery {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.deliveries) != 1 {
+		t.Fatalf("%d deliveries queued, want 1", len(s.deliveries))
+	}
+	return s.deliveries[0]
+}
+
+// partner is a webhook endpoint answering with status, recording the requests it gets
+type partner struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []received
+}
+
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+func newPartner(t *testing.T, status int) *partner {
+	p := &partner{status: status}
+	p.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.requests = append(p.requests, received{header: r.Header.Clone(), body: body})
+		w.WriteHeader(p.status)
+	}))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *partner) respond(status int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = status
+}
+
+func (p *partner) received() []received {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]received(nil), p.requests...)
+}
+
+func subscription(url string, events ...models.WebhookEvent) models.WebhookSubscription {
+	return models.WebhookSubscription{
+		BaseModel: models.BaseModel{ID: "subscription-1"},
+		AirlineID: "ga",
+		URL:       url,
+		Secret:    "whsec_test",
+		Events:    events,
+		IsActive:  true,
+	}
+}
+
+func TestDispatcherSignsDeliveries(t *testing.T) {
+	p := newPartner(t, http.StatusNoContent)
+	store := &memoryStore{subscriptions: []models.WebhookSubscription{subscription(p.URL, models.WebhookOrderCreated)}}
+	dispatcher := NewDispatcher(store, store, time.Second, 3, time.Minute)
+
+	if err := dispatcher.Publish("ga", models.WebhookOrderCancelled, map[string]string{"pnr": "ABC123"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.deliveries) != 0 {
+		t.Fatal("queued an event the subscription filters out")
+	}
+	if err := dispatcher.Publish("ga", models.WebhookOrderCreated, map[string]string{"pnr": "ABC123"}); err != nil {
+		t.Fatal(err)
+	}
+	dispatcher.deliverDue(context.Background())
+
+	delivery := store.only(t)
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusNoContent {
+		t.Errorf("delivery %+v, want delivered on the first attempt", delivery)
+	}
+	requests := p.received()
+	if len(requests) != 1 {
+		t.Fatalf("partner got %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+	if string(req.body) != delivery.Payload {
+		t.Errorf("posted %s, want the stored payload %s", req.body, delivery.Payload)
+	}
+	var payload Payload
+	if err := json.Unmarshal(req.body, &payload); err != nil || payload.Event != models.WebhookOrderCreated || payload.ID != delivery.EventID || payload.AirlineID != "ga" {
+		t.Errorf("payload %+v (%v), want order.created for ga under the event ID", payload, err)
+	}
+	if req.header.Get(HeaderEvent) != string(models.WebhookOrderCreated) || req.header.Get(HeaderEventID) != delivery.EventID || req.header.Get(HeaderDelivery) != delivery.ID {
+		t.Errorf("event headers %v", req.header)
+	}
+
+	// The signature covers the timestamp header and the body as sent, and nothing else matches it
+	timestamp, err := strconv.ParseInt(req.header.Get(HeaderTimestamp), 10, 64)
+	if err != nil || time.Since(time.Unix(timestamp, 0)) > time.Minute {
+		t.Fatalf("timestamp header %q, want the time it was sent", req.header.Get(HeaderTimestamp))
+	}
+	signature := req.header.Get(HeaderSignature)
+	if signature != Sign("whsec_test", timestamp, req.body) {
+		t.Errorf("signature %q doesn't match the body", signature)
+	}
+	for name, other := range map[string]string{
+		"other secret":    Sign("whsec_other", timestamp, req.body),
+		"other timestamp": Sign("whsec_test", timestamp+1, req.body),
+		"other body":      Sign("whsec_test", timestamp, append(req.body, ' ')),
+	} {
+		if other == signature {
+			t.Errorf("%s signs the same", name)
+		}
+	}
+}
+
+// Non-2xx responses are retried after the retry delay, doubling each time, until the attempts run out
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	p := newPartner(t, http.StatusServiceUnavailable)
+	store := &memoryStore{subscriptions: []models.WebhookSubscription{subscription(p.URL)}}
+	dispatcher := NewDispatcher(store, store, time.Second, 3, time.Minute)
+	if err := dispatcher.Publish("ga", models.WebhookOrderCreated, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for attempt, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		dispatcher.deliverDue(context.Background())
+		delivery := store.only(t)
+		if delivery.Status != models.WebhookDeliveryPending || delivery.Attempts != attempt+1 || delivery.LastStatusCode != http.StatusServiceUnavailable || delivery.LastError == "" {
+			t.Fatalf("after attempt %d: %+v, want pending with the 503", attempt+1, delivery)
+		}
+		if delay := delivery.NextAttemptAt.Sub(before); delay < wantDelay || delay > wantDelay+time.Second {
+			t.Errorf("after attempt %d retrying in %v, want %v", attempt+1, delay, wantDelay)
+		}
+
+		// Not due yet, so another pass leaves it alone
+		dispatcher.deliverDue(context.Background())
+		if len(p.received()) != attempt+1 {
+			t.Fatal("retried before the delay was up")
+		}
+		store.deliveries[0].NextAttemptAt = &before
+	}
+
+	dispatcher.deliverDue(context.Background())
+	dead := store.only(t)
+	if dead.Status != models.WebhookDeliveryDead || dead.Attempts != 3 || dead.NextAttemptAt != nil {
+		t.Fatalf("after the last attempt %+v, want dead", dead)
+	}
+
+	// Redelivering starts over with a fresh set of attempts
+	p.respond(http.StatusOK)
+	if err := dispatcher.Redeliver(&dead); err != nil {
+		t.Fatal(err)
+	}
+	dispatcher.deliverDue(context.Background())
+	if delivery := store.only(t); delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 1 || delivery.LastError != "" {
+		t.Errorf("after redelivery %+v, want delivered", delivery)
+	}
+	if requests := p.received(); len(requests) != 4 || string(requests[3].body) != string(requests[0].body) {
+		t.Errorf("partner got %d requests, want the same payload 4 times", len(requests))
+	}
+}
+
+// Deliveries to a subscription deactivated or deleted after they were queued go dead without a request
+func TestDispatcherSubscriptionGone(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(store *memoryStore)
+	}{
+		{name: "deactivated", change: func(store *memoryStore) { store.subscriptions[0].IsActive = false }},
+		{name: "deleted", change: func(store *memoryStore) { store.subscriptions = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPartner(t, http.StatusOK)
+			store := &memoryStore{subscriptions: []models.WebhookSubscription{subscription(p.URL)}}
+			dispatcher := NewDispatcher(store, store, time.Second, 3, time.Minute)
+			if err := dispatcher.Publish("ga", models.WebhookOrderCreated, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.change(store)
+			dispatcher.deliverDue(context.Background())
+			if delivery := store.only(t); delivery.Status != models.WebhookDeliveryDead || delivery.Attempts != 1 {
+				t.Errorf("delivery %+v, want dead on the first attempt", delivery)
+			}
+			if len(p.received()) != 0 {
+				t.Error("posted to a subscription that is gone")
+			}
+		})
+	}
+}
+
+func TestNilDispatcherDrops(t *testing.T) {
+	var dispatcher *Dispatcher
+	if err := dispatcher.Publish("ga", models.WebhookOrderCreated, nil); err != nil {
+		t.Errorf("Publish on a nil dispatcher: %v", err)
+	}
+}